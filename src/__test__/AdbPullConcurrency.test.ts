@@ -0,0 +1,57 @@
+// src/__test__/AdbPullConcurrency.test.ts
+
+import { forEachLimit, resolvePullConcurrency } from '../core/transfer/FileTransferService.js';
+import { DEFAULT_ADB_PULL_CONCURRENCY, MAX_ADB_PULL_CONCURRENCY } from '../shared/const.js';
+
+const tick = (ms: number) => new Promise((r) => setTimeout(r, ms));
+
+describe('forEachLimit', () => {
+  it('never runs more than the limit at once and visits every item', async () => {
+    let running = 0;
+    let peak = 0;
+    const seen: number[] = [];
+    await forEachLimit([5, 1, 4, 2, 3, 1, 2], 3, async (ms, i) => {
+      running++;
+      peak = Math.max(peak, running);
+      await tick(ms);
+      seen.push(i);
+      running--;
+    });
+    expect(peak).toBe(3);
+    expect(seen.sort()).toEqual([0, 1, 2, 3, 4, 5, 6]);
+  });
+
+  it('stops taking new items after abort', async () => {
+    const ac = new AbortController();
+    const seen: number[] = [];
+    await forEachLimit(
+      [0, 1, 2, 3, 4, 5],
+      2,
+      async (n) => {
+        seen.push(n);
+        if (n === 1) ac.abort();
+        await tick(1);
+      },
+      ac.signal,
+    );
+    expect(seen).toEqual([0, 1]);
+  });
+});
+
+describe('resolvePullConcurrency', () => {
+  const prev = process.env.EDGE_TOOL_ADB_PULL_CONCURRENCY;
+  afterEach(() => {
+    if (prev === undefined) delete process.env.EDGE_TOOL_ADB_PULL_CONCURRENCY;
+    else process.env.EDGE_TOOL_ADB_PULL_CONCURRENCY = prev;
+  });
+
+  it('uses option, then env, then the conservative default, clamped', () => {
+    delete process.env.EDGE_TOOL_ADB_PULL_CONCURRENCY;
+    expect(resolvePullConcurrency()).toBe(DEFAULT_ADB_PULL_CONCURRENCY);
+    process.env.EDGE_TOOL_ADB_PULL_CONCURRENCY = '5';
+    expect(resolvePullConcurrency()).toBe(5);
+    expect(resolvePullConcurrency(2)).toBe(2);
+    expect(resolvePullConcurrency(0)).toBe(1);
+    expect(resolvePullConcurrency(100)).toBe(MAX_ADB_PULL_CONCURRENCY);
+  });
+});
@@ -0,0 +1,19 @@
+// src/__test__/AdbStateHint.test.ts
+
+import { adbStateHint } from '../core/connection/adbClient.js';
+
+describe('adbStateHint', () => {
+  it('explains unauthorized and offline devices distinctly', () => {
+    expect(adbStateHint('unauthorized')).toBe(
+      'unauthorized — accept the RSA prompt on the device',
+    );
+    expect(adbStateHint('offline')).toMatch(/^offline — try reconnecting/);
+    expect(adbStateHint('device')).toBe('ready');
+  });
+
+  it('treats a missing serial as not found and passes other states through', () => {
+    expect(adbStateHint('unknown')).toMatch(/^not found/);
+    expect(adbStateHint(undefined)).toMatch(/^not found/);
+    expect(adbStateHint('recovery')).toBe('recovery — not available for shell commands');
+  });
+});
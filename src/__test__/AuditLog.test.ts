@@ -0,0 +1,75 @@
+// src/__test__/AuditLog.test.ts
+
+import * as fs from 'fs';
+import * as path from 'path';
+
+import { appendAuditLog, formatAuditLine, setAuditLogEnabled } from '../core/logging/audit-log.js';
+import { clearSecrets, REDACTED, registerSecret } from '../core/logging/redactor.js';
+import { cleanDir, prepareUniqueOutDir } from './helpers/testFs.js';
+
+const at = new Date('2025-03-01T09:00:00.000Z');
+
+describe('formatAuditLine', () => {
+  afterEach(() => clearSecrets());
+
+  it('writes one TSV line with connection, outcome and duration', () => {
+    const line = formatAuditLine({
+      at,
+      connectionId: 'ssh:root@10.0.0.2:22',
+      command: 'homeyRestart',
+      ok: true,
+      durationMs: 1234.4,
+    });
+    expect(line).toBe('2025-03-01T09:00:00.000Z\tssh:root@10.0.0.2:22\thomeyRestart\tok\t1234ms');
+  });
+
+  it('redacts secrets and folds multi-line errors', () => {
+    registerSecret('s3cr3t!pw');
+    const line = formatAuditLine({
+      at,
+      command: 'exec echo s3cr3t!pw',
+      ok: false,
+      durationMs: 5,
+      error: 'sudo: a password is required\npassword=hunter2',
+    });
+    expect(line).not.toContain('s3cr3t!pw');
+    expect(line).not.toContain('hunter2');
+    expect(line).toContain(REDACTED);
+    expect(line.split('\t')).toHaveLength(6);
+    expect(line).toContain('\t-\t');
+    expect(line).not.toContain('\n');
+  });
+});
+
+describe('appendAuditLog', () => {
+  let outDir: string;
+  beforeEach(() => {
+    outDir = prepareUniqueOutDir('audit_log');
+  });
+  afterEach(() => {
+    setAuditLogEnabled(false);
+    cleanDir(outDir);
+  });
+
+  it('is a no-op while disabled', async () => {
+    setAuditLogEnabled(false);
+    const file = path.join(outDir, '.config', 'audit.log');
+    const written = await appendAuditLog(file, { at, command: 'help', ok: true, durationMs: 1 });
+    expect(written).toBe(false);
+    expect(fs.existsSync(file)).toBe(false);
+  });
+
+  it('appends lines and rotates past the size limit', async () => {
+    setAuditLogEnabled(true);
+    const file = path.join(outDir, '.config', 'audit.log');
+    const opts = { maxBytes: 200, keep: 2 };
+    for (let i = 0; i < 12; i++) {
+      await appendAuditLog(file, { at, command: `cmd${i}`, ok: true, durationMs: i }, opts);
+    }
+    expect(fs.statSync(file).size).toBeLessThanOrEqual(200);
+    expect(fs.existsSync(`${file}.1`)).toBe(true);
+    expect(fs.existsSync(`${file}.2`)).toBe(true);
+    expect(fs.existsSync(`${file}.3`)).toBe(false);
+    expect(fs.readFileSync(file, 'utf8')).toContain('\tcmd11\tok\t11ms');
+  });
+});
@@ -0,0 +1,79 @@
+// src/__test__/ChunkRotation.test.ts
+
+import type { LogEntry } from '@ipc/messages';
+import * as fs from 'fs';
+import * as path from 'path';
+
+import { ChunkWriter } from '../core/logs/ChunkWriter.js';
+import { ManifestWriter } from '../core/logs/ManifestWriter.js';
+import { PagedReader } from '../core/logs/PagedReader.js';
+import { cleanDir, prepareUniqueOutDir } from './helpers/testFs.js';
+
+const entry = (i: number): LogEntry =>
+  ({ id: i, ts: i, level: 'I', type: 'system', source: 't', text: `line ${i}` }) as LogEntry;
+
+async function writeChunks(outDir: string, count: number, perChunk: number) {
+  const manifest = await ManifestWriter.loadOrCreate(outDir);
+  const writer = new ChunkWriter(outDir, perChunk);
+  let merged = 0;
+  for (let c = 0; c < count; c++) {
+    const batch = Array.from({ length: perChunk }, (_, i) => entry(merged + i));
+    for (const p of await writer.appendBatch(batch)) {
+      manifest.addChunk(p.file, p.lines, merged, p.bytes);
+      merged += p.lines;
+    }
+  }
+  return manifest;
+}
+
+describe('realtime chunk rotation', () => {
+  let outDir: string;
+  beforeEach(() => {
+    outDir = prepareUniqueOutDir('chunk_rotation');
+  });
+  afterEach(() => cleanDir(outDir));
+
+  it('drops the oldest parts beyond maxChunks and keeps global line indexes', async () => {
+    const manifest = await writeChunks(outDir, 5, 10);
+    const removed = await manifest.rotate({ maxChunks: 2 });
+    await manifest.save();
+
+    expect(removed.map((c) => c.file)).toEqual([
+      'part-000001.ndjson',
+      'part-000002.ndjson',
+      'part-000003.ndjson',
+    ]);
+    expect(fs.existsSync(path.join(outDir, 'part-000001.ndjson'))).toBe(false);
+    expect(manifest.data.chunkCount).toBe(2);
+    expect(manifest.data.mergedLines).toBe(50);
+
+    const reader = await PagedReader.open(outDir);
+    expect(reader.getFirstLine()).toBe(30);
+    // 회전된 구간은 빈 결과, 걸친 구간은 남은 부분만
+    expect(await reader.readLineRange(0, 10)).toEqual([]);
+    expect((await reader.readLineRange(25, 35)).map((e) => e.text)).toEqual(
+      [30, 31, 32, 33, 34].map((i) => `line ${i}`),
+    );
+  });
+
+  it('rotates by total bytes but always keeps the newest part', async () => {
+    const manifest = await writeChunks(outDir, 3, 10);
+    const [, b, c] = manifest.data.chunks.map((x) => x.bytes!);
+    expect(b).toBeGreaterThan(0);
+
+    await manifest.rotate({ maxBytes: b + c });
+    expect(manifest.data.chunks.map((c) => c.file)).toEqual([
+      'part-000002.ndjson',
+      'part-000003.ndjson',
+    ]);
+
+    await manifest.rotate({ maxBytes: 1 });
+    expect(manifest.data.chunks.map((c) => c.file)).toEqual(['part-000003.ndjson']);
+  });
+
+  it('is a no-op without limits', async () => {
+    const manifest = await writeChunks(outDir, 3, 10);
+    expect(await manifest.rotate({})).toEqual([]);
+    expect(manifest.data.chunkCount).toBe(3);
+  });
+});
@@ -0,0 +1,45 @@
+// src/__test__/CommandHistory.test.ts
+
+import * as fs from 'fs';
+import * as path from 'path';
+
+import {
+  appendCommandHistory,
+  readCommandHistory,
+  rememberCommand,
+} from '../core/config/commandHistory.js';
+import { cleanDir, prepareUniqueOutDir } from './helpers/testFs.js';
+
+describe('rememberCommand', () => {
+  it('moves a repeated command to the end and ignores blank lines', () => {
+    expect(rememberCommand(['git status', 'browse /etc'], ' git status ')).toEqual([
+      'browse /etc',
+      'git status',
+    ]);
+    expect(rememberCommand(['재시작'], '   ')).toEqual(['재시작']);
+  });
+
+  it('drops the oldest entries past the limit', () => {
+    expect(rememberCommand(['a', 'b', 'c'], 'd', 3)).toEqual(['b', 'c', 'd']);
+  });
+});
+
+describe('command history file', () => {
+  let outDir: string;
+  beforeEach(() => {
+    outDir = prepareUniqueOutDir('command_history');
+  });
+  afterEach(() => cleanDir(outDir));
+
+  it('persists commands under a missing .config directory and reads them back', async () => {
+    const file = path.join(outDir, '.config', 'edgetool-history');
+    expect(await readCommandHistory(file)).toEqual([]);
+
+    await appendCommandHistory(file, 'git log -n 5');
+    await appendCommandHistory(file, '재시작');
+    await appendCommandHistory(file, 'git log -n 5');
+
+    expect(fs.readFileSync(file, 'utf8')).toBe('재시작\ngit log -n 5\n');
+    expect(await readCommandHistory(file)).toEqual(['재시작', 'git log -n 5']);
+  });
+});
@@ -0,0 +1,191 @@
+// src/__test__/CommandLine.test.ts
+
+import * as fs from 'fs';
+import * as path from 'path';
+
+import {
+  completeCommandLine,
+  createCommandHandlers,
+  parseScriptLines,
+  stripJsonFlag,
+  toCommandLine,
+} from '../extension/commands/commandHandlers.js';
+import { CommandFailedError } from '../shared/errors.js';
+import { cleanDir, prepareUniqueOutDir } from './helpers/testFs.js';
+
+describe('toCommandLine', () => {
+  it('keeps plain args and quotes args with spaces or quotes', () => {
+    expect(toCommandLine('git', ['history', '20'])).toBe('git history 20');
+    expect(toCommandLine('exec', ['ls -al', '/data'])).toBe('exec "ls -al" /data');
+    expect(toCommandLine('exec', ['echo "hi"'])).toBe(`exec 'echo "hi"'`);
+    expect(toCommandLine(' help ')).toBe('help');
+  });
+});
+
+describe('typed command routing', () => {
+  it('hands the text after the command name to the registered handler', async () => {
+    const handlers = createCommandHandlers();
+    const def = handlers.listCommands().find((d) => d.name === 'browse')!;
+    const run = jest.spyOn(def, 'run').mockResolvedValue(undefined);
+
+    await handlers.route(toCommandLine('browse', ['/data/my dir']));
+    await handlers.route('browse --quiet /etc');
+
+    expect(run).toHaveBeenNthCalledWith(1, '"/data/my dir"', expect.any(AbortSignal));
+    expect(run).toHaveBeenNthCalledWith(2, '/etc', expect.any(AbortSignal));
+  });
+});
+
+describe('--json result envelope', () => {
+  it('strips only a leading --json token', () => {
+    expect(stripJsonFlag('--json /etc')).toEqual({ args: '/etc', json: true });
+    expect(stripJsonFlag('--json')).toEqual({ args: '', json: true });
+    expect(stripJsonFlag('ls --json')).toEqual({ args: 'ls --json', json: false });
+    expect(stripJsonFlag('--jsonx')).toEqual({ args: '--jsonx', json: false });
+  });
+
+  it('accepts --json with --quiet in any order and still returns the handler result', async () => {
+    const handlers = createCommandHandlers();
+    const def = handlers.listCommands().find((d) => d.name === 'browse')!;
+    const run = jest.spyOn(def, 'run').mockResolvedValue({ picked: '/etc/hosts' });
+
+    await expect(handlers.route('browse --json --quiet /etc')).resolves.toEqual({
+      picked: '/etc/hosts',
+    });
+    await handlers.route('browse --quiet --json /tmp');
+
+    expect(run).toHaveBeenNthCalledWith(1, '/etc', expect.any(AbortSignal));
+    expect(run).toHaveBeenNthCalledWith(2, '/tmp', expect.any(AbortSignal));
+  });
+
+  it('reports a failing handler in the envelope instead of throwing', async () => {
+    const handlers = createCommandHandlers();
+    const def = handlers.listCommands().find((d) => d.name === 'browse')!;
+    jest.spyOn(def, 'run').mockRejectedValue(new Error('boom'));
+
+    await expect(handlers.route('browse --json /etc')).resolves.toBeUndefined();
+    await expect(handlers.route('browse /etc')).rejects.toThrow('boom');
+  });
+
+  it('treats a handler-reported failure as a failed outcome, not a result', async () => {
+    const handlers = createCommandHandlers();
+    const def = handlers.listCommands().find((d) => d.name === 'browse')!;
+    jest.spyOn(def, 'run').mockRejectedValue(new CommandFailedError('no such dir', null, true));
+
+    await expect(handlers.route('browse --json /nope')).resolves.toBeUndefined();
+    await expect(handlers.route('browse /nope')).resolves.toBeUndefined();
+    await expect(handlers.route('browse --json /nope', { rethrow: true })).rejects.toThrow(
+      'no such dir',
+    );
+  });
+});
+
+describe('runScript', () => {
+  let outDir: string;
+  beforeEach(() => {
+    outDir = prepareUniqueOutDir('run_script');
+  });
+  afterEach(() => cleanDir(outDir));
+
+  const setup = (body: string) => {
+    const file = path.join(outDir, 'setup.edge');
+    fs.writeFileSync(file, body);
+    const handlers = createCommandHandlers();
+    const spy = (name: string) =>
+      jest.spyOn(handlers.listCommands().find((d) => d.name === name)!, 'run');
+    return { file, handlers, browse: spy('browse'), info: spy('connectInfo') };
+  };
+
+  it('skips blank and comment lines and keeps line numbers', () => {
+    expect(parseScriptLines('# mount\n\n  browse /etc  \r\n#x\nconnectInfo')).toEqual([
+      { lineNo: 3, line: 'browse /etc' },
+      { lineNo: 5, line: 'connectInfo' },
+    ]);
+  });
+
+  it('dispatches each line through route() in order', async () => {
+    const { file, handlers, browse, info } = setup('# setup\nbrowse /etc\nconnectInfo --all\n');
+    const order: string[] = [];
+    browse.mockImplementation(async (a) => void order.push(`browse ${a}`));
+    info.mockImplementation(async (a) => void order.push(`info ${a}`));
+
+    await expect(handlers.route(`runScript "${file}"`)).resolves.toEqual({
+      file,
+      commands: 2,
+      failed: [],
+    });
+    expect(order).toEqual(['browse /etc', 'info --all']);
+  });
+
+  it('stops on the first failure unless --continue-on-error is given', async () => {
+    const { file, handlers, browse, info } = setup('browse /a\nconnectInfo\n');
+    browse.mockRejectedValue(new Error('boom'));
+    info.mockResolvedValue(undefined);
+
+    await expect(handlers.route(`script ${file}`)).resolves.toBeUndefined();
+    await expect(handlers.route(`script ${file}`, { rethrow: true })).rejects.toThrow(
+      'runScript stopped at line 1: boom',
+    );
+    expect(info).not.toHaveBeenCalled();
+
+    await expect(handlers.route(`script ${file} --continue-on-error`)).resolves.toMatchObject({
+      failed: [1],
+    });
+    expect(info).toHaveBeenCalledTimes(1);
+  });
+
+  it('stops on a failure the handler already reported', async () => {
+    const { file, handlers, browse, info } = setup('browse /a\nconnectInfo\n');
+    browse.mockRejectedValue(new CommandFailedError('browse: not connected', null, true));
+    info.mockResolvedValue(undefined);
+
+    await expect(handlers.route(`script ${file}`, { rethrow: true })).rejects.toThrow(
+      'runScript stopped at line 1: browse: not connected',
+    );
+    expect(info).not.toHaveBeenCalled();
+  });
+
+  it('rejects unknown and nested commands before running anything', async () => {
+    const { file, handlers, browse } = setup('browse /a\nnope\n');
+    const run = () => handlers.route(`script ${file}`, { rethrow: true });
+    await expect(run()).rejects.toThrow(/:2: unknown command/);
+
+    fs.writeFileSync(file, 'browse /a\nrunScript other.edge\n');
+    await expect(run()).rejects.toThrow(/:2: nested runScript/);
+    expect(browse).not.toHaveBeenCalled();
+  });
+});
+
+describe('completeCommandLine', () => {
+  const defs = createCommandHandlers().listCommands();
+  const lines = (line: string) => completeCommandLine(defs, line).map((c) => c.line);
+
+  it('completes visible command names by name or alias prefix', () => {
+    expect(lines('connectI')).toEqual(['connectInfo ']);
+    expect(lines('connect-i')).toEqual(['connectInfo ']);
+    expect(lines('scr')).toEqual(['runScript ']);
+    expect(lines('hel')).toEqual([]);
+    expect(completeCommandLine(defs, 'doc')[0].help).toMatch(/adb\/ssh/);
+  });
+
+  it('offers subcommands from the help text as the first argument', () => {
+    expect(lines('homeyEnv ')).toEqual(
+      expect.arrayContaining(['homeyEnv list ', 'homeyEnv set ', 'homeyEnv unset ']),
+    );
+    expect(lines('homeyEnv s')).toEqual(['homeyEnv set ']);
+    expect(lines('homeyEnv ')).not.toContain('homeyEnv --env ');
+    expect(lines('homeyEnv set ')).toEqual(['homeyEnv set --quiet ', 'homeyEnv set --json ']);
+  });
+
+  it('offers options not yet typed, including the global flags', () => {
+    expect(lines('runScript a.edge --c')).toEqual(['runScript a.edge --continue-on-error ']);
+    expect(lines('homeyLoggingLive --since -10m --')).toEqual(
+      expect.arrayContaining(['homeyLoggingLive --since -10m --until ']),
+    );
+    expect(lines('homeyLoggingLive --since -10m --')).not.toContain(
+      'homeyLoggingLive --since -10m --since ',
+    );
+    expect(lines('browse --json --')).toEqual(['browse --json --quiet ']);
+    expect(lines('nope --')).toEqual([]);
+  });
+});
@@ -0,0 +1,269 @@
+// src/__test__/ConnectionLookup.test.ts
+
+import {
+  applyConnectionEdit,
+  type ConnectionConfigFile,
+  type ConnectionInfo,
+  editableFields,
+  findConnection,
+  formatConnectionTable,
+  formatFieldValue,
+  formatLastUsed,
+  lastUsedMs,
+  logSourceFilePaths,
+  markRecent,
+  migrateLastUsed,
+  redactConnectionConfig,
+  setDefaultConnection,
+  startupConnection,
+  upsertConnection,
+  validateConnectionConfig,
+} from '../core/config/connection-config.js';
+
+const cfg: ConnectionConfigFile = {
+  recent: 'adb:R58M',
+  connections: [
+    {
+      id: 'ssh:root@10.0.0.2:22',
+      alias: 'Bench-A',
+      type: 'SSH',
+      details: { host: '10.0.0.2', user: 'root', port: 22 },
+      lastUsed: '2025-03-02T00:00:00.000Z',
+    },
+    {
+      id: 'adb:R58M',
+      alias: 'bench-a',
+      type: 'ADB',
+      details: { deviceID: 'R58M' },
+      lastUsed: '2025-03-01T00:00:00.000Z',
+    },
+    {
+      id: 'ssh:homey@10.0.0.3:22',
+      type: 'SSH',
+      details: { host: '10.0.0.3', user: 'homey', port: 22 },
+      lastUsed: '2025-02-01T00:00:00.000Z',
+    },
+  ],
+};
+
+describe('findConnection', () => {
+  it('matches an exact id first', () => {
+    expect(findConnection(cfg, 'ssh:homey@10.0.0.3:22')?.id).toBe('ssh:homey@10.0.0.3:22');
+  });
+
+  it('matches aliases case-insensitively, preferring the most recent entry', () => {
+    expect(findConnection(cfg, ' BENCH-A ')?.id).toBe('ssh:root@10.0.0.2:22');
+  });
+
+  it('returns undefined for unknown or empty keys', () => {
+    expect(findConnection(cfg, 'bench-b')).toBeUndefined();
+    expect(findConnection(cfg, '')).toBeUndefined();
+  });
+});
+
+describe('default connection pointer', () => {
+  const clone = (): ConnectionConfigFile => JSON.parse(JSON.stringify(cfg));
+
+  it('keeps the pinned default as the startup target after connecting elsewhere', () => {
+    const c = setDefaultConnection(clone(), 'ssh:homey@10.0.0.3:22');
+    markRecent(c, 'adb:R58M');
+    expect(c.recent).toBe('adb:R58M');
+    expect(startupConnection(c)?.id).toBe('ssh:homey@10.0.0.3:22');
+  });
+
+  it('clears both pointers so nothing auto-connects', () => {
+    const c = setDefaultConnection(setDefaultConnection(clone(), 'adb:R58M'));
+    expect(c.default).toBeUndefined();
+    expect(c.recent).toBeUndefined();
+    expect(startupConnection(c)).toBeUndefined();
+  });
+
+  it('falls back to recent when the default id is gone and rejects unknown ids', () => {
+    const c = clone();
+    c.default = 'ssh:gone@10.0.0.9:22';
+    expect(startupConnection(c)?.id).toBe('adb:R58M');
+    expect(() => setDefaultConnection(c, 'ssh:gone@10.0.0.9:22')).toThrow();
+  });
+});
+
+describe('connection inventory', () => {
+  it('formats ISO and legacy unix-seconds lastUsed as the same local date', () => {
+    const iso = '2025-03-02T08:09:10.000Z';
+    const secs = String(Date.parse(iso) / 1000);
+    expect(formatLastUsed(secs)).toBe(formatLastUsed(iso));
+    expect(formatLastUsed(iso)).toMatch(/^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}$/);
+    expect(formatLastUsed('yesterday')).toBe('yesterday');
+    expect(formatLastUsed(undefined)).toBe('-');
+  });
+
+  it('lists every saved connection with flags and redacts passwords', () => {
+    const c: ConnectionConfigFile = JSON.parse(JSON.stringify(cfg));
+    c.default = 'ssh:homey@10.0.0.3:22';
+    Object.assign(c.connections[2].details, { password: 'hunter2', useSudo: true });
+    const lines = formatConnectionTable(c, 'ssh:root@10.0.0.2:22');
+    expect(lines).toHaveLength(4);
+    expect(lines[0]).toMatch(/^alias\s+type\s+target\s+auth\s+last used\s+flags$/);
+    expect(lines[1]).toMatch(/^Bench-A\s+SSH\s+root@10\.0\.0\.2:22\s+key\s+.*\sactive$/);
+    expect(lines[2]).toMatch(/^bench-a\s+ADB\s+R58M\s+-\s+.*\srecent$/);
+    expect(lines[3]).toMatch(/^-\s+SSH\s+homey@10\.0\.0\.3:22\s+password\(\*\*\*\)\s/);
+    expect(lines[3]).toMatch(/\sdefault,sudo$/);
+    expect(lines.join('\n')).not.toContain('hunter2');
+  });
+});
+
+describe('lastUsed ordering', () => {
+  const adb = (id: string, lastUsed: string) => ({
+    id,
+    type: 'ADB' as const,
+    details: { deviceID: id },
+    lastUsed,
+  });
+
+  it('parses ISO, unix seconds and unix ms to the same instant', () => {
+    const ms = Date.parse('2023-11-14T22:13:20.000Z');
+    expect(lastUsedMs('2023-11-14T22:13:20.000Z')).toBe(ms);
+    expect(lastUsedMs('1700000000')).toBe(ms);
+    expect(lastUsedMs(String(ms))).toBe(ms);
+    expect(lastUsedMs('garbage')).toBe(0);
+  });
+
+  it('prunes the genuinely oldest entry across a digit-count boundary', () => {
+    const c: ConnectionConfigFile = {
+      connections: [
+        adb('a', '999999999'), // 2001 — 문자열로는 가장 커 보인다
+        adb('b', '1700000000'),
+        adb('c', '1700000100'),
+        adb('d', '2024-01-01T00:00:00.000Z'),
+        adb('e', '2024-06-01T00:00:00.000Z'),
+      ],
+    };
+    upsertConnection(c, adb('f', '2025-01-01T00:00:00.000Z'));
+    expect(c.connections.map((x) => x.id)).toEqual(['f', 'e', 'd', 'c', 'b']);
+  });
+
+  it('migrates numeric lastUsed values to ISO strings on load', () => {
+    const c: ConnectionConfigFile = {
+      connections: [adb('old', '1600000000'), adb('new', '2024-06-01T00:00:00.000Z')],
+    };
+    expect(migrateLastUsed(c)).toBe(true);
+    expect(c.connections.map((x) => x.id)).toEqual(['new', 'old']);
+    expect(c.connections[1].lastUsed).toBe('2020-09-13T12:26:40.000Z');
+    expect(migrateLastUsed(c)).toBe(false);
+  });
+});
+
+describe('connection config checks', () => {
+  it('accepts a consistent config and redacts passwords for display', () => {
+    const c: ConnectionConfigFile = {
+      ...cfg,
+      connections: [
+        ...cfg.connections,
+        {
+          id: 'ssh:dev@10.0.0.4:2222',
+          type: 'SSH',
+          details: { host: '10.0.0.4', user: 'dev', port: 2222, password: 'hunter2' },
+          lastUsed: '2025-01-01T00:00:00.000Z',
+        },
+      ],
+      defaultLoggingConfig: {
+        configured: true,
+        log_types: ['homey', 'system'],
+        log_sources: { homey: 'cmd:journalctl -f -u homey-pro', system: 'file:/var/log/messages' },
+      },
+    };
+    expect(validateConnectionConfig(c)).toEqual([]);
+    const shown = JSON.stringify(redactConnectionConfig(c));
+    expect(shown).not.toContain('hunter2');
+    expect(shown).toContain('"password":"***"');
+    expect((c.connections[3].details as { password?: string }).password).toBe('hunter2');
+  });
+
+  it('reports dangling pointers, duplicate ids and malformed logging sources', () => {
+    const problems = validateConnectionConfig({
+      recent: 'adb:gone',
+      default: 'adb:R58M',
+      connections: [
+        { id: 'adb:R58M', type: 'ADB', details: { deviceID: 'R58M' }, lastUsed: '' },
+        { id: 'adb:R58M', type: 'ADB', details: { deviceID: '' }, lastUsed: 'yesterday' },
+        { id: 'ssh:x', type: 'SSH', details: { host: 'h', user: 'u', port: 0 }, lastUsed: '' },
+      ],
+      defaultLoggingConfig: {
+        log_types: ['homey', 'kernel'],
+        log_sources: { homey: '/var/log/homey.log', extra: 'cmd:dmesg -w' },
+      },
+    });
+    const has = (re: RegExp) => expect(problems.some((p) => re.test(p))).toBe(true);
+    has(/^recent가 없는 연결/);
+    has(/id 'adb:R58M'가 2번/);
+    has(/deviceID가 없습니다/);
+    has(/lastUsed를 해석할 수 없습니다\(yesterday\)/);
+    has(/SSH port가 잘못됐습니다\(0\)/);
+    has(/log_sources\.homey: 'file:<경로>' 또는 'cmd:<명령>'/);
+    has(/'kernel'에 해당하는 log_sources가 없습니다/);
+    has(/'extra'이 log_types에 없습니다/);
+    expect(problems.some((p) => p.startsWith('default'))).toBe(false);
+  });
+
+  it('lists only file: logging sources, once each', () => {
+    const paths = logSourceFilePaths({
+      connections: [],
+      defaultLoggingConfig: {
+        log_sources: {
+          homey: 'cmd:journalctl -f -u homey-pro',
+          system: 'file: /var/log/messages ',
+          kernel: 'file:/var/log/kern.log',
+          again: 'file:/var/log/messages',
+        },
+      },
+    });
+    expect(paths).toEqual(['/var/log/messages', '/var/log/kern.log']);
+    expect(logSourceFilePaths({ connections: [] })).toEqual([]);
+  });
+});
+
+describe('connection editing', () => {
+  const clone = (): ConnectionConfigFile => JSON.parse(JSON.stringify(cfg));
+  const field = (c: ConnectionInfo, key: string) => editableFields(c).find((f) => f.key === key)!;
+
+  it('adds and removes optional SSH fields without touching the id', () => {
+    const c = clone();
+    const draft = JSON.parse(JSON.stringify(c.connections[2])) as ConnectionInfo;
+    field(draft, 'password').set(draft, 'pw');
+    field(draft, 'useSudo').set(draft, true);
+    field(draft, 'alias').set(draft, '');
+    const saved = applyConnectionEdit(c, draft.id, draft);
+    expect(saved.id).toBe('ssh:homey@10.0.0.3:22');
+    expect(saved.details).toEqual({
+      host: '10.0.0.3',
+      user: 'homey',
+      port: 22,
+      password: 'pw',
+      useSudo: true,
+    });
+    expect(formatFieldValue(field(saved, 'password'), saved)).toBe('***');
+
+    field(draft, 'password').set(draft, '');
+    field(draft, 'useSudo').set(draft, false);
+    expect(applyConnectionEdit(c, draft.id, draft).details).toEqual({
+      host: '10.0.0.3',
+      user: 'homey',
+      port: 22,
+    });
+  });
+
+  it('re-keys the entry and its recent/default pointers when the target changes', () => {
+    const c = setDefaultConnection(clone(), 'adb:R58M');
+    const draft = JSON.parse(JSON.stringify(c.connections[1])) as ConnectionInfo;
+    field(draft, 'deviceID').set(draft, 'R58N');
+    const saved = applyConnectionEdit(c, 'adb:R58M', draft);
+    expect(saved.id).toBe('adb:R58N');
+    expect(c.recent).toBe('adb:R58N');
+    expect(c.default).toBe('adb:R58N');
+    expect(c.connections.map((x) => x.id)).toContain('adb:R58N');
+
+    const dup = JSON.parse(JSON.stringify(c.connections[0])) as ConnectionInfo;
+    field(dup, 'host').set(dup, '10.0.0.3');
+    field(dup, 'user').set(dup, 'homey');
+    expect(() => applyConnectionEdit(c, dup.id, dup)).toThrow(/이미 같은 대상/);
+  });
+});
@@ -0,0 +1,94 @@
+// src/__test__/Deadline.test.ts
+
+import * as fs from 'fs';
+import * as path from 'path';
+
+import {
+  createDeadline,
+  DEADLINE_EXCEEDED_MESSAGE,
+  defaultDeadlineMs,
+  parseDurationMs,
+  throwIfAborted,
+} from '../core/connection/deadline.js';
+import { FileTransferService } from '../core/transfer/FileTransferService.js';
+import { ErrorCategory, isXError } from '../shared/errors.js';
+import { cleanDir, prepareUniqueOutDir } from './helpers/testFs.js';
+
+describe('parseDurationMs / defaultDeadlineMs', () => {
+  const prev = process.env.EDGE_TOOL_DEADLINE;
+  afterEach(() => {
+    if (prev === undefined) delete process.env.EDGE_TOOL_DEADLINE;
+    else process.env.EDGE_TOOL_DEADLINE = prev;
+  });
+
+  it('reads seconds/minutes and falls back to EDGE_TOOL_DEADLINE', () => {
+    expect(parseDurationMs('90')).toBe(90_000);
+    expect(parseDurationMs('5m')).toBe(300_000);
+    expect(parseDurationMs('0')).toBeUndefined();
+    expect(parseDurationMs('soon')).toBeUndefined();
+
+    delete process.env.EDGE_TOOL_DEADLINE;
+    expect(defaultDeadlineMs()).toBeUndefined();
+    process.env.EDGE_TOOL_DEADLINE = '2m';
+    expect(defaultDeadlineMs()).toBe(120_000);
+  });
+});
+
+describe('createDeadline', () => {
+  afterEach(() => jest.useRealTimers());
+
+  it('aborts with a Timeout XError once the deadline passes', () => {
+    jest.useFakeTimers();
+    const dl = createDeadline(1500);
+    expect(dl.signal.aborted).toBe(false);
+    jest.advanceTimersByTime(1500);
+    expect(dl.signal.aborted).toBe(true);
+    expect(() => throwIfAborted(dl.signal)).toThrow(DEADLINE_EXCEEDED_MESSAGE);
+    expect(isXError(dl.signal.reason, ErrorCategory.Timeout)).toBe(true);
+    dl.dispose();
+  });
+
+  it('follows the parent signal and never fires after dispose', () => {
+    jest.useFakeTimers();
+    const parent = new AbortController();
+    const a = createDeadline(undefined, parent.signal);
+    parent.abort();
+    expect(a.signal.aborted).toBe(true);
+    a.dispose();
+
+    const b = createDeadline(1000);
+    b.dispose();
+    jest.advanceTimersByTime(5000);
+    expect(b.signal.aborted).toBe(false);
+  });
+});
+
+describe('transfer honors the deadline', () => {
+  it('cleans up the remote staging file and surfaces the deadline error', async () => {
+    const calls: string[] = [];
+    const dl = createDeadline(20);
+    // SSH 업로드: base64 청크 append 도중 링크가 멈춘 것처럼 signal이 올 때까지 대기
+    const cm: any = {
+      getSnapshot: () => ({ active: { type: 'SSH' } }),
+      run: (cmd: string, _args: string[] = [], opts: { signal?: AbortSignal } = {}) => {
+        calls.push(cmd);
+        if (!cmd.includes('printf')) return Promise.resolve({ code: 0, stdout: '', stderr: '' });
+        return new Promise((_res, rej) =>
+          opts.signal?.addEventListener('abort', () => rej(opts.signal!.reason)),
+        );
+      },
+      stream: async () => {},
+    };
+    const dir = prepareUniqueOutDir('deadline_upload');
+    fs.writeFileSync(path.join(dir, 'a.txt'), 'hello');
+    try {
+      const ft = new FileTransferService(cm);
+      const upload = ft.uploadViaTarBase64(dir, '/data/x', { signal: dl.signal });
+      await expect(upload).rejects.toMatchObject({ category: ErrorCategory.Timeout });
+      expect(calls[calls.length - 1]).toMatch(/rm -f .*edge-upload-/);
+    } finally {
+      dl.dispose();
+      cleanDir(dir);
+    }
+  });
+});
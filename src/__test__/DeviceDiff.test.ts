@@ -0,0 +1,34 @@
+// src/__test__/DeviceDiff.test.ts
+
+import { fileCategory, parseRemoteHashes } from '../core/controller/GitController.js';
+
+const DIRS = { pro: 'homey_pro', core: 'homey_core', sdk: 'homey_sdk', bridge: 'homey_bridge' };
+
+describe('fileCategory', () => {
+  it('buckets files by layout dir or host_sync', () => {
+    expect(fileCategory('/ws/homey_pro/lib/app.js', DIRS)).toBe('pro');
+    expect(fileCategory('C:\\ws\\homey_core\\a.json', DIRS)).toBe('core');
+    expect(fileCategory('/ws/x/homey-apps-sdk-v3/index.js', DIRS)).toBe('sdk');
+    expect(fileCategory('/ws/host_sync/etc/homey/config.json', DIRS)).toBe('host');
+    expect(fileCategory('/ws/README.md', DIRS)).toBeUndefined();
+  });
+});
+
+describe('parseRemoteHashes', () => {
+  it('maps batch indexes to hashes and skips missing files', () => {
+    const sha = 'a'.repeat(64);
+    const { algo, hashes } = parseRemoteHashes(`#sha256sum\n0 ${sha}\n2 ${'B'.repeat(64)}\n`);
+    expect(algo).toBe('sha256');
+    expect([...hashes]).toEqual([
+      [0, sha],
+      [2, 'b'.repeat(64)],
+    ]);
+  });
+
+  it('detects the md5sum fallback and ignores noise', () => {
+    const out = parseRemoteHashes(`#md5sum\r\n1 ${'c'.repeat(32)}\r\nsh: warning\r\n`);
+    expect(out.algo).toBe('md5');
+    expect(out.hashes.get(1)).toBe('c'.repeat(32));
+    expect(out.hashes.size).toBe(1);
+  });
+});
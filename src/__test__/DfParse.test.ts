@@ -0,0 +1,42 @@
+// src/__test__/DfParse.test.ts
+
+import { parseDfAvailableBytes } from '../core/controller/HostController.js';
+
+describe('parseDfAvailableBytes', () => {
+  it('reads KiB from POSIX / busybox -P output', () => {
+    const out = [
+      'Filesystem           1024-blocks    Used Available Capacity Mounted on',
+      '/dev/mmcblk0p3          3870592 1203344   2450432  33% /',
+    ].join('\n');
+    expect(parseDfAvailableBytes(out)).toBe(2450432 * 1024);
+  });
+
+  it('reads KiB from toybox (Android) df -k output', () => {
+    const out = [
+      'Filesystem     1K-blocks    Used Available Use% Mounted on',
+      '/dev/block/dm-5  5678948 1234567   4444381  22% /data',
+    ].join('\n');
+    expect(parseDfAvailableBytes(out)).toBe(4444381 * 1024);
+  });
+
+  it('handles wrapped long device names', () => {
+    const out = [
+      'Filesystem     1K-blocks    Used Available Use% Mounted on',
+      '/dev/mapper/very-long-volume-group-name-root',
+      '                 1000000  400000    600000  40% /',
+    ].join('\n');
+    expect(parseDfAvailableBytes(out)).toBe(600000 * 1024);
+  });
+
+  it('reads unit-suffixed values from legacy Android toolbox df', () => {
+    const out = ['Filesystem  Size   Used   Free   Blksize', '/data  5.5G  1.2G  4.3G  4096'].join(
+      '\n',
+    );
+    expect(parseDfAvailableBytes(out)).toBe(Math.floor(4.3 * 1024 ** 3));
+  });
+
+  it('returns undefined for unparseable output', () => {
+    expect(parseDfAvailableBytes('')).toBeUndefined();
+    expect(parseDfAvailableBytes('df: /nope: No such file or directory')).toBeUndefined();
+  });
+});
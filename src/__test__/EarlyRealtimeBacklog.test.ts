@@ -0,0 +1,72 @@
+// src/__test__/EarlyRealtimeBacklog.test.ts
+
+import type { LogEntry } from '@ipc/messages';
+
+import { HostWebviewBridge } from '../extension/messaging/hostWebviewBridge.js';
+import { REALTIME_BUFFER_MAX } from '../shared/const.js';
+
+const entry = (i: number): LogEntry =>
+  ({ id: i, idx: i, ts: i, level: 'I', type: 'system', text: `line ${i}` }) as LogEntry;
+
+/** 웹뷰 호스트 대역: postMessage 기록 + 웹뷰→호스트 메시지 주입 */
+function fakeHost() {
+  const posted: any[] = [];
+  let onMessage: (m: any) => Promise<void> = async () => {};
+  const host = {
+    visible: true,
+    onDidChangeVisibility: () => ({ dispose() {} }),
+    webview: {
+      postMessage: async (m: any) => (posted.push(m), true),
+      onDidReceiveMessage: (cb: (m: any) => Promise<void>) => {
+        onMessage = cb;
+        return { dispose() {} };
+      },
+    },
+  };
+  return { host: host as any, posted, receive: (m: any) => onMessage(m) };
+}
+
+const batches = (posted: any[]) => posted.filter((m) => m.type === 'logs.batch');
+
+describe('realtime lines before viewer.ready', () => {
+  it('keeps the most recent lines and sends them once the viewer is ready', async () => {
+    const { host, posted, receive } = fakeHost();
+    const bridge = new HostWebviewBridge(host);
+    bridge.start();
+
+    const n = REALTIME_BUFFER_MAX + 200;
+    for (let i = 1; i <= n; i += 100) {
+      const batch = Array.from({ length: 100 }, (_, k) => entry(i + k));
+      expect(bridge.deferUntilReady(batch)).toBe(true);
+    }
+    expect(batches(posted)).toHaveLength(0);
+
+    await receive({ v: 1, type: 'viewer.ready', payload: {} });
+    const [sent] = batches(posted);
+    expect(sent.payload.logs).toHaveLength(REALTIME_BUFFER_MAX);
+    expect(sent.payload.logs[0].idx).toBe(201);
+    expect(sent.payload.logs.at(-1).idx).toBe(n);
+
+    // 준비 후에는 보관하지 않고 호출부가 바로 보낸다
+    expect(bridge.deferUntilReady([entry(n + 1)])).toBe(false);
+    bridge.dispose();
+  });
+
+  it('sends each idx once when flushes pass overlapping latest windows', async () => {
+    const { host, posted, receive } = fakeHost();
+    const bridge = new HostWebviewBridge(host);
+    bridge.start();
+
+    // 실시간 세션처럼 매 flush마다 "최신 200줄" 윈도우를 넘긴다
+    const window = (end: number) => {
+      const start = Math.max(1, end - 199);
+      return Array.from({ length: end - start + 1 }, (_, k) => entry(start + k));
+    };
+    for (const end of [150, 200, 260, 300]) bridge.deferUntilReady(window(end));
+
+    await receive({ v: 1, type: 'viewer.ready', payload: {} });
+    const idxs = batches(posted)[0].payload.logs.map((e: LogEntry) => e.idx);
+    expect(idxs).toEqual(Array.from({ length: 300 }, (_, k) => k + 1));
+    bridge.dispose();
+  });
+});
@@ -0,0 +1,36 @@
+// src/__test__/GitHistory.test.ts
+
+import { classifyCommit, parseHistory } from '../core/controller/GitController.js';
+
+describe('git history', () => {
+  it('classifies download / [Do not push] / user commits', () => {
+    expect(classifyCommit('[Do not push] download homey_pro')).toBe('download');
+    expect(classifyCommit('[Do not push] add gitignore')).toBe('skip');
+    expect(classifyCommit('fix config')).toBe('user');
+  });
+
+  it('parses --graph output and keeps connector-only lines', () => {
+    const out = [
+      '* \x1fa1b2c3d\x1f2026-10-01 10:00\x1ffix config',
+      '|\\',
+      '| * \x1fe4f5a6b\x1f2026-09-30 09:12\x1f[Do not push] download host_sync',
+    ].join('\n');
+    expect(parseHistory(out)).toEqual([
+      {
+        graph: '* ',
+        hash: 'a1b2c3d',
+        date: '2026-10-01 10:00',
+        subject: 'fix config',
+        kind: 'user',
+      },
+      { graph: '|\\' },
+      {
+        graph: '| * ',
+        hash: 'e4f5a6b',
+        date: '2026-09-30 09:12',
+        subject: '[Do not push] download host_sync',
+        kind: 'download',
+      },
+    ]);
+  });
+});
@@ -0,0 +1,220 @@
+// src/__test__/GitPushFiles.test.ts
+
+import * as fs from 'fs';
+import * as path from 'path';
+
+import {
+  GitController,
+  type GitRunner,
+  isCommitId,
+  parseCommitChanges,
+} from '../core/controller/GitController.js';
+import type { HostController } from '../core/controller/HostController.js';
+import { DEFAULT_HOMEY_LAYOUT } from '../shared/const.js';
+import { cleanDir, prepareUniqueOutDir } from './helpers/testFs.js';
+
+const WS = path.join(path.sep, 'ws');
+
+/** `git log --name-status --pretty=format:%x1e%s` 형태의 합성 출력(최신 커밋부터) */
+const LOG = [
+  '\x1efix pro app',
+  'M\thomey_pro/lib/app.js',
+  'A\thost_sync/etc/homey/config.json',
+  '',
+  '\x1e[Do not push] download homey_core',
+  'A\thomey_core/dist/index.js',
+  '',
+  '\x1e[Do not push] add gitignore',
+  'A\t.gitignore',
+  '',
+  '\x1etweak sdk',
+  'A\thomey_sdk/index.js',
+  'A\thomey_pro/lib/app.js',
+].join('\n');
+
+const up = (file: string) => ({ file, deleted: false });
+
+/** 저장소 없이 git 호출을 기록하고 정해 둔 출력을 돌려주는 대역 */
+function fakeGit(stdout: string) {
+  const calls: string[][] = [];
+  const git: GitRunner = async (args) => {
+    calls.push(args);
+    return { stdout, stderr: '' };
+  };
+  return { git, calls };
+}
+
+/** 레이아웃/원격 경로만 흉내 내고 push 대상을 기록하는 HostController 대역 */
+function fakeHost() {
+  const pushed: Array<[string, string]> = [];
+  const removed: string[] = [];
+  const host = {
+    getHomeyLayout: async () => DEFAULT_HOMEY_LAYOUT,
+    resolveHomeyPath: async (kind: string) => `/vol/${kind}`,
+    toHostFromLocalHostSync: (f: string) => f.replace(/\\/g, '/').split('/host_sync')[1],
+    pushFile: async (local: string, remote: string) => void pushed.push([local, remote]),
+    removeFile: async (remote: string) => void removed.push(remote),
+  };
+  return { host: host as unknown as HostController, pushed, removed };
+}
+
+describe('parseCommitChanges', () => {
+  it('collects user commit files once and skips [Do not push] commits', () => {
+    expect(parseCommitChanges(LOG)).toEqual([
+      up('homey_pro/lib/app.js'),
+      up('host_sync/etc/homey/config.json'),
+      up('homey_sdk/index.js'),
+    ]);
+  });
+
+  it('keeps the most recent status per file so later deletions win', () => {
+    const out = ['\x1erm old', 'D\thomey_pro/old.js', '\x1eadd old', 'A\thomey_pro/old.js'];
+    const [latest] = parseCommitChanges(out.join('\n'));
+    expect(latest).toEqual({ file: 'homey_pro/old.js', deleted: true });
+    expect(parseCommitChanges('M\thomey_core/a.js\nD\thomey_core/b.js\n')).toEqual([
+      up('homey_core/a.js'),
+      { file: 'homey_core/b.js', deleted: true },
+    ]);
+  });
+
+  it('does not push older user changes to files a later [Do not push] commit re-recorded', () => {
+    const out = [
+      '\x1e[Do not push] download homey_pro',
+      'M\thomey_pro/lib/app.js',
+      '\x1efix pro app',
+      'M\thomey_pro/lib/app.js',
+      'M\thomey_pro/lib/util.js',
+    ];
+    expect(parseCommitChanges(out.join('\n'))).toEqual([up('homey_pro/lib/util.js')]);
+  });
+
+  it('returns nothing for empty output or only skipped commits', () => {
+    expect(parseCommitChanges('')).toEqual([]);
+    const skipped = '\x1e[Do not push] download host_sync\nA\thost_sync/a\n';
+    expect(parseCommitChanges(skipped)).toEqual([]);
+  });
+});
+
+describe('isCommitId', () => {
+  it('accepts 7-40 hex characters only', () => {
+    expect(isCommitId('a1b2c3d')).toBe(true);
+    expect(isCommitId('A1B2C3D4E5')).toBe(true);
+    expect(isCommitId('f'.repeat(40))).toBe(true);
+    expect(isCommitId('a1b2c3')).toBe(false);
+    expect(isCommitId('f'.repeat(41))).toBe(false);
+    expect(isCommitId('a1b2c3g')).toBe(false);
+    expect(isCommitId('homey_pro/app.js')).toBe(false);
+  });
+});
+
+describe('GitController push without a repository', () => {
+  it('pushes every user commit file to its category target', async () => {
+    const { git, calls } = fakeGit(LOG);
+    const { host, pushed } = fakeHost();
+    await new GitController(host, WS, git).push('');
+
+    expect(calls).toEqual([['log', '--name-status', '--no-renames', '--pretty=format:%x1e%s']]);
+    expect(pushed).toEqual([
+      [path.join(WS, 'host_sync/etc/homey/config.json'), '/etc/homey/config.json'],
+      [path.join(WS, 'homey_pro/lib/app.js'), '/vol/pro/lib/app.js'],
+      [path.join(WS, 'homey_sdk/index.js'), '/vol/sdk/index.js'],
+    ]);
+  });
+
+  it('skips uploads whose local file no longer exists and keeps pushing the rest', async () => {
+    const { git } = fakeGit('M\thomey_pro/gone.js\nM\thomey_pro/app.js\n');
+    const { host, pushed } = fakeHost();
+    const enoent = Object.assign(new Error('ENOENT: no such file'), { code: 'ENOENT' });
+    const pushFile = host.pushFile.bind(host);
+    host.pushFile = async (local, remote) =>
+      local.endsWith('gone.js') ? Promise.reject(enoent) : pushFile(local, remote);
+
+    const sent = await new GitController(host, WS, git).push('a1b2c3d');
+    expect(pushed).toEqual([[path.join(WS, 'homey_pro/app.js'), '/vol/pro/app.js']]);
+    expect(sent.map((p) => p.remote)).toEqual(['/vol/pro/app.js']);
+  });
+
+  it('rejects -h when the push spans several targets', async () => {
+    const { host, pushed } = fakeHost();
+    const twoKinds = fakeGit('M\thomey_pro/a.js\nM\thomey_core/b.js\n').git;
+    const mixed = new GitController(host, WS, twoKinds);
+    await expect(mixed.push('a1b2c3d', { hostPath: '/opt/x' })).rejects.toThrow('pro, core');
+    const hosts = new GitController(host, WS, fakeGit('M\thost_sync/a\nM\thost_sync/b\n').git);
+    await expect(hosts.push('a1b2c3d', { hostPath: '/etc/x' })).rejects.toThrow('파일 2개');
+    expect(pushed).toEqual([]);
+
+    const one = new GitController(host, WS, fakeGit('M\thost_sync/etc/a.conf\n').git);
+    await one.push('a1b2c3d', { hostPath: '/etc/b.conf' });
+    expect(pushed).toEqual([[path.join(WS, 'host_sync/etc/a.conf'), '/etc/b.conf']]);
+  });
+
+  it('reads a commit range for SHA arguments', async () => {
+    const { git, calls } = fakeGit('M\thomey_core/dist/a.js\n');
+    const { host, pushed } = fakeHost();
+    await new GitController(host, WS, git).push('a1b2c3d');
+
+    expect(calls).toEqual([['diff', '--name-status', '--no-renames', 'a1b2c3d..HEAD']]);
+    expect(pushed).toEqual([[path.join(WS, 'homey_core/dist/a.js'), '/vol/core/dist/a.js']]);
+  });
+});
+
+describe('GitController push deletions', () => {
+  const DEL = 'M\thomey_pro/app.js\nD\thomey_pro/gone.js\nD\thost_sync/etc/old.conf\n';
+
+  it('removes deleted files on the device after confirmation, never via hostPath', async () => {
+    const { git } = fakeGit(DEL);
+    const { host, pushed, removed } = fakeHost();
+    const confirmDelete = jest.fn(async () => true);
+    const ctl = new GitController(host, WS, git);
+    await ctl.push('a1b2c3d', { hostPath: '/opt/pro', confirmDelete });
+
+    expect(confirmDelete).toHaveBeenCalledWith(['/etc/old.conf', '/vol/pro/gone.js']);
+    expect(pushed).toEqual([[path.join(WS, 'homey_pro/app.js'), '/opt/pro/app.js']]);
+    expect(removed).toEqual(['/etc/old.conf', '/vol/pro/gone.js']);
+  });
+
+  it('keeps files that exist locally again even if an older commit deleted them', async () => {
+    const ws = prepareUniqueOutDir('push_restored');
+    try {
+      fs.mkdirSync(path.join(ws, 'homey_pro'), { recursive: true });
+      fs.writeFileSync(path.join(ws, 'homey_pro', 'gone.js'), '// pulled back\n');
+      const { git } = fakeGit(DEL);
+      const { host, removed } = fakeHost();
+      const confirmDelete = jest.fn(async () => true);
+      await new GitController(host, ws, git).push('a1b2c3d', { confirmDelete });
+
+      expect(confirmDelete).toHaveBeenCalledWith(['/etc/old.conf']);
+      expect(removed).toEqual(['/etc/old.conf']);
+    } finally {
+      cleanDir(ws);
+    }
+  });
+
+  it('uploads only when deletion is declined or no confirmation is given', async () => {
+    for (const confirmDelete of [async () => false, undefined]) {
+      const { git } = fakeGit(DEL);
+      const { host, pushed, removed } = fakeHost();
+      await new GitController(host, WS, git).push('a1b2c3d', { confirmDelete });
+      expect(pushed).toHaveLength(1);
+      expect(removed).toEqual([]);
+    }
+  });
+
+  it('lists uploads and deletions in a dry run without touching the device', async () => {
+    const { git } = fakeGit(DEL);
+    const { host, pushed, removed } = fakeHost();
+    const confirmDelete = jest.fn(async () => true);
+    const ctl = new GitController(host, WS, git);
+    const changes = await ctl.getChangesSince('a1b2c3d');
+    const plan = await ctl.pushFilesByCategory(changes, { dryRun: true, confirmDelete });
+
+    expect(plan.map((p) => [p.deleted ? 'D' : 'U', p.remote])).toEqual([
+      ['D', '/etc/old.conf'],
+      ['U', '/vol/pro/app.js'],
+      ['D', '/vol/pro/gone.js'],
+    ]);
+    expect(confirmDelete).not.toHaveBeenCalled();
+    expect(pushed).toEqual([]);
+    expect(removed).toEqual([]);
+  });
+});
@@ -0,0 +1,56 @@
+// src/__test__/HomeyLayout.test.ts
+
+import { mergeHomeyLayout } from '../core/config/homeyLayout.js';
+import type { HomeyLayoutOverride } from '../core/config/userconfig.js';
+import { DEFAULT_HOMEY_LAYOUT } from '../shared/const.js';
+
+describe('mergeHomeyLayout', () => {
+  it('returns a copy of the defaults without overrides', () => {
+    const out = mergeHomeyLayout(DEFAULT_HOMEY_LAYOUT, undefined);
+    expect(out).toEqual(DEFAULT_HOMEY_LAYOUT);
+    out.local_dirs.pro = 'changed';
+    expect(DEFAULT_HOMEY_LAYOUT.local_dirs.pro).toBe('homey_pro');
+  });
+
+  it('applies partial overrides per category, later ones winning', () => {
+    const global: HomeyLayoutOverride = {
+      docker_root_fallback: '/data/docker',
+      volumes: { core: { volume: 'node2', subpath: 'core/dist' } },
+      local_dirs: { sdk: 'sdk_v3' },
+    };
+    const perConn: HomeyLayoutOverride = {
+      paths: { pro: '/opt/homey-app' },
+      local_dirs: { sdk: 'sdk_dev' },
+    };
+    const out = mergeHomeyLayout(DEFAULT_HOMEY_LAYOUT, global, perConn);
+    expect(out.docker_root_fallback).toBe('/data/docker');
+    expect(out.volumes.core).toEqual({ volume: 'node2', subpath: 'core/dist' });
+    expect(out.volumes.pro).toEqual(DEFAULT_HOMEY_LAYOUT.volumes.pro);
+    expect(out.paths).toEqual({ pro: '/opt/homey-app' });
+    expect(out.local_dirs).toEqual({ ...DEFAULT_HOMEY_LAYOUT.local_dirs, sdk: 'sdk_dev' });
+  });
+
+  it('ignores local_dirs that would leave the workspace folder', () => {
+    const out = mergeHomeyLayout(DEFAULT_HOMEY_LAYOUT, {
+      local_dirs: {
+        pro: '../outside',
+        core: '/abs/core',
+        sdk: '  ',
+        bridge: 'bridge_ok',
+        other: 'x',
+      } as HomeyLayoutOverride['local_dirs'],
+    });
+    expect(out.local_dirs).toEqual({ ...DEFAULT_HOMEY_LAYOUT.local_dirs, bridge: 'bridge_ok' });
+    expect(out.local_dirs).not.toHaveProperty('other');
+  });
+
+  it('ignores non-string local_dirs values and an empty docker root', () => {
+    const bad = { pro: 42, core: null, sdk: '..' } as unknown as HomeyLayoutOverride['local_dirs'];
+    const out = mergeHomeyLayout(DEFAULT_HOMEY_LAYOUT, {
+      docker_root_fallback: '',
+      local_dirs: bad,
+    });
+    expect(out.docker_root_fallback).toBe(DEFAULT_HOMEY_LAYOUT.docker_root_fallback);
+    expect(out.local_dirs).toEqual(DEFAULT_HOMEY_LAYOUT.local_dirs);
+  });
+});
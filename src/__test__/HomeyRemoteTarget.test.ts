@@ -0,0 +1,27 @@
+// src/__test__/HomeyRemoteTarget.test.ts
+
+import { homeyRemoteTarget } from '../core/controller/GitController.js';
+
+describe('homeyRemoteTarget', () => {
+  it('maps subpaths under the local category dir onto the default base', () => {
+    expect(
+      homeyRemoteTarget('/ws/homey_pro/lib/app.js', 'homey_pro', '/var/lib/docker/volumes/x/_data'),
+    ).toBe('/var/lib/docker/volumes/x/_data/lib/app.js');
+  });
+
+  it('keeps the same subpath mapping when the remote base is overridden', () => {
+    expect(homeyRemoteTarget('/ws/homey_pro/lib/app.js', 'homey_pro', '/tmp/pro/')).toBe(
+      '/tmp/pro/lib/app.js',
+    );
+  });
+
+  it('normalizes Windows separators', () => {
+    expect(homeyRemoteTarget('C:\\ws\\homey_core\\a\\b.json', 'homey_core', '/tmp/core')).toBe(
+      '/tmp/core/a/b.json',
+    );
+  });
+
+  it('falls back to the file name outside the category dir', () => {
+    expect(homeyRemoteTarget('/ws/other/c.txt', 'homey_sdk', '/tmp/sdk')).toBe('/tmp/sdk/c.txt');
+  });
+});
@@ -0,0 +1,35 @@
+// src/__test__/HybridLogBuffer.test.ts
+
+import type { LogEntry } from '@ipc/messages';
+
+import { HybridLogBuffer } from '../core/logs/HybridLogBuffer.js';
+import { REALTIME_BUFFER_MAX } from '../shared/const.js';
+
+function entry(i: number): LogEntry {
+  return { id: i, ts: i, level: 'I', type: 'system', source: 'stress', text: `line ${i}` };
+}
+
+describe('HybridLogBuffer', () => {
+  it('stays bounded at capacity under a 100k burst and keeps the newest entries', () => {
+    const hb = new HybridLogBuffer();
+    const N = 100_000;
+    for (let i = 0; i < N / 2; i++) hb.add(entry(i));
+    const batch: LogEntry[] = [];
+    for (let i = N / 2; i < N; i++) batch.push(entry(i));
+    hb.addBatch(batch);
+
+    expect(hb.getMetrics().realtime).toBe(REALTIME_BUFFER_MAX);
+    const snap = hb.snapshot(REALTIME_BUFFER_MAX + 10);
+    expect(snap).toHaveLength(REALTIME_BUFFER_MAX);
+    expect(snap[0].id).toBe(N - REALTIME_BUFFER_MAX);
+    expect(snap[snap.length - 1].id).toBe(N - 1);
+  });
+
+  it('returns the newest entries in insertion order after wrap-around', () => {
+    const hb = new HybridLogBuffer(3);
+    [1, 2, 3, 4, 5].forEach((i) => hb.add(entry(i)));
+    expect(hb.snapshot(2).map((e) => e.id)).toEqual([4, 5]);
+    hb.clear();
+    expect(hb.snapshot()).toEqual([]);
+  });
+});
@@ -0,0 +1,59 @@
+// src/__test__/JsonLogLine.test.ts
+
+import { lineToEntryWithParser, parseJsonLogLine } from '../core/logs/ParserEngine.js';
+
+const levelOf = (level: unknown) =>
+  parseJsonLogLine(JSON.stringify({ level, msg: 'hello' }))?.level;
+
+describe('parseJsonLogLine', () => {
+  it('extracts time/level/process/pid and flattens extra fields', () => {
+    const r = parseJsonLogLine(
+      '{"time":1735725600,"level":"warn","name":"app","pid":42,' +
+        '"msg":"disk low","free":12,"ctx":{"dev":"sda"}}',
+    );
+    expect(r).toEqual({
+      ts: Date.UTC(2025, 0, 1, 10, 0, 0),
+      time: '1735725600',
+      level: 'W',
+      process: 'app',
+      pid: '42',
+      message: 'disk low free=12 ctx={"dev":"sda"}',
+    });
+  });
+
+  it('maps level names by whole word only', () => {
+    expect(levelOf('ERROR')).toBe('E');
+    expect(levelOf('fatal')).toBe('E');
+    expect(levelOf('warning')).toBe('W');
+    expect(levelOf('information')).toBe('I');
+    expect(levelOf('notice')).toBe('I');
+    expect(levelOf('trace')).toBe('D');
+    expect(levelOf('errorless')).toBeUndefined();
+    expect(levelOf('warned')).toBeUndefined();
+    expect(levelOf('infinite')).toBeUndefined();
+  });
+
+  it('maps pino/bunyan numeric levels', () => {
+    expect([10, 20, 30, 40, 50, 60].map(levelOf)).toEqual(['D', 'D', 'I', 'W', 'E', 'E']);
+  });
+
+  it('rejects malformed or non-log JSON', () => {
+    expect(parseJsonLogLine('{"level":"info","msg":"cut off"')).toBeUndefined();
+    expect(parseJsonLogLine('{level: info, msg: hi}')).toBeUndefined();
+    expect(parseJsonLogLine('[{"level":"info","msg":"hi"}]')).toBeUndefined();
+    expect(parseJsonLogLine('{"msg":"no time or level"}')).toBeUndefined();
+    expect(parseJsonLogLine('{"level":"info"}')).toBeUndefined();
+    expect(parseJsonLogLine('plain text line')).toBeUndefined();
+  });
+});
+
+describe('lineToEntryWithParser (JSON)', () => {
+  it('keeps the raw line as text and carries the message in parsed', () => {
+    const line = '{"time":1735725600,"level":"error","name":"app","msg":"boom"}';
+    const e = lineToEntryWithParser('/logs/app.log', line);
+    expect(e.text).toBe(line);
+    expect(e.level).toBe('E');
+    expect(e.ts).toBe(Date.UTC(2025, 0, 1, 10, 0, 0));
+    expect(e.parsed).toEqual({ time: '1735725600', process: 'app', pid: null, message: 'boom' });
+  });
+});
@@ -0,0 +1,54 @@
+// src/__test__/LevelGuess.test.ts
+
+import { compileParserConfig, lineToEntryWithParser } from '../core/logs/ParserEngine.js';
+import { guessLevel } from '../core/logs/time/TimeParser.js';
+
+const HEADER = '[Jan 02 10:00:00.123] homey-app[42]: ';
+
+const parser = (level_from_message?: boolean) =>
+  compileParserConfig({
+    configure: { level_from_message },
+    parser: [
+      {
+        file: 'homey-pro.log.{n}',
+        regex: {
+          time: '^\\[(?<time>[A-Z][a-z]{2}\\s+\\d{1,2}\\s+\\d{2}:\\d{2}:\\d{2}(?:\\.\\d+)?)\\]',
+          message: '^\\[[^\\]]+\\]\\s+[\\w.-]+(?:\\[\\d+\\])?:\\s+(?<message>.+)$',
+        },
+      },
+    ],
+  });
+
+describe('guessLevel', () => {
+  it.each([
+    ['Error: ENOENT /userdata/app.json', 'E'],
+    ['Unhandled exception in driver', 'E'],
+    ['[ManagerZigbee] panic: radio not responding', 'E'],
+    ['pairing failed after 3 attempts', 'E'],
+    ['Warning: deprecated API', 'W'],
+    ['[debug] tick', 'D'],
+    ['Flow started', 'I'],
+  ])('%s → %s', (line, level) => {
+    expect(guessLevel(line)).toBe(level);
+  });
+
+  it('ignores negated phrases, plurals and compound identifiers', () => {
+    expect(guessLevel('sync finished with no errors')).toBe('I');
+    expect(guessLevel('0 failures, 12 passed')).toBe('I');
+    expect(guessLevel('stats errors=0 warnings: 0')).toBe('I');
+    expect(guessLevel('piping stderr to file')).toBe('I');
+    expect(guessLevel('registered onError handler')).toBe('I');
+  });
+});
+
+describe('rule-parsed lines', () => {
+  it('takes the level from the message by default and can be turned off', () => {
+    const line = `${HEADER}Error: device unreachable`;
+    const on = lineToEntryWithParser('/logs/homey-pro.log.1', line, parser());
+    expect(on.level).toBe('E');
+    expect(on.text).toBe('Error: device unreachable');
+
+    const off = lineToEntryWithParser('/logs/homey-pro.log.1', line, parser(false));
+    expect(off.level).toBe('I');
+  });
+});
@@ -0,0 +1,65 @@
+// src/__test__/LogCapture.test.ts
+
+import type { LogEntry } from '@ipc/messages';
+import * as fs from 'fs';
+import * as path from 'path';
+
+import {
+  captureFormatOf,
+  formatCaptureLines,
+  LogCaptureWriter,
+  parseCaptureSize,
+} from '../core/logs/LogCaptureWriter.js';
+import { cleanDir, prepareUniqueOutDir } from './helpers/testFs.js';
+
+const entry = (i: number): LogEntry =>
+  ({ id: i, idx: i, ts: i, level: 'I', type: 'system', text: `line ${i}` }) as LogEntry;
+
+describe('log capture formatting', () => {
+  it('writes raw text or one JSON object per line', () => {
+    expect(formatCaptureLines([entry(1), entry(2)], 'raw')).toBe('line 1\nline 2\n');
+    const [json] = formatCaptureLines([entry(3)], 'jsonl').split('\n');
+    expect(JSON.parse(json)).toMatchObject({ idx: 3, level: 'I', text: 'line 3' });
+  });
+
+  it('infers the format from the extension and parses sizes', () => {
+    expect(captureFormatOf('/tmp/a.jsonl')).toBe('jsonl');
+    expect(captureFormatOf('/tmp/a.NDJSON')).toBe('jsonl');
+    expect(captureFormatOf('/tmp/a.jsonl', 'raw')).toBe('raw');
+    expect(captureFormatOf('/tmp/a.log')).toBe('raw');
+    expect(parseCaptureSize('10M')).toBe(10 * 1024 ** 2);
+    expect(parseCaptureSize('512kb')).toBe(512 * 1024);
+    expect(parseCaptureSize('4096')).toBe(4096);
+    expect(parseCaptureSize('ten')).toBeUndefined();
+  });
+});
+
+describe('LogCaptureWriter', () => {
+  let outDir: string;
+  beforeEach(() => {
+    outDir = prepareUniqueOutDir('log_capture');
+  });
+  afterEach(() => cleanDir(outDir));
+
+  it('appends batches in order and rotates past maxBytes keeping N old files', async () => {
+    const file = path.join(outDir, 'sub', 'live.log');
+    // 배치 = 두 줄(14~16 bytes) → 30 bytes 한도: [1-4] [5-8] [9-10] [11-12] 순으로 채워진다
+    const w = await LogCaptureWriter.open({ file, maxBytes: 30, keep: 2 });
+    for (let i = 1; i <= 12; i += 2) void w.append([entry(i), entry(i + 1)]);
+    await w.close();
+
+    const read = (f: string) => fs.readFileSync(f, 'utf8');
+    expect(read(file)).toBe('line 11\nline 12\n');
+    expect(read(`${file}.1`)).toBe('line 9\nline 10\n');
+    expect(read(`${file}.2`)).toBe('line 5\nline 6\nline 7\nline 8\n');
+    expect(fs.existsSync(`${file}.3`)).toBe(false);
+  });
+
+  it('continues an existing file instead of truncating it', async () => {
+    const file = path.join(outDir, 'keep.log');
+    fs.writeFileSync(file, 'old\n');
+    const w = await LogCaptureWriter.open({ file });
+    await w.append([entry(1)]);
+    expect(fs.readFileSync(file, 'utf8')).toBe('old\nline 1\n');
+  });
+});
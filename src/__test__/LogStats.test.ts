@@ -0,0 +1,34 @@
+// src/__test__/LogStats.test.ts
+
+import type { LogEntry } from '@ipc/messages';
+
+import { paginationService, toCount } from '../core/logs/PaginationService.js';
+
+describe('toCount', () => {
+  it('normalizes manifest/JSON values to non-negative integers', () => {
+    expect(toCount(12)).toBe(12);
+    expect(toCount('34')).toBe(34);
+    expect(toCount(5.9)).toBe(5);
+    expect(toCount(-1)).toBe(0);
+    expect(toCount(undefined)).toBe(0);
+    expect(toCount('abc')).toBe(0);
+  });
+});
+
+describe('paginationService.getStats', () => {
+  afterEach(() => {
+    paginationService.setSeverityFloor(null);
+    paginationService.clearWarmup();
+  });
+
+  it('reports warm buffer counts and the filtered total', async () => {
+    const logs = Array.from(
+      { length: 10 },
+      (_, i) => ({ id: i, ts: i, level: i < 3 ? 'E' : 'I', text: `l${i}` }) as LogEntry,
+    );
+    paginationService.seedWarmupBuffer(logs, logs.length);
+    expect(await paginationService.getStats()).toMatchObject({ total: 10, memory: 10, warm: true });
+    paginationService.setSeverityFloor('E');
+    expect((await paginationService.getStats()).total).toBe(3);
+  });
+});
@@ -0,0 +1,46 @@
+// src/__test__/LogcatParser.test.ts
+
+import { logcatLevel, parseLogcatLine } from '../core/logs/LogcatParser.js';
+
+const year = new Date().getFullYear();
+
+describe('parseLogcatLine', () => {
+  it('parses threadtime headers into ts/level/pid/tag', () => {
+    const r = parseLogcatLine(
+      '03-14 09:26:53.123  1234  1290 W ActivityManager: Slow operation: 52ms',
+    );
+    expect(r).toMatchObject({
+      ts: Date.UTC(year, 2, 14, 9, 26, 53, 123),
+      level: 'W',
+      pid: '1234',
+      tid: '1290',
+      process: 'ActivityManager',
+      message: 'Slow operation: 52ms',
+    });
+  });
+
+  it('keeps tags with spaces and colons in the message', () => {
+    const r = parseLogcatLine('03-14 09:26:53.123   812   812 E Homey App : crash: boom');
+    expect(r?.process).toBe('Homey App');
+    expect(r?.level).toBe('E');
+    expect(r?.message).toBe('crash: boom');
+  });
+
+  it('still accepts the legacy -v time layout', () => {
+    const r = parseLogcatLine('03-14 09:26:53.123 D/wpa_supplicant(  641): wlan0: CTRL-EVENT');
+    expect(r).toMatchObject({ level: 'D', pid: '641', process: 'wpa_supplicant' });
+    expect(r?.tid).toBeUndefined();
+  });
+
+  it('returns undefined for banners and non-logcat lines', () => {
+    expect(parseLogcatLine('--------- beginning of main')).toBeUndefined();
+    expect(parseLogcatLine('plain text')).toBeUndefined();
+  });
+});
+
+describe('logcatLevel', () => {
+  it('maps logcat priorities onto D/I/W/E', () => {
+    const levels = ['V', 'D', 'I', 'W', 'E', 'F', 'A'].map(logcatLevel);
+    expect(levels).toEqual(['D', 'D', 'I', 'W', 'E', 'E', 'E']);
+  });
+});
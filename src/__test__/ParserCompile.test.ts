@@ -0,0 +1,24 @@
+// src/__test__/ParserCompile.test.ts
+
+import { compileParserConfig, matchRuleForPath } from '../core/logs/ParserEngine.js';
+
+const MESSAGE = '^\\S+\\s+(?<message>.+)$';
+
+describe('compileParserConfig file filter', () => {
+  it('reports an invalid file regex and keeps the valid rules', () => {
+    const cp = compileParserConfig({
+      parser: [
+        { file: '^(broken', regex: { message: MESSAGE } },
+        { file: 'homey-pro.log.{x}', regex: { message: MESSAGE } },
+        { file: 'kernel.log.{n}', regex: { message: MESSAGE } },
+      ],
+    })!;
+
+    expect(cp.rules).toHaveLength(1);
+    expect(matchRuleForPath('/logs/kernel.log.2', cp)).toBe(cp.rules[0]);
+    expect(cp.failures).toEqual([
+      expect.objectContaining({ file: '^(broken', field: 'file' }),
+      expect.objectContaining({ file: 'homey-pro.log.{x}', field: 'file' }),
+    ]);
+  });
+});
@@ -0,0 +1,36 @@
+// src/__test__/ProcFilter.test.ts
+
+import type { LogEntry } from '@ipc/messages';
+
+import { PaginationService } from '../core/logs/PaginationService.js';
+
+const PROCS = ['net', 'netd', 'ethernet', 'kernel', 'bt_player'];
+
+const entry = (i: number): LogEntry =>
+  ({
+    id: i,
+    ts: i,
+    level: 'I',
+    type: 'system',
+    source: 't',
+    text: `[Jan 02 10:00:0${i}] ${PROCS[i]}[${100 + i}]: hello ${i}`,
+  }) as LogEntry;
+
+describe('paginationService process filter', () => {
+  const procsOf = async (pages: PaginationService) =>
+    (await pages.readRangeByIdx(1, PROCS.length)).map((e) => /\] (\w+)\[/.exec(e.text)?.[1]);
+
+  it('matches picked processes exactly and skips near-miss names', async () => {
+    const pages = new PaginationService();
+    const logs = PROCS.map((_, i) => entry(i)).reverse();
+    pages.seedWarmupBuffer(logs, logs.length);
+
+    pages.setFilter({ proc: '=net, =kernel' });
+    expect(await pages.getFilteredTotal()).toBe(2);
+    expect((await procsOf(pages)).sort()).toEqual(['kernel', 'net']);
+
+    // 직접 입력한 토큰은 지금처럼 부분 포함
+    pages.setFilter({ proc: 'net' });
+    expect((await procsOf(pages)).sort()).toEqual(['ethernet', 'net', 'netd']);
+  });
+});
@@ -0,0 +1,68 @@
+// src/__test__/PullSelect.test.ts
+
+import { compilePathSelect, expandRemotePaths } from '../core/transfer/FileTransferService.js';
+
+const FILES = [
+  'app.json',
+  'lib/app.js',
+  'lib/util/fmt.js',
+  'lib/app.js.map',
+  'node_modules/x/index.js',
+  'src/lib/inner.js',
+];
+
+const pick = (include?: string[], exclude?: string[]) =>
+  FILES.filter(compilePathSelect({ include, exclude })!);
+
+describe('compilePathSelect', () => {
+  it('passes everything through when no pattern is given', () => {
+    expect(compilePathSelect()).toBeUndefined();
+    expect(compilePathSelect({ include: [], exclude: [' '] })).toBeUndefined();
+  });
+
+  it('matches bare names at any depth and anchors patterns with a slash', () => {
+    expect(pick(['lib'])).toEqual([
+      'lib/app.js',
+      'lib/util/fmt.js',
+      'lib/app.js.map',
+      'src/lib/inner.js',
+    ]);
+    expect(pick(['/lib'])).toEqual(['lib/app.js', 'lib/util/fmt.js', 'lib/app.js.map']);
+    expect(pick(['lib/*.js'])).toEqual(['lib/app.js']);
+  });
+
+  it('lets ** span directories and exclude win over include', () => {
+    expect(pick(['**/*.js'], ['node_modules'])).toEqual([
+      'lib/app.js',
+      'lib/util/fmt.js',
+      'src/lib/inner.js',
+    ]);
+    expect(pick(['lib', 'app.json'], ['*.map', 'src/**'])).toEqual([
+      'app.json',
+      'lib/app.js',
+      'lib/util/fmt.js',
+    ]);
+    expect(pick(undefined, ['lib'])).toEqual(['app.json', 'node_modules/x/index.js']);
+  });
+});
+
+describe('expandRemotePaths', () => {
+  it('expands directory entries with the raw (unquoted) remote path', async () => {
+    const listed: string[] = [];
+    const kinds: Record<string, string> = {
+      '/app/my dir': 'DIR',
+      '/app/app.json': 'FILE',
+    };
+    const out = await expandRemotePaths(
+      '/app/',
+      ['my dir', 'app.json', 'gone'],
+      async (abs) => kinds[abs] ?? 'NONE',
+      async (abs) => {
+        listed.push(abs);
+        return ['a.js', 'sub/b.js'];
+      },
+    );
+    expect(listed).toEqual(['/app/my dir']);
+    expect(out).toEqual(['my dir/a.js', 'my dir/sub/b.js', 'app.json']);
+  });
+});
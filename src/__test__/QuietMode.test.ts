@@ -0,0 +1,41 @@
+// src/__test__/QuietMode.test.ts
+
+import { isQuietMode, runQuiet, setQuietMode, stripQuietFlag } from '../core/logging/quiet-mode.js';
+
+describe('stripQuietFlag', () => {
+  it('removes only a leading --quiet token', () => {
+    expect(stripQuietFlag('--quiet pull --deadline 5m')).toEqual({
+      args: 'pull --deadline 5m',
+      quiet: true,
+    });
+    expect(stripQuietFlag('--quiet')).toEqual({ args: '', quiet: true });
+    expect(stripQuietFlag('grep --quiet x')).toEqual({ args: 'grep --quiet x', quiet: false });
+    expect(stripQuietFlag('--quieter')).toEqual({ args: '--quieter', quiet: false });
+  });
+});
+
+describe('quiet scope', () => {
+  afterEach(() => setQuietMode(false));
+
+  it('is active only while a --quiet command runs, even when it throws', async () => {
+    setQuietMode(false);
+    let inside = false;
+    await runQuiet(async () => {
+      inside = isQuietMode();
+    });
+    expect(inside).toBe(true);
+    expect(isQuietMode()).toBe(false);
+    await expect(
+      runQuiet(async () => {
+        throw new Error('boom');
+      }),
+    ).rejects.toThrow('boom');
+    expect(isQuietMode()).toBe(false);
+  });
+
+  it('stays on when toggled globally', async () => {
+    setQuietMode(true);
+    await runQuiet(async () => undefined);
+    expect(isQuietMode()).toBe(true);
+  });
+});
@@ -0,0 +1,91 @@
+// src/__test__/RealtimeSource.test.ts
+
+import {
+  composeSourceChain,
+  isJournalTime,
+  journalctlSource,
+  journalWindowError,
+  matchesLineFilter,
+  parseLineFilter,
+  readSourceMark,
+  remoteFileTestCommand,
+  SOURCE_MARK_PREFIX,
+  tailFileSource,
+} from '../core/logs/RealtimeSource.js';
+
+describe('composeSourceChain', () => {
+  it('prints a marker before each fallback source', () => {
+    const cmd = composeSourceChain([
+      { name: 'journalctl', cmd: 'journalctl -f' },
+      { name: 'docker', cmd: 'docker logs -f homey' },
+    ]);
+    expect(cmd).toBe(
+      `sh -lc '{ echo ${SOURCE_MARK_PREFIX}journalctl; journalctl -f; } || ` +
+        `{ echo ${SOURCE_MARK_PREFIX}docker; docker logs -f homey; }'`,
+    );
+  });
+});
+
+describe('readSourceMark', () => {
+  it('extracts the source name only from marker lines', () => {
+    expect(readSourceMark(`${SOURCE_MARK_PREFIX}docker`)).toBe('docker');
+    expect(readSourceMark('Mar 14 09:26:53 homey[12]: __edgetool_src__ x')).toBeUndefined();
+    expect(readSourceMark(SOURCE_MARK_PREFIX)).toBeUndefined();
+  });
+});
+
+describe('tailFileSource', () => {
+  it('follows one quoted remote file and is labelled by its basename', () => {
+    expect(tailFileSource("/var/log/it's.log", 200)).toEqual({
+      name: "it's.log",
+      cmd: `tail -n 200 -f '/var/log/it'\\''s.log'`,
+    });
+    expect(tailFileSource('/tmp/app.log').cmd).toBe(`tail -n 0 -f '/tmp/app.log'`);
+    expect(remoteFileTestCommand('/tmp/a b')).toBe(`test -f '/tmp/a b'`);
+  });
+});
+
+describe('journalctlSource time window', () => {
+  it('keeps the follow-from-now command when no window is given', () => {
+    expect(journalctlSource(50).cmd).toBe(
+      `journalctl -f -o short-iso -n 50 -u "homey*" 2>/dev/null`,
+    );
+  });
+
+  it('reads a bounded window, or follows from since when until is absent', () => {
+    expect(journalctlSource(50, { since: '2025-03-01 10:00', until: 'now' }).cmd).toBe(
+      `journalctl --since='2025-03-01 10:00' --until='now' -o short-iso -u "homey*" 2>/dev/null`,
+    );
+    expect(journalctlSource(0, { since: '-1h' }).cmd).toBe(
+      `journalctl --since='-1h' -f -o short-iso -u "homey*" 2>/dev/null`,
+    );
+  });
+
+  it('accepts journalctl time expressions and rejects anything else', () => {
+    const good = ['today', '2025-03-01', '2025-03-01T10:00:05', '09:30', '-2h', '1h 30min ago'];
+    for (const t of good) expect(isJournalTime(t)).toBe(true);
+    for (const t of ['2025-02-30', '25:00', 'last tuesday', "now'; reboot #", '']) {
+      expect(isJournalTime(t)).toBe(false);
+    }
+    expect(journalWindowError({ since: '2025-03-02', until: '2025-03-01 23:00' })).toMatch(/늦습니다/);
+    expect(journalWindowError({ since: 'yesterday', until: '2025-03-01' })).toBeUndefined();
+    expect(journalWindowError({ until: 'soon' })).toMatch(/--until/);
+  });
+});
+
+describe('parseLineFilter / matchesLineFilter', () => {
+  it('ANDs include terms, drops any exclude term, ignores case', () => {
+    const f = parseLineFilter('wifi !heartbeat "scan DONE"');
+    expect(f).toEqual({ include: ['wifi', 'scan done'], exclude: ['heartbeat'] });
+    expect(matchesLineFilter('WiFi: scan done (5 APs)', f)).toBe(true);
+    expect(matchesLineFilter('wifi: scan started', f)).toBe(false);
+    expect(matchesLineFilter('wifi heartbeat scan done', f)).toBe(false);
+  });
+
+  it('treats shell metacharacters as plain text and empty input as no filter', () => {
+    const f = parseLineFilter(`'; rm -rf / #`);
+    expect(matchesLineFilter(`x '; rm -rf / # y`, f)).toBe(true);
+    expect(parseLineFilter('  ')).toBeUndefined();
+    expect(matchesLineFilter('anything', parseLineFilter(''))).toBe(true);
+  });
+});
@@ -0,0 +1,33 @@
+// src/__test__/Redactor.test.ts
+
+import {
+  clearSecrets,
+  redact,
+  REDACTED,
+  registerSecret,
+  unregisterSecret,
+} from '../core/logging/redactor.js';
+
+afterEach(() => clearSecrets());
+
+describe('redact', () => {
+  it('masks a registered password inside a debug-logged ssh command', () => {
+    registerSecret('s3cr3t!pw');
+    const line = `[trace] ssh root@192.168.0.10 -p 22 "echo s3cr3t!pw | sudo -S ls /data"`;
+    const out = redact(line);
+    expect(out).not.toContain('s3cr3t!pw');
+    expect(out).toContain(REDACTED);
+  });
+
+  it('masks known key/value patterns without registration', () => {
+    expect(redact('sshpass -p hunter2 ssh root@host')).toBe(`sshpass -p ${REDACTED} ssh root@host`);
+    expect(redact('{"host":"h","password":"abc"}')).toBe(`{"host":"h","password":"${REDACTED}"}`);
+    expect(redact('export ALLOW_DEVTOKEN=1234abcd')).toBe(`export ALLOW_DEVTOKEN=${REDACTED}`);
+  });
+
+  it('stops masking after the secret is unregistered', () => {
+    registerSecret('topsecret');
+    unregisterSecret('topsecret');
+    expect(redact('echo topsecret')).toBe('echo topsecret');
+  });
+});
@@ -0,0 +1,80 @@
+// src/__test__/RemoteBrowse.test.ts
+
+import type { IConnectionManager } from '../core/connection/ConnectionManager.js';
+import {
+  HostController,
+  isPseudoFsPath,
+  parseLsLong,
+} from '../core/controller/HostController.js';
+import { ErrorCategory } from '../shared/errors.js';
+
+const BUSYBOX = [
+  'total 24',
+  'drwxr-xr-x    5 root     root          4096 Jan  1 00:00 .',
+  'drwxr-xr-x   18 root     root          4096 Jan  1  2024 ..',
+  '-rw-r--r--    1 root     root         12345 Mar 10 12:34 config.json',
+  'lrwxrwxrwx    1 root     root            11 Mar 10 12:34 data -> /lg_rw/data',
+  'drwxr-xr-x    2 root     root          4096 Mar 10 12:34 my dir',
+  'crw-rw-rw-    1 root     root        1,   3 Jan  1 00:00 null',
+].join('\n');
+
+/** 실행한 명령을 기록하고 정해 둔 결과를 돌려주는 연결 대역 */
+function fakeCm(type: 'SSH' | 'ADB', code: number, stdout: string, stderr = '') {
+  const calls: string[] = [];
+  const cm = {
+    getSnapshot: () => ({ active: { type } }),
+    run: async (cmd: string) => (calls.push(cmd), { code, stdout, stderr }),
+  };
+  return { cm: cm as unknown as IConnectionManager, calls };
+}
+
+describe('parseLsLong', () => {
+  it('reads sizes and types, lists directories first and drops . and ..', () => {
+    expect(parseLsLong(BUSYBOX)).toEqual([
+      { name: 'my dir', type: 'dir', size: 4096, target: undefined },
+      { name: 'config.json', type: 'file', size: 12345, target: undefined },
+      { name: 'data', type: 'link', size: 11, target: '/lg_rw/data' },
+      { name: 'null', type: 'other', size: 0, target: undefined },
+    ]);
+  });
+
+  it('accepts the ISO dates printed by Android toybox', () => {
+    const out =
+      'drwxrwx--x 4 system system 3452 2024-01-01 00:00 app\n' +
+      '-rw-r--r-- 1 root root 10 2024-05-01 10:00 a -> b.txt\n';
+    expect(parseLsLong(out).map((e) => [e.type, e.name])).toEqual([
+      ['dir', 'app'],
+      ['file', 'a -> b.txt'],
+    ]);
+  });
+});
+
+describe('HostController.listDir', () => {
+  it('lists through the link with a trailing slash on both transports', async () => {
+    for (const type of ['SSH', 'ADB'] as const) {
+      const { cm, calls } = fakeCm(type, 0, BUSYBOX);
+      const entries = await new HostController(cm, '/ws').listDir('/etc');
+      expect(entries).toHaveLength(4);
+      expect(calls[0]).toMatch(/ls -la \S*\/etc\/'/);
+      expect(calls[0].startsWith('sh -lc')).toBe(type === 'SSH');
+    }
+  });
+
+  it('fails with a remote command error when the directory cannot be read', async () => {
+    const { cm } = fakeCm('SSH', 2, '', 'ls: /root: Permission denied');
+    await expect(new HostController(cm, '/ws').listDir('/root')).rejects.toMatchObject({
+      category: ErrorCategory.RemoteCommand,
+    });
+  });
+});
+
+describe('isPseudoFsPath', () => {
+  it('matches /proc, /sys, /dev and anything below them only', () => {
+    for (const p of ['/proc', '/proc/1/maps', '/sys/', '/dev/null', '//dev']) {
+      expect(isPseudoFsPath(p)).toBe(true);
+    }
+    for (const p of ['/', '/devices', '/data/proc', '/system']) {
+      expect(isPseudoFsPath(p)).toBe(false);
+    }
+  });
+});
@@ -0,0 +1,87 @@
+// src/__test__/RepeatCoalescer.test.ts
+
+import type { LogEntry } from '@ipc/messages';
+
+import { RepeatCoalescer, repeatKey, repeatSummaryText } from '../core/logs/RepeatCoalescer.js';
+
+const entry = (text: string, extra: Partial<LogEntry> = {}): LogEntry => ({
+  id: 0,
+  ts: 0,
+  level: 'I',
+  file: 'journalctl',
+  text,
+  ...extra,
+});
+
+describe('repeatKey', () => {
+  it('ignores a leading timestamp so identical messages match', () => {
+    const a = repeatKey(entry('2024-05-01T10:00:00+0000 homey node[1]: retry failed'));
+    const b = repeatKey(entry('2024-05-01T10:00:01+0000 homey node[1]: retry failed'));
+    expect(a).toBe(b);
+    expect(repeatKey(entry('May  1 10:00:00 homey node[1]: x'))).toBe('homey node[1]: x');
+  });
+
+  it('uses level/tag/pid/message for parsed logcat lines', () => {
+    const parsed = (msg: string) => ({ time: '', process: 'Wifi', pid: '7', message: msg });
+    const a = entry('05-01 10:00:00.000 7 7 E Wifi: scan', { process: 'Wifi', pid: '7' });
+    a.parsed = parsed('scan');
+    const b = { ...a, text: '05-01 10:00:09.999 7 8 E Wifi: scan', parsed: parsed('scan') };
+    const c = { ...a, level: 'W' as const, parsed: parsed('scan') };
+    expect(repeatKey(a)).toBe(repeatKey(b));
+    expect(repeatKey(a)).not.toBe(repeatKey(c));
+  });
+});
+
+describe('RepeatCoalescer', () => {
+  it('absorbs consecutive duplicates and summarizes when the run ends', () => {
+    const c = new RepeatCoalescer();
+    expect(c.offer(entry('boom'))).toEqual({ absorbed: false, summary: undefined });
+    expect(c.offer(entry('boom')).absorbed).toBe(true);
+    expect(c.offer(entry('boom')).absorbed).toBe(true);
+    const next = c.offer(entry('other', { level: 'W' }));
+    expect(next.absorbed).toBe(false);
+    expect(next.summary).toMatchObject({
+      text: repeatSummaryText(2),
+      repeat: 2,
+      level: 'I',
+      file: 'journalctl',
+    });
+    // 반복 없이 끝나면 요약 없음
+    expect(c.end()).toBeUndefined();
+  });
+
+  it('does not merge non-consecutive duplicates', () => {
+    const c = new RepeatCoalescer();
+    c.offer(entry('a'));
+    c.offer(entry('b'));
+    expect(c.offer(entry('a')).absorbed).toBe(false);
+  });
+
+  it('annotates the flushed head row with the live count', () => {
+    const c = new RepeatCoalescer();
+    const head = entry('spam');
+    c.offer(entry('before'));
+    c.offer(head);
+    c.noteFlushed([entry('before'), head], 10); // head → idx 12
+    expect(c.pendingRepeat).toBe(false);
+    c.offer(entry('spam'));
+    c.offer(entry('spam'));
+    expect(c.pendingRepeat).toBe(true);
+
+    const page: LogEntry[] = [
+      { ...entry('before'), idx: 11 },
+      { ...entry('spam'), idx: 12 },
+    ];
+    const out = c.annotate(page);
+    expect(out[0].repeat).toBeUndefined();
+    expect(out[1].repeat).toBe(2);
+    expect(page[1].repeat).toBeUndefined();
+
+    expect(c.end()?.text).toBe('last message repeated 2 times');
+    expect(c.annotate(page)).toBe(page);
+  });
+
+  it('uses singular wording for a single repeat', () => {
+    expect(repeatSummaryText(1)).toBe('last message repeated 1 time');
+  });
+});
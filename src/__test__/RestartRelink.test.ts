@@ -0,0 +1,46 @@
+// src/__test__/RestartRelink.test.ts
+
+import type { RunResult, ShellExecutor } from '../core/connection/ConnectionManager.js';
+import { RestartTaskRunner } from '../core/tasks/RestartTaskRunner.js';
+import { ErrorCategory } from '../shared/errors.js';
+
+const ok = (stdout = ''): RunResult => ({ code: 0, stdout, stderr: '' });
+
+function fakeShell() {
+  const calls: string[] = [];
+  const sh: ShellExecutor = {
+    run: async (cmd: string) => {
+      calls.push(cmd);
+      if (cmd.includes('ActiveState')) return ok('ActiveState=active\nSubState=running\n');
+      return ok();
+    },
+  };
+  return { sh, calls };
+}
+
+describe('RestartTaskRunner relink after restart', () => {
+  it('waits for the link between the restart and the unit polling', async () => {
+    const { sh, calls } = fakeShell();
+    const order: string[] = [];
+    const relink = jest.fn(async () => {
+      order.push(calls.some((c) => c.includes('ActiveState')) ? 'after-poll' : 'before-poll');
+      return true;
+    });
+    const runner = new RestartTaskRunner({ sh, unit: 'homey-pro@test.service', relink });
+
+    await runner.run();
+
+    expect(relink).toHaveBeenCalledTimes(1);
+    expect(calls.some((c) => c.includes('systemctl restart'))).toBe(true);
+    expect(order).toEqual(['before-poll']);
+  });
+
+  it('fails with a connection error when the device does not come back', async () => {
+    const { sh, calls } = fakeShell();
+    const relink = jest.fn(async () => false);
+    const runner = new RestartTaskRunner({ sh, unit: 'homey-pro@test.service', relink });
+
+    await expect(runner.run()).rejects.toMatchObject({ category: ErrorCategory.Connection });
+    expect(calls.some((c) => c.includes('ActiveState'))).toBe(false);
+  });
+});
@@ -0,0 +1,111 @@
+// src/__test__/SearchPaging.test.ts
+
+import type { LogEntry } from '@ipc/messages';
+
+import {
+  buildSearchMatcher,
+  buildSearchRanger,
+  paginationService,
+} from '../core/logs/PaginationService.js';
+
+const entry = (i: number): LogEntry =>
+  ({
+    id: i,
+    ts: i,
+    level: 'I',
+    type: 'system',
+    source: 't',
+    text: i % 2 ? `odd ${i}` : `even ${i}`,
+  }) as LogEntry;
+
+describe('paginationService.searchPage', () => {
+  beforeEach(() => {
+    // warm 버퍼는 최신→오래된(내림차순)
+    const logs = Array.from({ length: 500 }, (_, i) => entry(500 - i));
+    paginationService.seedWarmupBuffer(logs, logs.length);
+  });
+  afterEach(() => paginationService.clearWarmup());
+
+  it('pages through every match with a cursor', async () => {
+    const pages: number[] = [];
+    const got: number[] = [];
+    let cursor;
+    do {
+      const r = await paginationService.searchPage('even', { cursor, pageSize: 100 });
+      pages.push(r.hits.length);
+      got.push(...r.hits.map((h) => h.idx));
+      cursor = r.nextCursor;
+    } while (cursor);
+
+    expect(pages).toEqual([100, 100, 50]);
+    const all = await paginationService.searchAll('even');
+    expect(got).toEqual(all.map((h) => h.idx));
+    expect(new Set(got).size).toBe(250);
+  });
+
+  it('returns match ranges within each hit', async () => {
+    const [hit] = await paginationService.searchAll('EVEN', { top: 1 });
+    expect(hit.ranges).toEqual([[0, 4]]);
+  });
+
+  it('keeps searchAll top as a single capped page', async () => {
+    const hits = await paginationService.searchAll('odd', { top: 100 });
+    expect(hits).toHaveLength(100);
+  });
+});
+
+describe('buildSearchMatcher', () => {
+  it('ignores case by default', () => {
+    const m = buildSearchMatcher('Err');
+    expect(m('some error here')).toBe(true);
+  });
+
+  it('honours caseSensitive', () => {
+    const m = buildSearchMatcher('Err', { caseSensitive: true });
+    expect(m('some error here')).toBe(false);
+    expect(m('some Error here')).toBe(true);
+  });
+
+  it('matches wholeWord only on token boundaries', () => {
+    const m = buildSearchMatcher('id', { wholeWord: true });
+    expect(m('device id=3')).toBe(true);
+    expect(m('android pid=3')).toBe(false);
+    expect(m('(id)')).toBe(true);
+  });
+
+  it('escapes regex metacharacters for wholeWord', () => {
+    const m = buildSearchMatcher('a.b', { wholeWord: true });
+    expect(m('x a.b y')).toBe(true);
+    expect(m('x aXb y')).toBe(false);
+  });
+});
+
+describe('buildSearchRanger', () => {
+  it('returns every occurrence', () => {
+    expect(buildSearchRanger('ab')('ab xAB ab')).toEqual([
+      [0, 2],
+      [4, 6],
+      [7, 9],
+    ]);
+  });
+
+  it('follows the matcher options', () => {
+    const r = buildSearchRanger('id', { caseSensitive: true, wholeWord: true });
+    expect(r('pid id ID (id)')).toEqual([
+      [4, 6],
+      [11, 13],
+    ]);
+  });
+
+  it('returns captured spans for regex groups', () => {
+    const r = buildSearchRanger('pid=(\\d+)', { regex: true });
+    expect(r('a pid=42 b pid=7')).toEqual([
+      [6, 8],
+      [15, 16],
+    ]);
+  });
+
+  it('survives zero-length regex matches', () => {
+    expect(buildSearchRanger('x*', { regex: true })('abx')).toEqual([[2, 3]]);
+  });
+});
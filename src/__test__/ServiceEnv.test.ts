@@ -0,0 +1,71 @@
+// src/__test__/ServiceEnv.test.ts
+
+import {
+  parseServiceEnv,
+  serviceEnvKeyRegex,
+  serviceEnvLine,
+  validateServiceEnv,
+} from '../core/service/serviceEnv.js';
+import { XError } from '../shared/errors.js';
+
+const UNIT = [
+  '[Service]',
+  'ExecStart=/usr/bin/docker run --rm --name homey-pro \\',
+  ' --env="HOMEY_APP_LOG=1" \\',
+  "  --env='TZ=Europe/Amsterdam' \\",
+  '  --env=NODE_OPTIONS=--max-old-space-size=512 \\',
+  '  --volume="homey-app:/app:rw" \\',
+  '  --env="HOMEY_APP_LOG=2" \\',
+  '  homey/homey-pro:latest',
+].join('\n');
+
+describe('parseServiceEnv', () => {
+  it('reads double/single/unquoted --env= entries, last value wins', () => {
+    expect(parseServiceEnv(UNIT)).toEqual([
+      { key: 'HOMEY_APP_LOG', value: '2' },
+      { key: 'TZ', value: 'Europe/Amsterdam' },
+      { key: 'NODE_OPTIONS', value: '--max-old-space-size=512' },
+    ]);
+  });
+
+  it('returns an empty list when there are no env lines', () => {
+    expect(parseServiceEnv('[Service]\nExecStart=/bin/true\n')).toEqual([]);
+  });
+
+  it('round-trips the line it generates', () => {
+    const line = serviceEnvLine('FOO', 'a b=c');
+    expect(line).toBe('--env="FOO=a b=c"');
+    expect(parseServiceEnv(` ${line} \\`)).toEqual([{ key: 'FOO', value: 'a b=c' }]);
+  });
+});
+
+describe('serviceEnvKeyRegex', () => {
+  it('matches only the exact key regardless of quoting', () => {
+    const rx = new RegExp(serviceEnvKeyRegex('HOMEY_APP_LOG'));
+    expect(rx.test(' --env="HOMEY_APP_LOG=1" \\')).toBe(true);
+    expect(rx.test(" --env='HOMEY_APP_LOG=0'")).toBe(true);
+    expect(rx.test(' --env=HOMEY_APP_LOG=1')).toBe(true);
+    expect(rx.test(' --env="HOMEY_APP_LOG_LEVEL=3" \\')).toBe(false);
+    expect(rx.test(' --env="X_HOMEY_APP_LOG=1" \\')).toBe(false);
+  });
+});
+
+describe('validateServiceEnv', () => {
+  it('accepts shell-style names and plain values', () => {
+    expect(() => validateServiceEnv('HOMEY_DEV_TOKEN', '1')).not.toThrow();
+    expect(() => validateServiceEnv('_x9', undefined)).not.toThrow();
+  });
+
+  it.each(['', '9LIVES', 'A-B', 'A B', 'A;rm', '$(id)'])('rejects key %j', (key) => {
+    expect(() => validateServiceEnv(key, '1')).toThrow(XError);
+  });
+
+  it.each(['a"b', 'a\\b', 'a\nb'])('rejects value %j', (value) => {
+    expect(() => validateServiceEnv('KEY', value)).toThrow(XError);
+  });
+
+  // systemd 확장($VAR, %i 지정자)과 sed `a\` 삽입 텍스트를 깨는 문자
+  it.each(['$HOME', '${X}', '100%', '%i', 'a&b', '/data/x'])('rejects value %j', (value) => {
+    expect(() => validateServiceEnv('KEY', value)).toThrow(XError);
+  });
+});
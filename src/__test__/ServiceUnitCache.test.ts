@@ -0,0 +1,116 @@
+// src/__test__/ServiceUnitCache.test.ts
+
+import * as vscode from 'vscode';
+
+import type { ConnectionInfo } from '../core/config/connection-config.js';
+import { connectionManager } from '../core/connection/ConnectionManager.js';
+import {
+  detectHomeyUnit,
+  invalidateHomeyUnitCache,
+  resolveHomeyUnit,
+  setHomeyUnit,
+  watchConnectionForUnitCache,
+} from '../core/service/serviceDiscovery.js';
+
+function conn(id: string): ConnectionInfo {
+  return {
+    id,
+    type: 'SSH',
+    details: { host: id, port: 22, user: 'root' } as any,
+    lastUsed: new Date(0).toISOString(),
+  };
+}
+
+describe('Homey unit cache ↔ connection change', () => {
+  // 연결별로 기기에 존재하는 유닛(가짜 systemctl)
+  let deviceUnit = '';
+  let listUnitsCalls = 0;
+  let sub: { dispose(): void };
+
+  beforeEach(() => {
+    invalidateHomeyUnitCache('test');
+    listUnitsCalls = 0;
+    sub = watchConnectionForUnitCache();
+    jest.spyOn(connectionManager, 'run').mockImplementation(async (cmd: string) => {
+      if (cmd.includes('list-units')) {
+        listUnitsCalls++;
+        return { code: 0, stdout: `${deviceUnit}\n`, stderr: '' };
+      }
+      if (cmd.includes('FragmentPath')) {
+        const hit = deviceUnit.split('\n').find((u) => cmd.includes(u));
+        return { code: 0, stdout: hit ? `/lib/systemd/system/${hit}\n` : '\n', stderr: '' };
+      }
+      return { code: 0, stdout: '', stderr: '' };
+    });
+  });
+
+  afterEach(() => {
+    sub.dispose();
+    jest.restoreAllMocks();
+  });
+
+  it('reuses the cached unit while the connection stays the same', async () => {
+    connectionManager.setActive(conn('ssh:root@a:22'));
+    deviceUnit = 'homey-pro@a.service';
+    expect(await resolveHomeyUnit()).toBe('homey-pro@a.service');
+    expect(await resolveHomeyUnit()).toBe('homey-pro@a.service');
+    expect(listUnitsCalls).toBe(1);
+  });
+
+  it('re-detects the unit after switching connections', async () => {
+    connectionManager.setActive(conn('ssh:root@a:22'));
+    deviceUnit = 'homey-pro@a.service';
+    expect(await resolveHomeyUnit()).toBe('homey-pro@a.service');
+
+    const gen = connectionManager.getGeneration();
+    connectionManager.setActive(conn('ssh:root@b:22'));
+    deviceUnit = 'homey-bridge@b.service';
+    expect(connectionManager.getGeneration()).toBe(gen + 1);
+    expect(await resolveHomeyUnit()).toBe('homey-bridge@b.service');
+    expect(listUnitsCalls).toBe(2);
+  });
+
+  it('asks once for concurrent callers when several instances exist', async () => {
+    connectionManager.setActive(conn('ssh:root@g:22'));
+    deviceUnit = 'homey-pro@a.service\nhomey-pro@b.service';
+    const quickPick = jest.mocked(vscode.window.showQuickPick);
+    quickPick.mockResolvedValueOnce('homey-pro@b.service' as any);
+    const [x, y] = await Promise.all([resolveHomeyUnit(), resolveHomeyUnit()]);
+    expect([x, y]).toEqual(['homey-pro@b.service', 'homey-pro@b.service']);
+    expect(quickPick).toHaveBeenCalledTimes(1);
+    expect(await resolveHomeyUnit()).toBe('homey-pro@b.service');
+    quickPick.mockClear();
+  });
+
+  it('refuses to pick silently when several instances exist', async () => {
+    connectionManager.setActive(conn('ssh:root@e:22'));
+    deviceUnit = 'homey-pro@a.service\nhomey-pro@b.service\nhomey-pro@a.service';
+    // 선택 창을 닫으면(undefined) 임의로 고르지 않고 오류
+    await expect(resolveHomeyUnit()).rejects.toThrow(
+      'multiple homey units found: homey-pro@a.service, homey-pro@b.service',
+    );
+    const pick = jest.fn(async (units: string[]) => units[1]);
+    expect(await detectHomeyUnit(pick)).toBe('homey-pro@b.service');
+    expect(pick).toHaveBeenCalledWith(['homey-pro@a.service', 'homey-pro@b.service']);
+  });
+
+  it('caches a --service override so later operations use it', async () => {
+    connectionManager.setActive(conn('ssh:root@f:22'));
+    deviceUnit = 'homey-pro@a.service\nhomey-pro@b.service';
+    expect(await setHomeyUnit('homey-pro@b')).toBe('homey-pro@b.service');
+    expect(await resolveHomeyUnit()).toBe('homey-pro@b.service');
+    expect(listUnitsCalls).toBe(0);
+    await expect(setHomeyUnit('homey-pro@zz')).rejects.toThrow('unit not found on device');
+    await expect(setHomeyUnit('x; reboot')).rejects.toThrow('invalid unit name');
+  });
+
+  it('does not notify when the same connection is set again', () => {
+    const seen: (string | undefined)[] = [];
+    const d = connectionManager.onDidChangeConnection((next) => seen.push(next?.id));
+    connectionManager.setActive(conn('ssh:root@c:22'));
+    connectionManager.setActive(conn('ssh:root@c:22'));
+    d.dispose();
+    connectionManager.setActive(conn('ssh:root@d:22'));
+    expect(seen).toEqual(['ssh:root@c:22']);
+  });
+});
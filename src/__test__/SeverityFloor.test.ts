@@ -0,0 +1,61 @@
+// src/__test__/SeverityFloor.test.ts
+
+import type { LogEntry } from '@ipc/messages';
+
+import { PaginationService, paginationService } from '../core/logs/PaginationService.js';
+
+const LEVELS = ['D', 'I', 'W', 'E'] as const;
+
+const entry = (i: number): LogEntry =>
+  ({
+    id: i,
+    ts: i,
+    level: LEVELS[i % 4],
+    type: 'system',
+    source: 't',
+    text: `line ${i} ${i % 8 < 4 ? 'alpha' : 'beta'}`,
+  }) as LogEntry;
+
+describe('paginationService severity floor', () => {
+  beforeEach(() => {
+    const logs = Array.from({ length: 80 }, (_, i) => entry(80 - i));
+    paginationService.seedWarmupBuffer(logs, logs.length);
+  });
+  afterEach(() => {
+    paginationService.setSeverityFloor(null);
+    paginationService.setFilter(null);
+    paginationService.clearWarmup();
+  });
+
+  it('keeps only entries at or above the floor', async () => {
+    paginationService.setSeverityFloor('W');
+    expect(paginationService.isFilterActive()).toBe(true);
+    expect(await paginationService.getFilteredTotal()).toBe(40);
+    const rows = await paginationService.readRangeByIdx(1, 40);
+    expect(rows.every((e) => e.level === 'W' || e.level === 'E')).toBe(true);
+  });
+
+  it('combines with the text filter', async () => {
+    paginationService.setSeverityFloor('E');
+    paginationService.setFilter({ msg: 'alpha' });
+    const rows = await paginationService.readRangeByIdx(1, 80);
+    expect(rows.length).toBe(10);
+    expect(rows.every((e) => e.level === 'E' && e.text.includes('alpha'))).toBe(true);
+  });
+
+  it('guesses the level from text when missing', () => {
+    paginationService.setSeverityFloor('W');
+    const bare = { id: 1, ts: 1, text: 'fatal error here' } as LogEntry;
+    expect(paginationService.meetsSeverityFloor(bare)).toBe(true);
+    expect(paginationService.meetsSeverityFloor({ ...bare, text: 'all good' })).toBe(false);
+  });
+
+  it('keeps a second viewer instance independent of the shared one', async () => {
+    const side = new PaginationService();
+    side.seedWarmupBuffer([entry(2), entry(1)], 2);
+    side.setSeverityFloor('E');
+    expect(paginationService.isFilterActive()).toBe(false);
+    expect(await paginationService.getFilteredTotal()).toBe(80);
+    expect(await side.getFilteredTotal()).toBe(0);
+  });
+});
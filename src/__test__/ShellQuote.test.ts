@@ -0,0 +1,67 @@
+// src/__test__/ShellQuote.test.ts
+
+import { execFileSync } from 'child_process';
+
+import { shellEscape, shellQuote } from '../core/connection/shellQuote.js';
+import { wrapSudo } from '../core/connection/sudo.js';
+import { composeSourceChain, tailFileSource } from '../core/logs/RealtimeSource.js';
+
+const ADVERSARIAL = [
+  'plain',
+  'with space',
+  "it's",
+  "''",
+  'a; rm -rf /tmp/x',
+  'x && echo pwned',
+  '`id`',
+  '$(id)',
+  '$HOME',
+  '"double"',
+  'back\\slash',
+  'line\nbreak',
+  '*',
+  '',
+];
+
+/** 실제 sh로 `printf %s <인자>`를 실행해 셸이 본 인자를 돌려받는다 */
+function shPrintf(script: string): string {
+  return execFileSync('sh', ['-c', script], { encoding: 'utf8' });
+}
+
+const itSh = process.platform === 'win32' ? it.skip : it;
+
+describe('shellQuote', () => {
+  it('wraps in single quotes and escapes embedded quotes', () => {
+    expect(shellQuote('a b')).toBe(`'a b'`);
+    expect(shellQuote("it's")).toBe(`'it'\\''s'`);
+    expect(shellEscape("it's")).toBe(`it'\\''s`);
+  });
+
+  itSh.each(ADVERSARIAL)('passes %j through sh as one literal argument', (s) => {
+    expect(shPrintf(`printf %s ${shellQuote(s)}`)).toBe(s);
+    // 인자 개수도 1개여야 한다(공백/글롭으로 쪼개지지 않음)
+    expect(shPrintf(`set -- ${shellQuote(s)}; printf %s "$#"`)).toBe('1');
+  });
+
+  itSh.each(ADVERSARIAL)('survives nesting inside sh -lc %j', (s) => {
+    const inner = `printf %s ${shellQuote(s)}`;
+    expect(shPrintf(`sh -c ${shellQuote(inner)}`)).toBe(s);
+  });
+});
+
+describe('remote command builders', () => {
+  itSh('wrapSudo keeps the command intact (quotes included)', () => {
+    const cmd = `printf %s ${shellQuote("a'b; `id`")}`;
+    const wrapped = wrapSudo(cmd);
+    expect(wrapped).toBe(`sudo -n sh -c ${shellQuote(cmd)}`);
+    // sudo 없이 같은 래핑을 실행해 원문이 보존되는지 확인
+    expect(shPrintf(wrapped.replace(/^sudo -n /, ''))).toBe("a'b; `id`");
+  });
+
+  itSh('composeSourceChain survives a source path with quotes', () => {
+    const src = tailFileSource("/var/log/it's $(id).log");
+    expect(src.cmd).toBe(`tail -n 0 -f ${shellQuote("/var/log/it's $(id).log")}`);
+    const chain = composeSourceChain([{ name: 'echo', cmd: `printf %s ${shellQuote("it's")}` }]);
+    expect(shPrintf(chain.replace(/^sh -lc /, 'sh -c '))).toBe("__edgetool_src__ echo\nit's");
+  });
+});
@@ -0,0 +1,110 @@
+// src/__test__/SingleLogFile.test.ts
+
+import * as fs from 'fs';
+import * as path from 'path';
+import * as zlib from 'zlib';
+
+import { countLinesInFile, readSingleLogFile } from '../core/logs/LogFileIntegration.js';
+import type { ParserConfig } from '../core/logs/ParserEngine.js';
+import { cleanDir, prepareUniqueOutDir } from './helpers/testFs.js';
+
+const PARSER_TEMPLATE_PATH = path.resolve(
+  __dirname,
+  '..',
+  '..',
+  'media',
+  'resources',
+  'custom_log_parser.template.v1.json',
+);
+
+describe('readSingleLogFile', () => {
+  let outDir: string;
+  beforeEach(() => {
+    outDir = prepareUniqueOutDir('single_log_file');
+  });
+  afterEach(() => cleanDir(outDir));
+
+  it('reads one file newest-first and stitches continuation lines', async () => {
+    const file = path.join(outDir, 'homey-pro.log');
+    fs.writeFileSync(
+      file,
+      [
+        '[2025-01-01 10:00:00.000] app[1]: start',
+        '[2025-01-01 10:00:01.000] app[1]: Error: boom',
+        '    at fn (a.js:1:1)',
+        '    at main (a.js:2:1)',
+        '[2025-01-01 10:00:02.000] app[1]: done',
+      ].join('\n'),
+    );
+
+    const r = await readSingleLogFile(file);
+    expect(r.parsed).toBe(3);
+    expect(r.skipped).toBe(0);
+    expect(r.logs.map((e) => e.ts)).toEqual([
+      Date.UTC(2025, 0, 1, 10, 0, 2),
+      Date.UTC(2025, 0, 1, 10, 0, 1),
+      Date.UTC(2025, 0, 1, 10, 0, 0),
+    ]);
+    expect(r.logs[1].text.split('\n')).toHaveLength(3);
+    expect(r.logs.every((e) => e.file === 'homey-pro.log')).toBe(true);
+  });
+
+  it('reads a gzip-rotated file', async () => {
+    const file = path.join(outDir, 'homey-pro.log.1.gz');
+    const lines = [
+      '[2025-01-01 10:00:00.000] app[1]: start',
+      '[2025-01-01 10:00:01.000] app[1]: done',
+    ];
+    fs.writeFileSync(file, zlib.gzipSync(lines.join('\n')));
+
+    expect(await countLinesInFile(file)).toBe(2);
+    const r = await readSingleLogFile(file);
+    expect(r.parsed).toBe(2);
+    expect(r.logs.map((e) => e.text)).toEqual([...lines].reverse());
+    expect(r.logs[0].ts).toBe(Date.UTC(2025, 0, 1, 10, 0, 1));
+  });
+
+  it('counts continuation lines of a discarded header as skipped', async () => {
+    const parser: ParserConfig = JSON.parse(fs.readFileSync(PARSER_TEMPLATE_PATH, 'utf8'));
+    const file = path.join(outDir, 'homey-pro.log');
+    const good = Array.from(
+      { length: 12 },
+      (_, i) => `[Jan  1 10:00:${String(i).padStart(2, '0')}.000] app[1]: line ${i}`,
+    );
+    fs.writeFileSync(
+      file,
+      [...good.slice(0, 6), 'garbage header', '    at fn (a.js:1:1)', '    at main (a.js:2:1)']
+        .concat(good.slice(6))
+        .join('\n'),
+    );
+
+    const r = await readSingleLogFile(file, { parser });
+    expect(r.parsed).toBe(12);
+    expect(r.skipped).toBe(3);
+  });
+
+  it('keeps leading orphan continuation lines on parser files', async () => {
+    const parser: ParserConfig = JSON.parse(fs.readFileSync(PARSER_TEMPLATE_PATH, 'utf8'));
+    const file = path.join(outDir, 'homey-pro.log');
+    const good = Array.from(
+      { length: 12 },
+      (_, i) => `[Jan  1 10:00:${String(i).padStart(2, '0')}.000] app[1]: line ${i}`,
+    );
+    const orphan = ['    at orphan (a.js:1:1)', '    at top (a.js:2:1)'];
+    fs.writeFileSync(file, [...orphan, ...good].join('\n'));
+
+    const r = await readSingleLogFile(file, { parser });
+    expect(r.parsed).toBe(13);
+    expect(r.skipped).toBe(0);
+    const oldest = r.logs[12].text.split('\n');
+    expect(oldest).toHaveLength(2);
+    expect(oldest[0]).toContain('at orphan');
+  });
+
+  it('rejects a missing path and a directory', async () => {
+    await expect(readSingleLogFile(path.join(outDir, 'nope.log'))).rejects.toThrow(
+      /Cannot read log file/,
+    );
+    await expect(readSingleLogFile(outDir)).rejects.toThrow(/not a regular file/);
+  });
+});
@@ -0,0 +1,123 @@
+// src/__test__/SshConfigAlias.test.ts
+
+import * as os from 'os';
+import * as path from 'path';
+
+import {
+  listSshConfigAliases,
+  resolveSshConfigHost,
+  resolveSshTarget,
+} from '../core/config/ssh-config.js';
+import { sshKeepaliveOptions } from '../core/connection/sshClient.js';
+import { SSH_ALIVE_COUNT_MAX_DEFAULT, SSH_ALIVE_INTERVAL_S_DEFAULT } from '../shared/const.js';
+import { ErrorCategory } from '../shared/errors.js';
+
+const CONFIG = `
+# 전역 기본값
+User fallback
+
+Host bench bench-alt
+  HostName 10.0.0.2
+  Port 2222
+  IdentityFile ~/.ssh/id_bench
+
+Host jump-*
+  ProxyJump bastion
+
+Host *.lan !printer.lan
+  User lanuser
+
+Match host foo
+  User ignored
+
+Host *
+  User star
+  Port 22
+`;
+
+describe('resolveSshConfigHost', () => {
+  it('applies the first value per key across matching blocks', () => {
+    expect(resolveSshConfigHost('bench', CONFIG)).toEqual({
+      hostName: '10.0.0.2',
+      user: 'fallback',
+      port: 2222,
+      identityFile: '~/.ssh/id_bench',
+      proxyJump: undefined,
+    });
+  });
+
+  it('honours negated patterns and returns undefined when only Host * matches', () => {
+    expect(resolveSshConfigHost('nas.lan', CONFIG)?.user).toBe('fallback');
+    expect(resolveSshConfigHost('printer.lan', CONFIG)).toBeUndefined();
+    expect(resolveSshConfigHost('unknown', CONFIG)).toBeUndefined();
+  });
+});
+
+describe('listSshConfigAliases', () => {
+  it('lists concrete Host names only', () => {
+    expect(listSshConfigAliases(CONFIG)).toEqual(['bench', 'bench-alt']);
+  });
+});
+
+describe('resolveSshTarget', () => {
+  it('passes saved details through when no alias is set', () => {
+    const t = resolveSshTarget({ host: 'h', user: 'root', port: 22, password: 'pw' }, CONFIG);
+    expect(t).toEqual({ host: 'h', user: 'root', port: 22, password: 'pw' });
+  });
+
+  it('resolves host/user/port/key from the alias and expands ~', () => {
+    const t = resolveSshTarget({ host: '', user: '', port: 22, configAlias: 'bench' }, CONFIG);
+    expect(t).toMatchObject({ host: '10.0.0.2', user: 'fallback', port: 2222 });
+    expect(t.keyPath).toBe(path.join(os.homedir(), '.ssh', 'id_bench'));
+  });
+
+  it('rejects unknown aliases and ProxyJump hosts with a Connection error', () => {
+    const d = { host: '', user: '', port: 22 };
+    expect(() => resolveSshTarget({ ...d, configAlias: 'nope' }, CONFIG)).toThrow(/nope/);
+    try {
+      resolveSshTarget({ ...d, configAlias: 'jump-a' }, CONFIG);
+      throw new Error('expected throw');
+    } catch (e: any) {
+      expect(e.category).toBe(ErrorCategory.Connection);
+      expect(e.message).toContain('ProxyJump');
+    }
+  });
+});
+
+describe('ssh keepalive', () => {
+  const ALIVE = `
+Host flaky
+  HostName 10.0.0.9
+  ServerAliveInterval 30
+  ServerAliveCountMax 2
+`;
+  const d = { host: '', user: 'root', port: 22, configAlias: 'flaky' };
+
+  afterEach(() => {
+    delete process.env.EDGE_TOOL_SSH_ALIVE_INTERVAL;
+    delete process.env.EDGE_TOOL_SSH_ALIVE_COUNT_MAX;
+  });
+
+  it('reads ServerAlive* from ssh config, with saved details taking precedence', () => {
+    expect(resolveSshTarget(d, ALIVE)).toMatchObject({ aliveIntervalS: 30, aliveCountMax: 2 });
+    const t = resolveSshTarget({ ...d, serverAliveInterval: 0 }, ALIVE);
+    expect(t).toMatchObject({ aliveIntervalS: 0, aliveCountMax: 2 });
+  });
+
+  it('falls back to env overrides, then conservative defaults', () => {
+    expect(sshKeepaliveOptions({})).toEqual({
+      keepaliveInterval: SSH_ALIVE_INTERVAL_S_DEFAULT * 1000,
+      keepaliveCountMax: SSH_ALIVE_COUNT_MAX_DEFAULT,
+    });
+    process.env.EDGE_TOOL_SSH_ALIVE_INTERVAL = '5';
+    process.env.EDGE_TOOL_SSH_ALIVE_COUNT_MAX = 'lots';
+    expect(sshKeepaliveOptions({})).toEqual({
+      keepaliveInterval: 5000,
+      keepaliveCountMax: SSH_ALIVE_COUNT_MAX_DEFAULT,
+    });
+    expect(sshKeepaliveOptions({ aliveIntervalS: 60, aliveCountMax: 1 })).toEqual({
+      keepaliveInterval: 60_000,
+      keepaliveCountMax: 1,
+    });
+  });
+});
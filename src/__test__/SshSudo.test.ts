@@ -0,0 +1,41 @@
+// src/__test__/SshSudo.test.ts
+
+import { isSudoPasswordError, needsSudo, wrapSudo } from '../core/connection/sudo.js';
+
+describe('wrapSudo', () => {
+  it('wraps the whole script so pipes and redirects run as root', () => {
+    expect(wrapSudo('mount | grep data > /tmp/m')).toBe(
+      `sudo -n sh -c 'mount | grep data > /tmp/m'`,
+    );
+  });
+
+  it('escapes single quotes of an already quoted sh -lc command', () => {
+    const cmd = `sh -lc 'systemctl restart homey-pro@default'`;
+    const wrapped = wrapSudo(cmd);
+    expect(wrapped.startsWith(`sudo -n sh -c '`)).toBe(true);
+    expect(wrapped).toContain(`'\\''systemctl restart homey-pro@default'\\''`);
+  });
+
+  it('does not double wrap commands that already use sudo', () => {
+    expect(wrapSudo('sudo docker ps')).toBe('sudo docker ps');
+    expect(wrapSudo(wrapSudo('id -u'))).toBe(wrapSudo('id -u'));
+  });
+});
+
+describe('isSudoPasswordError', () => {
+  it('detects sudo -n password/terminal failures', () => {
+    expect(isSudoPasswordError('sudo: a password is required\n')).toBe(true);
+    expect(isSudoPasswordError('sudo: a terminal is required to read the password')).toBe(true);
+    expect(isSudoPasswordError('docker: permission denied')).toBe(false);
+    expect(isSudoPasswordError(undefined)).toBe(false);
+  });
+});
+
+describe('needsSudo', () => {
+  it('applies only to non-root users with useSudo enabled', () => {
+    expect(needsSudo({ user: 'homey', useSudo: true })).toBe(true);
+    expect(needsSudo({ user: 'root', useSudo: true })).toBe(false);
+    expect(needsSudo({ user: 'homey' })).toBe(false);
+    expect(needsSudo(undefined)).toBe(false);
+  });
+});
@@ -0,0 +1,82 @@
+// src/__test__/StableLogIndex.test.ts
+
+import type { LogEntry } from '@ipc/messages';
+
+import { ChunkWriter } from '../core/logs/ChunkWriter.js';
+import { HybridLogBuffer } from '../core/logs/HybridLogBuffer.js';
+import { ManifestWriter } from '../core/logs/ManifestWriter.js';
+import { paginationService } from '../core/logs/PaginationService.js';
+import { cleanDir, prepareUniqueOutDir } from './helpers/testFs.js';
+
+const N = 3000;
+const CHUNK = 500;
+
+const entry = (i: number): LogEntry =>
+  ({ id: 0, ts: i, level: 'I', type: 'system', source: 't', text: `line ${i}` }) as LogEntry;
+
+/** 실시간 flush와 같은 순서: 전역 idx 부여 → 메모리 → 청크 append → manifest(asc) */
+async function pushRealtime(outDir: string, hb: HybridLogBuffer) {
+  const manifest = await ManifestWriter.loadOrCreate(outDir);
+  manifest.setOrder('asc');
+  const writer = new ChunkWriter(outDir, CHUNK);
+  let appended = 0;
+  let merged = 0;
+  for (let i = 0; i < N; i += 137) {
+    const batch = Array.from({ length: Math.min(137, N - i) }, (_, k) => entry(i + k + 1));
+    for (let k = 0; k < batch.length; k++) batch[k].id = batch[k].idx = appended + k + 1;
+    hb.addBatch(batch);
+    appended += batch.length;
+    for (const p of await writer.appendBatch(batch)) {
+      manifest.addChunk(p.file, p.lines, merged, p.bytes);
+      merged += p.lines;
+    }
+  }
+  manifest.setTotal(merged);
+  await manifest.save();
+  return manifest;
+}
+
+describe('realtime log index stability', () => {
+  let outDir: string;
+  beforeEach(() => {
+    outDir = prepareUniqueOutDir('stable_index');
+  });
+  afterEach(() => {
+    paginationService.detachManifestDir(outDir);
+    cleanDir(outDir);
+  });
+
+  it('keeps one contiguous, sorted idx per line across memory, file and reload', async () => {
+    const hb = new HybridLogBuffer(CHUNK);
+    const manifest = await pushRealtime(outDir, hb);
+    expect(manifest.data.chunkCount).toBe(N / CHUNK);
+
+    await paginationService.setManifestDir(outDir);
+    await paginationService.reload();
+    const all = await paginationService.readRangeByIdx(1, N);
+    expect(all.map((e) => e.idx)).toEqual(Array.from({ length: N }, (_, i) => i + 1));
+    // 파일에 기록된 id(= flush 시 부여한 idx)와 읽을 때의 idx가 같고, 순서는 append 순서
+    expect(all.every((e) => e.id === e.idx && e.text === `line ${e.idx}`)).toBe(true);
+
+    // 최신 윈도우: 메모리 링버퍼와 같은 라인/같은 idx
+    const tail = await paginationService.readRangeByIdx(N - CHUNK + 1, N);
+    expect(tail.map((e) => [e.idx, e.text])).toEqual(
+      hb.snapshot(CHUNK).map((e) => [e.idx, e.text]),
+    );
+  });
+
+  it('keeps global indexes for the remaining lines after rotation', async () => {
+    const manifest = await pushRealtime(outDir, new HybridLogBuffer());
+    await manifest.rotate({ maxChunks: 2 });
+    await manifest.save();
+
+    await paginationService.setManifestDir(outDir);
+    await paginationService.reload();
+    const page = await paginationService.readRangeByIdx(1995, 2005);
+    expect(page.map((e) => e.idx)).toEqual(Array.from({ length: 5 }, (_, i) => 2001 + i));
+    expect(page.every((e) => e.text === `line ${e.idx}`)).toBe(true);
+
+    const [hit] = await paginationService.searchAll('line 2750');
+    expect(hit.idx).toBe(2750);
+  });
+});
@@ -0,0 +1,114 @@
+// src/__test__/TarDownload.test.ts
+
+import { execFileSync } from 'child_process';
+import * as fs from 'fs';
+import * as path from 'path';
+
+import type { IConnectionManager } from '../core/connection/ConnectionManager.js';
+import {
+  assertTransferComplete,
+  FileTransferService,
+} from '../core/transfer/FileTransferService.js';
+import { ErrorCategory, XError } from '../shared/errors.js';
+import { cleanDir, prepareUniqueOutDir } from './helpers/testFs.js';
+
+/** 로컬 디렉터리를 tar로 묶어 base64 줄로 흘려보내는 SSH 연결 대역(원격 명령은 기록만) */
+function fakeSsh(srcDir: string) {
+  const streamed: string[] = [];
+  const ran: string[] = [];
+  const cm = {
+    getSnapshot: () => ({ active: { type: 'SSH' } }),
+    run: async (cmd: string) => {
+      ran.push(cmd);
+      return { code: 0, stdout: '', stderr: '' };
+    },
+    stream: async (cmd: string, onLine: (l: string) => void) => {
+      streamed.push(cmd);
+      if (cmd.includes(' find ')) {
+        const args = ['.', '!', '-type', 'd', '-o', '-type', 'd', '-empty'];
+        execFileSync('find', args, { cwd: srcDir }).toString().split('\n').forEach(onLine);
+        return;
+      }
+      const b64 = execFileSync('tar', ['-C', srcDir, '-cf', '-', '.']).toString('base64');
+      for (let i = 0; i < b64.length; i += 76) onLine(b64.slice(i, i + 76));
+    },
+  };
+  return { cm: cm as unknown as IConnectionManager, streamed, ran };
+}
+
+describe('SSH tar download', () => {
+  let root: string;
+  beforeEach(() => {
+    root = prepareUniqueOutDir('tar_download');
+  });
+  afterEach(() => cleanDir(root));
+
+  it('recreates empty directories next to the extracted files', async () => {
+    const src = path.join(root, 'remote');
+    fs.mkdirSync(path.join(src, 'lib'), { recursive: true });
+    fs.mkdirSync(path.join(src, 'userdata', 'empty'), { recursive: true });
+    fs.writeFileSync(path.join(src, 'lib', 'app.js'), 'x');
+    const dst = path.join(root, 'local');
+
+    const { cm } = fakeSsh(src);
+    const s = await new FileTransferService(cm).downloadViaTarBase64('/app', dst);
+
+    expect(s).toMatchObject({ done: 1, failed: 0 });
+    expect(fs.readFileSync(path.join(dst, 'lib', 'app.js'), 'utf8')).toBe('x');
+    expect(fs.statSync(path.join(dst, 'userdata', 'empty')).isDirectory()).toBe(true);
+  });
+
+  it('excludes skip names in the remote tar when nothing is selected', async () => {
+    const src = path.join(root, 'remote');
+    fs.mkdirSync(src, { recursive: true });
+    fs.writeFileSync(path.join(src, 'app.js'), 'x');
+
+    const { cm, streamed } = fakeSsh(src);
+    await new FileTransferService(cm).downloadViaTarBase64('/app', path.join(root, 'local'));
+
+    const tarCmd = streamed.find((c) => c.includes('tar -C'))!;
+    expect(tarCmd).toMatch(/--exclude=\W*node_modules/);
+    expect(tarCmd).toMatch(/--exclude=\W*\.git\b/);
+  });
+
+  it('passes the selected members to the remote tar through a temp list', async () => {
+    const src = path.join(root, 'remote');
+    fs.mkdirSync(path.join(src, 'lib'), { recursive: true });
+    fs.writeFileSync(path.join(src, 'lib', 'app.js'), 'x');
+    fs.writeFileSync(path.join(src, 'lib', 'app.js.map'), 'm');
+    fs.writeFileSync(path.join(src, 'app.json'), '{}');
+
+    const { cm, streamed, ran } = fakeSsh(src);
+    await new FileTransferService(cm).downloadViaTarBase64('/app', path.join(root, 'local'), {
+      include: ['lib'],
+      exclude: ['*.map'],
+      onProgress: () => {},
+    });
+
+    const tarCmd = streamed.find((c) => c.includes('tar -C'))!;
+    const listFile = /\/tmp\/edge-list-\d+\.txt/.exec(tarCmd)?.[0];
+    expect(listFile).toBeDefined();
+    expect(tarCmd).toContain('-T ');
+    expect(tarCmd).not.toContain('lib/app.js');
+    const written = ran.filter((c) => c.includes('printf') && c.includes(listFile!)).join(' ');
+    expect(written).toContain('lib/app.js');
+    expect(written).not.toContain('app.js.map');
+    expect(written).not.toContain('app.json');
+    expect(ran.some((c) => c.includes('rm -f') && c.includes(listFile!))).toBe(true);
+  });
+});
+
+describe('assertTransferComplete', () => {
+  it('passes complete transfers and rejects partial ones', () => {
+    const ok = { total: 2, done: 2, skipped: 0, failed: 0, renamed: 0 };
+    expect(assertTransferComplete('pull /a', ok)).toBe(ok);
+    const partial = () => assertTransferComplete('pull /a', { ...ok, done: 1, failed: 1 });
+    expect(partial).toThrow('pull /a: 1/2 file(s) failed');
+    expect(() => partial()).toThrow(XError);
+    try {
+      partial();
+    } catch (e) {
+      expect(e).toMatchObject({ category: ErrorCategory.Connection });
+    }
+  });
+});
@@ -0,0 +1,102 @@
+// src/__test__/TimezoneCorrection.test.ts
+
+import {
+  correctTimestamps,
+  MonotonicCorrector,
+  readTimezoneCorrectionOptions,
+  TimezoneCorrector,
+} from '../core/logs/time/TimezoneHeuristics.js';
+
+const MIN = 60 * 1000;
+const HOUR = 60 * MIN;
+const T0 = Date.UTC(2025, 0, 1, 12, 0, 0);
+
+/** 최신→오래된 순 합성 로그: idx 2..3 구간만 시계가 +offsetH 만큼 튄 상태 */
+function syntheticFeed(offsetH: number): number[] {
+  const jump = offsetH * HOUR;
+  return [T0, T0 - 1 * MIN, T0 - 2 * MIN + jump, T0 - 3 * MIN + jump, T0 - 4 * MIN, T0 - 5 * MIN];
+}
+
+function runTz(feed: number[], tz: TimezoneCorrector) {
+  feed.forEach((ts, i) => tz.adjust(ts, i));
+  tz.finalizeSuspected();
+  return tz.drainRetroSegments();
+}
+
+describe('TimezoneCorrector', () => {
+  it('derives the offset from the logs (KST +9h)', () => {
+    const segs = runTz(syntheticFeed(9), new TimezoneCorrector('kst'));
+    expect(segs).toEqual([{ start: 2, end: 3, deltaMs: -9 * HOUR }]);
+  });
+
+  it('supports non-KST, non-hourly offsets (+5:30)', () => {
+    const segs = runTz(syntheticFeed(5.5), new TimezoneCorrector('ist'));
+    expect(segs).toEqual([{ start: 2, end: 3, deltaMs: -5.5 * HOUR }]);
+  });
+
+  it('applies a fixed offset when configured', () => {
+    const segs = runTz(syntheticFeed(4), new TimezoneCorrector('fixed', true, { offsetHours: 3 }));
+    expect(segs).toEqual([{ start: 2, end: 3, deltaMs: -3 * HOUR }]);
+  });
+
+  it('ignores jumps below the configured threshold', () => {
+    const segs = runTz(syntheticFeed(4), new TimezoneCorrector('thr', true, { minJumpHours: 6 }));
+    expect(segs).toEqual([]);
+  });
+});
+
+describe('MonotonicCorrector', () => {
+  it('does not shift beyond maxOffsetHours (local clamp only)', () => {
+    const mc = new MonotonicCorrector('cap', 1, { maxOffsetHours: 2 });
+    const out = syntheticFeed(5).map((ts) => mc.adjust(ts));
+    // 상한 초과 점프는 1ms 클램프만 → 뒤쪽 정상 라인은 원본 유지
+    expect(out[2]).toBe(out[1] - 1);
+    expect(out[4]).toBe(T0 - 4 * MIN);
+  });
+
+  it('clamps only the out-of-order line and keeps real timestamps afterwards', () => {
+    const mc = new MonotonicCorrector('one', 1);
+    const feed = [T0, T0 - 1 * MIN, T0 + 30 * MIN, T0 - 3 * MIN, T0 - 4 * MIN];
+    expect(feed.map((ts) => mc.adjust(ts))).toEqual([
+      T0,
+      T0 - 1 * MIN,
+      T0 - 1 * MIN - 1,
+      T0 - 3 * MIN,
+      T0 - 4 * MIN,
+    ]);
+  });
+});
+
+describe('correctTimestamps', () => {
+  it('undoes a returned timezone jump before the monotonic pass', () => {
+    const entries = syntheticFeed(5.5).map((ts) => ({ ts }));
+    correctTimestamps('merge', entries);
+    expect(entries.map((e) => e.ts)).toEqual([0, 1, 2, 3, 4, 5].map((m) => T0 - m * MIN));
+  });
+
+  it('uses the configured offset and leaves logs alone when disabled', () => {
+    const fixed = syntheticFeed(4).map((ts) => ({ ts }));
+    correctTimestamps('fixed', fixed, { offsetHours: 4 });
+    expect(fixed[2].ts).toBe(T0 - 2 * MIN);
+
+    const off = syntheticFeed(4).map((ts) => ({ ts }));
+    correctTimestamps('off', off, { enabled: false });
+    expect(off.map((e) => e.ts)).toEqual(syntheticFeed(4));
+  });
+});
+
+describe('readTimezoneCorrectionOptions', () => {
+  it('maps snake_case config and drops invalid values', () => {
+    expect(
+      readTimezoneCorrectionOptions({ enabled: false, offset_hours: 5.5, min_jump_hours: 'x' }),
+    ).toEqual({
+      enabled: false,
+      minJumpHours: undefined,
+      minReturnHours: undefined,
+      maxOffsetHours: undefined,
+      offsetHours: 5.5,
+      smallDeltaMs: undefined,
+    });
+    expect(readTimezoneCorrectionOptions(undefined)).toEqual({});
+  });
+});
@@ -0,0 +1,78 @@
+// src/__test__/ToolPreflight.test.ts
+
+import {
+  formatPreflightReport,
+  isToolMissingError,
+  runToolPreflight,
+  toolMissingError,
+} from '../core/connection/toolPreflight.js';
+import { ErrorCategory } from '../shared/errors.js';
+
+// 가짜 execFile: 도구별로 (err, stdout, stderr) 응답 — 표에 없으면 ENOENT
+const ENOENT = Object.assign(new Error('spawn ENOENT'), { code: 'ENOENT' });
+function fakeExec(table: Record<string, [any, string, string]>) {
+  return (file: string, _args: string[], cb: (err: any, so: string, se: string) => void) => {
+    const [err, so, se] = table[file] ?? [ENOENT, '', ''];
+    cb(err, so, se);
+  };
+}
+
+describe('isToolMissingError', () => {
+  it('recognizes ENOENT and shell "not found" messages', () => {
+    expect(isToolMissingError({ code: 'ENOENT' })).toBe(true);
+    expect(
+      isToolMissingError(new Error("'adb' is not recognized as an internal or external command")),
+    ).toBe(true);
+    expect(isToolMissingError({ stderr: 'sh: 1: scp: command not found' })).toBe(true);
+    expect(isToolMissingError(new Error('exit code 1'))).toBe(false);
+    // 도구가 실행된 뒤의 일반 오류(git의 경로 오류 등)는 부재가 아니다
+    const gitErr = 'fatal: cannot change to x: No such file or directory';
+    expect(isToolMissingError({ stderr: gitErr })).toBe(false);
+  });
+});
+
+describe('runToolPreflight', () => {
+  it('reports missing tools without throwing and keeps usage-exit tools as present', async () => {
+    const results = await runToolPreflight({
+      exec: fakeExec({
+        git: [null, 'git version 2.43.0\n', ''],
+        ssh: [null, '', 'OpenSSH_9.6p1, OpenSSL 3.0.13\n'],
+        // scp는 인자 없이 실행하면 usage를 찍고 code=1로 끝난다
+        scp: [Object.assign(new Error('Command failed'), { code: 1 }), '', 'usage: scp ...\n'],
+      }),
+      require: ['adb'],
+    });
+    const byName = Object.fromEntries(results.map((r) => [r.name, r]));
+    expect(byName.adb.present).toBe(false);
+    expect(byName.git).toMatchObject({ present: true, version: 'git version 2.43.0' });
+    expect(byName.ssh.version).toBe('OpenSSH_9.6p1, OpenSSL 3.0.13');
+    expect(byName.scp.present).toBe(true);
+
+    const report = formatPreflightReport(results);
+    expect(report).toContain('[MISS] adb');
+    expect(report).toContain('platform-tools');
+    expect(report).toContain('필수 도구 없음: adb');
+  });
+
+  it('keeps adb optional unless an ADB connection needs it', async () => {
+    const results = await runToolPreflight({
+      exec: fakeExec({
+        git: [null, 'git version 2.43.0\n', ''],
+        // 실행은 됐지만 출력에 "not found"가 있어도 spawn ENOENT가 아니면 존재
+        ssh: [Object.assign(new Error('Command failed'), { code: 255 }), '', 'ssh: not found\n'],
+      }),
+    });
+    const byName = Object.fromEntries(results.map((r) => [r.name, r]));
+    expect(byName.adb).toMatchObject({ present: false, required: false });
+    expect(byName.ssh.present).toBe(true);
+    expect(formatPreflightReport(results)).toContain('필수 도구가 모두 설치되어 있습니다.');
+  });
+});
+
+describe('toolMissingError', () => {
+  it('builds a ToolMissing XError with an install hint', () => {
+    const e = toolMissingError('git');
+    expect(e.category).toBe(ErrorCategory.ToolMissing);
+    expect(e.message).toContain('git-scm.com');
+  });
+});
@@ -0,0 +1,92 @@
+// src/__test__/TransferMeter.test.ts
+
+import {
+  fmtBytes,
+  formatTransferRate,
+  parseDuKiB,
+  TRANSFER_REPORT_INTERVAL_MS,
+  TransferMeter,
+} from '../core/transfer/TransferMeter.js';
+
+const clock = (start = 1_000) => {
+  let t = start;
+  return { now: () => t, tick: (ms: number) => (t += ms) };
+};
+
+describe('TransferMeter', () => {
+  it('computes speed, percent and ETA from a known total', () => {
+    const c = clock();
+    const m = new TransferMeter(4 * 1024 * 1024, c.now);
+    c.tick(2000);
+    m.add(1024 * 1024);
+    expect(m.snapshot()).toEqual({
+      bytes: 1024 * 1024,
+      totalBytes: 4 * 1024 * 1024,
+      bytesPerSec: 512 * 1024,
+      etaMs: 6000,
+      percent: 25,
+    });
+  });
+
+  it('caps at 99% until done when the estimate is too small', () => {
+    const c = clock();
+    const m = new TransferMeter(1000, c.now);
+    c.tick(100);
+    m.add(1500);
+    expect(m.snapshot().percent).toBe(99);
+    expect(m.snapshot(true)).toMatchObject({ percent: 100, etaMs: 0 });
+  });
+
+  it('reports only bytes and speed without a total', () => {
+    const c = clock();
+    const m = new TransferMeter(undefined, c.now);
+    expect(m.snapshot()).toEqual({ bytes: 0, bytesPerSec: undefined });
+    c.tick(1000);
+    m.add(2048);
+    expect(m.snapshot()).toEqual({ bytes: 2048, bytesPerSec: 2048 });
+  });
+
+  it('throttles reports to the interval', () => {
+    const c = clock();
+    const m = new TransferMeter(undefined, c.now);
+    expect(m.shouldReport()).toBe(true);
+    c.tick(TRANSFER_REPORT_INTERVAL_MS - 1);
+    expect(m.shouldReport()).toBe(false);
+    c.tick(1);
+    expect(m.shouldReport()).toBe(true);
+  });
+});
+
+describe('formatTransferRate', () => {
+  it('formats bytes, percent, speed and ETA', () => {
+    expect(
+      formatTransferRate({
+        bytes: 12.5 * 1024 * 1024,
+        totalBytes: 50 * 1024 * 1024,
+        percent: 25,
+        bytesPerSec: 1.5 * 1024 * 1024,
+        etaMs: 95_000,
+      }),
+    ).toBe('12.5 MiB / 50.0 MiB (25%) · 1.5 MiB/s · ETA 1m35s');
+  });
+
+  it('omits unknown parts and the ETA once finished', () => {
+    expect(formatTransferRate({ bytes: 300 })).toBe('300 B');
+    const done = { bytes: 2048, totalBytes: 2048, percent: 100, etaMs: 0, bytesPerSec: 1024 };
+    expect(formatTransferRate(done)).toBe('2.0 KiB / 2.0 KiB (100%) · 1.0 KiB/s');
+  });
+});
+
+describe('fmtBytes / parseDuKiB', () => {
+  it('picks a binary unit', () => {
+    expect(fmtBytes(1023)).toBe('1023 B');
+    expect(fmtBytes(1536)).toBe('1.5 KiB');
+    expect(fmtBytes(3 * 1024 ** 3)).toBe('3.00 GiB');
+  });
+
+  it('sums du -sk lines and ignores noise', () => {
+    expect(parseDuKiB('4\tmessages\n12\tkern.log\n')).toBe(16 * 1024);
+    expect(parseDuKiB('du: cannot access x: No such file\n8\ty\n')).toBe(8 * 1024);
+    expect(parseDuKiB('')).toBeUndefined();
+  });
+});
@@ -0,0 +1,139 @@
+// src/__test__/UnmountWorkflow.test.ts
+
+import type { RunResult, ShellExecutor } from '../core/connection/ConnectionManager.js';
+import { UnmountTaskRunner } from '../core/tasks/UnmountTaskRunner.js';
+
+// ── 가짜 기기: 명령 문자열 → 상태 기반 응답 ──────────────────────────────
+type Device = {
+  tokensInServiceFile: boolean;
+  containers: string[];
+  volumes: Set<string>;
+  /** 볼륨별 `docker volume rm` 실패 횟수(사용 중 등) */
+  rmFailures: Record<string, number>;
+  hash: string;
+  /** /tmp 작업 디렉터리 쓰기 시험 실패(가득 참/ro) */
+  tmpReadOnly?: boolean;
+};
+
+function fakeShell(dev: Device) {
+  const calls: string[] = [];
+  const ok = (stdout = '', code = 0): RunResult => ({ code, stdout, stderr: '' });
+  const sh: ShellExecutor = {
+    run: async (cmd: string) => {
+      calls.push(cmd);
+      if (cmd.includes('/proc/mounts')) return ok('rw,relatime\n');
+      if (cmd.includes('.write-probe') && dev.tmpReadOnly) {
+        return ok('touch: Read-only file system\n', 1);
+      }
+      if (cmd.includes('FragmentPath')) return ok('/lib/systemd/system/homey-pro@.service\n');
+      if (cmd.includes('ActiveState')) return ok('ActiveState=active\nSubState=running\n');
+      if (cmd.includes('sha256sum')) return ok(`${dev.hash}\n`);
+      if (cmd.includes('grep -nE')) {
+        return ok(dev.tokensInServiceFile ? '12:  --volume="homey-app:/app" \\\n' : '');
+      }
+      if (cmd.includes('grep -E "$1"')) return ok('', dev.tokensInServiceFile ? 0 : 1);
+      if (cmd.includes('sed -E -i')) {
+        dev.tokensInServiceFile = false;
+        return ok();
+      }
+      if (cmd.includes('mv -f')) {
+        dev.hash = `${dev.hash}'`;
+        return ok();
+      }
+      if (cmd.includes('docker stop')) {
+        dev.containers = [];
+        return ok();
+      }
+      if (cmd.includes('docker ps -a')) return ok(dev.containers.join('\n'));
+      const rm = /docker volume rm '?([\w.-]+)/.exec(cmd);
+      if (rm) {
+        const v = rm[1];
+        if ((dev.rmFailures[v] ?? 0) > 0) dev.rmFailures[v]--;
+        else dev.volumes.delete(v);
+        return ok();
+      }
+      if (cmd.includes('docker volume ls')) return ok(Array.from(dev.volumes).join('\n'));
+      return ok();
+    },
+  };
+  return { sh, calls };
+}
+
+// 러너 내부의 sleep/폴링을 가짜 시계로 빠르게 흘려보냄
+async function settle<T>(p: Promise<T>): Promise<T> {
+  let done = false;
+  p.then(
+    () => (done = true),
+    () => (done = true),
+  );
+  for (let i = 0; i < 600 && !done; i++) await jest.advanceTimersByTimeAsync(500);
+  return p;
+}
+
+const count = (calls: string[], needle: string) => calls.filter((c) => c.includes(needle)).length;
+
+describe('UnmountTaskRunner workflow (fake shell)', () => {
+  beforeEach(() => jest.useFakeTimers());
+  afterEach(() => jest.useRealTimers());
+
+  it.each([
+    {
+      name: 'no volumes → early exit to cleanup',
+      dev: { tokensInServiceFile: false, containers: [], volumes: [], rmFailures: {} },
+      expect: { stop: 0, rm: 0, restart: 0, volumesLeft: [] as string[] },
+    },
+    {
+      name: 'volumes with running containers → stop, patch, remove, restart',
+      dev: {
+        tokensInServiceFile: true,
+        containers: ['homey-pro'],
+        volumes: ['homey-app', 'homey-node', 'other'],
+        rmFailures: {},
+      },
+      expect: { stop: 1, rm: 2, restart: 1, volumesLeft: ['other'] },
+    },
+    {
+      name: 'volume busy once → remove-retry loop until gone',
+      dev: {
+        tokensInServiceFile: true,
+        containers: [],
+        volumes: ['homey-app'],
+        rmFailures: { 'homey-app': 1 },
+      },
+      expect: { stop: 1, rm: 2, restart: 1, volumesLeft: [] },
+    },
+  ])('$name', async ({ dev, expect: want }) => {
+    const device: Device = { ...dev, volumes: new Set(dev.volumes), hash: 'h0' };
+    const { sh, calls } = fakeShell(device);
+    const runner = new UnmountTaskRunner(undefined, { sh, unit: 'homey-pro@test.service' });
+
+    await settle(runner.run());
+
+    expect(count(calls, 'docker stop')).toBe(want.stop);
+    expect(count(calls, 'docker volume rm')).toBe(want.rm);
+    expect(count(calls, 'systemctl restart')).toBe(want.restart);
+    expect(Array.from(device.volumes)).toEqual(want.volumesLeft);
+    expect(device.tokensInServiceFile).toBe(false);
+    // 어떤 경로든 작업 디렉터리는 정리
+    expect(count(calls, 'rm -rf')).toBe(1);
+  });
+
+  it('stops before backup/containers/volumes when the work dir is not writable', async () => {
+    const device: Device = {
+      tokensInServiceFile: true,
+      containers: ['homey-pro'],
+      volumes: new Set(['homey-app', 'homey-node']),
+      rmFailures: {},
+      hash: 'h0',
+      tmpReadOnly: true,
+    };
+    const { sh, calls } = fakeShell(device);
+    const runner = new UnmountTaskRunner(undefined, { sh, unit: 'homey-pro@test.service' });
+
+    await expect(settle(runner.run())).rejects.toThrow(/작업 디렉터리를 쓸 수 없습니다/);
+    expect(count(calls, 'cp -f')).toBe(0);
+    expect(count(calls, 'docker stop')).toBe(0);
+    expect(count(calls, 'docker volume rm')).toBe(0);
+    expect(device.containers).toEqual(['homey-pro']);
+  });
+});
@@ -0,0 +1,52 @@
+// src/__test__/WarmupContinuation.test.ts
+
+import * as fs from 'fs';
+import * as path from 'path';
+
+import { warmupTailPrepass } from '../core/logs/LogFileIntegration.js';
+import { cleanDir, prepareUniqueOutDir } from './helpers/testFs.js';
+
+describe('warmupTailPrepass continuation lines', () => {
+  let outDir: string;
+  beforeEach(() => {
+    outDir = prepareUniqueOutDir('warmup_continuation');
+  });
+  afterEach(() => cleanDir(outDir));
+
+  it('attaches stack lines to their header across read chunks', async () => {
+    fs.writeFileSync(
+      path.join(outDir, 'homey-pro.log'),
+      [
+        '[2025-01-01 10:00:00.000] app[1]: start',
+        '[2025-01-01 10:00:01.000] app[1]: Error: boom',
+        '    at fn (a.js:1:1)',
+        '    at main (a.js:2:1)',
+        '[2025-01-01 10:00:02.000] app[1]: done',
+      ].join('\n'),
+    );
+
+    // target 2 → 연속 라인이 첫 청크 끝에 걸려 다음 청크의 헤더로 넘어가야 한다
+    const r = await warmupTailPrepass({ dir: outDir, memory_mode_threshold: 2 });
+    expect(r.logs).toHaveLength(2);
+    const lines = r.logs[1].text.split('\n');
+    expect(lines).toHaveLength(3);
+    expect(lines[0]).toContain('Error: boom');
+    expect(lines.slice(1)).toEqual(['    at fn (a.js:1:1)', '    at main (a.js:2:1)']);
+    expect(r.logs.some((e) => /^\s+at /.test(e.text))).toBe(false);
+  });
+
+  it('keeps leading orphan continuation lines as one entry', async () => {
+    fs.writeFileSync(
+      path.join(outDir, 'homey-pro.log'),
+      [
+        '    at orphan (a.js:1:1)',
+        '    at top (a.js:2:1)',
+        '[2025-01-01 10:00:00.000] app[1]: ok',
+      ].join('\n'),
+    );
+
+    const r = await warmupTailPrepass({ dir: outDir, memory_mode_threshold: 10 });
+    expect(r.logs).toHaveLength(2);
+    expect(r.logs.map((e) => e.text.split('\n').length).sort()).toEqual([1, 2]);
+  });
+});
@@ -0,0 +1,30 @@
+// src/__test__/WebviewAssets.test.ts
+
+import { rewriteAssetUrls } from '../extension/panels/webviewHtml.js';
+
+const toUri = (rel: string) => `vscode-webview://x/${rel}`;
+
+describe('rewriteAssetUrls', () => {
+  it('rewrites local script/link/img paths and collects them once', () => {
+    const html = [
+      '<link rel="stylesheet" href="styles/base.css" />',
+      "<script nonce=\"n\" src='app.bundle.js'></script>",
+      '<img src="styles/base.css">',
+    ].join('\n');
+    const out = rewriteAssetUrls(html, toUri);
+    expect(out.html).toContain('href="vscode-webview://x/styles/base.css"');
+    expect(out.html).toContain("src='vscode-webview://x/app.bundle.js'");
+    expect(out.assets).toEqual(['styles/base.css', 'app.bundle.js']);
+  });
+
+  it('leaves remote, inline and anchor urls alone', () => {
+    const html = [
+      '<script src="https://cdn.example/x.js"></script>',
+      '<img src="data:image/png;base64,AAAA">',
+      '<link href="//fonts.example/f.css">',
+      '<link href="#top">',
+      '<a href="local.html">',
+    ].join('\n');
+    expect(rewriteAssetUrls(html, toUri)).toEqual({ html, assets: [] });
+  });
+});
@@ -0,0 +1,26 @@
+// src/__test__/WindowsFileNames.test.ts
+
+import {
+  isWindowsIllegalPath,
+  sanitizeWindowsPath,
+} from '../core/transfer/FileTransferService.js';
+
+describe('Windows-illegal file names on pull', () => {
+  it.each([
+    ['apps/foo:bar.json', 'apps/foo_bar.json'],
+    ['a<b>/c|d?.txt', 'a_b_/c_d_.txt'],
+    ['logs/CON', 'logs/CON_'],
+    ['logs/nul.txt', 'logs/nul_.txt'],
+    ['dir./file ', 'dir_/file_'],
+  ])('sanitizes %s -> %s', (rel, expected) => {
+    expect(isWindowsIllegalPath(rel)).toBe(true);
+    expect(sanitizeWindowsPath(rel)).toBe(expected);
+  });
+
+  it('leaves valid paths untouched', () => {
+    for (const rel of ['a/b/c.json', './x/y', 'console.log', 'com10']) {
+      expect(isWindowsIllegalPath(rel)).toBe(false);
+      expect(sanitizeWindowsPath(rel)).toBe(rel);
+    }
+  });
+});
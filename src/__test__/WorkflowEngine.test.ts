@@ -0,0 +1,135 @@
+// src/__test__/WorkflowEngine.test.ts
+
+import { type Step, WorkflowEngine } from '../core/tasks/workflow/workflowEngine.js';
+
+async function settle<T>(p: Promise<T>): Promise<T> {
+  let done = false;
+  p.then(
+    () => (done = true),
+    () => (done = true),
+  );
+  for (let i = 0; i < 100 && !done; i++) await jest.advanceTimersByTimeAsync(1000);
+  return p;
+}
+
+describe('WorkflowEngine retry/rollback', () => {
+  beforeEach(() => jest.useFakeTimers());
+  afterEach(() => jest.useRealTimers());
+
+  it('rolls back completed steps in reverse order when a later step fails', async () => {
+    const log: string[] = [];
+    const step = (name: string, fail = false): Step => ({
+      name,
+      run: async () => {
+        log.push(`run:${name}`);
+        if (fail) throw new Error(`${name} boom`);
+        return 'ok';
+      },
+      rollback: async () => void log.push(`rollback:${name}`),
+    });
+    const wf = new WorkflowEngine([step('A'), step('B'), step('C', true), step('D')]);
+
+    await expect(settle(wf.runAll('t1'))).rejects.toThrow('C boom');
+    expect(log).toEqual(['run:A', 'run:B', 'run:C', 'rollback:B', 'rollback:A']);
+  });
+
+  it('rolls back a step only once when next() jumps back to it', async () => {
+    const log: string[] = [];
+    let loops = 0;
+    const wf = new WorkflowEngine([
+      { name: 'A', run: async () => 'ok', rollback: async () => void log.push('rollback:A') },
+      {
+        name: 'B',
+        run: async () => 'ok',
+        rollback: async () => void log.push('rollback:B'),
+        next: () => (++loops < 3 ? 'A' : undefined),
+      },
+      {
+        name: 'C',
+        run: async () => {
+          throw new Error('C boom');
+        },
+      },
+    ]);
+
+    await expect(settle(wf.runAll('t5'))).rejects.toThrow('C boom');
+    expect(log).toEqual(['rollback:B', 'rollback:A']);
+  });
+
+  it('retries a throwing step up to `retries` times before succeeding', async () => {
+    let calls = 0;
+    const wf = new WorkflowEngine([
+      {
+        name: 'FLAKY',
+        retries: 2,
+        run: async () => {
+          if (++calls < 3) throw new Error('transient');
+          return 'ok';
+        },
+      },
+    ]);
+    await settle(wf.runAll('t2'));
+    expect(calls).toBe(3);
+  });
+
+  it('fails after retries are exhausted and keeps going when a rollback throws', async () => {
+    const rolled: string[] = [];
+    const wf = new WorkflowEngine([
+      {
+        name: 'A',
+        run: async () => 'ok',
+        rollback: async () => void rolled.push('A'),
+      },
+      {
+        name: 'B',
+        run: async () => 'ok',
+        rollback: async () => {
+          throw new Error('rollback failed');
+        },
+      },
+      { name: 'C', retries: 1, run: async () => 'fail' },
+    ]);
+    await expect(settle(wf.runAll('t3'))).rejects.toThrow('step returned fail: C');
+    expect(rolled).toEqual(['A']);
+  });
+
+  it('stops at the next step on abort, skips retries and rolls back', async () => {
+    const log: string[] = [];
+    const ac = new AbortController();
+    const wf = new WorkflowEngine([
+      { name: 'A', run: async () => 'ok', rollback: async () => void log.push('rollback:A') },
+      {
+        name: 'B',
+        retries: 3,
+        run: async (ctx) => {
+          log.push('run:B');
+          ac.abort();
+          if (ctx.signal?.aborted) throw new Error('remote command aborted');
+          return 'ok';
+        },
+      },
+      {
+        name: 'C',
+        run: async () => {
+          log.push('run:C');
+          return 'ok';
+        },
+      },
+    ]);
+    await expect(settle(wf.runAll('t4', undefined, ac.signal))).rejects.toThrow(
+      'remote command aborted',
+    );
+    expect(log).toEqual(['run:B', 'rollback:A']);
+  });
+
+  it('throws a cancel error before the first step when already aborted', async () => {
+    const ac = new AbortController();
+    ac.abort();
+    const run = jest.fn(async () => 'ok' as const);
+    const wf = new WorkflowEngine([{ name: 'A', run }]);
+    await expect(settle(wf.runAll('t5', undefined, ac.signal))).rejects.toThrow(
+      'operation cancelled',
+    );
+    expect(run).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,36 @@
+// src/__test__/YearlessStitcher.test.ts
+
+import { __testOnly } from '../core/logs/LogFileIntegration.js';
+
+const { YearlessStitcher } = __testOnly;
+
+const NOW = Date.UTC(2026, 0, 5, 12, 0, 0);
+const at = (y: number, m: number, d: number) => Date.UTC(y, m, d, 10, 0, 0);
+
+describe('YearlessStitcher', () => {
+  it('rolls back a year across a New Year boundary (newest first)', () => {
+    const st = new YearlessStitcher({ now: NOW });
+    // 파서는 모두 올해(2026)로 주입
+    const got = [at(2026, 0, 2), at(2026, 0, 1), at(2026, 11, 31), at(2026, 11, 30)].map((t) =>
+      st.apply(t, true),
+    );
+    expect(got).toEqual([at(2026, 0, 2), at(2026, 0, 1), at(2025, 11, 31), at(2025, 11, 30)]);
+  });
+
+  it('treats logs dated in the future as last year', () => {
+    const st = new YearlessStitcher({ now: NOW });
+    expect(st.apply(at(2026, 11, 20), true)).toBe(at(2025, 11, 20));
+    expect(st.apply(at(2026, 11, 19), true)).toBe(at(2025, 11, 19));
+  });
+
+  it('honours an explicit log year', () => {
+    const st = new YearlessStitcher({ logYear: 2023, now: NOW });
+    expect(st.apply(at(2026, 2, 1), true)).toBe(at(2023, 2, 1));
+    expect(st.apply(at(2026, 10, 1), true)).toBe(at(2022, 10, 1));
+  });
+
+  it('leaves lines with a year untouched', () => {
+    const st = new YearlessStitcher({ logYear: 2023, now: NOW });
+    expect(st.apply(at(2026, 0, 1), false)).toBe(at(2026, 0, 1));
+  });
+});
@@ -0,0 +1,51 @@
+// === src/core/config/commandHistory.ts ===
+/**
+ * 명령 줄 실행 이력(워크스페이스 .config 아래, 한 줄에 명령 하나, 오래된 → 최신)
+ * - 같은 명령을 다시 실행하면 기존 줄을 지우고 맨 뒤로 옮긴다.
+ * - 상한(COMMAND_HISTORY_MAX)을 넘으면 가장 오래된 줄부터 버린다.
+ * - 읽기/쓰기 실패는 명령 실행에 영향을 주지 않도록 삼킨다.
+ */
+import * as fsp from 'fs/promises';
+import * as path from 'path';
+
+import { COMMAND_HISTORY_MAX } from '../../shared/const.js';
+import { getLogger } from '../logging/extension-logger.js';
+
+const log = getLogger('commandHistory');
+
+/** 이력에 명령 하나를 더한 새 목록(빈 줄은 무시, 중복은 맨 뒤로) */
+export function rememberCommand(
+  history: readonly string[],
+  line: string,
+  max = COMMAND_HISTORY_MAX,
+): string[] {
+  const text = line.trim();
+  if (!text) return [...history];
+  const next = history.filter((h) => h !== text);
+  next.push(text);
+  return next.slice(-Math.max(1, max));
+}
+
+export async function readCommandHistory(file: string): Promise<string[]> {
+  try {
+    const txt = await fsp.readFile(file, 'utf8');
+    return txt
+      .split(/\r?\n/)
+      .map((s) => s.trim())
+      .filter(Boolean);
+  } catch {
+    return [];
+  }
+}
+
+/** 이력 파일에 명령을 기록하고 갱신된 목록을 돌려준다 */
+export async function appendCommandHistory(file: string, line: string): Promise<string[]> {
+  const next = rememberCommand(await readCommandHistory(file), line);
+  try {
+    await fsp.mkdir(path.dirname(file), { recursive: true });
+    await fsp.writeFile(file, next.join('\n') + '\n', 'utf8');
+  } catch (e) {
+    log.warn(`command history: write failed (${e instanceof Error ? e.message : String(e)})`);
+  }
+  return next;
+}
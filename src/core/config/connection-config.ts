@@ -2,6 +2,8 @@
 import * as fs from 'fs';
 import * as path from 'path';
 
+import { MAX_SSH_PORT, MIN_SSH_PORT } from '../../shared/const.js';
+
 export type ConnectionType = 'ADB' | 'SSH';
 
 export interface AdbDetails {
@@ -14,18 +16,28 @@ export interface SshDetails {
   port: number;
   /** DEV 전용: 평문 저장. 보안 환경에선 저장 금지 또는 외부 시크릿으로 대체 권장 */
   password?: string;
+  /** 비root 로그인: 원격 명령을 `sudo -n`으로 감싸 실행(NOPASSWD sudo 필요) */
+  useSudo?: boolean;
+  /** ~/.ssh/config Host 별칭: 있으면 host/user/port/키를 접속 시점에 ssh config에서 해석 */
+  configAlias?: string;
+  /** keepalive 간격(초, 0 = 끔) — 없으면 ssh config → 환경변수 → 기본값 순 */
+  serverAliveInterval?: number;
+  /** 응답 없는 keepalive를 몇 번까지 참을지 */
+  serverAliveCountMax?: number;
 }
 
 export interface ConnectionInfo {
-  id: string; // e.g. "adb:<serial>" or "ssh:<user>@<host>:<port>"
+  id: string; // e.g. "adb:<serial>", "ssh:<user>@<host>:<port>" or "ssh:config:<Host 별칭>"
   alias?: string;
   type: ConnectionType;
   details: AdbDetails | SshDetails;
-  lastUsed: string; // ISO string
+  lastUsed: string; // ISO string (예전 파일의 Unix 초 문자열은 읽을 때 ISO로 바꾼다)
 }
 
 export interface ConnectionConfigFile {
   recent?: string; // id of last used
+  /** 시작 시 자동 연결 대상(사용자가 명시 지정) — 있으면 recent보다 우선 */
+  default?: string;
   connections: ConnectionInfo[];
   defaultLoggingConfig?: {
     configured?: boolean;
@@ -34,6 +46,40 @@ export interface ConnectionConfigFile {
   };
 }
 
+/**
+ * lastUsed → epoch ms (해석 불가면 0 → 정렬 시 가장 오래된 항목)
+ * - ISO 문자열 / 예전 형식 Unix 초(또는 ms) 숫자 문자열 모두 받는다
+ * - 문자열 비교는 자릿수·형식이 섞이면 순서가 틀어지므로 정렬은 항상 이 값으로
+ */
+export function lastUsedMs(v: string | number | undefined): number {
+  const raw = String(v ?? '').trim();
+  if (!raw) return 0;
+  if (/^\d+$/.test(raw)) {
+    const n = Number(raw);
+    return n >= 1e12 ? n : n * 1000;
+  }
+  const t = Date.parse(raw);
+  return Number.isNaN(t) ? 0 : t;
+}
+
+/** 최근 사용 순(내림차순) 정렬 */
+function sortByLastUsed(cfg: ConnectionConfigFile) {
+  cfg.connections.sort((a, b) => lastUsedMs(b.lastUsed) - lastUsedMs(a.lastUsed));
+}
+
+/** 숫자(Unix 시각) 형식 lastUsed를 ISO 문자열로 통일 — 변경 여부 반환(저장은 다음 save 때) */
+export function migrateLastUsed(cfg: ConnectionConfigFile): boolean {
+  let changed = false;
+  for (const c of cfg.connections) {
+    const raw = String((c.lastUsed as unknown) ?? '').trim();
+    if (!/^\d+$/.test(raw)) continue;
+    c.lastUsed = new Date(lastUsedMs(raw)).toISOString();
+    changed = true;
+  }
+  if (changed) sortByLastUsed(cfg);
+  return changed;
+}
+
 const CONFIG_DIR = '.config';
 const CONFIG_FILE = 'connection_config.json';
 const MAX_CONNECTIONS = 5;
@@ -59,6 +105,7 @@ export async function readConnectionConfig(workspacePath: string): Promise<Conne
     const raw = await fs.promises.readFile(filePath, 'utf8');
     const parsed = JSON.parse(raw) as ConnectionConfigFile;
     if (!parsed.connections) parsed.connections = [];
+    migrateLastUsed(parsed);
     return parsed;
   } catch {
     // fallback to empty on parse error
@@ -90,22 +137,391 @@ export function upsertConnection(
     cfg.connections.unshift(entry);
   }
   // sort by lastUsed desc
-  cfg.connections.sort((a, b) => new Date(b.lastUsed).getTime() - new Date(a.lastUsed).getTime());
+  sortByLastUsed(cfg);
   // cap size
   if (cfg.connections.length > MAX_CONNECTIONS) {
     cfg.connections = cfg.connections.slice(0, MAX_CONNECTIONS);
+    if (cfg.default && !cfg.connections.some((c) => c.id === cfg.default)) delete cfg.default;
   }
   cfg.recent = entry.id;
   return cfg;
 }
 
+/** 저장된 연결 조회: id 정확 일치 → alias(대소문자 무시) 순. alias 중복이면 최근 사용 항목 */
+export function findConnection(cfg: ConnectionConfigFile, key: string): ConnectionInfo | undefined {
+  const k = String(key ?? '').trim();
+  if (!k) return undefined;
+  const lower = k.toLowerCase();
+  return (
+    cfg.connections.find((c) => c.id === k) ??
+    cfg.connections.find((c) => c.alias?.trim().toLowerCase() === lower)
+  );
+}
+
+/**
+ * 자동 연결 대상 지정/해제
+ * - id: 기본 연결로 고정(recent도 함께 맞춘다)
+ * - undefined: 기본/최근 포인터 모두 해제 → 다음 연결 전까지 시작 시 자동 연결 안 함
+ */
+export function setDefaultConnection(cfg: ConnectionConfigFile, id?: string): ConnectionConfigFile {
+  if (id === undefined) {
+    delete cfg.default;
+    delete cfg.recent;
+    return cfg;
+  }
+  if (!cfg.connections.some((c) => c.id === id)) throw new Error(`unknown connection: ${id}`);
+  cfg.default = id;
+  cfg.recent = id;
+  return cfg;
+}
+
+/** 시작 시 자동 연결 대상: default → recent 순(목록에 없는 id는 무시) */
+export function startupConnection(cfg: ConnectionConfigFile): ConnectionInfo | undefined {
+  for (const id of [cfg.default, cfg.recent]) {
+    const hit = id ? cfg.connections.find((c) => c.id === id) : undefined;
+    if (hit) return hit;
+  }
+  return undefined;
+}
+
 export function markRecent(cfg: ConnectionConfigFile, id: string): ConnectionConfigFile {
   const idx = cfg.connections.findIndex((c) => c.id === id);
   if (idx >= 0) {
     cfg.connections[idx].lastUsed = new Date().toISOString();
     cfg.recent = id;
     // keep recency order
-    cfg.connections.sort((a, b) => new Date(b.lastUsed).getTime() - new Date(a.lastUsed).getTime());
+    sortByLastUsed(cfg);
   }
   return cfg;
 }
+
+/** 저장 id 규칙: `adb:<serial>` / `ssh:<user>@<host>:<port>` / `ssh:config:<Host 별칭>` */
+export function connectionId(c: Pick<ConnectionInfo, 'type' | 'details'>): string {
+  if (c.type === 'ADB') return `adb:${(c.details as AdbDetails).deviceID}`;
+  const d = c.details as SshDetails;
+  return d.configAlias ? `ssh:config:${d.configAlias}` : `ssh:${d.user}@${d.host}:${d.port}`;
+}
+
+/**
+ * 연결 편집 메뉴 항목(`connectEdit`) — 유형별로 어떤 필드를 고칠 수 있는지 여기서만 정의한다.
+ * - text/port: 입력값, count: 0 이상 정수, secret: 가려서 입력, flag: 켜기/끄기
+ * - optional: 빈 값으로 지우기 허용(필수 필드는 비울 수 없음)
+ * 새 SSH 옵션이 생기면 SSH_FIELDS에 한 줄 추가하면 메뉴/저장이 따라온다.
+ */
+export type ConnectionField = {
+  key: string;
+  label: string;
+  kind: 'text' | 'port' | 'count' | 'secret' | 'flag';
+  optional?: boolean;
+  get(c: ConnectionInfo): string | boolean | undefined;
+  set(c: ConnectionInfo, v: string | boolean | undefined): void;
+};
+
+const ssh = (c: ConnectionInfo) => c.details as SshDetails;
+/** 빈 문자열/false는 키 자체를 지워 파일에 남기지 않는다 */
+function assign<T extends object>(obj: T, key: keyof T, v: unknown) {
+  if (v === undefined || v === '' || v === false) delete obj[key];
+  else (obj as any)[key] = v;
+}
+
+const ALIAS_FIELD: ConnectionField = {
+  key: 'alias',
+  label: '별칭',
+  kind: 'text',
+  optional: true,
+  get: (c) => c.alias,
+  set: (c, v) => assign(c, 'alias', v),
+};
+const ADB_FIELDS: ConnectionField[] = [
+  {
+    key: 'deviceID',
+    label: 'ADB serial',
+    kind: 'text',
+    get: (c) => (c.details as AdbDetails).deviceID,
+    set: (c, v) => ((c.details as AdbDetails).deviceID = String(v ?? '')),
+  },
+];
+const SSH_FIELDS: ConnectionField[] = [
+  {
+    key: 'configAlias',
+    label: '~/.ssh/config Host 별칭',
+    kind: 'text',
+    optional: true,
+    get: (c) => ssh(c).configAlias,
+    set: (c, v) => assign(ssh(c), 'configAlias', v),
+  },
+  {
+    key: 'host',
+    label: 'Host',
+    kind: 'text',
+    get: (c) => ssh(c).host,
+    set: (c, v) => (ssh(c).host = String(v ?? '')),
+  },
+  {
+    key: 'user',
+    label: 'User',
+    kind: 'text',
+    get: (c) => ssh(c).user,
+    set: (c, v) => (ssh(c).user = String(v ?? '')),
+  },
+  {
+    key: 'port',
+    label: 'Port',
+    kind: 'port',
+    get: (c) => String(ssh(c).port ?? ''),
+    set: (c, v) => (ssh(c).port = Number(v)),
+  },
+  {
+    key: 'password',
+    label: 'Password(평문 저장)',
+    kind: 'secret',
+    optional: true,
+    get: (c) => ssh(c).password,
+    set: (c, v) => assign(ssh(c), 'password', v),
+  },
+  {
+    key: 'useSudo',
+    label: 'sudo -n으로 실행',
+    kind: 'flag',
+    optional: true,
+    get: (c) => !!ssh(c).useSudo,
+    set: (c, v) => assign(ssh(c), 'useSudo', v),
+  },
+  {
+    key: 'serverAliveInterval',
+    label: 'Keepalive 간격(초, 0 = 끔)',
+    kind: 'count',
+    optional: true,
+    get: (c) => ssh(c).serverAliveInterval?.toString(),
+    set: (c, v) => assign(ssh(c), 'serverAliveInterval', v === '' ? v : Number(v)),
+  },
+  {
+    key: 'serverAliveCountMax',
+    label: 'Keepalive 무응답 허용 횟수',
+    kind: 'count',
+    optional: true,
+    get: (c) => ssh(c).serverAliveCountMax?.toString(),
+    set: (c, v) => assign(ssh(c), 'serverAliveCountMax', v === '' ? v : Number(v)),
+  },
+];
+
+export function editableFields(c: ConnectionInfo): ConnectionField[] {
+  return [ALIAS_FIELD, ...(c.type === 'ADB' ? ADB_FIELDS : SSH_FIELDS)];
+}
+
+/** 메뉴 표시용 현재 값(비밀번호는 가림, 빈 값은 `-`) */
+export function formatFieldValue(f: ConnectionField, c: ConnectionInfo): string {
+  const v = f.get(c);
+  if (f.kind === 'flag') return v ? 'on' : 'off';
+  if (v === undefined || v === '') return '-';
+  return f.kind === 'secret' ? '***' : String(v);
+}
+
+/**
+ * 편집한 연결을 저장 목록에 반영 — id는 필드에서 다시 만들고(host/serial 변경 시 id도 바뀜)
+ * recent/default 포인터도 새 id로 옮긴다. 다른 항목과 id가 겹치면 throw
+ */
+export function applyConnectionEdit(
+  cfg: ConnectionConfigFile,
+  oldId: string,
+  edited: ConnectionInfo,
+): ConnectionInfo {
+  const idx = cfg.connections.findIndex((c) => c.id === oldId);
+  if (idx < 0) throw new Error(`unknown connection: ${oldId}`);
+  const next: ConnectionInfo = { ...edited, id: connectionId(edited) };
+  if (next.id !== oldId && cfg.connections.some((c) => c.id === next.id)) {
+    throw new Error(`이미 같은 대상의 연결이 있습니다: ${next.id}`);
+  }
+  cfg.connections[idx] = next;
+  if (cfg.recent === oldId) cfg.recent = next.id;
+  if (cfg.default === oldId) cfg.default = next.id;
+  return next;
+}
+
+/** 접속 대상 요약: ADB `R58M` / SSH `user@host:port` / ssh config `config:<Host>` (비밀번호 미포함) */
+export function connectionTarget(c: ConnectionInfo): string {
+  if (c.type === 'ADB') return (c.details as AdbDetails).deviceID;
+  const d = c.details as SshDetails;
+  return d.configAlias ? `config:${d.configAlias}` : `${d.user}@${d.host}:${d.port}`;
+}
+
+/** 인증 방식 — 저장된 비밀번호는 값 대신 `password(***)`로만 표시 */
+function connectionAuth(c: ConnectionInfo): string {
+  if (c.type === 'ADB') return '-';
+  const d = c.details as SshDetails;
+  if (d.password) return 'password(***)';
+  return d.configAlias ? 'ssh-config' : 'key';
+}
+
+/** lastUsed → `YYYY-MM-DD HH:mm:ss`(로컬 시각). 해석할 수 없으면 원문 그대로 */
+export function formatLastUsed(v: string | undefined): string {
+  const raw = String(v ?? '').trim();
+  if (!raw) return '-';
+  const ms = lastUsedMs(raw);
+  if (!ms) return raw;
+  const d = new Date(ms);
+  const p = (n: number) => String(n).padStart(2, '0');
+  return (
+    `${d.getFullYear()}-${p(d.getMonth() + 1)}-${p(d.getDate())} ` +
+    `${p(d.getHours())}:${p(d.getMinutes())}:${p(d.getSeconds())}`
+  );
+}
+
+/** 저장된 연결 전체 표(헤더 포함) — flags: active / recent / default / sudo */
+export function formatConnectionTable(cfg: ConnectionConfigFile, activeId?: string): string[] {
+  const rows = cfg.connections.map((c) => {
+    const flags = [
+      c.id === activeId && 'active',
+      c.id === cfg.recent && 'recent',
+      c.id === cfg.default && 'default',
+      c.type === 'SSH' && (c.details as SshDetails).useSudo && 'sudo',
+    ].filter(Boolean);
+    return [
+      c.alias || '-',
+      c.type,
+      connectionTarget(c),
+      connectionAuth(c),
+      formatLastUsed(c.lastUsed),
+      flags.join(',') || '-',
+    ];
+  });
+  const table = [['alias', 'type', 'target', 'auth', 'last used', 'flags'], ...rows];
+  const widths = table[0].map((_, i) => Math.max(...table.map((r) => r[i].length)));
+  return table.map((r) =>
+    r
+      .map((cell, i) => (i === r.length - 1 ? cell : cell.padEnd(widths[i] + 2)))
+      .join('')
+      .trimEnd(),
+  );
+}
+
+/**
+ * 진단용 읽기(`connectConfig show|validate`) — readConnectionConfig와 달리 파일을 만들거나
+ * 깨진 파일을 빈 설정으로 덮어쓰지 않고, JSON 오류를 그대로 돌려준다
+ */
+export async function inspectConnectionConfig(
+  workspacePath: string,
+): Promise<{ file: string; cfg?: ConnectionConfigFile; error?: string }> {
+  const file = getConfigFilePath(workspacePath);
+  let raw: string;
+  try {
+    raw = await fs.promises.readFile(file, 'utf8');
+  } catch {
+    return { file, error: '파일이 없습니다 (첫 연결 시 생성됩니다)' };
+  }
+  try {
+    return { file, cfg: JSON.parse(raw) as ConnectionConfigFile };
+  } catch (e) {
+    return { file, error: `JSON 파싱 실패: ${e instanceof Error ? e.message : String(e)}` };
+  }
+}
+
+/** 출력용 사본 — SSH 비밀번호는 값 대신 `***` */
+export function redactConnectionConfig(cfg: ConnectionConfigFile): ConnectionConfigFile {
+  const copy = JSON.parse(JSON.stringify(cfg)) as ConnectionConfigFile;
+  for (const c of Array.isArray(copy.connections) ? copy.connections : []) {
+    const d = c?.details as SshDetails | undefined;
+    if (d?.password) d.password = '***';
+  }
+  return copy;
+}
+
+/** 기본 로깅 설정 소스 값 형식: `file:<원격 경로>` 또는 `cmd:<명령>` */
+const LOG_SOURCE_RE = /^(file|cmd):\s*\S/;
+
+/** defaultLoggingConfig.log_sources 중 `file:` 소스의 원격 경로(선언 순서, 중복 제거) */
+export function logSourceFilePaths(cfg: ConnectionConfigFile): string[] {
+  const out: string[] = [];
+  for (const v of Object.values(cfg.defaultLoggingConfig?.log_sources ?? {})) {
+    const m = String(v ?? '').match(/^file:\s*(\S.*?)\s*$/);
+    if (m && !out.includes(m[1])) out.push(m[1]);
+  }
+  return out;
+}
+
+/**
+ * 설정 불변식 점검 — 문제마다 "무엇이 / 어떻게 고칠지" 한 줄씩(문제 없으면 빈 배열)
+ * - recent/default가 목록에 있는 id를 가리키는지, id 중복, 연결별 필수 필드
+ * - defaultLoggingConfig: 소스 값 형식(file:/cmd:), log_types ↔ log_sources 일치
+ */
+export function validateConnectionConfig(cfg: ConnectionConfigFile): string[] {
+  const problems: string[] = [];
+  if (!Array.isArray(cfg.connections)) {
+    return ['connections가 배열이 아닙니다 — `"connections": []`로 고치세요'];
+  }
+
+  const ids = new Map<string, number>();
+  cfg.connections.forEach((c, i) => {
+    const at = `connections[${i}]${c?.id ? ` (${c.id})` : ''}`;
+    if (!c?.id) problems.push(`${at}: id가 없습니다 — 항목을 지우고 다시 연결하세요`);
+    else ids.set(c.id, (ids.get(c.id) ?? 0) + 1);
+
+    if (c?.type === 'ADB') {
+      if (!(c.details as AdbDetails)?.deviceID) {
+        problems.push(`${at}: ADB deviceID가 없습니다 — \`adb devices\`의 serial로 채우세요`);
+      }
+    } else if (c?.type === 'SSH') {
+      const d = (c.details ?? {}) as SshDetails;
+      if (!d.configAlias) {
+        if (!d.host) problems.push(`${at}: SSH host가 없습니다`);
+        if (!d.user) problems.push(`${at}: SSH user가 없습니다`);
+        const port = Number(d.port);
+        if (!Number.isInteger(port) || port < MIN_SSH_PORT || port > MAX_SSH_PORT) {
+          problems.push(`${at}: SSH port가 잘못됐습니다(${d.port}) — ${MIN_SSH_PORT}~${MAX_SSH_PORT}`);
+        }
+      }
+      for (const key of ['serverAliveInterval', 'serverAliveCountMax'] as const) {
+        const v = d[key];
+        if (v !== undefined && !(Number.isInteger(v) && v >= 0)) {
+          problems.push(`${at}: ${key}는 0 이상의 정수여야 합니다(${String(v)})`);
+        }
+      }
+    } else {
+      problems.push(`${at}: type은 ADB 또는 SSH여야 합니다(현재 ${String(c?.type)})`);
+    }
+    if (c?.lastUsed && !lastUsedMs(c.lastUsed)) {
+      problems.push(`${at}: lastUsed를 해석할 수 없습니다(${c.lastUsed}) — ISO 시각으로 고치세요`);
+    }
+  });
+  for (const [id, n] of ids) {
+    if (n > 1) problems.push(`id '${id}'가 ${n}번 있습니다 — 하나만 남기세요`);
+  }
+  for (const key of ['recent', 'default'] as const) {
+    const id = cfg[key];
+    if (id && !ids.has(id)) {
+      problems.push(`${key}가 없는 연결을 가리킵니다(${id}) — connectDefault로 다시 지정하세요`);
+    }
+  }
+
+  const lc = cfg.defaultLoggingConfig;
+  if (lc) {
+    const types = Array.isArray(lc.log_types) ? lc.log_types : [];
+    const sources = lc.log_sources ?? {};
+    if (lc.log_types !== undefined && !Array.isArray(lc.log_types)) {
+      problems.push('defaultLoggingConfig.log_types가 배열이 아닙니다');
+    }
+    const seen = new Set<string>();
+    for (const t of types) {
+      if (seen.has(t)) problems.push(`defaultLoggingConfig.log_types: '${t}' 중복`);
+      seen.add(t);
+      if (!(t in sources)) {
+        problems.push(`defaultLoggingConfig: log_types의 '${t}'에 해당하는 log_sources가 없습니다`);
+      }
+    }
+    for (const [name, src] of Object.entries(sources)) {
+      if (!LOG_SOURCE_RE.test(String(src ?? ''))) {
+        problems.push(
+          `defaultLoggingConfig.log_sources.${name}: 'file:<경로>' 또는 'cmd:<명령>' 형식이어야 합니다` +
+            ` (현재 ${JSON.stringify(src)})`,
+        );
+      }
+      if (!seen.has(name)) {
+        problems.push(`defaultLoggingConfig: log_sources의 '${name}'이 log_types에 없습니다`);
+      }
+    }
+    if (lc.configured && !types.length) {
+      problems.push('defaultLoggingConfig: configured=true인데 log_types가 비어 있습니다');
+    }
+  }
+  return problems;
+}
@@ -0,0 +1,74 @@
+// === src/core/config/homeyLayout.ts ===
+import * as fsp from 'fs/promises';
+import * as path from 'path';
+
+import {
+  DEFAULT_HOMEY_LAYOUT,
+  type HomeyKind,
+  type HomeyLayout,
+  USERCFG_REL,
+} from '../../shared/const.js';
+import { getLogger } from '../logging/extension-logger.js';
+import type { HomeyLayoutOverride, HomeyUserConfig } from './userconfig.js';
+
+const log = getLogger('homeyLayout');
+
+/** local_dirs 값은 워크스페이스 바로 아래 폴더 이름 하나여야 한다(빈 값/절대·상위 경로 금지) */
+function isSafeLocalDir(v: unknown): v is string {
+  if (typeof v !== 'string') return false;
+  const s = v.trim();
+  return !!s && s !== '.' && s !== '..' && !/[\\/]/.test(s);
+}
+
+/**
+ * 기본값 ← 오버라이드 순으로 얕은(카테고리 단위) 병합
+ * - local_dirs에서 알 수 없는 카테고리/잘못된 이름은 경고 후 무시(이전 값 유지)
+ */
+export function mergeHomeyLayout(
+  base: HomeyLayout,
+  ...overrides: (HomeyLayoutOverride | undefined)[]
+): HomeyLayout {
+  let out: HomeyLayout = {
+    docker_root_fallback: base.docker_root_fallback,
+    volumes: { ...base.volumes },
+    paths: { ...base.paths },
+    local_dirs: { ...base.local_dirs },
+  };
+  for (const o of overrides) {
+    if (!o) continue;
+    const localDirs = { ...out.local_dirs };
+    for (const [kind, dir] of Object.entries(o.local_dirs ?? {})) {
+      if (kind in localDirs && isSafeLocalDir(dir)) {
+        localDirs[kind as HomeyKind] = dir.trim();
+      } else {
+        log.warn(`[warn] homey_layout.local_dirs.${kind} 무시: ${JSON.stringify(dir)}`);
+      }
+    }
+    out = {
+      docker_root_fallback: o.docker_root_fallback || out.docker_root_fallback,
+      volumes: { ...out.volumes, ...(o.volumes ?? {}) } as HomeyLayout['volumes'],
+      paths: { ...out.paths, ...(o.paths ?? {}) },
+      local_dirs: localDirs,
+    };
+  }
+  return out;
+}
+
+/**
+ * 워크스페이스의 custom_user_config.json에서 레이아웃을 읽는다.
+ * - homey_layout(전역) → homey_layout_by_connection[connId] 순으로 덮어씀
+ * - 파일이 없거나 깨졌으면 기본값
+ */
+export async function loadHomeyLayout(workspaceFs: string, connId?: string): Promise<HomeyLayout> {
+  let cfg: HomeyUserConfig = {};
+  try {
+    const txt = await fsp.readFile(path.join(workspaceFs, ...USERCFG_REL.split('/')), 'utf8');
+    cfg = (JSON.parse(txt) ?? {}) as HomeyUserConfig;
+  } catch {
+    // 설정 파일 없음/파싱 실패 → 기본값
+  }
+  const perConn = connId ? cfg.homey_layout_by_connection?.[connId] : undefined;
+  const layout = mergeHomeyLayout(DEFAULT_HOMEY_LAYOUT, cfg.homey_layout, perConn);
+  log.debug('[debug] loadHomeyLayout', { connId, perConn: !!perConn });
+  return layout;
+}
@@ -54,9 +54,35 @@ export type ParserPreflight = {
   /** 여기 패턴 중 하나라도 샘플에서 매칭되면 커스텀 파서 비활성화 */
   hard_skip_if_any_line_matches?: string[];
 };
+export type ParserTimezoneCorrection = {
+  /** false면 타임존/단조 보정을 수행하지 않음(기본 true) */
+  enabled?: boolean;
+  /** 점프 의심 임계(시간, 기본 3) */
+  min_jump_hours?: number;
+  /** 복귀 판정 최소 차이(시간, 기본 1) */
+  min_return_hours?: number;
+  /** 보정 상한(시간, 기본 14) */
+  max_offset_hours?: number;
+  /** 고정 오프셋(시간, 예: 5.5). 미지정 시 로그에서 실측 */
+  offset_hours?: number;
+  /** 작은 역전 허용 폭(ms, 기본 1500) */
+  small_delta_ms?: number;
+};
+export type ParserConfigure = {
+  memory_mode_threshold?: number;
+  /** 연도 없는 포맷(예: "Jan 02 10:00:00")의 최신 라인 연도. 미지정 시 미래 날짜면 작년으로 간주 */
+  log_year?: number;
+  timezone_correction?: ParserTimezoneCorrection;
+  /**
+   * 규칙으로 파싱한 라인은 레벨 필드가 없으므로 메시지 키워드로 레벨을 추정(기본 true).
+   * false면 모두 I — 키워드 목록은 TimeParser.guessLevel 참고
+   */
+  level_from_message?: boolean;
+};
 export type ParserConfig = {
   version?: number;
   requirements?: ParserRequirements;
   preflight?: ParserPreflight;
+  configure?: ParserConfigure;
   parser: ParserRule[];
 };
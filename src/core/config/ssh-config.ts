@@ -0,0 +1,181 @@
+// === src/core/config/ssh-config.ts ===
+/**
+ * ~/.ssh/config Host 별칭 해석(내장 ssh2 클라이언트용)
+ * - OpenSSH 규칙: 매칭되는 Host 블록을 위에서부터 보고, 키마다 "처음 나온 값"이 이긴다.
+ * - 지원 키: HostName / User / Port / IdentityFile / ProxyJump /
+ *   ServerAliveInterval / ServerAliveCountMax (그 외·Match·Include는 무시)
+ * - ProxyJump는 내장 클라이언트가 지원하지 않으므로 명확한 오류로 알린다.
+ */
+import * as fs from 'fs';
+import * as os from 'os';
+import * as path from 'path';
+
+import { ErrorCategory, XError } from '../../shared/errors.js';
+import type { SshDetails } from './connection-config.js';
+
+export type SshConfigHost = {
+  hostName?: string;
+  user?: string;
+  port?: number;
+  identityFile?: string;
+  proxyJump?: string;
+  serverAliveInterval?: number;
+  serverAliveCountMax?: number;
+};
+
+/** 실제 접속에 쓰는 값(별칭이면 ssh config에서 해석한 결과) */
+export type SshTarget = {
+  host: string;
+  user: string;
+  port: number;
+  password?: string;
+  keyPath?: string;
+  /** keepalive 간격(초)/허용 횟수 — 연결 설정 > ssh config, 둘 다 없으면 undefined(기본값 사용) */
+  aliveIntervalS?: number;
+  aliveCountMax?: number;
+};
+
+type Block = { patterns: string[]; opts: Map<string, string> };
+
+const KEYS = new Set([
+  'hostname',
+  'user',
+  'port',
+  'identityfile',
+  'proxyjump',
+  'serveraliveinterval',
+  'serveralivecountmax',
+]);
+
+/** 0 이상의 정수만(그 외는 undefined) */
+function nonNegativeInt(v: unknown): number | undefined {
+  const n = typeof v === 'string' && v.trim() ? Number(v) : v;
+  return typeof n === 'number' && Number.isInteger(n) && n >= 0 ? n : undefined;
+}
+
+export function userSshConfigPath(): string {
+  return path.join(os.homedir(), '.ssh', 'config');
+}
+
+/** 없거나 읽을 수 없으면 빈 문자열 */
+export function readUserSshConfig(file = userSshConfigPath()): string {
+  try {
+    return fs.readFileSync(file, 'utf8');
+  } catch {
+    return '';
+  }
+}
+
+function parseBlocks(text: string): Block[] {
+  // Host 이전의 전역 설정은 `Host *`와 같은 취급
+  const blocks: Block[] = [{ patterns: ['*'], opts: new Map() }];
+  for (const raw of text.split(/\r?\n/)) {
+    const line = raw.trim();
+    if (!line || line.startsWith('#')) continue;
+    const m = /^(\S+?)(?:\s*=\s*|\s+)(.+)$/.exec(line);
+    if (!m) continue;
+    const key = m[1].toLowerCase();
+    const value = m[2].trim().replace(/^"(.*)"$/, '$1');
+    if (key === 'host') {
+      blocks.push({ patterns: value.split(/\s+/), opts: new Map() });
+    } else if (key === 'match') {
+      // Match 조건은 해석하지 않음 → 해당 블록 전체를 건너뜀
+      blocks.push({ patterns: [], opts: new Map() });
+    } else if (KEYS.has(key)) {
+      const cur = blocks[blocks.length - 1];
+      if (!cur.opts.has(key)) cur.opts.set(key, value);
+    }
+  }
+  return blocks;
+}
+
+function globToRegExp(glob: string): RegExp {
+  const body = glob.replace(/[.+^${}()|[\]\\]/g, '\\$&').replace(/\*/g, '.*').replace(/\?/g, '.');
+  return new RegExp(`^${body}$`, 'i');
+}
+
+function matchesHost(patterns: string[], alias: string): boolean {
+  let hit = false;
+  for (const p of patterns) {
+    if (p.startsWith('!')) {
+      if (globToRegExp(p.slice(1)).test(alias)) return false;
+    } else if (globToRegExp(p).test(alias)) {
+      hit = true;
+    }
+  }
+  return hit;
+}
+
+/** 별칭에 매칭되는 블록이 하나도 없으면 undefined */
+export function resolveSshConfigHost(alias: string, text: string): SshConfigHost | undefined {
+  const merged = new Map<string, string>();
+  let named = false;
+  for (const b of parseBlocks(text)) {
+    if (!matchesHost(b.patterns, alias)) continue;
+    if (!b.patterns.includes('*')) named = true;
+    for (const [k, v] of b.opts) if (!merged.has(k)) merged.set(k, v);
+  }
+  if (!named) return undefined;
+  const port = Number(merged.get('port'));
+  return {
+    hostName: merged.get('hostname')?.replace(/%h/g, alias),
+    user: merged.get('user'),
+    port: Number.isInteger(port) && port > 0 ? port : undefined,
+    identityFile: merged.get('identityfile'),
+    proxyJump: merged.get('proxyjump'),
+    serverAliveInterval: nonNegativeInt(merged.get('serveraliveinterval')),
+    serverAliveCountMax: nonNegativeInt(merged.get('serveralivecountmax')),
+  };
+}
+
+/** 선택 목록용: 와일드카드/부정 패턴이 아닌 Host 이름들(등장 순서, 중복 제거) */
+export function listSshConfigAliases(text: string): string[] {
+  const out = new Set<string>();
+  for (const b of parseBlocks(text)) {
+    for (const p of b.patterns) if (!/[*?!]/.test(p)) out.add(p);
+  }
+  return [...out];
+}
+
+function expandHome(p: string): string {
+  if (p === '~' || p.startsWith('~/')) return path.join(os.homedir(), p.slice(1));
+  return p.replace(/%d/g, os.homedir());
+}
+
+/**
+ * 저장된 SSH 상세 → 실제 접속 대상
+ * - configAlias가 없으면 저장값 그대로
+ * - 있으면 접속 시점마다 ssh config를 다시 읽어(편집 반영) host/user/port/키를 채운다
+ */
+export function resolveSshTarget(d: SshDetails, configText?: string): SshTarget {
+  const alive = {
+    aliveIntervalS: nonNegativeInt(d.serverAliveInterval),
+    aliveCountMax: nonNegativeInt(d.serverAliveCountMax),
+  };
+  if (!d.configAlias) {
+    return { host: d.host, user: d.user, port: d.port ?? 22, password: d.password, ...alive };
+  }
+  const alias = d.configAlias;
+  const c = resolveSshConfigHost(alias, configText ?? readUserSshConfig());
+  if (!c) {
+    throw new XError(
+      ErrorCategory.Connection,
+      `~/.ssh/config에 Host '${alias}' 항목이 없습니다.`,
+    );
+  }
+  if (c.proxyJump && c.proxyJump.toLowerCase() !== 'none') {
+    throw new XError(
+      ErrorCategory.Connection,
+      `Host '${alias}'의 ProxyJump(${c.proxyJump})는 지원하지 않습니다. 직접 접속 가능한 Host를 사용하세요.`,
+    );
+  }
+  return {
+    host: c.hostName ?? alias,
+    user: c.user || d.user || os.userInfo().username,
+    port: c.port ?? 22,
+    password: d.password,
+    keyPath: c.identityFile ? expandHome(c.identityFile) : undefined,
+    aliveIntervalS: alive.aliveIntervalS ?? c.serverAliveInterval,
+    aliveCountMax: alive.aliveCountMax ?? c.serverAliveCountMax,
+  };
+}
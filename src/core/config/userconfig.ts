@@ -2,7 +2,7 @@
 import * as path from 'path';
 import * as vscode from 'vscode';
 
-import { USERCFG_REL } from '../../shared/const.js';
+import { type HomeyLayout, USERCFG_REL } from '../../shared/const.js';
 import { getLogger } from '../logging/extension-logger.js';
 import { measureBlock } from '../logging/perf.js';
 import { resolveWorkspaceInfo } from './userdata.js';
@@ -13,6 +13,17 @@ export type HomeyUserConfig = {
   homey_service_file_path?: string; // 기본 /lib/systemd/system/
   homey_service_file_name?: string; // 기본 homey-pro@.service
   homey_service_name?: string; // 탐지된 유닛명 저장(선택)
+  /** 기기 레이아웃 오버라이드(펌웨어별 경로 차이) — 생략 항목은 DEFAULT_HOMEY_LAYOUT */
+  homey_layout?: HomeyLayoutOverride;
+  /** 연결 id별 레이아웃 오버라이드(전역 homey_layout 위에 덮어씀) */
+  homey_layout_by_connection?: Record<string, HomeyLayoutOverride>;
+};
+
+export type HomeyLayoutOverride = {
+  docker_root_fallback?: string;
+  volumes?: Partial<HomeyLayout['volumes']>;
+  paths?: HomeyLayout['paths'];
+  local_dirs?: Partial<HomeyLayout['local_dirs']>;
 };
 
 export async function readUserHomeyConfig(ctx: vscode.ExtensionContext): Promise<HomeyUserConfig> {
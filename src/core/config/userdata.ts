@@ -32,6 +32,22 @@ export type AppConfigFile = {
     highlightWords?: { color: string; text: string }[]; // 최대 5개 (색상 슬롯+텍스트)
     columnWidths?: number[];
     theme?: 'light' | 'dark';
+    /** 이름 → 필터(pid/src/proc/msg/exclude) 프리셋 */
+    filterPresets?: Record<string, Record<string, string>>;
+    /** 실시간 시작 시 함께 보여줄 직전 로그 라인 수(0 = 연결 시점부터) */
+    realtimeTail?: number;
+    /** 실시간 세션 청크 보관 개수 상한(0 = 무제한) */
+    realtimeMaxChunks?: number;
+    /** 실시간 세션 청크 총 용량 상한(bytes, 0 = 무제한) */
+    realtimeMaxBytes?: number;
+    /** 실시간 로그를 세션 간 보존(다음 실시간 시작 시 이전 로그에 이어서 기록·표시) */
+    realtimePreserve?: boolean;
+    /** 실시간 연속 중복 라인 합치기(반복 횟수 표시 + `last message repeated N times` 기록) */
+    realtimeCoalesce?: boolean;
+    /** 실시간 배치 전송 최소 간격(ms) — 작을수록 드문 로그의 지연이 짧다 */
+    realtimeFlushIntervalMs?: number;
+    /** 실시간 배치 한 번에 보낼 최대 라인 수(버스트 시 이 수에 닿으면 즉시 전송) */
+    realtimeFlushMaxLines?: number;
     [k: string]: Json | undefined;
   };
   /** 그 외 확장 전역 설정 값들 */
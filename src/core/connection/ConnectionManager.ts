@@ -1,9 +1,22 @@
-import { ErrorCategory, XError } from '../../shared/errors.js';
-import type { ConnectionInfo } from '../config/connection-config.js';
+import { RELINK_BACKOFF_MAX_MS, RELINK_BACKOFF_MIN_MS } from '../../shared/const.js';
+import { ErrorCategory, notConnectedError, XError } from '../../shared/errors.js';
+import type { ConnectionInfo, SshDetails } from '../config/connection-config.js';
+import { resolveSshTarget } from '../config/ssh-config.js';
+import { isCommandTraceEnabled } from '../logging/command-trace.js';
 import { getLogger } from '../logging/extension-logger.js';
 import { measure } from '../logging/perf.js';
-import { adbShell, adbStream, getState as adbGetState } from './adbClient.js';
+import { registerSecret, unregisterSecret } from '../logging/redactor.js';
+import {
+  adbRemount,
+  adbRoot,
+  adbShell,
+  adbStream,
+  getState as adbGetState,
+  waitForDevice as adbWaitForDevice,
+} from './adbClient.js';
+import { throwIfAborted } from './deadline.js';
 import { execQuickCheck as sshQuickCheck, sshRun, sshStream } from './sshClient.js';
+import { isSudoPasswordError, needsSudo, SUDO_PASSWORD_MESSAGE, wrapSudo } from './sudo.js';
 export type HostConfig =
   | {
       id: string;
@@ -13,7 +26,12 @@ export type HostConfig =
       user: string;
       keyPath?: string;
       password?: string;
+      /** 비root 로그인에서 원격 명령을 `sudo -n`으로 감쌀지 */
+      sudo?: boolean;
       timeoutMs?: number;
+      /** keepalive 간격(초)/허용 횟수 — 미지정 시 sshClient 기본값 */
+      aliveIntervalS?: number;
+      aliveCountMax?: number;
     }
   | { id: string; type: 'adb'; serial?: string; timeoutMs?: number };
 
@@ -23,15 +41,37 @@ export type RunResult = {
   stderr: string;
 };
 
+/** run 옵션 — signal: 취소/전체 마감(abort 시 사유가 XError면 그대로 던진다) */
+export type RunOptions = { signal?: AbortSignal };
+
+/** 원격 셸 실행 seam — 태스크/가드/패처가 이것만 의존하도록 해 테스트에서 가짜로 교체 */
+export type ShellExecutor = Pick<IConnectionManager, 'run'>;
+
+/** 활성 연결 변경 알림(next=undefined면 연결 해제) */
+export type ConnectionChangeListener = (next?: ConnectionInfo, prev?: ConnectionInfo) => void;
+
+/** ADB 권한 상승 결과(adb root / adb remount) — reason은 실패 사유 */
+export type AdbElevation = { root: boolean; remounted: boolean; reason?: string };
+
 export interface IConnectionManager {
   connect(): Promise<void>; // 유지: (호환) 경량 프리체크
   isConnected(): boolean;
   getSnapshot(): { active?: ConnectionInfo; healthy?: boolean; lastCheckedAt?: number };
   setActive(info: ConnectionInfo): void;
   setRecentLoader(loader: () => Promise<ConnectionInfo | undefined>): void;
+  onDidChangeConnection(listener: ConnectionChangeListener): { dispose(): void };
+  getGeneration(): number;
   checkHealth(info?: ConnectionInfo, abort?: AbortSignal): Promise<boolean>;
-  run(cmd: string, args?: string[]): Promise<RunResult>;
-  stream(cmd: string, onLine: (line: string) => void, abort?: AbortSignal): Promise<void>;
+  waitUntilLive(timeoutMs: number, signal?: AbortSignal): Promise<boolean>;
+  run(cmd: string, args?: string[], opts?: RunOptions): Promise<RunResult>;
+  checkCommands(names: string[]): Promise<Record<string, boolean>>;
+  ensureAdbRoot(): Promise<AdbElevation | undefined>;
+  stream(
+    cmd: string,
+    onLine: (line: string) => void,
+    abort?: AbortSignal,
+    onErrLine?: (line: string) => void,
+  ): Promise<number | null>;
   dispose(): void;
 }
 
@@ -41,7 +81,12 @@ export class ConnectionManager implements IConnectionManager {
   private active?: ConnectionInfo;
   private healthy?: boolean;
   private lastCheckedAt?: number;
+  // 연결 전환 세대 카운터 + 구독자(연결 단위 캐시 무효화용)
+  private generation = 0;
+  private changeListeners = new Set<ConnectionChangeListener>();
   private recentLoader?: () => Promise<ConnectionInfo | undefined>;
+  // adb root/remount 결과(연결 세대 단위로 1회만 시도)
+  private adbElevation?: { generation: number; result: Promise<AdbElevation> };
 
   // 싱글톤 사용을 위해 기본 생성자
   constructor() {}
@@ -83,11 +128,38 @@ export class ConnectionManager implements IConnectionManager {
 
   @measure()
   setActive(info: ConnectionInfo) {
+    const prev = this.active;
+    // 이전 연결 비밀번호는 마스킹 목록에서 제거, 새 비밀번호 등록
+    unregisterSecret((prev?.details as any)?.password);
+    registerSecret((info.details as any)?.password);
     this.active = info;
     this.connected = true;
     this.healthy = undefined;
     this.lastCheckedAt = undefined;
     this.log.info(`[info] active connection set: ${info.id}`);
+    if (prev?.id !== info.id) this.notifyChange(info, prev);
+  }
+
+  /** 활성 연결이 바뀔 때 호출될 리스너 등록 — 반환값 dispose()로 해제 */
+  onDidChangeConnection(listener: ConnectionChangeListener) {
+    this.changeListeners.add(listener);
+    return { dispose: () => void this.changeListeners.delete(listener) };
+  }
+
+  /** 연결 전환 세대(전환될 때마다 1 증가) — 캐시에 함께 저장해 두고 비교하는 용도 */
+  getGeneration() {
+    return this.generation;
+  }
+
+  private notifyChange(next?: ConnectionInfo, prev?: ConnectionInfo) {
+    this.generation++;
+    for (const fn of Array.from(this.changeListeners)) {
+      try {
+        fn(next, prev);
+      } catch (e) {
+        this.log.warn(`connection change listener failed: ${String(e)}`);
+      }
+    }
   }
 
   @measure()
@@ -101,15 +173,20 @@ export class ConnectionManager implements IConnectionManager {
       const serial = (info.details as any)?.deviceID;
       return { id: info.id, type: 'adb', serial, timeoutMs: 15000 };
     } else {
-      const d = info.details as any;
+      const d = info.details as SshDetails;
+      const t = resolveSshTarget(d);
       return {
         id: info.id,
         type: 'ssh',
-        host: d.host,
-        port: d.port,
-        user: d.user,
-        password: d.password,
+        host: t.host,
+        port: t.port,
+        user: t.user,
+        keyPath: t.keyPath,
+        password: t.password,
+        sudo: needsSudo({ user: t.user, useSudo: d.useSudo }),
         timeoutMs: 15000,
+        aliveIntervalS: t.aliveIntervalS,
+        aliveCountMax: t.aliveCountMax,
       };
     }
   }
@@ -123,50 +200,103 @@ export class ConnectionManager implements IConnectionManager {
       const serial = (target.details as any)?.deviceID;
       ok = serial ? (await adbGetState(serial, { signal: abort })) === 'device' : false;
     } else {
-      const d = target.details as any;
-      ok = await sshQuickCheck({
-        host: d.host,
-        user: d.user,
-        port: d.port,
-        password: d.password,
-        timeoutMs: 5000,
-        signal: abort,
-      });
+      try {
+        const t = resolveSshTarget(target.details as SshDetails);
+        ok = await sshQuickCheck({ ...t, timeoutMs: 5000, signal: abort });
+      } catch (e) {
+        this.log.warn(`[warn] checkHealth: ${e instanceof Error ? e.message : String(e)}`);
+      }
     }
     this.healthy = ok;
     this.lastCheckedAt = Date.now();
     return ok;
   }
 
+  /**
+   * 재시작 경계 넘기: 활성 연결이 다시 명령을 받을 수 있을 때까지 대기(timeoutMs 안에 못 돌아오면 false)
+   * - ADB: `adb wait-for-device`처럼 'device' 상태를 기다린 뒤 셸 왕복(`echo ok`)까지 확인
+   * - SSH: 빠른 접속 확인을 backoff(RELINK_BACKOFF_MIN_MS → ×2 → MAX)로 반복
+   */
+  @measure()
+  async waitUntilLive(timeoutMs: number, signal?: AbortSignal): Promise<boolean> {
+    if (!this.active) throw notConnectedError();
+    const deadline = Date.now() + timeoutMs;
+    let delay = RELINK_BACKOFF_MIN_MS;
+    while (Date.now() < deadline) {
+      throwIfAborted(signal);
+      if (await this.probeLive(deadline - Date.now(), signal).catch(() => false)) {
+        this.healthy = true;
+        this.lastCheckedAt = Date.now();
+        return true;
+      }
+      const wait = Math.min(delay, Math.max(0, deadline - Date.now()));
+      await new Promise((r) => setTimeout(r, wait));
+      delay = Math.min(delay * 2, RELINK_BACKOFF_MAX_MS);
+    }
+    this.healthy = false;
+    this.lastCheckedAt = Date.now();
+    this.log.warn(`[warn] waitUntilLive: ${this.active.id} not back within ${timeoutMs}ms`);
+    return false;
+  }
+
+  private async probeLive(remainMs: number, signal?: AbortSignal): Promise<boolean> {
+    const cfg = this.toHostConfig(this.active!);
+    if (cfg.type === 'adb') {
+      if (!cfg.serial || !(await adbWaitForDevice(cfg.serial, remainMs, signal))) return false;
+      const r = await adbShell('echo ok', { serial: cfg.serial, timeoutMs: 5000, signal });
+      return r.code === 0 && r.stdout.trim() === 'ok';
+    }
+    return sshQuickCheck({ ...cfg, timeoutMs: 5000, signal });
+  }
+
+  /** 명령 추적(EDGE_TOOL_DEBUG/토글)이 켜져 있을 때만 실제 실행 명령을 info로 기록(비밀번호 제외) */
+  private traceCommand(kind: 'run' | 'stream', cfg: any, cmd: string) {
+    if (!isCommandTraceEnabled()) return;
+    const target =
+      cfg?.type === 'adb'
+        ? `adb -s ${cfg.serial ?? '?'} shell`
+        : `ssh ${cfg?.user ? `${cfg.user}@` : ''}${cfg?.host ?? '?'}:${cfg?.port ?? 22}`;
+    this.log.info(`[trace] ${kind} ${target} :: ${cmd}`);
+  }
+
   @measure()
-  async run(cmd: string, args: string[] = []): Promise<RunResult> {
+  async run(cmd: string, args: string[] = [], opts: RunOptions = {}): Promise<RunResult> {
     const via = this.active?.type ?? 'NONE';
+    const { signal } = opts;
     try {
+      throwIfAborted(signal);
       const full = [cmd, ...args].join(' ').trim();
       if (!this.active) {
-        throw new XError(
-          ErrorCategory.Connection,
-          'No active connection. Please connect a device.',
-        );
+        throw notConnectedError();
       }
       const cfg = this.toHostConfig(this.active);
+      this.traceCommand('run', cfg, full);
       if (cfg.type === 'adb') {
         this.log.debug('[debug] run(ADB) exec', { serial: cfg.serial, full });
-        const res = await adbShell(full, { serial: cfg.serial, timeoutMs: cfg.timeoutMs });
+        const res = await adbShell(full, { serial: cfg.serial, timeoutMs: cfg.timeoutMs, signal });
         return { code: res.code, stdout: res.stdout, stderr: res.stderr };
       }
-      const { code, stdout, stderr } = await sshRun(full, {
-        host: (cfg as any).host,
-        port: (cfg as any).port,
-        user: (cfg as any).user,
-        password: (cfg as any).password,
-        timeoutMs: (cfg as any).timeoutMs,
+      const { code, stdout, stderr } = await sshRun(cfg.sudo ? wrapSudo(full) : full, {
+        host: cfg.host,
+        port: cfg.port,
+        user: cfg.user,
+        keyPath: cfg.keyPath,
+        password: cfg.password,
+        timeoutMs: cfg.timeoutMs,
+        aliveIntervalS: cfg.aliveIntervalS,
+        aliveCountMax: cfg.aliveCountMax,
+        signal,
       });
+      if (code !== 0 && isSudoPasswordError(stderr)) {
+        throw new XError(ErrorCategory.Permission, SUDO_PASSWORD_MESSAGE);
+      }
       return { code, stdout, stderr };
     } catch (e) {
       this.log.error(`[debug] ConnectionManager.run: error`, {
         message: e instanceof Error ? e.message : String(e),
       });
+      throwIfAborted(signal);
+      if (e instanceof XError) throw e;
       throw new XError(
         ErrorCategory.Connection,
         `Command failed: ${e instanceof Error ? e.message : String(e)}`,
@@ -175,25 +305,82 @@ export class ConnectionManager implements IConnectionManager {
     }
   }
 
+  /** 원격 명령 존재 여부 일괄 확인(`command -v`) — 스트리밍 전 로그 소스 검증용 */
+  @measure()
+  async checkCommands(names: string[]): Promise<Record<string, boolean>> {
+    const out: Record<string, boolean> = {};
+    if (!names.length) return out;
+    const script = names
+      .map((n) => `command -v ${n} >/dev/null 2>&1 && echo ${n}=1 || echo ${n}=0`)
+      .join('; ');
+    const r = await this.run(`sh -lc '${script}'`);
+    for (const line of r.stdout.split(/\r?\n/)) {
+      const m = /^([\w.-]+)=([01])$/.exec(line.trim());
+      if (m) out[m[1]] = m[2] === '1';
+    }
+    for (const n of names) if (!(n in out)) out[n] = false;
+    this.log.debug('checkCommands', out);
+    return out;
+  }
+
+  /**
+   * ADB: 파일시스템 수정 전 `adb root` → `adb remount` 시도(SSH는 undefined, no-op).
+   * root 불가(production 빌드 등)여도 throw 하지 않고 사유를 돌려준다 — 실제 쓰기 가능 여부는
+   * 호출부(HostStateGuard)의 remount/probe가 판정한다.
+   */
   @measure()
-  async stream(cmd: string, onLine: (line: string) => void, abort?: AbortSignal) {
+  async ensureAdbRoot(): Promise<AdbElevation | undefined> {
+    if (!this.active) throw notConnectedError();
+    if (this.active.type !== 'ADB') return undefined;
+    const cfg = this.toHostConfig(this.active);
+    if (cfg.type !== 'adb') return undefined;
+    if (this.adbElevation?.generation !== this.generation) {
+      const opts = { serial: cfg.serial, timeoutMs: cfg.timeoutMs };
+      const result = (async (): Promise<AdbElevation> => {
+        const r = await adbRoot(opts);
+        if (!r.ok) {
+          this.log.warn(`[warn] adb root unavailable: ${r.reason}`);
+          return { root: false, remounted: false, reason: `adb root 불가: ${r.reason}` };
+        }
+        const m = await adbRemount(opts);
+        if (!m.ok) this.log.warn(`[warn] adb remount failed: ${m.reason}`);
+        return {
+          root: true,
+          remounted: m.ok,
+          reason: m.ok ? undefined : `adb remount 실패: ${m.reason}`,
+        };
+      })();
+      this.adbElevation = { generation: this.generation, result };
+      // 실패한 시도는 다음 호출에서 다시 시도
+      result.then((r) => {
+        if (!r.root && this.adbElevation?.result === result) this.adbElevation = undefined;
+      });
+    }
+    return this.adbElevation!.result;
+  }
+
+  @measure()
+  async stream(
+    cmd: string,
+    onLine: (line: string) => void,
+    abort?: AbortSignal,
+    onErrLine?: (line: string) => void,
+  ): Promise<number | null> {
     const via = this.active?.type ?? 'NONE';
     this.log.debug(`[debug] ConnectionManager.stream: start`);
     try {
-      if (!this.active)
-        throw new XError(
-          ErrorCategory.Connection,
-          'No active connection. Please connect a device.',
-        );
+      if (!this.active) throw notConnectedError();
       const cfg = this.toHostConfig(this.active);
+      this.traceCommand('stream', cfg, cmd);
       if (cfg.type === 'adb') {
         this.log.debug('[debug] stream(ADB) exec');
-        await adbStream(
+        // 스트림은 끝날 때까지 흘리는 명령(tail -f 등)용 — SSH처럼 전체 시간 제한 없이 abort로만 끊는다
+        return await adbStream(
           cmd,
-          { serial: cfg.serial, timeoutMs: cfg.timeoutMs, signal: abort },
+          { serial: cfg.serial, signal: abort },
           onLine,
+          onErrLine,
         );
-        return;
       }
       this.log.debug('[debug] stream(SSH) exec', {
         host: (cfg as any).host,
@@ -201,22 +388,35 @@ export class ConnectionManager implements IConnectionManager {
         port: (cfg as any).port,
         cmd,
       });
-      await sshStream(
-        cmd,
+      // sudo -n 비밀번호 요구는 stderr 한 줄로 온다 — 감지 후 명확한 오류로 바꾼다
+      let sudoDenied = false;
+      const code = await sshStream(
+        cfg.sudo ? wrapSudo(cmd) : cmd,
         {
-          host: (cfg as any).host,
-          port: (cfg as any).port,
-          user: (cfg as any).user,
-          password: (cfg as any).password,
-          timeoutMs: (cfg as any).timeoutMs,
+          host: cfg.host,
+          port: cfg.port,
+          user: cfg.user,
+          keyPath: cfg.keyPath,
+          password: cfg.password,
+          timeoutMs: cfg.timeoutMs,
+          aliveIntervalS: cfg.aliveIntervalS,
+          aliveCountMax: cfg.aliveCountMax,
           signal: abort,
         },
         onLine,
+        (line) => {
+          if (isSudoPasswordError(line)) sudoDenied = true;
+          onErrLine?.(line);
+        },
       );
+      if (sudoDenied) throw new XError(ErrorCategory.Permission, SUDO_PASSWORD_MESSAGE);
+      return code;
     } catch (e) {
       this.log.error(`[debug] ConnectionManager.stream: error`, {
         message: e instanceof Error ? e.message : String(e),
       });
+      if (abort?.reason instanceof XError) throw abort.reason;
+      if (e instanceof XError) throw e;
       throw new XError(
         ErrorCategory.Connection,
         `Stream failed: ${e instanceof Error ? e.message : String(e)}`,
@@ -227,10 +427,13 @@ export class ConnectionManager implements IConnectionManager {
 
   @measure()
   dispose() {
+    const prev = this.active;
+    unregisterSecret((prev?.details as any)?.password);
     this.connected = false;
     this.active = undefined;
     this.healthy = undefined;
     this.lastCheckedAt = undefined;
+    if (prev) this.notifyChange(undefined, prev);
     this.log.info(`[debug] ConnectionManager.disposed`);
   }
 }
@@ -27,6 +27,7 @@
 
 import { execFile, spawn } from 'child_process';
 
+import { isCommandTraceEnabled } from '../logging/command-trace.js';
 import { getLogger } from '../logging/extension-logger.js';
 import { measureBlock } from '../logging/perf.js';
 
@@ -54,6 +55,7 @@ export async function runCommandLine(
 ): Promise<{ code: number | null; stdout: string; stderr: string }> {
   return measureBlock('ExecRunner.runCommandLine', async () => {
     log.debug('[debug] runCommandLine: start');
+    if (isCommandTraceEnabled()) log.info(`[trace] local exec :: ${cmd}`);
     const isWin = process.platform === 'win32';
     // honor opts.shell if provided, otherwise choose sensible default per-OS
     const sh =
@@ -4,8 +4,10 @@ import * as fs from 'fs';
 import * as fsp from 'fs/promises';
 import * as path from 'path';
 
+import { ErrorCategory, XError } from '../../shared/errors.js';
 import { getLogger } from '../logging/extension-logger.js';
 import { measureBlock } from '../logging/perf.js';
+import { shellEscape, shellQuote } from './shellQuote.js';
 
 const log = getLogger('adb');
 
@@ -21,6 +23,8 @@ type ADBDevice = {
     src: NodeJS.ReadableStream,
     remotePath: string,
   ): Promise<NodeJS.ReadWriteStream & NodeJS.EventEmitter>;
+  root(): Promise<boolean>;
+  remount(): Promise<boolean>;
 };
 type ADBClient = {
   listDevices(): Promise<Array<{ id: string; type?: string; state?: string }>>;
@@ -53,7 +57,11 @@ async function resolveSerial(opts?: AdbOptions): Promise<string> {
   // v3는 d.type 또는 d.state 로 들어올 수 있음
   const online = list.filter((d) => (d.type ?? d.state) === 'device');
   if (online.length === 1) return online[0].id;
-  if (online.length === 0) throw new Error('No ADB devices in "device" state');
+  if (online.length === 0) {
+    const others = list.map((d) => `${d.id}: ${adbStateHint(d.type ?? d.state)}`);
+    const why = others.length ? ` (${others.join('; ')})` : '';
+    throw new Error(`No ADB devices in "device" state${why}`);
+  }
   throw new Error('Multiple ADB devices connected — specify serial');
 }
 
@@ -64,7 +72,7 @@ function wrapWithExitCode(cmd: string): string {
   if (/^\s*sh\s+-l?c\s+['"]/.test(flat)) {
     return `${flat}${trailer}`;
   }
-  return `sh -c '${flat.replace(/'/g, `'\\''`)}${trailer}'`;
+  return `sh -c '${shellEscape(flat)}${trailer}'`;
 }
 
 function installAbortAndTimeout(
@@ -116,28 +124,84 @@ export async function adbShell(
   });
 }
 
-export async function adbStream(cmd: string, opts: AdbOptions, onLine: (line: string) => void) {
+// exec 스트림용 래핑: 시작 시 셸 PID, stderr 라인엔 접두어, 끝에 종료코드 트레일러를 찍는다
+// (adb shell은 stdout/stderr를 한 스트림으로 섞으므로 fd를 바꿔 stderr만 접두어를 붙여 되돌린다)
+function wrapForExecStream(cmd: string): string {
+  const flat = cmd.replace(/\r/g, '').replace(/\n/g, ' ').trim().replace(/;\s*$/, '');
+  const script =
+    `echo "__EDGE_PID:$$"; ` +
+    `{ { ${flat}; printf "\\n__EDGE_CODE:%d\\n" $? >&3; } 2>&1 1>&3 | ` +
+    `while IFS= read -r l; do printf "__EDGE_ERR:%s\\n" "$l"; done; } 3>&1`;
+  return `sh -c '${shellEscape(script)}'`;
+}
+
+/**
+ * 원격 명령 출력을 라인 단위로 실시간 전달한다.
+ * - onErrLine 지정 시(exec 경로) stderr를 분리해 전달하고, 종료코드를 반환하며,
+ *   abort/타임아웃 때 원격 프로세스 그룹에 SIGINT를 보내 종료시킨다.
+ * - 미지정 시 명령을 감싸지 않고 그대로 흘린다(종료코드 null).
+ */
+export async function adbStream(
+  cmd: string,
+  opts: AdbOptions,
+  onLine: (line: string) => void,
+  onErrLine?: (line: string) => void,
+): Promise<number | null> {
   return measureBlock('adb.adbStream', async () => {
     log.debug('[debug] adbStream(adbkit): start');
     const serial = await resolveSerial(opts);
     const dev = client().getDevice(serial);
-    const s = await dev.shell(cmd);
-    installAbortAndTimeout(s, opts);
-    let residual = '';
-    await new Promise<void>((resolve, reject) => {
-      s.on('data', (b: Buffer) => {
-        const all = residual + b.toString('utf8');
-        const parts = all.split(/\r?\n/);
-        residual = parts.pop() ?? '';
-        for (const p of parts) onLine(p);
+    const exec = !!onErrLine;
+    const s = await dev.shell(exec ? wrapForExecStream(cmd) : cmd);
+    let pid: number | undefined;
+    let code: number | null = null;
+    // 스트림만 끊으면 adbd 쪽 프로세스(tail -f 등)가 남을 수 있어 별도 셸로 종료 신호를 보낸다
+    const killRemote = () => {
+      if (!pid) return;
+      const kill = `kill -INT -- -${pid} 2>/dev/null || pkill -INT -P ${pid}; kill -INT ${pid}`;
+      adbShell(kill, { serial, timeoutMs: 5000 }).catch((e) => {
+        const msg = e instanceof Error ? e.message : String(e);
+        log.warn(`[warn] adbStream: kill ${pid} failed: ${msg}`);
       });
-      s.on('error', reject);
-      s.on('end', () => {
-        if (residual) onLine(residual);
-        resolve();
+    };
+    if (exec) opts.signal?.addEventListener('abort', killRemote, { once: true });
+    installAbortAndTimeout(s, opts, exec ? killRemote : undefined);
+    // 트레일러 앞의 개행이 만든 빈 줄은 트레일러인지 확인될 때까지 보류
+    let heldBlank = false;
+    const emit = (p: string) => {
+      if (!exec) return onLine(p);
+      const m = p.match(/^__EDGE_(PID|CODE):(\d+)$/);
+      if (m?.[1] === 'PID' && pid === undefined) return void (pid = Number(m[2]));
+      if (m?.[1] === 'CODE') {
+        heldBlank = false;
+        return void (code = Number(m[2]));
+      }
+      if (p.startsWith('__EDGE_ERR:')) return onErrLine!(p.slice('__EDGE_ERR:'.length));
+      if (heldBlank) onLine('');
+      heldBlank = p === '';
+      if (!heldBlank) onLine(p);
+    };
+    let residual = '';
+    try {
+      await new Promise<void>((resolve, reject) => {
+        s.on('data', (b: Buffer) => {
+          const all = residual + b.toString('utf8');
+          const parts = all.split(/\r?\n/);
+          residual = parts.pop() ?? '';
+          for (const p of parts) emit(p);
+        });
+        s.on('error', reject);
+        s.on('end', () => {
+          if (residual) emit(residual);
+          if (heldBlank) onLine('');
+          resolve();
+        });
       });
-    });
-    log.debug('[debug] adbStream(adbkit): end');
+    } finally {
+      opts.signal?.removeEventListener('abort', killRemote);
+    }
+    log.debug('[debug] adbStream(adbkit): end', { code });
+    return code;
   });
 }
 
@@ -155,6 +219,49 @@ export async function listDevices(_opts?: {
   return list.map((d) => ({ id: d.id, state: (d.type ?? (d as any).state) as any }));
 }
 
+/**
+ * `adb devices` 상태별 안내 문구(연결 불가 사유를 구분해 보여주기 위함)
+ * - unauthorized: 기기 화면의 RSA 지문 허용 대기
+ * - offline: adbd 응답 없음(케이블/재연결)
+ */
+export function adbStateHint(state: string | undefined): string {
+  switch (state) {
+    case 'device':
+      return 'ready';
+    case 'unauthorized':
+      return 'unauthorized — accept the RSA prompt on the device';
+    case 'offline':
+      return 'offline — try reconnecting (replug USB or `adb reconnect`)';
+    case 'authorizing':
+      return 'authorizing — waiting for the device to finish the RSA handshake';
+    case 'no permissions':
+      return 'no permissions — check udev rules for the USB device';
+    case undefined:
+    case 'unknown':
+      return 'not found — check `adb devices`';
+    default:
+      return `${state} — not available for shell commands`;
+  }
+}
+
+/**
+ * serial이 `adb devices`에 'device' 상태로 있는지 확인(현재 연결과 무관한 기기 지정용)
+ * - 없거나 offline/unauthorized면 연결 가능한 목록과 함께 XError(Connection)
+ */
+export async function assertAdbDevice(serial: string): Promise<void> {
+  const list = await listDevices();
+  const hit = list.find((d) => d.id === serial);
+  if (hit?.state === 'device') return;
+  const ready = list.filter((d) => d.state === 'device').map((d) => d.id);
+  throw new XError(
+    ErrorCategory.Connection,
+    hit
+      ? `ADB 기기를 사용할 수 없습니다: ${serial} (${adbStateHint(hit.state)})`
+      : `ADB 기기를 찾을 수 없습니다: ${serial} (연결된 기기: ${ready.join(', ') || '없음'})`,
+    { serial, devices: list },
+  );
+}
+
 export async function getState(
   serial: string,
   _opts?: { timeoutMs?: number; signal?: AbortSignal },
@@ -164,20 +271,82 @@ export async function getState(
   return found ? (found.type ?? (found as any).state ?? 'unknown') : 'unknown';
 }
 
+// ─────────────────────────────────────────────────────────────
+//  권한 상승: adb root / adb remount (파일시스템 수정 전 단계)
+// ─────────────────────────────────────────────────────────────
+/** 기기가 (다시) 'device' 상태가 될 때까지 대기(adbd 재시작, RSA 허용 대기에도 사용) */
+export async function waitForDevice(serial: string, timeoutMs: number, signal?: AbortSignal) {
+  const deadline = Date.now() + timeoutMs;
+  // 재시작 직후에는 이전 상태가 잠깐 보일 수 있으므로 한 번 쉬고 시작
+  await new Promise((r) => setTimeout(r, 500));
+  while (Date.now() < deadline && !signal?.aborted) {
+    if ((await getState(serial).catch(() => 'unknown')) === 'device') return true;
+    await new Promise((r) => setTimeout(r, 500));
+  }
+  return false;
+}
+
+/**
+ * adbd를 root로 재시작(이미 uid 0이면 생략)하고 재연결까지 대기.
+ * production 빌드처럼 root가 불가하면 ok=false + 사유 반환(throw 하지 않음)
+ */
+export async function adbRoot(opts: AdbOptions): Promise<{ ok: boolean; reason?: string }> {
+  return measureBlock('adb.adbRoot', async () => {
+    const serial = await resolveSerial(opts);
+    const uid = await adbShell('id -u', { ...opts, serial }).catch(() => undefined);
+    if (uid?.stdout.trim() === '0') return { ok: true };
+    try {
+      await client().getDevice(serial).root();
+    } catch (e) {
+      return { ok: false, reason: e instanceof Error ? e.message : String(e) };
+    }
+    if (!(await waitForDevice(serial, opts.timeoutMs ?? 10_000))) {
+      return { ok: false, reason: 'device did not come back after adbd restart' };
+    }
+    const after = await adbShell('id -u', { ...opts, serial }).catch(() => undefined);
+    log.debug('[debug] adbRoot: uid after restart', { uid: after?.stdout.trim() });
+    return after?.stdout.trim() === '0'
+      ? { ok: true }
+      : { ok: false, reason: 'adbd is still not running as root' };
+  });
+}
+
+/** adb remount(시스템 파티션 rw). 실패 시 ok=false + 사유(verity 활성 등) */
+export async function adbRemount(opts: AdbOptions): Promise<{ ok: boolean; reason?: string }> {
+  return measureBlock('adb.adbRemount', async () => {
+    const serial = await resolveSerial(opts);
+    try {
+      await client().getDevice(serial).remount();
+      return { ok: true };
+    } catch (e) {
+      return { ok: false, reason: e instanceof Error ? e.message : String(e) };
+    }
+  });
+}
+
 // ─────────────────────────────────────────────────────────────
 //  파일 전송 헬퍼 (adbkit v3: device.pull/push)
 // ─────────────────────────────────────────────────────────────
 export async function adbMkdirP(dir: string, opts: AdbOptions) {
-  await adbShell(`mkdir -p "${dir}"`, opts);
+  await adbShell(`mkdir -p ${shellQuote(dir)}`, opts);
 }
 
-export async function adbPullFile(remote: string, localFs: string, opts: AdbOptions) {
+/** onBytes: 이 파일에서 지금까지 받은 누적 바이트(adbkit progress 이벤트) */
+export async function adbPullFile(
+  remote: string,
+  localFs: string,
+  opts: AdbOptions,
+  onBytes?: (bytesTransferred: number) => void,
+) {
   const serial = await resolveSerial(opts);
   const dev = client().getDevice(serial);
   const s = await dev.pull(remote);
   await fsp.mkdir(path.dirname(localFs), { recursive: true });
   await new Promise<void>((res, rej) => {
     const ws = fs.createWriteStream(localFs);
+    if (onBytes) {
+      s.on('progress', (st: { bytesTransferred: number }) => onBytes(st.bytesTransferred));
+    }
     s.on('error', rej);
     ws.on('error', rej);
     ws.on('close', () => res());
@@ -197,14 +366,29 @@ export async function adbPushFile(localFs: string, remote: string, opts: AdbOpti
   });
 }
 
-/** 원격 디렉터리 아래 모든 파일 리스트(상대 경로) */
+/**
+ * 원격 디렉터리 아래 모든 파일 리스트(상대 경로)
+ * - find -L 로 심볼릭 링크를 따라간다.
+ * - 같은 실파일을 가리키는 경로(링크 루프/별칭)는 readlink -f 기준 visited 셋으로 한 번만 포함
+ */
 export async function adbListFilesRec(remoteDir: string, opts: AdbOptions): Promise<string[]> {
+  const base = remoteDir.replace(/\/+$/, '');
   const { stdout } = await adbShell(
-    `find "${remoteDir}" -type f -print0 2>/dev/null | sed -z 's#^${remoteDir}/##'`,
+    `find -L ${shellQuote(base)} -maxdepth 32 -type f 2>/dev/null | ` +
+      `while IFS= read -r f; do printf '%s\\t%s\\n' "$(readlink -f "$f" 2>/dev/null)" "$f"; done`,
     opts,
   );
-  return stdout
-    .split('\0')
-    .map((s) => s.trim())
-    .filter(Boolean);
+  const visited = new Set<string>();
+  const out: string[] = [];
+  for (const line of stdout.split(/\r?\n/)) {
+    const tab = line.indexOf('\t');
+    if (tab < 0) continue;
+    const real = line.slice(0, tab) || line.slice(tab + 1);
+    const abs = line.slice(tab + 1);
+    if (!abs || visited.has(real)) continue;
+    visited.add(real);
+    const rel = abs.startsWith(`${base}/`) ? abs.slice(base.length + 1) : abs;
+    if (rel) out.push(rel);
+  }
+  return out;
 }
@@ -0,0 +1,60 @@
+// === src/core/connection/deadline.ts ===
+/**
+ * 장시간 작업(pull/push 등) 전체 마감 시간.
+ * - 파일 단위 timeoutMs는 파일마다 초기화되므로, 링크가 중간에 멈추면 전체 작업이 끝나지 않을 수 있다.
+ * - 마감 시 AbortSignal을 reason=XError(Timeout)으로 abort → run/stream/전송 루프가 모두 이 신호를 따른다.
+ */
+import { ErrorCategory, XError } from '../../shared/errors.js';
+
+export type Deadline = {
+  signal: AbortSignal;
+  /** 타이머/부모 구독 해제(작업 종료 시 반드시 호출) */
+  dispose(): void;
+};
+
+export const DEADLINE_EXCEEDED_MESSAGE = 'operation deadline exceeded';
+
+export function deadlineExceededError(ms: number): XError {
+  return new XError(
+    ErrorCategory.Timeout,
+    `${DEADLINE_EXCEEDED_MESSAGE} (${Math.round(ms / 1000)}s)`,
+    { deadlineMs: ms },
+  );
+}
+
+/** ms가 없거나 0 이하면 마감 없음(부모 신호만 전달) */
+export function createDeadline(ms?: number, parent?: AbortSignal): Deadline {
+  const ac = new AbortController();
+  const onParent = () => ac.abort(parent?.reason);
+  if (parent?.aborted) onParent();
+  else parent?.addEventListener('abort', onParent, { once: true });
+  const timer = ms && ms > 0 ? setTimeout(() => ac.abort(deadlineExceededError(ms)), ms) : undefined;
+  return {
+    signal: ac.signal,
+    dispose() {
+      if (timer) clearTimeout(timer);
+      parent?.removeEventListener('abort', onParent);
+    },
+  };
+}
+
+/** `90`, `90s`, `5m` → ms (잘못된 값이면 undefined) */
+export function parseDurationMs(v: string): number | undefined {
+  const m = /^(\d+(?:\.\d+)?)(s|m)?$/i.exec(v.trim());
+  if (!m) return undefined;
+  const ms = Number(m[1]) * (m[2]?.toLowerCase() === 'm' ? 60_000 : 1000);
+  return ms > 0 ? ms : undefined;
+}
+
+/** `--deadline`을 주지 않은 pull/push/업데이트의 기본 마감 — EDGE_TOOL_DEADLINE(초|Nm), 없으면 마감 없음 */
+export function defaultDeadlineMs(): number | undefined {
+  return parseDurationMs(String(process.env.EDGE_TOOL_DEADLINE ?? ''));
+}
+
+/** abort된 신호면 사유(XError면 그대로, 아니면 취소 오류)를 던진다 */
+export function throwIfAborted(signal?: AbortSignal) {
+  if (!signal?.aborted) return;
+  const r = signal.reason;
+  if (r instanceof XError) throw r;
+  throw new XError(ErrorCategory.Unknown, 'operation cancelled', r);
+}
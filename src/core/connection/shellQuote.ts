@@ -0,0 +1,18 @@
+// === src/core/connection/shellQuote.ts ===
+/**
+ * 원격 셸 명령 인자 인용(POSIX sh)
+ * - 사용자 입력(경로/필터/유닛 이름 등)은 반드시 shellQuote로 감싸 한 개의 리터럴 인자로 만든다.
+ * - 큰따옴표("...")는 $(...)·`...`·$VAR가 그대로 확장되므로 인자 인용에 쓰지 않는다.
+ * - `sh -lc <script>`로 감쌀 때도 script 전체를 shellQuote → 내부 인용과 겹쳐 깨지지 않음
+ *   (예: `sh -lc ${shellQuote(`rm -f ${shellQuote(p)}`)}`)
+ */
+
+/** 작은따옴표 안에 넣을 본문만 이스케이프: a'b → a'\''b (호출부가 '…'로 감쌀 때) */
+export function shellEscape(s: string): string {
+  return String(s).replace(/'/g, `'\\''`);
+}
+
+/** 한 개의 리터럴 인자로 인용: a'b → 'a'\''b' */
+export function shellQuote(s: string): string {
+  return `'${shellEscape(s)}'`;
+}
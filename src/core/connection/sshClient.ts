@@ -1,6 +1,8 @@
 // === src/core/connection/sshClient.ts ===
+import * as fs from 'fs';
 import { Client } from 'ssh2';
 
+import { SSH_ALIVE_COUNT_MAX_DEFAULT, SSH_ALIVE_INTERVAL_S_DEFAULT } from '../../shared/const.js';
 import { getLogger } from '../logging/extension-logger.js';
 import { measureBlock } from '../logging/perf.js';
 
@@ -12,10 +14,52 @@ export type SshOptions = {
   password?: string;
   timeoutMs?: number;
   signal?: AbortSignal;
+  /** keepalive 간격(초, 0 = 끔)/허용 횟수 — 미지정 시 환경변수 → 기본값 */
+  aliveIntervalS?: number;
+  aliveCountMax?: number;
 };
 
 const log = getLogger('ssh');
 
+/**
+ * 인증 옵션: 비밀번호 + (있으면) 개인키 파일 + ssh-agent
+ * - ssh config 별칭 연결은 보통 키/agent로 인증하므로 비밀번호가 없을 수 있다.
+ */
+export function sshAuthOptions(opts: Pick<SshOptions, 'password' | 'keyPath'>) {
+  let privateKey: Buffer | undefined;
+  if (opts.keyPath) {
+    try {
+      privateKey = fs.readFileSync(opts.keyPath);
+    } catch (e) {
+      log.warn(`[warn] ssh: identity file not readable (${opts.keyPath}): ${String(e)}`);
+    }
+  }
+  return {
+    password: opts.password, // 비밀번호 인증
+    privateKey,
+    // 비밀번호 연결에는 agent 키를 섞지 않음(MaxAuthTries 소진 방지)
+    agent: opts.password ? undefined : process.env.SSH_AUTH_SOCK,
+  };
+}
+
+function envInt(name: string): number | undefined {
+  const n = Number(process.env[name]);
+  return process.env[name]?.trim() && Number.isInteger(n) && n >= 0 ? n : undefined;
+}
+
+/**
+ * ssh2 keepalive 옵션: 연결별 값 > EDGE_TOOL_SSH_ALIVE_* > 기본값
+ * - 유휴 구간이 긴 `journalctl -f` 같은 스트림이 NAT/방화벽 타임아웃으로 조용히 멈추지 않게 하고,
+ *   간격 × 횟수 동안 응답이 없으면 연결을 끊어 스트림이 오류로 끝나게 한다.
+ */
+export function sshKeepaliveOptions(opts: Pick<SshOptions, 'aliveIntervalS' | 'aliveCountMax'>) {
+  const interval =
+    opts.aliveIntervalS ?? envInt('EDGE_TOOL_SSH_ALIVE_INTERVAL') ?? SSH_ALIVE_INTERVAL_S_DEFAULT;
+  const countMax =
+    opts.aliveCountMax ?? envInt('EDGE_TOOL_SSH_ALIVE_COUNT_MAX') ?? SSH_ALIVE_COUNT_MAX_DEFAULT;
+  return { keepaliveInterval: interval * 1000, keepaliveCountMax: Math.max(1, countMax) };
+}
+
 function connectOnce(opts: SshOptions): Promise<Client> {
   return new Promise((resolve, reject) => {
     const conn = new Client();
@@ -27,9 +71,9 @@ function connectOnce(opts: SshOptions): Promise<Client> {
         host: opts.host,
         port: opts.port ?? 22,
         username: opts.user,
-        password: opts.password, // 비밀번호 인증
+        ...sshAuthOptions(opts),
         readyTimeout,
-        keepaliveInterval: 10000,
+        ...sshKeepaliveOptions(opts),
         tryKeyboard: false,
       });
   });
@@ -42,6 +86,8 @@ export async function sshRun(
   return measureBlock('ssh.sshRun', async () => {
     log.debug('[debug] sshRun: start');
     const conn = await connectOnce(opts);
+    // abort(마감/취소): 채널을 닫고 대기 중인 실행을 즉시 실패시킨다
+    let onAbort: (() => void) | undefined;
     try {
       const out: { code: number | null; stdout: string; stderr: string } = {
         code: 0,
@@ -49,8 +95,18 @@ export async function sshRun(
         stderr: '',
       };
       await new Promise<void>((resolve, reject) => {
+        if (opts.signal?.aborted) return reject(new Error('aborted'));
+        // keepalive 무응답 등으로 연결이 먼저 끊기면 채널 close 없이 끝날 수 있다
+        conn.on('error', reject).on('close', () => reject(new Error('ssh connection closed')));
         conn.exec(cmd, (err: Error | undefined, stream: any) => {
           if (err) return reject(err);
+          onAbort = () => {
+            try {
+              stream.close?.();
+            } catch {}
+            reject(new Error('aborted'));
+          };
+          opts.signal?.addEventListener('abort', onAbort, { once: true });
           stream
             .on('close', (code: number | null) => {
               out.code = code ?? 0;
@@ -70,6 +126,7 @@ export async function sshRun(
       log.debug('[debug] sshRun: end');
       return out;
     } finally {
+      if (onAbort) opts.signal?.removeEventListener('abort', onAbort);
       // 안전 종료
       try {
         conn.end();
@@ -78,24 +135,52 @@ export async function sshRun(
   });
 }
 
-export async function sshStream(cmd: string, opts: SshOptions, onLine: (line: string) => void) {
+/**
+ * 원격 명령 출력을 라인 단위로 실시간 전달한다.
+ * - onErrLine 지정 시 stderr도 라인 단위로 전달(미지정 시 process.stderr로 흘림)
+ * - abort 시 원격 프로세스에 SIGINT를 보낸 뒤 채널/연결을 닫는다.
+ * - 채널 종료코드를 반환(abort로 끊겼거나 알 수 없으면 null)
+ */
+export async function sshStream(
+  cmd: string,
+  opts: SshOptions,
+  onLine: (line: string) => void,
+  onErrLine?: (line: string) => void,
+): Promise<number | null> {
   return measureBlock('ssh.sshStream', async () => {
     log.debug('[debug] sshStream: start');
     const conn = await connectOnce(opts);
     let residual = '';
+    let errResidual = '';
+    let channel: any;
     const abort = () => {
+      try {
+        channel?.signal?.('INT');
+      } catch {}
+      try {
+        channel?.close?.();
+      } catch {}
       try {
         conn.end();
       } catch {}
     };
     if (opts.signal) opts.signal.addEventListener('abort', abort, { once: true });
-    await new Promise<void>((resolve, reject) => {
+    const code = await new Promise<number | null>((resolve, reject) => {
+      // keepalive 무응답으로 연결이 끊기면 채널 close 없이 끝날 수 있다 — 멈춘 스트림 대신 오류로 끝냄
+      conn.on('error', reject).on('close', () => {
+        if (opts.signal?.aborted) resolve(null);
+        else reject(new Error('ssh connection closed'));
+      });
       conn.exec(cmd, (err: Error | undefined, stream: any) => {
         if (err) return reject(err);
+        channel = stream;
+        if (opts.signal?.aborted) abort();
         stream
-          .on('close', () => {
+          .on('close', (exitCode: number | null | undefined) => {
             if (opts.signal) opts.signal.removeEventListener('abort', abort);
-            resolve();
+            if (residual) onLine(residual);
+            if (errResidual) onErrLine?.(errResidual);
+            resolve(opts.signal?.aborted ? null : (exitCode ?? null));
             try {
               conn.end();
             } catch {}
@@ -106,10 +191,17 @@ export async function sshStream(cmd: string, opts: SshOptions, onLine: (line: st
             residual = parts.pop() ?? '';
             for (const p of parts) onLine(p);
           });
-        (stream.stderr as any).on('data', (b: Buffer) => process.stderr.write(b));
+        (stream.stderr as any).on('data', (b: Buffer) => {
+          if (!onErrLine) return void process.stderr.write(b);
+          const all = errResidual + b.toString('utf8');
+          const parts = all.split(/\r?\n/);
+          errResidual = parts.pop() ?? '';
+          for (const p of parts) onErrLine(p);
+        });
       });
     });
-    log.debug('[debug] sshStream: end');
+    log.debug('[debug] sshStream: end', { code });
+    return code;
   });
 }
 
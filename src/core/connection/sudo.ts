@@ -0,0 +1,31 @@
+// === src/core/connection/sudo.ts ===
+/**
+ * 비root SSH 로그인용 sudo 래핑.
+ * - `sudo -n`(non-interactive): 비밀번호가 필요하면 대기하지 않고 즉시 실패한다.
+ * - 명령 전체를 `sh -c '<...>'`로 감싸 파이프/리다이렉트/`&&`까지 root로 실행되게 한다.
+ */
+import { shellQuote } from './shellQuote.js';
+
+/** 이미 sudo로 시작하는 명령은 다시 감싸지 않는다 */
+const ALREADY_SUDO_RE = /^\s*sudo(?:\s|$)/;
+
+/** `sudo -n`이 비밀번호/터미널을 요구하며 실패했을 때의 stderr */
+const SUDO_PASSWORD_RE = /sudo:\s*(?:a\s+)?password is required|sudo:\s*a terminal is required/i;
+
+export const SUDO_PASSWORD_MESSAGE =
+  'sudo 비밀번호가 필요합니다(sudo -n 실패). 기기에서 해당 사용자에 NOPASSWD sudo 권한을 설정하거나 root로 접속하세요.';
+
+export function wrapSudo(cmd: string): string {
+  const full = String(cmd ?? '').trim();
+  if (!full || ALREADY_SUDO_RE.test(full)) return full;
+  return `sudo -n sh -c ${shellQuote(full)}`;
+}
+
+export function isSudoPasswordError(stderr: string | undefined): boolean {
+  return SUDO_PASSWORD_RE.test(String(stderr ?? ''));
+}
+
+/** SSH 연결 설정상 sudo 래핑 대상인지(root 로그인은 불필요) */
+export function needsSudo(details: { user?: string; useSudo?: boolean } | undefined): boolean {
+  return !!details?.useSudo && details.user !== 'root';
+}
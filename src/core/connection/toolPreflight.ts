@@ -0,0 +1,177 @@
+// === src/core/connection/toolPreflight.ts ===
+/**
+ * 로컬 외부 도구(adb/ssh/scp/git) 존재 확인 — 시작 시 1회 + `doctor` 명령.
+ * - 없는 도구는 설치 힌트와 함께 보고만 하고 시작을 막지 않는다.
+ * - "command not found"/"is not recognized"/ENOENT 판별을 여기 한 곳에 모아
+ *   각 기능(git init, adb 셸 등)이 같은 기준으로 ToolMissing 오류를 만든다.
+ * - adb는 저장된 연결(또는 현재 연결)에 ADB가 있을 때만 필수 — SSH만 쓰면 안내만 한다.
+ */
+import { execFile as execFileCb } from 'child_process';
+
+import { ErrorCategory, XError } from '../../shared/errors.js';
+import { readConnectionConfig } from '../config/connection-config.js';
+import { getLogger } from '../logging/extension-logger.js';
+import { measureBlock } from '../logging/perf.js';
+
+const log = getLogger('preflight');
+
+export type LocalTool = 'adb' | 'ssh' | 'scp' | 'git';
+
+export type ToolStatus = {
+  name: LocalTool;
+  present: boolean;
+  /** 버전 출력 첫 줄(있으면) */
+  version?: string;
+  /** 이 도구가 필요한 기능 */
+  usedBy: string;
+  /** 없을 때 false면 경고 대신 안내만(대체 경로가 있는 도구) */
+  required: boolean;
+  hint: string;
+};
+
+type ToolSpec = Omit<ToolStatus, 'name' | 'present' | 'version'> & { args: string[] };
+
+const TOOL_SPECS: Record<LocalTool, ToolSpec> = {
+  adb: {
+    args: ['version'],
+    usedBy: 'ADB 연결·adb 셸',
+    required: false,
+    hint: 'Android SDK Platform-Tools 설치 후 PATH에 추가 (https://developer.android.com/tools/releases/platform-tools)',
+  },
+  git: {
+    args: ['--version'],
+    usedBy: '워크스페이스 git 동기화·이력',
+    required: true,
+    hint: 'Git 설치 후 PATH에 추가 (https://git-scm.com/downloads)',
+  },
+  ssh: {
+    args: ['-V'],
+    usedBy: '외부 터미널 SSH(확장 내부 SSH는 내장 클라이언트 사용)',
+    required: false,
+    hint: 'OpenSSH 클라이언트 설치 (Windows: 설정 > 선택적 기능 > OpenSSH 클라이언트)',
+  },
+  scp: {
+    args: [],
+    usedBy: '외부 파일 복사(확장 내부 전송은 tar/base64 사용)',
+    required: false,
+    hint: 'OpenSSH 클라이언트 설치 시 함께 제공',
+  },
+};
+
+export const PREFLIGHT_TOOLS = Object.keys(TOOL_SPECS) as LocalTool[];
+
+// 셸이 실행 파일을 못 찾았을 때의 문구만 — "No such file or directory"는 도구 자체의 일반 오류(git 경로 등)와 겹친다
+const NOT_FOUND_RE =
+  /is not recognized as an internal or external command|command not found|not found on PATH/i;
+
+/** 실행 파일 부재로 인한 실패인지(spawn ENOENT 또는 셸의 not found/not recognized) */
+export function isToolMissingError(e: unknown): boolean {
+  const err = e as { code?: unknown; message?: unknown; stderr?: unknown } | undefined;
+  if (err?.code === 'ENOENT') return true;
+  return NOT_FOUND_RE.test(`${String(err?.stderr ?? '')}\n${String(err?.message ?? e ?? '')}`);
+}
+
+export function toolInstallHint(name: LocalTool): string {
+  return TOOL_SPECS[name].hint;
+}
+
+/** ToolMissing 오류 — 메시지에 설치 힌트 포함 */
+export function toolMissingError(name: LocalTool, cause?: unknown): XError {
+  return new XError(
+    ErrorCategory.ToolMissing,
+    `'${name}' 실행 파일을 찾을 수 없습니다. ${toolInstallHint(name)}`,
+    cause,
+  );
+}
+
+type ExecFileFn = (
+  file: string,
+  args: string[],
+  cb: (err: any, stdout: string, stderr: string) => void,
+) => unknown;
+
+const defaultExec: ExecFileFn = (file, args, cb) =>
+  execFileCb(file, args, { timeout: 5000, windowsHide: true }, (err, so, se) =>
+    cb(err, String(so ?? ''), String(se ?? '')),
+  );
+
+/**
+ * 도구 1개 확인: 실행 자체가 되면(종료코드 무관) 존재로 본다 — scp는 인자 없이 usage로 종료
+ * - 셸을 거치지 않고 직접 spawn하므로 부재 판정은 spawn의 ENOENT만 본다(출력 문구는 무시)
+ */
+export function probeTool(
+  name: LocalTool,
+  exec: ExecFileFn = defaultExec,
+  required = TOOL_SPECS[name].required,
+): Promise<ToolStatus> {
+  const { args, ...spec } = TOOL_SPECS[name];
+  return new Promise((resolve) => {
+    exec(name, args, (err, stdout, stderr) => {
+      const present = err?.code !== 'ENOENT';
+      const first = `${stdout}\n${stderr}`.split(/\r?\n/).find((l) => l.trim());
+      resolve({
+        name,
+        present,
+        version: present && args.length ? first?.trim() : undefined,
+        ...spec,
+        required,
+      });
+    });
+  });
+}
+
+let last: ToolStatus[] | undefined;
+
+/** 마지막 preflight 결과(아직 없으면 undefined) */
+export function getLastPreflight(): readonly ToolStatus[] | undefined {
+  return last;
+}
+
+/** 환경에 따라 필수가 되는 도구 — 현재 연결이나 저장된 연결에 ADB가 있으면 adb */
+export async function requiredToolsFor(
+  workspacePath?: string,
+  activeType?: string,
+): Promise<LocalTool[]> {
+  if (activeType === 'ADB') return ['adb'];
+  if (!workspacePath) return [];
+  try {
+    const cfg = await readConnectionConfig(workspacePath);
+    return cfg.connections.some((c) => c.type === 'ADB') ? ['adb'] : [];
+  } catch {
+    return [];
+  }
+}
+
+export async function runToolPreflight(
+  opts: { tools?: LocalTool[]; exec?: ExecFileFn; require?: LocalTool[] } = {},
+): Promise<ToolStatus[]> {
+  return measureBlock('preflight.tools', async () => {
+    const tools = opts.tools ?? PREFLIGHT_TOOLS;
+    const results = await Promise.all(
+      tools.map((t) =>
+        probeTool(t, opts.exec, TOOL_SPECS[t].required || !!opts.require?.includes(t)),
+      ),
+    );
+    last = results;
+    for (const r of results) {
+      if (r.present) log.debug(`[debug] preflight: ${r.name} ok ${r.version ?? ''}`);
+      else if (r.required) log.warn(`[warn] preflight: ${r.name} not found — ${r.hint}`);
+      else log.info(`[info] preflight: ${r.name} not found (optional)`);
+    }
+    return results;
+  });
+}
+
+/** doctor 출력용 보고서 */
+export function formatPreflightReport(results: readonly ToolStatus[]): string {
+  const lines = results.map((r) => {
+    const mark = r.present ? 'OK  ' : r.required ? 'MISS' : 'opt ';
+    const tail = r.present ? (r.version ?? '') : `— ${r.hint}`;
+    return `  [${mark}] ${r.name.padEnd(4)} ${r.usedBy}${tail ? ` ${tail}` : ''}`;
+  });
+  const missing = results.filter((r) => !r.present && r.required).map((r) => r.name);
+  const summary = missing.length
+    ? `필수 도구 없음: ${missing.join(', ')}`
+    : '필수 도구가 모두 설치되어 있습니다.';
+  return `Local tools:\n${lines.join('\n')}\n${summary}`;
+}
@@ -1,40 +1,122 @@
 // src/core/controller/GitController.ts
-import { exec as execCb } from 'child_process';
+import { exec as execCb, execFile as execFileCb } from 'child_process';
+import * as crypto from 'crypto';
 import * as fs from 'fs';
+import * as fsp from 'fs/promises';
 import * as path from 'path';
 import { promisify } from 'util';
 
+import type { HomeyKind } from '../../shared/const.js';
+import { ErrorCategory, XError } from '../../shared/errors.js';
 import type { GitLite, GitLiteItem } from '../../shared/ipc/messages.js';
+import { throwIfAborted } from '../connection/deadline.js';
+import { shellQuote } from '../connection/shellQuote.js';
 import { getLogger } from '../logging/extension-logger.js';
 import { measure } from '../logging/perf.js';
+import {
+  type PathSelect,
+  shouldSkipFile,
+  type TransferOptions,
+} from '../transfer/FileTransferService.js';
 import { HostController } from './HostController.js';
 
 const exec = promisify(execCb);
+const execFile = promisify(execFileCb);
 const log = getLogger('GitController');
 
 export type PullOptions = {
+  /** 로컬 저장 경로(상대 경로는 워크스페이스 기준). homey 카테고리도 적용 */
   localPath?: string;
-};
+  /** 취소/전체 마감(createDeadline) — 전송 중 원격 명령까지 전파 */
+  signal?: AbortSignal;
+  /** 전송 진행(파일 수/바이트/속도/ETA) 알림 */
+  onProgress?: TransferOptions['onProgress'];
+} & PathSelect;
 export type PushOptions = {
+  /** host_sync: 업로드 대상 절대경로 / homey 카테고리: 원격 베이스 디렉터리 */
   hostPath?: string;
   /** UI 호출 시 ESC로 입력창이 취소되면 arg가 undefined가 되므로,
    *  이 경우를 '취소'로 간주하도록 의도를 명시할 수 있는 옵션(향후 호환용).
    *  현재 구현은 ui 여부와 무관하게 undefined를 취소로 처리한다. */
   ui?: boolean;
+  /** 취소/전체 마감(createDeadline) — 파일 사이에서도 확인해 남은 전송을 멈춘다 */
+  signal?: AbortSignal;
+  /** 미리보기: 업로드/삭제 계획만 출력하고 기기는 건드리지 않는다 */
+  dryRun?: boolean;
+  /** 로컬에서 지운 파일을 기기에서도 지울지 확인 — 거절하거나 없으면 삭제는 건너뛰고 업로드만 한다 */
+  confirmDelete?: (remotePaths: string[]) => Promise<boolean>;
+};
+
+/** 푸시 대상에서 제외되는 동기화 기록용 커밋 접두어 */
+export const SKIP_COMMIT_PREFIX = '[Do not push]';
+/** pull 시 자동 생성되는 다운로드 커밋 접두어 */
+export const DOWNLOAD_COMMIT_PREFIX = `${SKIP_COMMIT_PREFIX} download`;
+
+/** 워크스페이스 동기화 카테고리(homey_* 볼륨 + host_sync) */
+export type SyncCategory = HomeyKind | 'host';
+export const SYNC_CATEGORIES: readonly SyncCategory[] = ['pro', 'core', 'sdk', 'bridge', 'host'];
+
+/** 푸시할 변경 하나(워크스페이스 기준 경로) — deleted면 기기의 대응 파일을 지운다 */
+export type CommitChange = { file: string; deleted: boolean };
+/** 카테고리 매핑을 마친 푸시 계획 항목 */
+export type PushPlanItem = {
+  local: string;
+  remote: string;
+  category: SyncCategory;
+  deleted: boolean;
+};
+
+/** pull 결과: 받은 위치와 다운로드 커밋에 들어간 파일 수 */
+export type PullResult = {
+  target: 'pro' | 'core' | 'sdk' | 'bridge' | 'host';
+  remote: string;
+  local: string;
+  fileCount: number;
+};
+
+/** diff-device 결과: added = 기기에 없음, modified = 내용 다름 */
+export type DeviceDiffStatus = 'added' | 'modified' | 'identical';
+export type DeviceDiffEntry = { local: string; remote: string; status: DeviceDiffStatus };
+
+/** 원격 해시 명령 한 번에 넣는 파일 수(명령줄 길이 제한 대비) */
+const DIFF_HASH_BATCH = 64;
+/** getAllCommitChanges의 커밋 머리줄 표식(`%x1e` — 파일 경로에 나올 수 없는 RS 문자) */
+const COMMIT_MARK = '\x1e';
+
+export type CommitKind = 'download' | 'skip' | 'user';
+export type HistoryEntry = {
+  /** --graph 접두(`* `, `| ` 등) */
+  graph: string;
+  hash?: string;
+  date?: string;
+  subject?: string;
+  kind?: CommitKind;
 };
 
 const DEFAULT_PULL_MESSAGE: Record<string, string> = {
-  pro: '[Do not push] download homey_pro',
-  core: '[Do not push] download homey_core',
-  sdk: '[Do not push] download homey_sdk',
-  bridge: '[Do not push] download homey_bridge',
-  host: '[Do not push] download host_sync',
+  pro: `${DOWNLOAD_COMMIT_PREFIX} homey_pro`,
+  core: `${DOWNLOAD_COMMIT_PREFIX} homey_core`,
+  sdk: `${DOWNLOAD_COMMIT_PREFIX} homey_sdk`,
+  bridge: `${DOWNLOAD_COMMIT_PREFIX} homey_bridge`,
+  host: `${DOWNLOAD_COMMIT_PREFIX} host_sync`,
+};
+
+/** `git <args>` 실행기(셸 미경유) — 테스트에서는 저장소 없이 출력 문자열을 돌려주는 대역으로 바꾼다 */
+export type GitRunner = (
+  args: string[],
+  cwd: string,
+) => Promise<{ stdout: string; stderr: string }>;
+
+const runGit: GitRunner = async (args, cwd) => {
+  const { stdout, stderr } = await execFile('git', args, { cwd, maxBuffer: 32 * 1024 * 1024 });
+  return { stdout: String(stdout), stderr: String(stderr) };
 };
 
 export class GitController {
   constructor(
     private host: HostController,
     private workspaceFs: string,
+    private git: GitRunner = runGit,
   ) {}
 
   @measure()
@@ -42,7 +124,7 @@ export class GitController {
     target: 'pro' | 'core' | 'sdk' | 'bridge' | 'host',
     hostAbsPath?: string,
     opts?: PullOptions,
-  ) {
+  ): Promise<PullResult> {
     const ws = this.workspaceFs;
     let localBase = '';
     let remoteBase = '';
@@ -57,8 +139,11 @@ export class GitController {
       const kind = await this.host.statType(remoteBase);
       log.debug('[debug] pull:statType', { target, remoteBase, kind });
 
-      if (kind === 'FILE') await this.host.pullFile(remoteBase, localBase);
-      else if (kind === 'DIR') await this.host.pullDir(remoteBase, localBase);
+      if (kind === 'FILE') {
+        await this.host.pullFile(remoteBase, localBase, opts?.signal, opts?.onProgress);
+      } else if (kind === 'DIR') {
+        await this.host.pullDir(remoteBase, localBase, opts?.onProgress, opts?.signal, opts);
+      }
       else {
         log.error('[error] pull:path-not-found', {
           target,
@@ -69,12 +154,16 @@ export class GitController {
       }
     } else {
       remoteBase = await this.host.resolveHomeyPath(target);
-      localBase = path.join(ws, `homey_${target}`);
+      localBase = opts?.localPath
+        ? path.resolve(ws, opts.localPath)
+        : path.join(ws, (await this.host.getHomeyLayout()).local_dirs[target]);
 
       const kind = await this.host.statType(remoteBase);
       log.debug('[debug] pull:statType', { target, remoteBase, kind });
 
-      if (kind === 'DIR') await this.host.pullDir(remoteBase, localBase);
+      if (kind === 'DIR') {
+        await this.host.pullDir(remoteBase, localBase, opts?.onProgress, opts?.signal, opts);
+      }
       else {
         log.error('[error] pull:unexpected-type', { target, remoteBase, kind });
         throw new Error(`unexpected type for ${target}: ${kind}`);
@@ -84,10 +173,12 @@ export class GitController {
     const msg = DEFAULT_PULL_MESSAGE[target];
     const { fileCount, durationMs } = await this.commitAsync(msg);
     log.info(`pull[${target}] commit: ${fileCount} files, ${durationMs}ms`);
+    return { target, remote: remoteBase, local: localBase, fileCount };
   }
 
+  /** 반환: 실제로 올리거나 지운 항목(dryRun이면 계획) — 취소/변경 없음이면 빈 배열 */
   @measure()
-  async push(arg?: string, opts?: PushOptions) {
+  async push(arg?: string, opts?: PushOptions): Promise<PushPlanItem[]> {
     log.debug('[debug] push:start', {
       arg: typeof arg === 'undefined' ? '(undefined)' : arg,
       opts,
@@ -98,15 +189,16 @@ export class GitController {
     // - 명시적 전체 push는 빈 문자열('')로 처리하고, undefined는 '취소'로 간주한다.
     if (typeof arg === 'undefined') {
       log.info('push: cancelled (arg is undefined)');
-      return;
+      return [];
     }
-    const files = arg === '' ? await this.getAllCommitFiles() : await this._inferFilesFromArg(arg);
-    log.debug('[debug] push:files', { count: files.length });
-    if (files.length === 0) {
+    const changes =
+      arg === '' ? await this.getAllCommitChanges() : await this._inferChangesFromArg(arg);
+    log.debug('[debug] push:changes', { count: changes.length });
+    if (changes.length === 0) {
       log.info('push: 변경 파일이 없습니다.');
-      return;
+      return [];
     }
-    await this.pushFilesByCategory(files, opts);
+    return this.pushFilesByCategory(changes, opts);
   }
 
   // ────────────────────────────────────────────────────────────
@@ -126,97 +218,196 @@ export class GitController {
       else if (/^[ ][A-Z]/.test(code)) unstaged.push(file);
       else if (/^\?\?/.test(code)) untracked.push(file);
     }
-    log.info('=== git status ===');
-    if (staged.length) log.info(`[STAGED] (${staged.length})\n  - ${staged.join('\n  - ')}`);
+    log.result('=== git status ===');
+    if (staged.length) log.result(`[STAGED] (${staged.length})\n  - ${staged.join('\n  - ')}`);
     if (unstaged.length)
-      log.info(`[UNSTAGED] (${unstaged.length})\n  - ${unstaged.join('\n  - ')}`);
+      log.result(`[UNSTAGED] (${unstaged.length})\n  - ${unstaged.join('\n  - ')}`);
     if (untracked.length)
-      log.info(`[UNTRACKED] (${untracked.length})\n  - ${untracked.join('\n  - ')}`);
-    if (!lines.length) log.info('clean working tree');
+      log.result(`[UNTRACKED] (${untracked.length})\n  - ${untracked.join('\n  - ')}`);
+    if (!lines.length) log.result('clean working tree');
   }
   // ── Internals ───────────────────────────────────────────────
-  private async _inferFilesFromArg(arg: string): Promise<string[]> {
+  private async _inferChangesFromArg(arg: string): Promise<CommitChange[]> {
     // 커밋ID처럼 보이면: <arg>..HEAD 범위
-    if (/^[0-9a-f]{5,40}$/i.test(arg)) {
-      return this.getFilesSince(arg);
+    if (isCommitId(arg)) {
+      return this.getChangesSince(arg);
     }
     // 파일 경로로 취급
     const abs = path.isAbsolute(arg) ? arg : path.join(this.workspaceFs, arg);
-    if (fs.existsSync(abs)) return [abs];
+    if (fs.existsSync(abs)) return [{ file: abs, deleted: false }];
     log.info(`인식 실패: ${arg} — 커밋ID 또는 파일경로가 아닙니다.`);
     return [];
   }
 
   @measure()
-  async getAllCommitFiles(): Promise<string[]> {
-    // "[Do not push] download ..." 커밋은 제외
-    const { stdout } = await exec(
-      `git log --grep="^\\[Do not push\\] download" --invert-grep --name-only --pretty=format:`,
-      { cwd: this.workspaceFs },
+  async getAllCommitChanges(): Promise<CommitChange[]> {
+    // "[Do not push] ..." 커밋(다운로드/동기화 기록)은 parseCommitChanges에서 제외
+    // --no-renames: 이름 변경은 D(옛 경로) + A(새 경로)로 받아 기기에서도 옛 파일을 지운다
+    const { stdout } = await this.git(
+      ['log', '--name-status', '--no-renames', '--pretty=format:%x1e%s'],
+      this.workspaceFs,
     );
-    const set = new Set<string>();
-    stdout
-      .split(/\r?\n/)
-      .map((s) => s.trim())
-      .filter(Boolean)
-      .forEach((f) => set.add(path.join(this.workspaceFs, f)));
-    return Array.from(set);
+    return this.toWorkspace(parseCommitChanges(stdout));
   }
 
   @measure()
-  async getFilesSince(commitId: string): Promise<string[]> {
-    const { stdout } = await exec(`git diff --name-only ${commitId}..HEAD`, {
-      cwd: this.workspaceFs,
-    });
-    return stdout
-      .split(/\r?\n/)
-      .map((s) => s.trim())
-      .filter(Boolean)
-      .map((f) => path.join(this.workspaceFs, f));
+  async getChangesSince(commitId: string): Promise<CommitChange[]> {
+    const { stdout } = await this.git(
+      ['diff', '--name-status', '--no-renames', `${commitId}..HEAD`],
+      this.workspaceFs,
+    );
+    return this.toWorkspace(parseCommitChanges(stdout));
+  }
+
+  private toWorkspace(changes: CommitChange[]): CommitChange[] {
+    return changes.map((c) => ({ ...c, file: path.join(this.workspaceFs, c.file) }));
   }
 
+  /**
+   * 변경 → 카테고리별 업로드/삭제. dryRun이면 계획만 출력하고,
+   * 삭제는 confirmDelete로 확인받은 경우에만 업로드 뒤에 실행한다.
+   */
   @measure()
-  async pushFilesByCategory(files: string[], opts?: PushOptions) {
-    const buckets = {
-      pro: [] as string[],
-      core: [] as string[],
-      sdk: [] as string[],
-      bridge: [] as string[],
-      host: [] as string[],
-    };
-    for (const f of files) {
-      const norm = f.replace(/\\/g, '/');
-      if (norm.includes('/homey_pro/')) buckets.pro.push(f);
-      else if (norm.includes('/homey_core/')) buckets.core.push(f);
-      else if (norm.includes('/homey-apps-sdk-v3/') || norm.includes('/homey_sdk/'))
-        buckets.sdk.push(f);
-      else if (norm.includes('/homey-bridge/') || norm.includes('/homey_bridge/'))
-        buckets.bridge.push(f);
-      else if (norm.includes('/host_sync/')) buckets.host.push(f);
+  async pushFilesByCategory(changes: CommitChange[], opts?: PushOptions): Promise<PushPlanItem[]> {
+    if (opts?.hostPath && !opts.hostPath.startsWith('/')) {
+      throw new XError(ErrorCategory.Path, `HostPath는 절대 경로여야 합니다: ${opts.hostPath}`);
     }
-    // 전송(파일/디렉토리) — 현재는 훅으로 로깅만, 다음 단계에서 실제 전송 구현
-    for (const f of buckets.host) {
-      const target = opts?.hostPath ? opts.hostPath : this.host.toHostFromLocalHostSync(f);
-      await this.host.pushFile(f, target);
+    const plan = await this.planPush(changes, opts?.hostPath);
+    if (opts?.hostPath) assertSingleTarget(plan, opts.hostPath);
+    if (opts?.dryRun) {
+      printPushPlan(plan);
+      return plan;
     }
-    // homey_* 카테고리: 원격 베이스 + 상대경로 계산
-    for (const kind of ['pro', 'core', 'sdk', 'bridge'] as const) {
-      if (!(buckets as any)[kind].length) continue;
-      const base = await this.host.resolveHomeyPath(kind);
-      for (const f of (buckets as any)[kind] as string[]) {
-        const rel = this._relUnder(f, `homey_${kind}`);
-        await this.host.pushFile(f, path.posix.join(base, rel));
+    const uploads = plan.filter((p) => !p.deleted);
+    let deletes = plan.filter((p) => p.deleted);
+    if (deletes.length && !(await opts?.confirmDelete?.(deletes.map((p) => p.remote)))) {
+      log.info(`push: 기기 파일 삭제 ${deletes.length}건은 확인되지 않아 건너뜁니다.`);
+      deletes = [];
+    }
+    const sent: PushPlanItem[] = [];
+    for (const p of uploads) {
+      throwIfAborted(opts?.signal);
+      try {
+        await this.host.pushFile(p.local, p.remote, opts?.signal);
+        sent.push(p);
+      } catch (e: any) {
+        // 커밋 뒤 로컬에서 지운(아직 커밋 안 한) 파일 — 보낼 내용이 없으니 나머지는 계속 올린다
+        if (e?.code !== 'ENOENT') throw e;
+        log.warn(`push: 로컬 파일이 없어 건너뜁니다: ${p.local}`);
       }
     }
+    for (const p of deletes) {
+      throwIfAborted(opts?.signal);
+      await this.host.removeFile(p.remote, opts?.signal);
+    }
+    const n = (kind: SyncCategory) => sent.filter((p) => p.category === kind).length;
+    const missing = uploads.length - sent.length;
     log.info(
-      `push 완료 (host:${buckets.host.length}, pro:${buckets.pro.length}, core:${buckets.core.length}, sdk:${buckets.sdk.length}, bridge:${buckets.bridge.length})`,
+      `push 완료 (host:${n('host')}, pro:${n('pro')}, core:${n('core')}, sdk:${n('sdk')}, ` +
+        `bridge:${n('bridge')}, deleted:${deletes.length}` +
+        `${missing ? `, missing:${missing}` : ''})`,
     );
+    return [...sent, ...deletes];
+  }
+
+  /**
+   * 변경마다 카테고리/원격 경로 매핑 — 순서는 host → pro → core → sdk → bridge, 카테고리 밖 파일은 제외
+   * - 삭제는 -h(hostPath)와 무관하게 항상 기본 매핑으로 해상(엉뚱한 파일을 지우지 않도록)
+   * - 이력에 삭제로 남았어도 지금 로컬에 다시 있는 파일(이후 pull로 복원 등)은 지우지 않는다
+   */
+  private async planPush(changes: CommitChange[], hostPath?: string): Promise<PushPlanItem[]> {
+    // 로컬 디렉터리 이름은 레이아웃 설정을 따름(기본 homey_pro/homey_core/…)
+    const dirs = (await this.host.getHomeyLayout()).local_dirs;
+    const plan: PushPlanItem[] = [];
+    for (const category of ['host', 'pro', 'core', 'sdk', 'bridge'] as const) {
+      const mine = changes
+        .filter((c) => fileCategory(c.file, dirs) === category)
+        .filter((c) => !c.deleted || !fs.existsSync(c.file));
+      if (!mine.length) continue;
+      const base = category === 'host' ? '' : await this.host.resolveHomeyPath(category);
+      const mapped = (file: string) =>
+        category === 'host'
+          ? this.host.toHostFromLocalHostSync(file)
+          : homeyRemoteTarget(file, dirs[category], base);
+      for (const c of mine) {
+        // -h: host는 업로드 대상 경로, homey_*는 원격 베이스 교체(하위 경로 매핑은 동일)
+        const remote =
+          c.deleted || !hostPath
+            ? mapped(c.file)
+            : category === 'host'
+              ? hostPath
+              : homeyRemoteTarget(c.file, dirs[category], hostPath);
+        plan.push({ local: c.file, remote, category, deleted: c.deleted });
+      }
+    }
+    return plan;
+  }
+
+  /**
+   * 로컬 카테고리 파일 ↔ 기기 비교 — 원격에서는 해시만 구하고 아무것도 내려받지 않는다.
+   * 원격 경로는 push와 같은 매핑(host_sync → 절대경로, homey_* → 볼륨 베이스 + 상대경로)
+   */
+  @measure()
+  async diffDevice(category: SyncCategory, signal?: AbortSignal): Promise<DeviceDiffEntry[]> {
+    const dirs = (await this.host.getHomeyLayout()).local_dirs;
+    const root = path.join(this.workspaceFs, category === 'host' ? 'host_sync' : dirs[category]);
+    const base = category === 'host' ? '' : await this.host.resolveHomeyPath(category);
+    const pairs = (await listLocalFiles(root))
+      .filter((f) => fileCategory(f, dirs) === category)
+      .map((local) => ({
+        local,
+        remote:
+          category === 'host'
+            ? this.host.toHostFromLocalHostSync(local)
+            : homeyRemoteTarget(local, dirs[category], base),
+      }));
+    log.debug('[debug] diffDevice', { category, root, base, files: pairs.length });
+
+    const out: DeviceDiffEntry[] = [];
+    for (let i = 0; i < pairs.length; i += DIFF_HASH_BATCH) {
+      throwIfAborted(signal);
+      const batch = pairs.slice(i, i + DIFF_HASH_BATCH);
+      const { algo, hashes } = await this.remoteHashes(batch.map((p) => p.remote));
+      for (const [j, p] of batch.entries()) {
+        const remote = hashes.get(j);
+        const local = remote ? await localFileHash(p.local, algo) : undefined;
+        out.push({ ...p, status: !remote ? 'added' : remote === local ? 'identical' : 'modified' });
+      }
+    }
+    return out;
   }
 
-  private _relUnder(abs: string, marker: string): string {
-    const norm = abs.replace(/\\/g, '/');
-    const p = norm.split(`/${marker}/`)[1];
-    return p || path.basename(abs);
+  /** 원격 파일 해시(sha256sum, 없으면 md5sum) — 일반 파일이 아니면 결과에서 빠진다 */
+  private async remoteHashes(remotePaths: string[]) {
+    const script =
+      `h=sha256sum; command -v sha256sum >/dev/null 2>&1 || h=md5sum; echo "#$h"; i=0; ` +
+      `for f in ${remotePaths.map(shellQuote).join(' ')}; do ` +
+      `[ -f "$f" ] && echo "$i $($h < "$f" | cut -d" " -f1)"; i=$((i+1)); done; true`;
+    const { stdout } = await this.host.execOut(script);
+    return parseRemoteHashes(stdout);
+  }
+
+  /** 워크스페이스 커밋 이력(--graph) — 커밋 줄에는 분류(kind)를 붙인다 */
+  @measure()
+  async getHistory(limit = 50): Promise<HistoryEntry[]> {
+    const { stdout } = await this.runRaw([
+      'log',
+      '--graph',
+      '--all',
+      `-n${limit}`,
+      '--date=format:%Y-%m-%d %H:%M',
+      '--pretty=format:%x1f%h%x1f%ad%x1f%s',
+    ]);
+    return parseHistory(stdout);
+  }
+
+  /**
+   * 일반 `git <args>` 실행 — 셸을 거치지 않고 워크스페이스에서 실행해 출력 반환.
+   * 출력 채널은 ANSI를 렌더링하지 않으므로 color는 끈다.
+   */
+  @measure()
+  async runRaw(args: string[]): Promise<{ stdout: string; stderr: string }> {
+    return this.git(['-c', 'color.ui=never', ...args], this.workspaceFs);
   }
 
   @measure()
@@ -239,6 +430,140 @@ export class GitController {
   }
 }
 
+export function classifyCommit(subject: string): CommitKind {
+  if (subject.startsWith(DOWNLOAD_COMMIT_PREFIX)) return 'download';
+  if (subject.startsWith(SKIP_COMMIT_PREFIX)) return 'skip';
+  return 'user';
+}
+
+/** 축약~전체 SHA(7~40자리 16진수)처럼 보이는지 — push 인자를 커밋ID/파일 경로로 가를 때 사용 */
+export function isCommitId(arg: string): boolean {
+  return /^[0-9a-f]{7,40}$/i.test(arg);
+}
+
+/**
+ * `git log --name-status --pretty=format:%x1e%s`(또는 `git diff --name-status`) 출력
+ * → 푸시할 변경(상대 경로, 처음 나온 순서, 파일당 하나)
+ * - 사용자 커밋의 파일만 모으고 `[Do not push]` 커밋의 파일은 건너뛴다.
+ * - log는 최신 커밋부터 나오므로 파일마다 처음 본 상태(가장 최근 변경)가 이긴다: D면 삭제, 그 외는 업로드.
+ *   `[Do not push]` 커밋이 더 최근이면(기기에서 다시 받음) 그 파일의 옛 사용자 변경도 보내지 않는다.
+ */
+export function parseCommitChanges(stdout: string): CommitChange[] {
+  const changes = new Map<string, CommitChange>();
+  const seen = new Set<string>();
+  let skip = false;
+  for (const raw of String(stdout ?? '').split(/\r?\n/)) {
+    if (raw.startsWith(COMMIT_MARK)) {
+      skip = classifyCommit(raw.slice(COMMIT_MARK.length)) !== 'user';
+      continue;
+    }
+    const m = /^([A-Z])\d*\t(.+)$/.exec(raw.trim());
+    if (!m || seen.has(m[2])) continue;
+    seen.add(m[2]);
+    if (!skip) changes.set(m[2], { file: m[2], deleted: m[1] === 'D' });
+  }
+  return [...changes.values()];
+}
+
+/**
+ * -h(hostPath)는 대상 하나만 가리킨다 — 업로드가 여러 카테고리에 걸치거나(pro/core가 한 베이스로 섞임)
+ * host_sync 파일이 둘 이상이면(모두 같은 경로에 덮어씀) 보내기 전에 거절한다
+ */
+function assertSingleTarget(plan: PushPlanItem[], hostPath: string) {
+  const uploads = plan.filter((p) => !p.deleted);
+  const categories = [...new Set(uploads.map((p) => p.category))];
+  const hostFiles = uploads.filter((p) => p.category === 'host').length;
+  if (categories.length <= 1 && hostFiles <= 1) return;
+  const what =
+    categories.length > 1
+      ? `여러 카테고리(${categories.join(', ')})`
+      : `host_sync 파일 ${hostFiles}개`;
+  throw new XError(
+    ErrorCategory.Path,
+    `-h ${hostPath}는 한 카테고리(host_sync는 파일 하나)에만 쓸 수 있습니다 — ${what}가 포함됨. ` +
+      '-h 없이 push하거나 대상을 나눠 push하세요.',
+    { categories, hostFiles },
+  );
+}
+
+/** dry-run 출력: `U 로컬 → 원격` / `D 원격` */
+function printPushPlan(plan: PushPlanItem[]) {
+  log.result('=== push (dry-run) ===');
+  for (const p of plan) log.result(p.deleted ? `D ${p.remote}` : `U ${p.local} → ${p.remote}`);
+  const deleted = plan.filter((p) => p.deleted).length;
+  log.result(`[info] push dry-run: ${plan.length - deleted} upload(s), ${deleted} delete(s)`);
+}
+
+/** `git log --graph --pretty=format:%x1f%h%x1f%ad%x1f%s` 출력 파싱 */
+export function parseHistory(stdout: string): HistoryEntry[] {
+  return stdout
+    .split(/\r?\n/)
+    .filter((ln) => ln.trim())
+    .map((ln) => {
+      const [graph, hash, date, ...rest] = ln.split('\x1f');
+      if (hash === undefined) return { graph: ln.trimEnd() };
+      const subject = rest.join('\x1f');
+      return { graph, hash, date, subject, kind: classifyCommit(subject) };
+    });
+}
+
+/**
+ * 로컬 카테고리 파일 → 원격 경로: `<base>/<dirName 아래 상대경로>`
+ * (dirName 밖의 파일이면 파일명만 사용)
+ */
+export function homeyRemoteTarget(localFile: string, dirName: string, base: string): string {
+  const norm = localFile.replace(/\\/g, '/');
+  const rel = norm.split(`/${dirName}/`)[1] || path.posix.basename(norm);
+  return path.posix.join(base, rel);
+}
+
+/** 로컬 파일이 속한 동기화 카테고리(레이아웃 local_dirs / host_sync 기준), 해당 없으면 undefined */
+export function fileCategory(
+  file: string,
+  dirs: Record<HomeyKind, string>,
+): SyncCategory | undefined {
+  const norm = file.replace(/\\/g, '/');
+  const under = (dir: string) => norm.includes(`/${dir}/`);
+  if (under(dirs.pro)) return 'pro';
+  if (under(dirs.core)) return 'core';
+  if (norm.includes('/homey-apps-sdk-v3/') || under(dirs.sdk)) return 'sdk';
+  if (norm.includes('/homey-bridge/') || under(dirs.bridge)) return 'bridge';
+  if (norm.includes('/host_sync/')) return 'host';
+  return undefined;
+}
+
+/** remoteHashes 출력(`#sha256sum|#md5sum` 머리줄 + `<순번> <해시>` 줄) 파싱 */
+export function parseRemoteHashes(stdout: string): {
+  algo: 'sha256' | 'md5';
+  hashes: Map<number, string>;
+} {
+  const hashes = new Map<number, string>();
+  let algo: 'sha256' | 'md5' = 'sha256';
+  for (const raw of String(stdout ?? '').split(/\r?\n/)) {
+    const line = raw.trim();
+    if (line === '#md5sum') algo = 'md5';
+    const m = /^(\d+) ([0-9a-f]{32,64})$/i.exec(line);
+    if (m) hashes.set(Number(m[1]), m[2].toLowerCase());
+  }
+  return { algo, hashes };
+}
+
+async function localFileHash(file: string, algo: string): Promise<string> {
+  return crypto.createHash(algo).update(await fsp.readFile(file)).digest('hex');
+}
+
+/** 로컬 디렉터리의 파일(절대경로, 재귀·정렬) — .git 등 전송 제외 대상은 건너뜀 */
+async function listLocalFiles(dir: string): Promise<string[]> {
+  const out: string[] = [];
+  for (const ent of await fsp.readdir(dir, { withFileTypes: true }).catch(() => [])) {
+    if (shouldSkipFile(ent.name)) continue;
+    const p = path.join(dir, ent.name);
+    if (ent.isDirectory()) out.push(...(await listLocalFiles(p)));
+    else if (ent.isFile()) out.push(p);
+  }
+  return out.sort();
+}
+
 // ────────────────────────────────────────────────────────────
 // Git status (lightweight) helpers
 // ────────────────────────────────────────────────────────────
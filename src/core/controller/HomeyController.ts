@@ -1,10 +1,17 @@
-import { ErrorCategory, XError } from '../../shared/errors.js';
+import { notConnectedError } from '../../shared/errors.js';
 import { connectionManager } from '../connection/ConnectionManager.js';
 import { getLogger } from '../logging/extension-logger.js';
 import { measure } from '../logging/perf.js';
+import {
+  invalidateHomeyUnitCache,
+  resolveHomeyUnit,
+  setHomeyUnit,
+} from '../service/serviceDiscovery.js';
+import { parseServiceEnv, type ServiceEnvVar } from '../service/serviceEnv.js';
+import { ServiceFilePatcher } from '../service/ServiceFilePatcher.js';
 import { MountTaskRunner } from '../tasks/MountTaskRunner.js';
 import { RestartTaskRunner } from '../tasks/RestartTaskRunner.js';
-import { ToggleTaskRunner } from '../tasks/ToggleTaskRunner.js';
+import { ServiceEnvTaskRunner } from '../tasks/ServiceEnvTaskRunner.js';
 import { UnmountTaskRunner } from '../tasks/UnmountTaskRunner.js';
 
 const log = getLogger('HomeyController');
@@ -15,53 +22,80 @@ export class HomeyController {
   private async ensureConnected() {
     await connectionManager.connect();
     if (!connectionManager.isConnected()) {
-      throw new XError(
-        ErrorCategory.Connection,
-        '활성 연결이 없습니다. 먼저 "기기 연결"을 수행하세요.',
-      );
+      throw notConnectedError();
     }
   }
 
   @measure()
-  async restart() {
+  async restart(signal?: AbortSignal) {
     log.debug('[debug] HomeyController restart: start');
     await this.ensureConnected();
-    await new RestartTaskRunner().run();
+    await new RestartTaskRunner().run(signal);
     log.debug('[debug] HomeyController restart: end');
   }
 
+  /** 캐시를 버리고 Homey 서비스 유닛을 다시 탐지 — service를 주면 탐지 대신 그 유닛으로 고정 */
   @measure()
-  async mount() {
+  async redetectServiceUnit(service?: string): Promise<string> {
+    await this.ensureConnected();
+    invalidateHomeyUnitCache('redetect');
+    if (service) return await setHomeyUnit(service);
+    const unit = await resolveHomeyUnit(undefined, { force: true });
+    log.info(`HomeyController redetectServiceUnit: ${unit}`);
+    return unit;
+  }
+
+  @measure()
+  async mount(signal?: AbortSignal) {
     // ✅ 정책: homey-app + homey-node 둘 다 삽입
     log.debug('[debug] HomeyController mount: start');
     await this.ensureConnected();
     const runner = new MountTaskRunner(); // default ['pro','core']
-    await runner.run();
+    await runner.run(signal);
     log.debug('[debug] HomeyController mount: end');
   }
 
   @measure()
-  async unmount() {
+  async unmount(signal?: AbortSignal) {
     log.debug('[debug] HomeyController unmount: start');
     await this.ensureConnected();
     const runner = new UnmountTaskRunner();
-    await runner.run();
+    await runner.run(signal);
     log.debug('[debug] HomeyController unmount: end');
   }
 
   @measure()
-  async toggleAppLog(enable: boolean) {
+  async toggleAppLog(enable: boolean, signal?: AbortSignal) {
     log.debug('[debug] HomeyController toggleAppLog: start', { enable });
     await this.ensureConnected();
-    await new ToggleTaskRunner('HOMEY_APP_LOG', enable).run();
+    await new ServiceEnvTaskRunner('HOMEY_APP_LOG', enable ? '1' : undefined).run(signal);
     log.debug('[debug] HomeyController toggleAppLog: end');
   }
 
   @measure()
-  async toggleDevToken(enable: boolean) {
+  async toggleDevToken(enable: boolean, signal?: AbortSignal) {
     log.debug('[debug] HomeyController toggleDevToken: start', { enable });
     await this.ensureConnected();
-    await new ToggleTaskRunner('HOMEY_DEV_TOKEN', enable).run();
+    await new ServiceEnvTaskRunner('HOMEY_DEV_TOKEN', enable ? '1' : undefined).run(signal);
     log.debug('[debug] HomeyController toggleDevToken: end');
   }
+
+  /** 서비스 파일의 `--env=` 목록 */
+  @measure()
+  async listServiceEnv(): Promise<{ unit: string; path: string; vars: ServiceEnvVar[] }> {
+    await this.ensureConnected();
+    const unit = await resolveHomeyUnit();
+    const svc = new ServiceFilePatcher(unit);
+    const path = await svc.resolveServicePath();
+    return { unit, path, vars: parseServiceEnv(await svc.read(path)) };
+  }
+
+  /** value가 undefined면 제거 — 변경 시 서비스 재시작 */
+  @measure()
+  async setServiceEnv(key: string, value: string | undefined, signal?: AbortSignal) {
+    log.debug('[debug] HomeyController setServiceEnv: start', { key, value });
+    await this.ensureConnected();
+    await new ServiceEnvTaskRunner(key, value).run(signal);
+    log.debug('[debug] HomeyController setServiceEnv: end');
+  }
 }
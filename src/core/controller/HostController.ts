@@ -4,14 +4,73 @@ import * as fsp from 'fs/promises';
 import * as os from 'os';
 import * as path from 'path';
 
+import {
+  type HomeyKind,
+  type HomeyLayout,
+  PUSH_SPACE_CHECK_MIN_BYTES,
+  REMOTE_PSEUDO_FS_DIRS,
+} from '../../shared/const.js';
+import { ErrorCategory, RemoteCommandError, XError } from '../../shared/errors.js';
+import { loadHomeyLayout } from '../config/homeyLayout.js';
 import type { IConnectionManager } from '../connection/ConnectionManager.js';
 import { connectionManager } from '../connection/ConnectionManager.js';
+import { shellQuote } from '../connection/shellQuote.js';
 import { getLogger } from '../logging/extension-logger.js';
 import { measure } from '../logging/perf.js';
-import { FileTransferService } from '../transfer/FileTransferService.js';
+import {
+  assertTransferComplete,
+  FileTransferService,
+  type PathSelect,
+  sanitizeWindowsPath,
+  shouldSkipFile,
+  type TransferOptions,
+  type TransferSummary,
+} from '../transfer/FileTransferService.js';
+import { fmtBytes, parseDuKiB } from '../transfer/TransferMeter.js';
 
 const log = getLogger('HostController');
 
+export type RemoteEntryType = 'dir' | 'file' | 'link' | 'other';
+export type RemoteEntry = {
+  name: string;
+  type: RemoteEntryType;
+  /** bytes (장치 파일 등 크기가 없는 항목은 0) */
+  size: number;
+  /** 심볼릭 링크 대상(`name -> target`) */
+  target?: string;
+};
+
+// 권한 링크수 소유자 그룹 크기 날짜 이름 — 날짜는 `Jan  1 00:00`/`Jan  1  2024`(GNU/BusyBox) 또는
+// `2024-01-01 00:00`(Android toybox), 장치 파일은 크기 자리에 `major, minor`
+const LS_LINE = new RegExp(
+  /^([-dlcbps])\S{9}\S?\s+\d+\s+\S+\s+\S+\s+(\d+|\d+,\s*\d+)\s+/.source +
+    /(?:\w{3}\s+\d{1,2}\s+[\d:]+|\d{4}-\d\d-\d\d\s+[\d:.]+(?:\s+[+-]\d{4})?)\s(.*)$/.source,
+);
+
+/** /proc, /sys, /dev 또는 그 아래 경로면 true(가상 파일시스템은 받을 수 없다) */
+export function isPseudoFsPath(absPath: string): boolean {
+  const p = path.posix.normalize(absPath).replace(/(.)\/+$/, '$1');
+  return REMOTE_PSEUDO_FS_DIRS.some((d) => p === d || p.startsWith(`${d}/`));
+}
+
+/** `ls -la` 출력 → 항목 목록(`total` 줄, `.`/`..` 제외, 디렉터리 먼저 이름순) */
+export function parseLsLong(stdout: string): RemoteEntry[] {
+  const kinds: Record<string, RemoteEntryType> = { '-': 'file', d: 'dir', l: 'link' };
+  const out: RemoteEntry[] = [];
+  for (const line of String(stdout ?? '').split(/\r?\n/)) {
+    const m = LS_LINE.exec(line);
+    if (!m) continue;
+    const type = kinds[m[1]] ?? 'other';
+    const arrow = type === 'link' ? m[3].indexOf(' -> ') : -1;
+    const name = arrow >= 0 ? m[3].slice(0, arrow) : m[3];
+    if (name === '.' || name === '..') continue;
+    const target = arrow >= 0 ? m[3].slice(arrow + 4) : undefined;
+    out.push({ name, type, size: /^\d+$/.test(m[2]) ? Number(m[2]) : 0, target });
+  }
+  const rank = (e: RemoteEntry) => (e.type === 'dir' ? 0 : 1);
+  return out.sort((a, b) => rank(a) - rank(b) || a.name.localeCompare(b.name));
+}
+
 export class HostController {
   constructor(
     private cm: IConnectionManager = connectionManager,
@@ -19,17 +78,13 @@ export class HostController {
   ) {}
 
   // ── Utils ──────────────────────────────────────────────────
-  private sq(s: string) {
-    // POSIX single-quote safe: 'a'\''b'
-    return String(s).replace(/'/g, `'\\''`);
-  }
   private wrap(cmd: string) {
     // ✅ ADB: 여기서는 절대 감싸지 않는다(이중 래핑 방지). adbClient.adbShell()이 단 한 번만 감쌈.
     // ✅ SSH: 기존대로 로그인 셸(-lc)로 감싼다.
     const flat = cmd.replace(/\n/g, ' ').trim();
     const t = this.cm.getSnapshot()?.active?.type;
     if (t === 'ADB') return flat;
-    return `sh -lc '${this.sq(flat)}'`;
+    return `sh -lc ${shellQuote(flat)}`;
   }
   private normLocal(p: string) {
     return p.replace(/\\/g, '/');
@@ -50,10 +105,10 @@ export class HostController {
   @measure()
   async statType(absPath: string): Promise<'FILE' | 'DIR' | 'NONE'> {
     // BusyBox/임베디드 안전: -e 대신 세부 타입(-f/-L/-h/-c/-b/-p/-S) 사용
-    const rp = absPath;
+    const rp = shellQuote(absPath);
     const script =
-      `if [ -d "${rp}" ]; then echo DIR; ` +
-      `elif [ -f "${rp}" ] || [ -L "${rp}" ] || [ -h "${rp}" ] || [ -c "${rp}" ] || [ -b "${rp}" ] || [ -p "${rp}" ] || [ -S "${rp}" ]; then echo FILE; ` +
+      `if [ -d ${rp} ]; then echo DIR; ` +
+      `elif [ -f ${rp} ] || [ -L ${rp} ] || [ -h ${rp} ] || [ -c ${rp} ] || [ -b ${rp} ] || [ -p ${rp} ] || [ -S ${rp} ]; then echo FILE; ` +
       `else echo NONE; fi`;
     const wrapped = this.wrap(script);
     log.debug('[debug] statType: request', {
@@ -72,15 +127,20 @@ export class HostController {
 
   @measure()
   async ensureDir(absPath: string) {
-    const wrapped = this.wrap(`mkdir -p "${absPath}"`);
+    const wrapped = this.wrap(`mkdir -p ${shellQuote(absPath)}`);
     log.debug('[debug] ensureDir', { absPath, wrapped });
     await this.cm.run(wrapped);
   }
 
-  // Docker Root (eg. /lg_rw/var/lib/docker)
+  /** 기기 레이아웃(기본값 ← 사용자 설정 ← 연결별 설정) */
+  async getHomeyLayout(): Promise<HomeyLayout> {
+    return loadHomeyLayout(this.workspaceFs, this.cm.getSnapshot()?.active?.id);
+  }
+
+  // Docker Root (eg. /lg_rw/var/lib/docker) — 조회 실패 시 레이아웃 폴백
   @measure()
-  async getDockerRoot(): Promise<string> {
-    let root = '/lg_rw/var/lib/docker';
+  async getDockerRoot(fallback?: string): Promise<string> {
+    let root = fallback ?? (await this.getHomeyLayout()).docker_root_fallback;
     const wrapped = this.wrap(`docker info -f "{{.DockerRootDir}}" 2>/dev/null || true`);
     log.debug('[debug] getDockerRoot: request', { wrapped });
     const { stdout } = await this.cm.run(wrapped);
@@ -93,21 +153,40 @@ export class HostController {
     return root;
   }
 
+  /**
+   * 카테고리 원격 경로 해상 순서:
+   *  1) 레이아웃 paths[kind] (절대 경로 강제)
+   *  2) docker volume inspect 의 Mountpoint + subpath
+   *  3) <DockerRoot>/volumes/<volume>/_data + subpath
+   */
   @measure()
-  async resolveHomeyPath(kind: 'pro' | 'core' | 'sdk' | 'bridge'): Promise<string> {
-    const dockerRoot = await this.getDockerRoot();
-    let p: string;
-    if (kind === 'pro') p = path.posix.join(dockerRoot, 'volumes/homey-app/_data');
-    else if (kind === 'core')
-      p = path.posix.join(dockerRoot, 'volumes/homey-node/_data/@athombv/homey-core/dist');
-    else if (kind === 'sdk')
-      p = path.posix.join(dockerRoot, 'volumes/homey-node/_data/@athombv/homey-apps-sdk-v3');
-    else
-      /* bridge */ p = path.posix.join(
-        dockerRoot,
-        'volumes/homey-node/_data/@athombv/homey-bridge',
+  async resolveHomeyPath(kind: HomeyKind): Promise<string> {
+    const layout = await this.getHomeyLayout();
+    const forced = layout.paths[kind];
+    if (forced) {
+      log.debug('[debug] resolveHomeyPath(config)', { kind, path: forced });
+      return forced;
+    }
+    const { volume, subpath } = layout.volumes[kind];
+    const { stdout } = await this.cm.run(
+      this.wrap(
+        `docker volume inspect -f "{{.Mountpoint}}" ${shellQuote(volume)} 2>/dev/null || true`,
+      ),
+    );
+    const mount = String(stdout || '')
+      .split(/\r?\n/)
+      .map((s) => s.trim())
+      .find((s) => s.startsWith('/'));
+    const base =
+      mount ??
+      path.posix.join(
+        await this.getDockerRoot(layout.docker_root_fallback),
+        'volumes',
+        volume,
+        '_data',
       );
-    log.debug('[debug] resolveHomeyPath', { kind, path: p });
+    const p = subpath ? path.posix.join(base, subpath) : base;
+    log.debug('[debug] resolveHomeyPath', { kind, volume, mount, path: p });
     return p;
   }
 
@@ -136,45 +215,223 @@ export class HostController {
   }
 
   @measure()
-  async pullFile(absHost: string, localFs: string) {
+  async pullFile(
+    absHost: string,
+    localFs: string,
+    signal?: AbortSignal,
+    onProgress?: TransferOptions['onProgress'],
+  ) {
     await this.ensureLocalDir(path.dirname(localFs));
     const remoteDir = path.posix.dirname(absHost);
     const baseName = path.posix.basename(absHost);
     const tmp = await fsp.mkdtemp(path.join(os.tmpdir(), 'edge-pull-'));
     log.debug('[debug] pullFile: plan', { absHost, localFs, remoteDir, baseName, tmp });
     try {
-      await this.getFT().downloadViaTarBase64(remoteDir, tmp, { paths: [baseName] });
-      const src = path.join(tmp, baseName);
+      // 단일 파일은 저장 경로(localFs)가 따로 정해지므로 임시 이름만 안전하게 바꿈
+      const win = process.platform === 'win32';
+      const s = await this.getFT().downloadViaTarBase64(remoteDir, tmp, {
+        paths: [baseName],
+        illegalNames: win ? 'rename' : 'keep',
+        signal,
+        onProgress,
+      });
+      assertTransferComplete(`pull ${absHost}`, s);
+      const src = path.join(tmp, win ? sanitizeWindowsPath(baseName) : baseName);
       const buf = await fsp.readFile(src);
       await fsp.writeFile(localFs, buf);
-      log.info(`[pullFile] ${absHost} -> ${localFs} (${buf.length} bytes)`);
+      const secs = (s.elapsedMs ?? 0) / 1000;
+      const rate = secs > 0 ? `, ${fmtBytes(Math.round(buf.length / secs))}/s` : '';
+      log.info(`[pullFile] ${absHost} -> ${localFs} (${buf.length} bytes${rate})`);
     } finally {
       await fsp.rm(tmp, { recursive: true, force: true });
     }
   }
 
+  /** 디렉터리 재귀 다운로드(스킵 규칙 + include/exclude glob 적용). 결과 요약 반환 */
   @measure()
-  async pullDir(absHostDir: string, localDir: string) {
+  async pullDir(
+    absHostDir: string,
+    localDir: string,
+    onProgress?: TransferOptions['onProgress'],
+    signal?: AbortSignal,
+    select?: PathSelect,
+  ): Promise<TransferSummary> {
     await this.ensureLocalDir(localDir);
-    log.debug('[debug] pullDir: plan', { absHostDir, localDir });
-    await this.getFT().downloadViaTarBase64(absHostDir, localDir);
-    log.info(`[pullDir] ${absHostDir} -> ${localDir}`);
+    log.debug('[debug] pullDir: plan', { absHostDir, localDir, select });
+    const s = await this.getFT().downloadViaTarBase64(absHostDir, localDir, {
+      onProgress,
+      signal,
+      include: select?.include,
+      exclude: select?.exclude,
+    });
+    assertTransferComplete(`pull ${absHostDir}`, s);
+    const renamed = s.renamed ? `, ${s.renamed} renamed` : '';
+    const secs = ((s.elapsedMs ?? 0) / 1000).toFixed(1);
+    const moved = s.bytes ? `, ${fmtBytes(s.bytes)} in ${secs}s` : '';
+    log.info(
+      `[pullDir] ${absHostDir} -> ${localDir} ` +
+        `(${s.done} ok, ${s.skipped} skipped${renamed}${moved})`,
+    );
+    return s;
   }
 
+  /** 원격 디렉터리(없으면 가장 가까운 상위)의 여유 공간(bytes). 판별 불가면 undefined */
   @measure()
-  async pushFile(localFs: string, absHost: string) {
+  async getRemoteFreeBytes(absHostDir: string): Promise<number | undefined> {
+    const cmd = [
+      `d=${shellQuote(absHostDir)}`,
+      `while [ ! -d "$d" ] && [ "$d" != "/" ]; do d=$(dirname "$d"); done`,
+      `df -kP "$d" 2>/dev/null || df -k "$d" 2>/dev/null || df "$d"`,
+    ].join('; ');
+    const { stdout } = await this.cm.run(this.wrap(cmd));
+    return parseDfAvailableBytes(stdout);
+  }
+
+  /**
+   * 대용량 push 전 여유 공간 확인 — 부족하면 숫자를 담은 XError(InsufficientSpace)
+   * - PUSH_SPACE_CHECK_MIN_BYTES 미만은 건너뜀(작은 파일마다 df 왕복 방지)
+   * - SSH는 /tmp에 base64 스테이징(≈4/3배)도 하므로 함께 확인
+   */
+  async ensureRemoteSpace(absHostDir: string, bytes: number) {
+    if (bytes < PUSH_SPACE_CHECK_MIN_BYTES) return;
+    const checks: [string, number][] = [[absHostDir, bytes]];
+    if (this.cm.getSnapshot()?.active?.type !== 'ADB') checks.push(['/tmp', (bytes * 4) / 3]);
+    for (const [dir, size] of checks) {
+      const need = Math.ceil(size * 1.1); // 블록/메타 여유 10%
+      const free = await this.getRemoteFreeBytes(dir).catch(() => undefined);
+      if (free === undefined) {
+        log.warn(`[space] cannot determine free space at ${dir}; continuing`);
+        continue;
+      }
+      log.debug('[debug] ensureRemoteSpace', { dir, need, free });
+      if (free < need) {
+        throw new XError(
+          ErrorCategory.InsufficientSpace,
+          `insufficient space on device: ${dir} 여유 ${fmtMiB(free)} < 필요 ${fmtMiB(need)}`,
+          { dir, free, need },
+        );
+      }
+    }
+  }
+
+  @measure()
+  async pushFile(localFs: string, absHost: string, signal?: AbortSignal) {
     const remoteDir = path.posix.dirname(absHost);
     const baseName = path.posix.basename(absHost);
     const baseDir = path.dirname(localFs);
     log.debug('[debug] pushFile: plan', { localFs, absHost, baseDir, remoteDir, baseName });
-    await this.getFT().uploadViaTarBase64(baseDir, remoteDir, { paths: [baseName] });
+    await this.ensureRemoteSpace(remoteDir, (await fsp.stat(localFs)).size);
+    const s = await this.getFT().uploadViaTarBase64(baseDir, remoteDir, {
+      paths: [baseName],
+      signal,
+    });
+    assertTransferComplete(`push ${absHost}`, s);
     log.info(`[pushFile] ${localFs} -> ${absHost}`);
   }
 
+  /** 기기 파일 하나 삭제(로컬 삭제를 push로 전파) — 디렉터리는 지우지 않고, 이미 없으면 통과 */
   @measure()
-  async pushDir(localDir: string, absHostDir: string) {
+  async removeFile(absHost: string, signal?: AbortSignal) {
+    const rp = shellQuote(absHost);
+    const cmd = `if [ -d ${rp} ]; then echo "is a directory" >&2; exit 1; fi; rm -f -- ${rp}`;
+    const { code, stdout, stderr } = await this.cm.run(this.wrap(cmd), [], { signal });
+    if (code !== 0) {
+      const out = `${stdout}${stderr}`.trim();
+      throw new RemoteCommandError(cmd, code, out, `기기 파일 삭제 실패: ${absHost} (${out})`);
+    }
+    log.info(`[removeFile] ${absHost}`);
+  }
+
+  /** 링크를 따라간 실제 경로(`readlink -f`) — 실패하면 입력 그대로 */
+  @measure()
+  async realPath(absPath: string, signal?: AbortSignal): Promise<string> {
+    const cmd = `readlink -f ${shellQuote(absPath)} 2>/dev/null`;
+    const { stdout } = await this.cm.run(this.wrap(cmd), [], { signal });
+    return String(stdout || '').trim() || absPath;
+  }
+
+  /** 원격 경로의 디스크 사용량(`du -sk`, bytes) — 측정할 수 없으면 undefined */
+  @measure()
+  async sizeBytes(absPath: string, signal?: AbortSignal): Promise<number | undefined> {
+    const cmd = `du -sk ${shellQuote(absPath)} 2>/dev/null`;
+    const { stdout } = await this.cm.run(this.wrap(cmd), [], { signal });
+    return parseDuKiB(stdout);
+  }
+
+  /** 원격 디렉터리 한 단계 목록(`ls -la`) — 없거나 읽을 수 없으면 RemoteCommandError */
+  @measure()
+  async listDir(absHostDir: string, signal?: AbortSignal): Promise<RemoteEntry[]> {
+    // 끝의 `/`: 디렉터리를 가리키는 링크면 링크 자체가 아니라 안쪽을 나열
+    const dir = absHostDir.endsWith('/') ? absHostDir : `${absHostDir}/`;
+    const cmd = `LC_ALL=C ls -la ${shellQuote(dir)}`;
+    const { code, stdout, stderr } = await this.cm.run(this.wrap(cmd), [], { signal });
+    if (code !== 0) {
+      const out = String(stderr || stdout).trim();
+      throw new RemoteCommandError(cmd, code, out, `디렉터리 목록 실패: ${absHostDir} (${out})`);
+    }
+    const entries = parseLsLong(stdout);
+    log.debug('[debug] listDir', { absHostDir, count: entries.length });
+    return entries;
+  }
+
+  /** 디렉터리 재귀 업로드(스킵 규칙 적용). 결과 요약 반환 */
+  @measure()
+  async pushDir(
+    localDir: string,
+    absHostDir: string,
+    onProgress?: TransferOptions['onProgress'],
+    signal?: AbortSignal,
+  ): Promise<TransferSummary> {
     log.debug('[debug] pushDir: plan', { localDir, absHostDir });
-    await this.getFT().uploadViaTarBase64(localDir, absHostDir);
-    log.info(`[pushDir] ${localDir} -> ${absHostDir}`);
+    await this.ensureRemoteSpace(absHostDir, await localTreeBytes(localDir));
+    const s = await this.getFT().uploadViaTarBase64(localDir, absHostDir, {
+      onProgress,
+      signal,
+    });
+    assertTransferComplete(`push ${localDir}`, s);
+    log.info(`[pushDir] ${localDir} -> ${absHostDir} (${s.done} ok, ${s.skipped} skipped)`);
+    return s;
+  }
+}
+
+/**
+ * `df` 출력에서 가용 공간(bytes) 추출 — 구현별 형식 차이 흡수
+ * - coreutils/busybox(-P)/toybox: "Filesystem 1K-blocks|1024-blocks Used Available …" (KiB 정수)
+ * - 구형 Android toolbox: "Filesystem Size Used Free Blksize" (1.2G 같은 단위 접미사)
+ * - 장치명이 길어 줄바꿈된 경우도 이어 붙여 처리
+ */
+export function parseDfAvailableBytes(out: string): number | undefined {
+  const lines = String(out || '')
+    .split(/\r?\n/)
+    .map((s) => s.trim())
+    .filter(Boolean);
+  const hi = lines.findIndex((l) => /^Filesystem\b/i.test(l));
+  if (hi < 0 || hi === lines.length - 1) return undefined;
+  const col = lines[hi].split(/\s+/).findIndex((h) => /^(Available|Avail|Free)$/i.test(h));
+  if (col < 0) return undefined;
+  const raw = lines
+    .slice(hi + 1)
+    .join(' ')
+    .split(/\s+/)[col];
+  const m = /^(\d+(?:\.\d+)?)([KMGT])?$/i.exec(raw ?? '');
+  if (!m) return undefined;
+  const n = Number(m[1]);
+  const unit = (m[2] ?? 'K').toUpperCase();
+  const mul = { K: 1024, M: 1024 ** 2, G: 1024 ** 3, T: 1024 ** 4 }[unit] ?? 1024;
+  return Math.floor(n * mul);
+}
+
+async function localTreeBytes(dir: string): Promise<number> {
+  let total = 0;
+  for (const ent of await fsp.readdir(dir, { withFileTypes: true }).catch(() => [])) {
+    if (shouldSkipFile(ent.name)) continue;
+    const p = path.join(dir, ent.name);
+    if (ent.isDirectory()) total += await localTreeBytes(p);
+    else if (ent.isFile()) total += (await fsp.stat(p).catch(() => undefined))?.size ?? 0;
   }
+  return total;
+}
+
+function fmtMiB(n: number) {
+  return `${(n / 1024 / 1024).toFixed(1)}MiB`;
 }
@@ -0,0 +1,99 @@
+// === src/core/logging/audit-log.ts ===
+/**
+ * 작업 감사 로그(edgetool 자신의 명령 실행 이력) — 기기 로그 뷰어와 별개.
+ * - 기본값: 꺼짐. 환경변수 EDGE_TOOL_AUDIT=1 또는 'homey.debug.toggleAuditLog' 명령으로 활성
+ * - 한 줄 형식(TSV): 시각(ISO) · 연결 ID · 명령 · ok|fail · 소요(ms) [· 오류]
+ * - 모든 줄은 redact()를 거쳐 비밀번호/토큰이 남지 않는다.
+ * - 크기 상한을 넘으면 <file>.1 … <file>.N 으로 밀어내며 회전한다.
+ */
+import * as fs from 'fs/promises';
+import * as path from 'path';
+
+import { AUDIT_LOG_KEEP, AUDIT_LOG_MAX_BYTES } from '../../shared/const.js';
+import { redact } from './redactor.js';
+
+export type AuditEntry = {
+  at: Date;
+  connectionId?: string;
+  command: string;
+  ok: boolean;
+  durationMs: number;
+  error?: string;
+};
+
+function readEnvFlag(): boolean {
+  const v = String(process.env.EDGE_TOOL_AUDIT ?? '')
+    .trim()
+    .toLowerCase();
+  return v === '1' || v === 'true' || v === 'yes' || v === 'on';
+}
+
+let enabled = readEnvFlag();
+
+export function isAuditLogEnabled(): boolean {
+  return enabled;
+}
+
+export function setAuditLogEnabled(on: boolean) {
+  enabled = !!on;
+}
+
+/** 탭/개행은 한 줄 TSV를 깨므로 공백으로 접는다 */
+function cell(s: string | undefined) {
+  return String(s ?? '')
+    .replace(/[\t\r\n]+/g, ' ')
+    .trim();
+}
+
+export function formatAuditLine(e: AuditEntry): string {
+  const cols = [
+    e.at.toISOString(),
+    cell(e.connectionId) || '-',
+    cell(e.command),
+    e.ok ? 'ok' : 'fail',
+    `${Math.max(0, Math.round(e.durationMs))}ms`,
+  ];
+  if (!e.ok && e.error) cols.push(cell(e.error));
+  return redact(cols.join('\t'));
+}
+
+async function rotateIfNeeded(file: string, incoming: number, maxBytes: number, keep: number) {
+  const size = await fs
+    .stat(file)
+    .then((s) => s.size)
+    .catch(() => 0);
+  if (size === 0 || size + incoming <= maxBytes) return;
+  await fs.rm(`${file}.${keep}`, { force: true });
+  for (let i = keep - 1; i >= 1; i--) {
+    await fs.rename(`${file}.${i}`, `${file}.${i + 1}`).catch(() => {});
+  }
+  await fs.rename(file, `${file}.1`);
+}
+
+// 동시 명령의 append/회전이 섞이지 않도록 직렬화
+let chain: Promise<void> = Promise.resolve();
+
+/**
+ * 감사 로그 한 줄 추가(비활성 상태면 no-op).
+ * 기록 실패는 명령 실행에 영향을 주지 않도록 삼키고 false를 돌려준다.
+ */
+export function appendAuditLog(
+  file: string,
+  entry: AuditEntry,
+  opts: { maxBytes?: number; keep?: number } = {},
+): Promise<boolean> {
+  if (!enabled) return Promise.resolve(false);
+  const line = formatAuditLine(entry) + '\n';
+  const maxBytes = opts.maxBytes ?? AUDIT_LOG_MAX_BYTES;
+  const keep = Math.max(1, opts.keep ?? AUDIT_LOG_KEEP);
+  const task = chain.then(async () => {
+    await fs.mkdir(path.dirname(file), { recursive: true });
+    await rotateIfNeeded(file, Buffer.byteLength(line), maxBytes, keep);
+    await fs.appendFile(file, line, 'utf8');
+  });
+  chain = task.catch(() => {});
+  return task.then(
+    () => true,
+    () => false,
+  );
+}
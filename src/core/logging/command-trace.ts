@@ -0,0 +1,23 @@
+// === src/core/logging/command-trace.ts ===
+/**
+ * 원격 명령 추적(ssh/adb 실제 실행 명령 로그) 런타임 스위치.
+ * - 기본값: 환경변수 EDGE_TOOL_DEBUG=1 이면 활성
+ * - 실행 중에는 'homey.debug.toggleCommandTrace' 명령으로 토글
+ * - 추적 로그에는 명령/호스트/사용자만 남기고 비밀번호는 절대 포함하지 않는다.
+ */
+function readEnvFlag(): boolean {
+  const v = String(process.env.EDGE_TOOL_DEBUG ?? '')
+    .trim()
+    .toLowerCase();
+  return v === '1' || v === 'true' || v === 'yes' || v === 'on';
+}
+
+let enabled = readEnvFlag();
+
+export function isCommandTraceEnabled(): boolean {
+  return enabled;
+}
+
+export function setCommandTraceEnabled(on: boolean) {
+  enabled = !!on;
+}
@@ -4,15 +4,23 @@ import * as path from 'path';
 import { inspect } from 'util';
 
 import { LOG_LEVEL_DEFAULT } from '../../shared/const.js';
+import { redact } from './redactor.js';
 
 type Level = 'debug' | 'info' | 'warn' | 'error';
-type Logger = { debug?: Fn; info: Fn; warn: Fn; error: Fn };
+// info 이하는 stdout, warn/error는 stderr(console.warn/error)로 나간다.
+// result: 최종 결과(조용한 모드에서도 남는 info)
+type Logger = { debug?: Fn; info: Fn; result: Fn; warn: Fn; error: Fn };
 type Fn = (msg?: any, ...args: any[]) => void;
 
 const levelRank: Record<Level, number> = { debug: 10, info: 20, warn: 30, error: 40 };
-const currentLevel = (process.env.EDGE_TOOL_LOG_LEVEL || LOG_LEVEL_DEFAULT || 'debug')
-  .toString()
-  .toLowerCase() as Level;
+const currentLevel: Level = (() => {
+  const v = (process.env.EDGE_TOOL_LOG_LEVEL || LOG_LEVEL_DEFAULT || 'debug')
+    .toString()
+    .trim()
+    .toLowerCase();
+  if (v === 'warning') return 'warn';
+  return v in levelRank ? (v as Level) : 'debug';
+})();
 
 function enabled(lv: Level) {
   return levelRank[lv] >= levelRank[currentLevel];
@@ -48,7 +56,7 @@ function fileSinkWrite(level: Level, parts: any[]) {
         : inspect(p, { depth: 5, maxArrayLength: 200, breakLength: Infinity }),
     )
     .join(' ');
-  const line = `${ts} ${level.toUpperCase()} ${body}\n`;
+  const line = `${ts} ${level.toUpperCase()} ${redact(body)}\n`;
   getTestWriteStream().write(line);
 }
 
@@ -65,6 +73,7 @@ export function getConsoleLogger(name: string) /*: Logger*/ {
       };
     const logger: Logger = {
       info: mk('info'),
+      result: mk('info'),
       warn: mk('warn'),
       error: mk('error'),
     };
@@ -75,9 +84,19 @@ export function getConsoleLogger(name: string) /*: Logger*/ {
     const wrap =
       (fn: (...a: any[]) => void): Fn =>
       (msg?: any, ...args: any[]) =>
-        fn(prefix, msg, ...args);
+        fn(
+          prefix,
+          ...[msg, ...args].map((a) =>
+            typeof a === 'string'
+              ? redact(a)
+              : a !== undefined && typeof a === 'object'
+                ? redact(inspect(a, { depth: 5, breakLength: Infinity }))
+                : a,
+          ),
+        );
     const logger: Logger = {
       info: wrap(console.log.bind(console)),
+      result: wrap(console.log.bind(console)),
       warn: wrap(console.warn.bind(console)),
       error: wrap(console.error.bind(console)),
     };
@@ -8,6 +8,8 @@ import {
   LOG_MAX_BUFFER,
 } from '../../shared/const.js';
 import { getConsoleLogger } from './console-logger.js';
+import { isQuietMode } from './quiet-mode.js';
+import { redact } from './redactor.js';
 // test 모드(npm run test)에서는 VS Code 로그 채널 대신 콘솔로 보냄
 import { isTestMode } from './test-mode.js';
 
@@ -21,6 +23,20 @@ const LEVEL_ORDER: Record<LogLevel, number> = {
   error: 40,
 };
 
+/** 레벨 문자열 정규화(잘못된 값이면 fallback) */
+export function parseLogLevel(v: unknown, fallback: LogLevel): LogLevel {
+  const s = String(v ?? '')
+    .trim()
+    .toLowerCase();
+  if (s === 'warning') return 'warn';
+  return s in LEVEL_ORDER ? (s as LogLevel) : fallback;
+}
+
+/** 최소 레벨 결정: EDGE_TOOL_LOG_LEVEL 환경변수 > 기본값 */
+export function resolveLogLevel(fallback: LogLevel): LogLevel {
+  return parseLogLevel(process.env.EDGE_TOOL_LOG_LEVEL, fallback);
+}
+
 class ExtensionLoggerCore {
   private level: LogLevel = 'debug';
   private channel = vscode.window.createOutputChannel(LOG_CHANNEL_NAME);
@@ -66,10 +82,13 @@ class ExtensionLoggerCore {
   }
 
   getLogger(scope: string) {
-    const emit = (lvl: LogLevel, args: any[]) => this._emit(lvl, scope, args);
+    const emit = (lvl: LogLevel, args: any[], result = false) =>
+      this._emit(lvl, scope, args, result);
     return {
       debug: (...a: any[]) => emit('debug', a),
       info: (...a: any[]) => emit('info', a),
+      /** 최종 결과(목록/보고서/원격 출력) — 조용한 모드에서도 남는 info */
+      result: (...a: any[]) => emit('info', a, true),
       warn: (...a: any[]) => emit('warn', a),
       error: (...a: any[]) => emit('error', a),
     };
@@ -122,26 +141,30 @@ class ExtensionLoggerCore {
     this.origConsole = undefined;
   }
 
-  private _emit(level: LogLevel, scope: string, args: any[]) {
+  private _emit(level: LogLevel, scope: string, args: any[], result = false) {
     if (LEVEL_ORDER[level] < LEVEL_ORDER[this.level]) return;
+    // 조용한 모드: 진행/상태 안내(debug·info)는 버리고 결과와 warn/error만 남김
+    if (!result && isQuietMode() && LEVEL_ORDER[level] < LEVEL_ORDER.warn) return;
 
     const now = new Date();
     const ts =
       now.toTimeString().split(' ')[0] + '.' + now.getMilliseconds().toString().padStart(3, '0');
 
-    const body = args
-      .map((a) => {
-        if (a instanceof Error) return a.stack || a.message;
-        if (typeof a === 'object') {
-          try {
-            return JSON.stringify(a);
-          } catch {
-            return String(a);
+    const body = redact(
+      args
+        .map((a) => {
+          if (a instanceof Error) return a.stack || a.message;
+          if (typeof a === 'object') {
+            try {
+              return JSON.stringify(a);
+            } catch {
+              return String(a);
+            }
           }
-        }
-        return String(a);
-      })
-      .join(' ');
+          return String(a);
+        })
+        .join(' '),
+    );
 
     const shortLevel =
       level === 'debug'
@@ -156,9 +179,11 @@ class ExtensionLoggerCore {
 
     const line = `[${ts}] [${shortLevel}] [${scope}] ${body}`;
 
-    // edge-panel 준비 전: 즉시 console.log로 출력
+    // edge-panel 준비 전: 즉시 콘솔로 출력(warn/error는 stderr)
     if (!this.webviewReady) {
-      this.origConsole?.log(line);
+      if (level === 'error') this.origConsole?.error(line);
+      else if (level === 'warn') this.origConsole?.warn(line);
+      else this.origConsole?.log(line);
     }
 
     // 1) VSCode Output Channel
@@ -0,0 +1,44 @@
+// === src/core/logging/quiet-mode.ts ===
+/**
+ * 조용한 모드: 진행/상태 안내(debug·info)를 버리고 최종 결과(log.result)와 warn/error만 남긴다.
+ * - 기본값: 환경변수 EDGE_TOOL_QUIET=1 이면 활성
+ * - 실행 중에는 'homey.debug.toggleQuiet' 명령으로 토글
+ * - 명령 한 번만: 명령 이름 바로 뒤에 `--quiet` (예: `gitFlow --quiet pull`) → 그 명령이 끝날 때까지 활성
+ */
+function readEnvFlag(): boolean {
+  const v = String(process.env.EDGE_TOOL_QUIET ?? '')
+    .trim()
+    .toLowerCase();
+  return v === '1' || v === 'true' || v === 'yes' || v === 'on';
+}
+
+let enabled = readEnvFlag();
+/** `--quiet`로 실행 중인 명령 수(중첩/동시 실행 대비 카운터) */
+let scoped = 0;
+
+export function isQuietMode(): boolean {
+  return enabled || scoped > 0;
+}
+
+export function setQuietMode(on: boolean) {
+  enabled = !!on;
+}
+
+/** fn 실행 동안만 조용한 모드 */
+export async function runQuiet<T>(fn: () => Promise<T>): Promise<T> {
+  scoped++;
+  try {
+    return await fn();
+  } finally {
+    scoped--;
+  }
+}
+
+/**
+ * 인자 맨 앞의 `--quiet` 토큰을 떼어낸다
+ * - 맨 앞만 본다: `exec grep --quiet x`처럼 원격 명령에 넘길 인자는 건드리지 않음
+ */
+export function stripQuietFlag(args: string): { args: string; quiet: boolean } {
+  const m = /^--quiet(?:\s+|$)/.exec(args);
+  return m ? { args: args.slice(m[0].length), quiet: true } : { args, quiet: false };
+}
@@ -0,0 +1,46 @@
+// === src/core/logging/redactor.ts ===
+/**
+ * 로그 비밀값 마스킹(중앙 처리)
+ * - 등록된 비밀값(활성 SSH 비밀번호 등)은 그대로 등장하면 마스킹
+ * - 알려진 키 패턴(password=..., ALLOW_DEVTOKEN=..., "password":"...", sshpass -p ...)은 값만 마스킹
+ * - extension-logger / console-logger 양쪽 출력 직전에 적용된다.
+ */
+export const REDACTED = '****';
+
+const secrets = new Set<string>();
+
+// 값만 치환하기 위해 (키+구분자)를 1그룹, 값을 2그룹으로 캡처
+const KEY_VALUE_PATTERNS: RegExp[] = [
+  /((?:password|passwd|pwd|token|secret|ALLOW_DEVTOKEN)\s*[=:]\s*)("[^"]*"|'[^']*'|[^\s,;&"']+)/gi,
+  /("(?:password|passwd|token|secret)"\s*:\s*)("(?:[^"\\]|\\.)*")/gi,
+  /(sshpass\s+-p\s*)("[^"]*"|'[^']*'|\S+)/gi,
+];
+
+/** 마스킹 대상 비밀값 등록(짧은 값은 오탐 방지를 위해 무시) */
+export function registerSecret(value?: string | null) {
+  const v = String(value ?? '');
+  if (v.length >= 3) secrets.add(v);
+}
+
+export function unregisterSecret(value?: string | null) {
+  secrets.delete(String(value ?? ''));
+}
+
+export function clearSecrets() {
+  secrets.clear();
+}
+
+export function redact(text: string): string {
+  let out = String(text ?? '');
+  if (!out) return out;
+  for (const rx of KEY_VALUE_PATTERNS) {
+    out = out.replace(rx, (_m, key: string, val: string) => {
+      const q = val[0] === '"' || val[0] === "'" ? val[0] : '';
+      return `${key}${q}${REDACTED}${q}`;
+    });
+  }
+  for (const s of secrets) {
+    if (out.includes(s)) out = out.split(s).join(REDACTED);
+  }
+  return out;
+}
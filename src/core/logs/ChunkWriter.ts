@@ -10,6 +10,8 @@ export type ChunkWriteResult = {
   file: string;
   /** 이 파일에 기록된 라인 수 */
   lines: number;
+  /** 기록된 바이트 수 */
+  bytes?: number;
 };
 
 export class ChunkWriter {
@@ -148,7 +150,7 @@ export class ChunkWriter {
       }
 
       this.currentIndex += 1;
-      return { file: partName, lines };
+      return { file: partName, lines, bytes: Buffer.byteLength(text, 'utf8') };
     };
 
     // 직렬화 체인에 등록해 동시 호출을 순차 처리
@@ -0,0 +1,35 @@
+// === src/core/logs/CompressedLog.ts ===
+/**
+ * gzip 로테이션 로그(system.log.1.gz 등) 지원 헬퍼
+ * - 이름 판정/확장자 제거: 회전번호·타입키·화이트리스트 매칭은 ".gz"를 뗀 이름 기준
+ * - 읽기: 순방향은 gunzip 스트림, 역방향 리더는 전체 해제 후 메모리에서 처리
+ */
+import * as fs from 'fs';
+import { promisify } from 'util';
+import * as zlib from 'zlib';
+
+const gunzip = promisify(zlib.gunzip);
+
+export function isGzipPath(p: string): boolean {
+  return /\.gz$/i.test(p);
+}
+
+/** 'system.log.1.gz' → 'system.log.1' (gz가 아니면 그대로) */
+export function stripGzExt(name: string): string {
+  return name.replace(/\.gz$/i, '');
+}
+
+/** 로그 파일 읽기 스트림(gz면 압축 해제 스트림) — encoding 미지정 시 Buffer 청크 */
+export function openLogReadStream(filePath: string): NodeJS.ReadableStream {
+  const rs = fs.createReadStream(filePath);
+  if (!isGzipPath(filePath)) return rs;
+  const gz = zlib.createGunzip();
+  rs.on('error', (e) => gz.destroy(e));
+  return rs.pipe(gz);
+}
+
+/** 압축 해제된 전체 내용(역방향 리더용) */
+export async function readLogFileBuffer(filePath: string): Promise<Buffer> {
+  const raw = await fs.promises.readFile(filePath);
+  return isGzipPath(filePath) ? gunzip(raw) : raw;
+}
@@ -15,26 +15,49 @@ export interface IHybridLogBuffer {
   snapshot(count?: number): LogEntry[];
 }
 
+/**
+ * 실시간 메모리 윈도우(고정 용량 링버퍼)
+ * - 버스트 입력에서도 보관 개수는 항상 capacity 이하(초과분은 가장 오래된 것부터 덮어씀)
+ * - add/addBatch 모두 O(1)/O(n) — shift()로 인한 배열 재배치 없음
+ */
 export class HybridLogBuffer implements IHybridLogBuffer {
   private log = getLogger('HybridLogBuffer');
-  private realtime: LogEntry[] = [];
+  private ring: (LogEntry | undefined)[];
+  private head = 0; // 가장 오래된 항목 위치
+  private size = 0;
+
+  constructor(private readonly capacity = REALTIME_BUFFER_MAX) {
+    this.capacity = Math.max(1, Math.floor(capacity));
+    this.ring = new Array(this.capacity);
+  }
+
   // viewport/search/spill은 나중에 확장. 지금은 뼈대만.
   getMetrics(): BufferMetrics {
-    return { realtime: this.realtime.length, viewport: 0, search: 0, spill: 0 };
+    return { realtime: this.size, viewport: 0, search: 0, spill: 0 };
   }
   add(entry: LogEntry) {
-    this.realtime.push(entry);
-    if (this.realtime.length > REALTIME_BUFFER_MAX) this.realtime.shift();
+    const tail = (this.head + this.size) % this.capacity;
+    this.ring[tail] = entry;
+    if (this.size < this.capacity) this.size++;
+    else this.head = (this.head + 1) % this.capacity;
   }
   @measure()
   addBatch(entries: LogEntry[]) {
-    for (const e of entries) this.add(e);
+    // 용량보다 큰 배치는 마지막 capacity개만 의미가 있다.
+    const start = Math.max(0, entries.length - this.capacity);
+    for (let i = start; i < entries.length; i++) this.add(entries[i]);
   }
   clear() {
-    this.realtime = [];
+    this.ring = new Array(this.capacity);
+    this.head = 0;
+    this.size = 0;
   }
   @measure()
   snapshot(count = 50): LogEntry[] {
-    return this.realtime.slice(-count);
+    const n = Math.max(0, Math.min(count, this.size));
+    const out: LogEntry[] = new Array(n);
+    const from = this.head + this.size - n;
+    for (let i = 0; i < n; i++) out[i] = this.ring[(from + i) % this.capacity] as LogEntry;
+    return out;
   }
 }
@@ -0,0 +1,108 @@
+// === src/core/logs/LogCaptureWriter.ts ===
+/**
+ * 실시간 스트림 로컬 캡처(homeyLoggingLive --append)
+ * - 뷰어용 청크(임시/회전 대상)와 별개로, flush되는 라인을 사용자가 지정한 파일에 바로 덧붙인다.
+ * - format: raw(원문 한 줄씩) / jsonl(LogEntry JSON 한 줄씩, idx/레벨/소스 포함)
+ * - maxBytes를 넘기면 file → file.1 → … → file.<keep>로 밀어내고 새 파일에 이어 쓴다(0 = 회전 없음)
+ * - 쓰기 실패는 스트리밍을 멈추지 않고 경고만 남긴다.
+ */
+import type { LogEntry } from '@ipc/messages';
+import * as fsp from 'fs/promises';
+import * as path from 'path';
+
+import { LOG_CAPTURE_KEEP_DEFAULT } from '../../shared/const.js';
+import { getLogger } from '../logging/extension-logger.js';
+
+const log = getLogger('LogCaptureWriter');
+
+export type LogCaptureFormat = 'raw' | 'jsonl';
+export type LogCaptureOptions = {
+  file: string;
+  /** 미지정 시 확장자로 추론(.jsonl/.ndjson → jsonl, 그 외 raw) */
+  format?: LogCaptureFormat;
+  /** 회전 기준 크기(bytes, 0/미지정 = 회전 없음) */
+  maxBytes?: number;
+  /** 회전 시 남길 이전 파일 수(기본 LOG_CAPTURE_KEEP_DEFAULT) */
+  keep?: number;
+};
+
+export function captureFormatOf(file: string, format?: LogCaptureFormat): LogCaptureFormat {
+  if (format) return format;
+  return /\.(jsonl|ndjson)$/i.test(file) ? 'jsonl' : 'raw';
+}
+
+/** `10M`, `512k`, `1.5G`, `4096` → bytes (1024 단위). 형식이 틀리면 undefined */
+export function parseCaptureSize(raw: string): number | undefined {
+  const m = /^(\d+(?:\.\d+)?)([KMG])?B?$/i.exec(raw.trim());
+  if (!m) return undefined;
+  const mul = { K: 1024, M: 1024 ** 2, G: 1024 ** 3 }[(m[2] ?? '').toUpperCase()] ?? 1;
+  return Math.floor(Number(m[1]) * mul);
+}
+
+export function formatCaptureLines(entries: LogEntry[], format: LogCaptureFormat): string {
+  return entries.map((e) => (format === 'jsonl' ? JSON.stringify(e) : e.text) + '\n').join('');
+}
+
+export class LogCaptureWriter {
+  private chain: Promise<void> = Promise.resolve();
+  readonly format: LogCaptureFormat;
+  private readonly maxBytes: number;
+  private readonly keep: number;
+
+  private constructor(
+    readonly file: string,
+    opts: LogCaptureOptions,
+    private size: number,
+  ) {
+    this.format = captureFormatOf(file, opts.format);
+    this.maxBytes = Math.max(0, opts.maxBytes ?? 0);
+    this.keep = Math.max(0, opts.keep ?? LOG_CAPTURE_KEEP_DEFAULT);
+  }
+
+  /** 파일을 덧붙이기 모드로 준비(상위 디렉터리 생성, 기존 크기부터 회전 계산) */
+  static async open(opts: LogCaptureOptions): Promise<LogCaptureWriter> {
+    const file = path.resolve(opts.file);
+    await fsp.mkdir(path.dirname(file), { recursive: true });
+    const size = (await fsp.stat(file).catch(() => undefined))?.size ?? 0;
+    return new LogCaptureWriter(file, opts, size);
+  }
+
+  /** 배치를 덧붙인다 — 호출 순서대로 직렬화되며, 반환 Promise는 이 배치까지 기록되면 끝난다 */
+  append(entries: LogEntry[]): Promise<void> {
+    if (!entries.length) return this.chain;
+    const text = formatCaptureLines(entries, this.format);
+    this.chain = this.chain
+      .then(() => this.write(text))
+      .catch((e) => log.warn(`capture: write failed ${this.file}: ${String(e)}`));
+    return this.chain;
+  }
+
+  /** 남은 쓰기 완료 대기 */
+  close(): Promise<void> {
+    return this.chain;
+  }
+
+  private async write(text: string) {
+    const bytes = Buffer.byteLength(text, 'utf8');
+    if (this.maxBytes > 0 && this.size > 0 && this.size + bytes > this.maxBytes) {
+      await this.rotate();
+    }
+    await fsp.appendFile(this.file, text, 'utf8');
+    this.size += bytes;
+  }
+
+  private async rotate() {
+    const f = this.file;
+    if (this.keep === 0) {
+      await fsp.rm(f, { force: true });
+    } else {
+      await fsp.rm(`${f}.${this.keep}`, { force: true });
+      for (let i = this.keep - 1; i >= 1; i--) {
+        await fsp.rename(`${f}.${i}`, `${f}.${i + 1}`).catch(() => {});
+      }
+      await fsp.rename(f, `${f}.1`);
+    }
+    this.size = 0;
+    log.info(`capture: rotated ${f} (keep=${this.keep})`);
+  }
+}
@@ -13,14 +13,21 @@ import {
 import { ErrorCategory, XError } from '../../shared/errors.js';
 import { getLogger } from '../logging/extension-logger.js';
 import { measureBlock } from '../logging/perf.js';
+import { isGzipPath, openLogReadStream, readLogFileBuffer, stripGzExt } from './CompressedLog.js';
 import {
+  appendContinuationLines,
   compileParserConfig,
+  isContinuationLine,
   isParsedHeaderAllMissing,
   lineToEntryWithParser,
   shouldUseParserForFile,
 } from './ParserEngine.js';
 import { extractHeaderTimeToken, isYearlessTimeToken, parseTs } from './time/TimeParser.js';
-import { MonotonicCorrector } from './time/TimezoneHeuristics.js';
+import {
+  correctTimestamps,
+  readTimezoneCorrectionOptions,
+  type TimezoneCorrectionOptions,
+} from './time/TimezoneHeuristics.js';
 
 const log = getLogger('LogFileIntegration');
 // 파일 첫 문자 위치의 BOM 제거
@@ -57,13 +64,19 @@ const toIso = (t?: number) => (typeof t === 'number' ? new Date(t).toISOString()
 // 연도 없는 포맷(syslog류) → 논리 연도 롤오버 연결기
 // - 최신→오래된 스캔 중, ts가 "연도 없는 포맷"으로 파싱된 라인들에 한해
 //   연(-1) 단위로 이동시키며 단조비증가(desc)를 보장한다.
-// - 실제 연도 추정/주입 없음. 오직 순서 보존 목적.
+// - 기준 연도: 파서가 호스트 올해를 주입하므로, 가장 최신 라인에서 한 번만 고정한다.
+//   · logYear 지정 시 그 연도로 맞춤(configure.log_year)
+//   · 미지정 시 최신 라인이 "미래"(now + FUTURE_TOLERANCE_MS 초과)면 작년 로그로 간주
 // - [방법 A] 같은 초 또는 소규모 지터(≤ JITTER_TOLERANCE_MS)는 "연도 이동 금지"하고
 //   해당 라인만 1ms 로컬 클램프하여 단조를 유지한다(출력 텍스트는 그대로).
 // ────────────────────────────────────────────────────────────────────────────
 class YearlessStitcher {
   private shiftYears = 0;
   private last?: number;
+  private anchored = false;
+  // 기기/호스트 시계 차이로 살짝 미래인 로그는 올해로 유지
+  private readonly FUTURE_TOLERANCE_MS = 24 * 60 * 60 * 1000;
+  constructor(private readonly opts: { logYear?: number; now?: number } = {}) {}
   // 같은 초/수백 ms 뒤섞임 허용(연도 롤오버로 오판 금지)
   private readonly JITTER_TOLERANCE_MS = 1500; // 1~2s 권장
   // 주어진 ts를 years 만큼 ±이동(UTC 기준, 월/일/윤년 보존)
@@ -87,6 +100,10 @@ class YearlessStitcher {
       this.last = this.last === undefined ? ts : Math.min(this.last, ts);
       return ts;
     }
+    if (!this.anchored) {
+      this.anchored = true;
+      this.shiftYears = this.anchorShift(ts);
+    }
     let corrected = this.shiftByYears(ts, this.shiftYears);
     if (this.last === undefined) {
       this.last = corrected;
@@ -110,6 +127,14 @@ class YearlessStitcher {
     this.last = corrected;
     return corrected;
   }
+  /** 최신 연도 없는 라인의 연도 이동량(0 또는 음수) */
+  private anchorShift(ts: number): number {
+    if (!(ts > 0)) return 0;
+    const year = new Date(ts).getUTCFullYear();
+    if (this.opts.logYear) return this.opts.logYear - year;
+    const now = this.opts.now ?? Date.now();
+    return ts > now + this.FUTURE_TOLERANCE_MS ? -1 : 0;
+  }
 }
 
 /* ──────────────────────────────────────────────────────────────────────────
@@ -194,6 +219,9 @@ export async function mergeDirectory(opts: MergeOptions) {
     //   - parser.configure.memory_mode_threshold 가 유효하면 warmupTarget 기본값으로 사용
     //   - T1(SKIP) 사전 결정에 활용
     const memoryModeThreshold = getMemoryModeThresholdFromParser(opts.parser);
+    // configure.timezone_correction 해석(미지정 시 기본 보정)
+    const tzOpts = getTimezoneCorrectionFromParser(opts.parser);
+    const logYear = getLogYearFromParser(opts.parser);
 
     // ─────────────────────────────────────────────────────────────────────────
     // 사전 총량 추정: SKIP 결정을 위해 선행 계산(가능한 경우)
@@ -286,8 +314,8 @@ export async function mergeDirectory(opts: MergeOptions) {
           `mergeDirectory: SKIP T1 (memory-mode) reason=${reason} ` +
             `(total=${preEstimate ?? warmLogs.length}, threshold=${threshold})`,
         );
-        // 진행률 종료 신호
-        const doneTotal = typeof preEstimate === 'number' ? preEstimate : warmLogs.length;
+        // 진행률 종료 신호 — warmup이 다 읽었으면 연속 라인을 합친 뒤의 엔트리 수가 실제 총량
+        const doneTotal = warmFullyCovered ? warmLogs.length : (preEstimate ?? warmLogs.length);
         opts.onProgress?.({ done: doneTotal, total: doneTotal, active: false });
         // 최종 단계 알림 (메모리 모드)
         opts.onStage?.('정식 병합 스킵: 메모리 모드로 완료', 'done');
@@ -468,7 +496,13 @@ export async function mergeDirectory(opts: MergeOptions) {
         let line: string | null;
         let revIdx = 0;
         let prevTs: number | undefined = undefined;
+        // 역방향 스캔이므로 연속 라인(스택트레이스 등)이 헤더보다 먼저 나온다 → 보류 후 헤더에 부착
+        let contLines: string[] = [];
         while ((line = await rr.nextLine()) !== null) {
+          if (isContinuationLine(line)) {
+            contLines.push(line);
+            continue;
+          }
           // fullPath를 넘겨 path/file 일관성 유지
           const entry = await lineToEntryWithParser(
             fullPath,
@@ -476,6 +510,10 @@ export async function mergeDirectory(opts: MergeOptions) {
             useParserForThisFile ? compiledParser : undefined,
             { fileRank: fileIdx, revIdx: revIdx++, fallbackTs: prevTs },
           );
+          if (contLines.length) {
+            appendContinuationLines(entry, contLines.reverse());
+            contLines = [];
+          }
 
           // 파서 적용 파일: time/process/pid 셋 모두 없으면 무효 라인으로 폐기
           if (useParserForThisFile && isParsedHeaderAllMissing((entry as any)?.parsed)) {
@@ -521,6 +559,20 @@ export async function mergeDirectory(opts: MergeOptions) {
           restoreFullTextIfNeeded(entry, !!opts.preserveFullText);
           logs.push(entry); // 전체 logs가 최신→오래된 순
         }
+        // 파일 선두의 고아 연속 라인(선행 헤더 없음)은 파서 적용 여부와 무관하게 하나의 엔트리로 보존
+        if (contLines.length) {
+          const orphan = contLines.reverse();
+          const entry = await lineToEntryWithParser(fullPath, orphan[0], undefined, {
+            fileRank: fileIdx,
+            revIdx: revIdx++,
+            fallbackTs: prevTs,
+          });
+          appendContinuationLines(entry, orphan.slice(1));
+          sum.lines++;
+          sum.noParsedTime++;
+          if (!(entry.ts > 0)) sum.tsZero++;
+          logs.push(entry);
+        }
         await rr.close();
         fileSummaries.push(sum);
       }
@@ -553,23 +605,17 @@ export async function mergeDirectory(opts: MergeOptions) {
       }
 
       // 연도 없는 포맷(syslog류) 롤오버 연결 (단조비증가 보장)
-      const yearlessStitcher = new YearlessStitcher();
+      const yearlessStitcher = new YearlessStitcher({ logYear });
       for (const log of logs) {
         const timeToken = extractHeaderTimeToken(log.text);
         const isYearless = timeToken ? isYearlessTimeToken(timeToken) : false;
         log.ts = yearlessStitcher.apply(log.ts, isYearless);
       }
 
-      // 단조 보정 (MonotonicCorrector 사용)
+      // 타임존 점프 소급 보정 → 단조 보정
       // ✅ 보정기는 "최신→오래된" 순으로 feed되는 것을 전제한다.
       // logs[]는 이미 최신→오래된 물리 순서이므로 0..N-1로 전진하며 처리한다.
-      const tzc = new MonotonicCorrector(typeKey, 1); // 1ms 단위 클램프
-      for (let asc = 0; asc < logs.length; asc++) {
-        const corrected = tzc.adjust(logs[asc].ts);
-        logs[asc].ts = corrected;
-      }
-      // 요약 출력
-      tzc.summary();
+      correctTimestamps(typeKey, logs, tzOpts);
 
       // ── 병합 DESC 검증(타임존 보정 후, 소트 전) 요약 ──
       if (logs.length) {
@@ -746,6 +792,105 @@ export async function listInputLogFiles(
   }
 }
 
+export type SingleFileResult = {
+  /** 최신→오래된(내림차순) 엔트리 — paged면 비어 있고 페이지는 디스크 인덱스에서 읽는다 */
+  logs: LogEntry[];
+  /** 엔트리로 채택된 라인 수(연속 라인은 헤더에 합쳐져 세지 않음) */
+  parsed: number;
+  /** 파서 헤더(time/process/pid)를 하나도 찾지 못해 버린 라인 수 */
+  skipped: number;
+  /** 메모리 모드 문턱을 넘어 디렉터리 병합과 같은 웜업 + 페이징 경로로 연 경우(skipped는 집계 안 함) */
+  paged?: boolean;
+};
+
+/**
+ * 단일 로그 파일 읽기 — 타입 그룹/타임존 보정/k-way 병합 없이 한 파일만 엔트리로 변환.
+ * - 파싱 규칙(파서 프리플라이트, 멀티라인 연속 라인 부착, 무효 라인 폐기)은 병합 경로와 동일
+ * - 존재하지 않거나 읽을 수 없는 경로는 XError(Path)
+ */
+export async function readSingleLogFile(
+  filePath: string,
+  opts: { parser?: import('./ParserEngine.js').ParserConfig; signal?: AbortSignal } = {},
+): Promise<SingleFileResult> {
+  try {
+    const st = await fs.promises.stat(filePath);
+    if (!st.isFile()) throw new Error('not a regular file');
+    await fs.promises.access(filePath, fs.constants.R_OK);
+  } catch (e) {
+    throw new XError(
+      ErrorCategory.Path,
+      `Cannot read log file ${filePath}: ${e instanceof Error ? e.message : String(e)}`,
+      e,
+    );
+  }
+
+  const compiledParser = opts.parser ? compileParserConfig(opts.parser) : undefined;
+  let useParser = false;
+  if (compiledParser) {
+    try {
+      useParser = await shouldUseParserForFile(filePath, path.basename(filePath), compiledParser);
+    } catch {}
+  }
+  const cp = useParser ? compiledParser : undefined;
+
+  const logs: LogEntry[] = [];
+  let skipped = 0;
+  const rr = await ReverseLineReader.open(filePath);
+  try {
+    let line: string | null;
+    let revIdx = 0;
+    let prevTs: number | undefined = undefined;
+    // 역방향 스캔: 연속 라인이 헤더보다 먼저 나오므로 보류 후 부착
+    let contLines: string[] = [];
+    while ((line = await rr.nextLine()) !== null) {
+      if (opts.signal?.aborted) break;
+      if (isContinuationLine(line)) {
+        contLines.push(line);
+        continue;
+      }
+      const entry = lineToEntryWithParser(filePath, line, cp, {
+        fileRank: 0,
+        revIdx: revIdx++,
+        fallbackTs: prevTs,
+      });
+      const attached = contLines.length;
+      if (attached) {
+        appendContinuationLines(entry, contLines.reverse());
+        contLines = [];
+      }
+      if (useParser && isParsedHeaderAllMissing(entry.parsed)) {
+        // 버린 헤더에 붙었던 연속 라인도 함께 버려지므로 같이 센다
+        skipped += 1 + attached;
+        continue;
+      }
+      if (entry.ts > 0) prevTs = entry.ts;
+      restoreFullTextIfNeeded(entry, true);
+      logs.push(entry);
+    }
+    // 파일 선두의 고아 연속 라인은 파서 파일이어도 버리지 않고 하나의 엔트리로 보존
+    if (contLines.length) {
+      const orphan = contLines.reverse();
+      const entry = lineToEntryWithParser(filePath, orphan[0], undefined, {
+        fileRank: 0,
+        revIdx: revIdx++,
+        fallbackTs: prevTs,
+      });
+      appendContinuationLines(entry, orphan.slice(1));
+      logs.push(entry);
+    }
+  } finally {
+    await rr.close();
+  }
+  // 연도 없는 포맷: 병합 경로와 같은 규칙으로 연도 롤오버/기준 연도 적용
+  const stitcher = new YearlessStitcher({ logYear: getLogYearFromParser(opts.parser) });
+  for (const e of logs) {
+    const token = extractHeaderTimeToken(e.text);
+    e.ts = stitcher.apply(e.ts, token ? isYearlessTimeToken(token) : false);
+  }
+  log.info(`readSingleLogFile: ${filePath} parsed=${logs.length} skipped=${skipped}`);
+  return { logs, parsed: logs.length, skipped };
+}
+
 /** 루트 디렉터리 워커(비재귀): 상대경로 기준으로 필터링 */
 async function walk(root: string, rel: string, out: string[], allowPathRegexes?: RegExp[]) {
   const base = rel ? path.join(root, rel) : root;
@@ -771,8 +916,8 @@ async function walk(root: string, rel: string, out: string[], allowPathRegexes?:
     if (!st.isFile()) {
       continue;
     }
-    // 기본 허용: *.log / *.log.N / *.txt
-    const bn = path.basename(relPath);
+    // 기본 허용: *.log / *.log.N / *.txt (+ 각각의 .gz 압축본)
+    const bn = stripGzExt(path.basename(relPath));
     const isLogLike = /\.log(\.\d+)?$/i.test(bn) || /\.txt$/i.test(bn);
     if (!allowPathRegexes?.length && !isLogLike) {
       continue;
@@ -818,8 +963,8 @@ function compareLogOrderAsc(a: string, b: string) {
   return -compareLogOrderDesc(a, b);
 }
 function numberSuffix(name: string) {
-  // 파일명 끝의 ".숫자"를 회전 번호로 간주(없으면 -1)
-  const bn = path.basename(name);
+  // 파일명 끝의 ".숫자"를 회전 번호로 간주(없으면 -1). ".N.gz"도 동일
+  const bn = stripGzExt(path.basename(name));
   const m = bn.match(/^(.*)\.(\d+)$/);
   return m ? parseInt(m[2], 10) : -1;
 }
@@ -832,13 +977,14 @@ async function isRegularFile(p: string): Promise<boolean> {
   }
 }
 
-async function countLinesInFile(filePath: string): Promise<number> {
+/** 파일 라인 수(마지막 줄에 개행이 없어도 1줄로 셈) */
+export async function countLinesInFile(filePath: string): Promise<number> {
   return new Promise<number>((resolve, reject) => {
     let count = 0;
     let sawAny = false;
     let endsWithLF = false;
 
-    const rs = fs.createReadStream(filePath);
+    const rs = openLogReadStream(filePath);
     rs.on('data', (chunk: string | Buffer) => {
       const buf = Buffer.isBuffer(chunk) ? chunk : Buffer.from(chunk);
       sawAny = sawAny || buf.length > 0;
@@ -855,7 +1001,7 @@ async function countLinesInFile(filePath: string): Promise<number> {
 
 /** 타입키 추출(확장자 무관): 'system.log.1' -> 'system.log' / 'homey-pro.2' -> 'homey-pro' / 'clip.log' -> 'clip.log' */
 function typeKeyOf(name: string): string {
-  const bn = path.basename(name);
+  const bn = stripGzExt(path.basename(name));
   const m = bn.match(/^(.+?)(?:\.(\d+))?$/);
   return m ? m[1] : bn;
 }
@@ -889,9 +1035,10 @@ export function compileWhitelistPathRegexes(globs: string[]): RegExp[] {
 }
 
 export function pathMatchesWhitelist(relPath: string, allow: RegExp[]): boolean {
-  // 베이스네임만 매칭
+  // 베이스네임만 매칭(.gz 압축본은 원래 이름으로도 매칭)
   const bn = path.basename(relPath.replace(/\\/g, '/'));
-  for (const rx of allow) if (rx.test(bn)) return true;
+  const plain = stripGzExt(bn);
+  for (const rx of allow) if (rx.test(bn) || rx.test(plain)) return true;
   return false;
 }
 
@@ -972,8 +1119,17 @@ class ReverseLineReader {
 
   constructor(public readonly filePath: string) {}
 
+  // gz 파일: 임의 위치 읽기가 불가하므로 전체 해제 후 메모리 버퍼에서 역방향 분할
+  private inMemory = false;
+
   static async open(filePath: string) {
     const r = new ReverseLineReader(filePath);
+    if (isGzipPath(filePath)) {
+      r.buffer = stripBomStart((await readLogFileBuffer(filePath)).toString('utf8'));
+      r.bomStripped = true;
+      r.inMemory = true;
+      return r;
+    }
     const st = await fs.promises.stat(filePath);
     r.fileSize = st.size;
     r.pos = st.size;
@@ -982,7 +1138,7 @@ class ReverseLineReader {
   }
 
   async nextLine(): Promise<string | null> {
-    if (this.fh === null) return null;
+    if (this.fh === null && !this.inMemory) return null;
     while (true) {
       const nlIdx = this.buffer.lastIndexOf('\n');
       if (nlIdx >= 0) {
@@ -991,7 +1147,7 @@ class ReverseLineReader {
         if (line.length === 0) continue;
         return line.replace(/\r$/, '');
       }
-      if (this.pos === 0) {
+      if (this.pos === 0 || this.fh === null) {
         if (!this.buffer) return null;
         const last = this.buffer;
         this.buffer = '';
@@ -1014,6 +1170,8 @@ class ReverseLineReader {
   }
 
   async close() {
+    this.inMemory = false;
+    this.buffer = '';
     if (this.fh !== null) {
       try {
         await this.fh.close();
@@ -1262,7 +1420,12 @@ class MergedCursor {
 // Test-only exports (do not use in production)
 //  - 내부 커서/유틸을 단위/회귀 테스트에서만 직접 검증할 수 있도록 노출
 // ────────────────────────────────────────────────────────────────────────────
-export const __testOnly = { MergedCursor, listMergedJsonlFiles, typeKeyFromJsonl };
+export const __testOnly = {
+  MergedCursor,
+  listMergedJsonlFiles,
+  typeKeyFromJsonl,
+  YearlessStitcher,
+};
 
 type HeapItem = { ts: number; entry: LogEntry; typeKey: string; seq: number };
 
@@ -1331,6 +1494,8 @@ function restoreFullTextIfNeeded(e: LogEntry, preserve: boolean) {
       }
     | undefined;
   if (!p) return;
+  // text가 message-only가 아닌 경우(예: 원본을 유지한 JSON 라인)는 그대로 둔다
+  if (p.message != null && e.text !== p.message) return;
   const time = (p.time ?? '').toString().trim();
   const proc = (p.process ?? '').toString().trim();
   if (!time || !proc) return;
@@ -1362,6 +1527,8 @@ export async function warmupTailPrepass(opts: WarmupOptions): Promise<WarmupResu
     const { dir, signal } = opts;
     log.debug?.(`warmupTailPrepass: start dir=${dir}`);
     const compiledParser = opts.parser ? compileParserConfig(opts.parser) : undefined;
+    const tzOpts = getTimezoneCorrectionFromParser(opts.parser);
+    const logYear = getLogYearFromParser(opts.parser);
     const target = Math.max(
       1,
       Number(
@@ -1496,32 +1663,58 @@ export async function warmupTailPrepass(opts: WarmupOptions): Promise<WarmupResu
       return e;
     };
 
+    // 역방향 스캔이라 연속 라인(스택트레이스 등)이 헤더보다 먼저 나온다 → 타입별로 보류(청크 경계 넘어 유지)
+    const pendingCont = new Map<string, { file: string; lines: string[] }>();
+
     // 4) 1차 수집: 균등 할당만큼 per-type 로딩
     const batchRead = async (typeKey: string, need: number) => {
       if (need <= 0) return 0;
       const w = walkers.get(typeKey)!;
       let got = 0; // ✅ 유효 엔트리(버퍼에 push된) 개수
       let prevTs = prevTsMap.get(typeKey);
+      const buf = buffers.get(typeKey)!;
+      // 파일 선두의 고아 연속 라인(선행 헤더 없음)은 전체 로딩과 같이 하나의 엔트리로 보존
+      const flushOrphans = async () => {
+        const p = pendingCont.get(typeKey);
+        pendingCont.delete(typeKey);
+        if (!p) return;
+        const orphan = p.lines.reverse();
+        const e = await toEntry(p.file, orphan[0], false, prevTs);
+        appendContinuationLines(e, orphan.slice(1));
+        buf.push(e);
+        got++;
+      };
       // 한 번에 너무 큰 I/O를 피하려고 소형 청크로 읽음
       const CHUNK = 64;
       while (got < need && !w.isExhausted && !aborted()) {
         const n = Math.min(CHUNK, need - got);
         const part = await w.next(n);
         if (!part.length) break;
-        const buf = buffers.get(typeKey)!;
-        let pushedThisRound = 0;
         for (const { line, file, useParser } of part) {
+          const held = pendingCont.get(typeKey);
+          if (held && held.file !== file) await flushOrphans();
+          if (isContinuationLine(line)) {
+            const p = pendingCont.get(typeKey) ?? { file, lines: [] };
+            p.lines.push(line);
+            pendingCont.set(typeKey, p);
+            continue;
+          }
           const e = await toEntry(file, line, useParser, prevTs);
+          const cont = pendingCont.get(typeKey);
+          if (cont) {
+            appendContinuationLines(e, cont.lines.reverse());
+            pendingCont.delete(typeKey);
+          }
           if (useParser && isParsedHeaderAllMissing((e as any)?.parsed)) {
             continue; // 무효 라인 폐기
           }
           if (typeof e.ts === 'number' && Number.isFinite(e.ts)) prevTs = e.ts;
           buf.push(e);
-          pushedThisRound++;
+          // ⚠️ 단순 읽은 라인 개수가 아니라 "유효하게 추가된" 라인 수로 집계
+          got++;
         }
-        // ⚠️ 단순 읽은 라인 개수가 아니라 "유효하게 추가된" 라인 수로 집계
-        got += pushedThisRound;
       }
+      if (w.isExhausted) await flushOrphans();
       prevTsMap.set(typeKey, prevTs);
       return got;
     };
@@ -1604,19 +1797,14 @@ export async function warmupTailPrepass(opts: WarmupOptions): Promise<WarmupResu
         `[probe:warm-desc] type=${k} beforeTZ len=${arr.length} range=[${toIso(r0.max)}..${toIso(r0.min)}] inversions=${inv0}`,
       );
       // 연도 없는 포맷(syslog류) 롤오버 연결 (단조비증가 보장)
-      const yearlessStitcher = new YearlessStitcher();
+      const yearlessStitcher = new YearlessStitcher({ logYear });
       for (const log of arr) {
         const timeToken = extractHeaderTimeToken(log.text);
         const isYearless = timeToken ? isYearlessTimeToken(timeToken) : false;
         log.ts = yearlessStitcher.apply(log.ts, isYearless);
       }
-      const tzc = new MonotonicCorrector(k, 1); // 1ms 단위 클램프
       // warm 버퍼도 물리 배열은 최신→오래된. ✅ 앞→뒤(0..N-1)로 feed
-      for (let asc = 0; asc < arr.length; asc++) {
-        const corrected = tzc.adjust(arr[asc].ts);
-        arr[asc].ts = corrected;
-      }
-      // quiet summary
+      correctTimestamps(k, arr, tzOpts);
       // after TZ(before sort)
       const r1 = tsRange(arr);
       const inv1 = countDescInversions(arr);
@@ -1696,3 +1884,30 @@ function getMemoryModeThresholdFromParser(
   } catch {}
   return undefined;
 }
+
+/* ──────────────────────────────────────────────────────────────────────────
+ * configure.timezone_correction 추출기
+ *  - { enabled, min_jump_hours, min_return_hours, max_offset_hours, offset_hours, small_delta_ms }
+ *  - 미지정/무효 값은 기본값 사용
+ * ────────────────────────────────────────────────────────────────────────── */
+function getTimezoneCorrectionFromParser(
+  parser?: import('./ParserEngine.js').ParserConfig | any,
+): TimezoneCorrectionOptions {
+  try {
+    return readTimezoneCorrectionOptions(parser?.configure?.timezone_correction);
+  } catch {
+    return {};
+  }
+}
+
+/* ──────────────────────────────────────────────────────────────────────────
+ * configure.log_year 추출기
+ *  - 연도 없는 포맷(`Jan  1 10:00:00`)에서 가장 최신 라인이 속한 연도
+ *  - 1970..9999 정수만 인정, 그 외(미지정 포함)는 undefined → 호스트 올해 기준(미래면 작년)
+ * ────────────────────────────────────────────────────────────────────────── */
+function getLogYearFromParser(
+  parser?: import('./ParserEngine.js').ParserConfig | any,
+): number | undefined {
+  const y = Number(parser?.configure?.log_year);
+  return Number.isInteger(y) && y >= 1970 && y <= 9999 ? y : undefined;
+}
@@ -0,0 +1,57 @@
+// === src/core/logs/LogcatParser.ts ===
+/**
+ * ADB `logcat` 라인 파서(실시간 스트림용)
+ * - threadtime: `MM-DD HH:MM:SS.mmm  PID  TID L Tag: message`
+ * - time(구 형식): `MM-DD HH:MM:SS.mmm L/Tag( PID): message`
+ * - 시간은 parseTs 규칙(연도 없음 → 올해 주입, UTC 해석)을 그대로 따른다.
+ * - 매치 실패(배너 `--------- beginning of main` 등)면 undefined → 호출부가 기본값 사용
+ */
+import type { LogEntry, LogLevel } from '@ipc/messages';
+
+import { parseTs } from './time/TimeParser.js';
+
+export type LogcatFields = Required<Pick<LogEntry, 'ts' | 'level' | 'pid' | 'process'>> & {
+  tid?: string;
+  time: string;
+  message: string;
+};
+
+const THREADTIME_RX =
+  /^(\d{2}-\d{2}\s+\d{2}:\d{2}:\d{2}\.\d{3})\s+(\d+)\s+(\d+)\s+([VDIWEFAS])\s+(.*?)\s*:\s(.*)$/;
+const TIME_RX =
+  /^(\d{2}-\d{2}\s+\d{2}:\d{2}:\d{2}\.\d{3})\s+([VDIWEFAS])\/(.*?)\(\s*(\d+)\):\s?(.*)$/;
+
+/** logcat 우선순위 문자 → 정규화 레벨(V/D→D, W→W, E/F/A→E, 나머지 I) */
+export function logcatLevel(ch: string): LogLevel {
+  switch (ch) {
+    case 'V':
+    case 'D':
+      return 'D';
+    case 'W':
+      return 'W';
+    case 'E':
+    case 'F':
+    case 'A':
+      return 'E';
+    default:
+      return 'I';
+  }
+}
+
+export function parseLogcatLine(line: string): LogcatFields | undefined {
+  const tt = THREADTIME_RX.exec(line);
+  if (tt) {
+    const [, time, pid, tid, lv, tag, message] = tt;
+    const ts = parseTs(time);
+    if (ts === undefined) return undefined;
+    return { ts, level: logcatLevel(lv), pid, tid, process: tag.trim(), time, message };
+  }
+  const tm = TIME_RX.exec(line);
+  if (tm) {
+    const [, time, lv, tag, pid, message] = tm;
+    const ts = parseTs(time);
+    if (ts === undefined) return undefined;
+    return { ts, level: logcatLevel(lv), pid, process: tag.trim(), time, message };
+  }
+  return undefined;
+}
@@ -7,6 +7,8 @@ export type LogChunkMeta = {
   lines: number;
   /** 병합 전체 기준의 시작 라인 인덱스(0-based) */
   start: number;
+  /** 파일 크기(bytes) — 용량 기준 회전용(이전 manifest에는 없음) */
+  bytes?: number;
 };
 
 export type LogManifest = {
@@ -22,6 +24,11 @@ export type LogManifest = {
   chunkCount: number;
   /** 청크 메타 목록 (시간순/소트된 순서) */
   chunks: LogChunkMeta[];
+  /**
+   * 청크 안 라인 저장 순서 — desc(병합 결과: 최신→과거, 미지정 시 기본) / asc(실시간 append: 과거→최신)
+   * 어느 쪽이든 LogEntry.idx는 과거=1 오름차순으로 매핑된다.
+   */
+  order?: 'asc' | 'desc';
 };
 
 export function isLogManifest(x: unknown): x is LogManifest {
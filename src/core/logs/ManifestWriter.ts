@@ -2,10 +2,43 @@
 import * as fs from 'fs';
 import * as path from 'path';
 
+import { safeParseJson } from '../../shared/utils.js';
+import { getLogger } from '../logging/extension-logger.js';
 import { measure } from '../logging/perf.js';
 import type { LogChunkMeta, LogManifest } from './ManifestTypes.js';
 import { isLogManifest } from './ManifestTypes.js';
 
+const log = getLogger('ManifestWriter');
+
+const PART_FILE_RE = /^part-(\d+)\.ndjson$/i;
+
+/** outDir의 part 파일 목록(번호 오름차순) */
+async function listPartFiles(outDir: string): Promise<{ name: string; n: number }[]> {
+  const names = await fs.promises.readdir(outDir).catch(() => [] as string[]);
+  return names
+    .map((name) => ({ name, n: Number(PART_FILE_RE.exec(name)?.[1]) }))
+    .filter((p) => Number.isFinite(p.n))
+    .sort((a, b) => a.n - b.n);
+}
+
+/** 청크 파일의 라인 수/크기 — PagedReader(readline)와 같은 기준: 마지막 개행 뒤 빈 조각은 라인이 아님 */
+async function measureChunk(
+  full: string,
+): Promise<{ lines: number; bytes: number; firstIdx?: number }> {
+  const text = await fs.promises.readFile(full, 'utf8');
+  const lines = text.split('\n');
+  if (lines[lines.length - 1] === '') lines.pop();
+  let firstIdx: number | undefined;
+  try {
+    const idx = JSON.parse(lines[0] ?? '')?.idx;
+    if (Number.isInteger(idx) && idx > 0) firstIdx = idx;
+  } catch {}
+  return { lines: lines.length, bytes: Buffer.byteLength(text), firstIdx };
+}
+
+/** 청크 보관 정책(0/미지정 = 무제한) */
+export type ChunkRetention = { maxChunks?: number; maxBytes?: number };
+
 export class ManifestWriter {
   private manifest: LogManifest;
   private manifestPath: string;
@@ -28,9 +61,16 @@ export class ManifestWriter {
   @measure()
   static async loadOrCreate(outDir: string): Promise<ManifestWriter> {
     const mf = path.join(outDir, 'manifest.json');
+    let buf: string | undefined;
+    try {
+      buf = await fs.promises.readFile(mf, 'utf8');
+    } catch {}
+    const json = buf === undefined ? undefined : safeParseJson<unknown>(buf);
+    // 파일은 있는데 깨졌으면(저장 중 크래시 등) 청크 파일로부터 재구성
+    if (buf !== undefined && !isLogManifest(json)) {
+      return ManifestWriter.repair(outDir);
+    }
     try {
-      const buf = await fs.promises.readFile(mf, 'utf8');
-      const json = JSON.parse(buf);
       if (isLogManifest(json)) {
         // 기존 파일을 그대로 사용하되, 정합성 보완(정렬/카운트 재계산)
         const w = new ManifestWriter(outDir, json);
@@ -67,6 +107,68 @@ export class ManifestWriter {
     return new ManifestWriter(outDir);
   }
 
+  /**
+   * 손상된 manifest 복구
+   * - 기존 파일은 manifest.json.corrupt-<시각>으로 백업(없으면 생략)
+   * - part-*.ndjson을 번호순으로 훑어 청크별 라인 수/크기를 다시 세고, start는 청크 첫 엔트리에
+   *   저장된 idx로 되살린다(회전으로 앞 청크가 지워져도 전역 인덱스 유지).
+   *   idx가 없거나 앞 청크와 겹치면 앞 청크 끝에 이어 붙인다.
+   * - 재구성 결과는 즉시 저장한다.
+   */
+  @measure()
+  static async repair(outDir: string): Promise<ManifestWriter> {
+    const mf = path.join(outDir, 'manifest.json');
+    const stamp = new Date()
+      .toISOString()
+      .replace(/[-:TZ.]/g, '')
+      .slice(0, 14);
+    const backup = `${mf}.corrupt-${stamp}`;
+    await fs.promises.rename(mf, backup).then(
+      () => log.warn(`manifest: corrupt manifest.json backed up to ${backup}`),
+      () => {},
+    );
+    const w = new ManifestWriter(outDir);
+    for (const p of await listPartFiles(outDir)) {
+      try {
+        const { lines, bytes, firstIdx } = await measureChunk(path.join(outDir, p.name));
+        const next = w.manifest.mergedLines;
+        const start = firstIdx !== undefined && firstIdx - 1 >= next ? firstIdx - 1 : next;
+        w.addChunk(p.name, lines, start, bytes);
+      } catch (e) {
+        log.warn(`manifest: skip unreadable chunk ${p.name}: ${String(e)}`);
+      }
+    }
+    w.manifest.totalLines = w.manifest.mergedLines;
+    await w.save();
+    log.warn(
+      `manifest: rebuilt from ${w.manifest.chunkCount} chunk(s), ${w.manifest.mergedLines} line(s)`,
+    );
+    return w;
+  }
+
+  /**
+   * manifest에 아직 안 올라간 청크 편입(청크 rename 후 manifest 저장 전에 크래시한 경우)
+   * - manifest의 마지막 part 번호보다 큰 part 파일만 대상 → 이어서 쓰기 전에 호출
+   * 반환: 편입된 청크 수 (save는 호출자가 수행)
+   */
+  @measure()
+  async adoptOrphanChunks(): Promise<number> {
+    const last = nextPartIndexFrom(this.manifest);
+    let adopted = 0;
+    for (const p of await listPartFiles(this.outDir)) {
+      if (p.n <= last) continue;
+      try {
+        const { lines, bytes } = await measureChunk(path.join(this.outDir, p.name));
+        this.addChunk(p.name, lines, this.manifest.mergedLines, bytes);
+        adopted++;
+      } catch (e) {
+        log.warn(`manifest: skip unreadable chunk ${p.name}: ${String(e)}`);
+      }
+    }
+    if (adopted) log.info(`manifest: adopted ${adopted} unlisted chunk(s) in ${this.outDir}`);
+    return adopted;
+  }
+
   get path() {
     return this.manifestPath;
   }
@@ -79,12 +181,17 @@ export class ManifestWriter {
     if (typeof total === 'number') this.manifest.totalLines = total;
   }
 
+  setOrder(order: 'asc' | 'desc') {
+    this.manifest.order = order;
+  }
+
   @measure()
-  addChunk(file: string, lines: number, start: number) {
+  addChunk(file: string, lines: number, start: number, bytes?: number) {
     // 안전장치: 잘못된 청크는 무시
     if (!file || !file.trim() || !(lines > 0)) return;
     if (!(start >= 0)) start = 0;
     const meta: LogChunkMeta = { file, lines, start };
+    if (typeof bytes === 'number') meta.bytes = bytes;
     this.manifest.chunks.push(meta);
     this.manifest.chunks.sort((a, b) => a.start - b.start);
     this.manifest.chunkCount = this.manifest.chunks.length;
@@ -93,6 +200,35 @@ export class ManifestWriter {
     this.manifest.mergedLines = last.start + last.lines;
   }
 
+  /**
+   * 보관 정책 초과 시 가장 오래된 청크부터 manifest에서 빼고 파일을 삭제한다.
+   * - 최신 청크 1개는 항상 남긴다
+   * - 남은 청크의 start는 그대로(전역 라인 인덱스 유지) → mergedLines 불변
+   * 반환: 제거된 청크 목록 (save는 호출자가 수행)
+   */
+  @measure()
+  async rotate(policy: ChunkRetention): Promise<LogChunkMeta[]> {
+    const maxChunks = Math.max(0, policy.maxChunks ?? 0);
+    const maxBytes = Math.max(0, policy.maxBytes ?? 0);
+    const chunks = this.manifest.chunks;
+    let bytes = chunks.reduce((a, c) => a + (c.bytes ?? 0), 0);
+    let drop = 0;
+    while (
+      chunks.length - drop > 1 &&
+      ((maxChunks && chunks.length - drop > maxChunks) || (maxBytes && bytes > maxBytes))
+    ) {
+      bytes -= chunks[drop].bytes ?? 0;
+      drop++;
+    }
+    if (!drop) return [];
+    const removed = chunks.splice(0, drop);
+    this.manifest.chunkCount = chunks.length;
+    for (const c of removed) {
+      await fs.promises.unlink(path.join(this.outDir, c.file)).catch(() => {});
+    }
+    return removed;
+  }
+
   @measure()
   async save() {
     await fs.promises.mkdir(this.outDir, { recursive: true });
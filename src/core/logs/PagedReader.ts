@@ -9,6 +9,7 @@ import { getLogger } from '../logging/extension-logger.js';
 import { measure } from '../logging/perf.js';
 import type { LogManifest } from './ManifestTypes.js';
 import { isLogManifest } from './ManifestTypes.js';
+import { ManifestWriter } from './ManifestWriter.js';
 
 const log = getLogger('PagedReader');
 
@@ -27,9 +28,15 @@ export class PagedReader {
   static async open(manifestDir: string): Promise<PagedReader> {
     const mf = path.join(manifestDir, 'manifest.json');
     const buf = await fs.promises.readFile(mf, 'utf8');
-    const json = JSON.parse(buf);
-    if (!isLogManifest(json)) {
-      throw new Error('Invalid manifest.json');
+    const parsed = safeParseJson<unknown>(buf);
+    let json: LogManifest;
+    if (isLogManifest(parsed)) {
+      json = parsed;
+    } else {
+      // 저장 중 크래시 등으로 깨진 manifest → 백업 후 청크 파일로 재구성
+      log.warn(`manifest: invalid manifest.json in ${manifestDir}; rebuilding from chunks`);
+      const repaired = (await ManifestWriter.repair(manifestDir)).data;
+      json = { ...repaired, chunks: [...repaired.chunks] };
     }
     // 정합성 보장: 정렬 + mergedLines 재계산(마지막 청크 기준)
     json.chunks.sort((a, b) => a.start - b.start);
@@ -59,6 +66,11 @@ export class PagedReader {
     return this.manifest.totalLines;
   }
 
+  /** 읽을 수 있는 첫 라인(0-based) — 회전으로 앞쪽 청크가 지워졌으면 0보다 크다 */
+  getFirstLine(): number {
+    return this.manifest.chunks[0]?.start ?? 0;
+  }
+
   getPageCount(pageSize: number): number {
     const total = this.getTotalLines() ?? 0;
     if (total <= 0) return 0;
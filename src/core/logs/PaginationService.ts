@@ -1,10 +1,77 @@
 // === src/core/logs/PaginationService.ts ===
-import type { LogEntry } from '@ipc/messages';
+import type {
+  LogEntry,
+  LogFilter,
+  LogLevel,
+  LogStats,
+  SearchCursor,
+  SearchHit,
+  SearchOptions,
+} from '@ipc/messages';
 
 import { getLogger } from '../logging/extension-logger.js';
 import { PagedReader } from './PagedReader.js';
+import { guessLevel } from './time/TimeParser.js';
 
-class PaginationService {
+const LEVEL_RANK: Record<LogLevel, number> = { D: 0, I: 1, W: 2, E: 3 };
+
+/** 통계 수치 정규화 — manifest/JSON에서 온 문자열·실수·NaN을 0 이상의 정수로 */
+export function toCount(v: unknown): number {
+  const n = typeof v === 'number' ? v : Number(v);
+  return Number.isFinite(n) && n > 0 ? Math.floor(n) : 0;
+}
+
+/**
+ * 검색어 매처 — 기본은 대소문자 무시 부분 문자열.
+ * wholeWord는 단어 문자(\w) 경계에서만 매칭(짧은 식별자 검색용)
+ */
+export function buildSearchMatcher(
+  q: string,
+  opts?: { regex?: boolean } & SearchOptions,
+): (txt: string) => boolean {
+  if (!q) return () => true;
+  if (!opts?.regex && !opts?.wholeWord) {
+    if (opts?.caseSensitive) return (txt) => txt.includes(q);
+    const ql = q.toLowerCase();
+    return (txt) => txt.toLowerCase().includes(ql);
+  }
+  const re = new RegExp(searchSource(q, opts), opts.caseSensitive ? '' : 'i');
+  return (txt) => re.test(txt);
+}
+
+/**
+ * 매칭 구간 추출 — 매처와 같은 의미론으로 text 안의 [start, end) 목록을 돌려준다.
+ * regex 모드에서 캡처 그룹이 있으면 그룹 구간, 없으면 전체 매치 구간
+ */
+export function buildSearchRanger(
+  q: string,
+  opts?: { regex?: boolean } & SearchOptions,
+): (txt: string) => [number, number][] {
+  if (!q) return () => [];
+  const re = new RegExp(searchSource(q, opts), `gd${opts?.caseSensitive ? '' : 'i'}`);
+  return (txt) => {
+    const out: [number, number][] = [];
+    re.lastIndex = 0;
+    for (let m = re.exec(txt); m; m = re.exec(txt)) {
+      // 빈 매치에서 무한 루프 방지
+      if (m[0].length === 0) re.lastIndex++;
+      const groups = opts?.regex && m.indices ? m.indices.slice(1) : [];
+      if (groups.some(Boolean)) {
+        for (const g of groups) if (g && g[1] > g[0]) out.push([g[0], g[1]]);
+      } else if (m[0].length) {
+        out.push([m.index, m.index + m[0].length]);
+      }
+    }
+    return out;
+  };
+}
+
+function searchSource(q: string, opts?: { regex?: boolean } & SearchOptions) {
+  const body = opts?.regex ? q : q.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+  return opts?.wholeWord ? `(?<!\\w)(?:${body})(?!\\w)` : body;
+}
+
+export class PaginationService {
   private manifestDir?: string;
   private reader?: PagedReader;
   private log = getLogger('PaginationService');
@@ -20,7 +87,8 @@ class PaginationService {
   private warmBuffer: LogEntry[] | null = null; // 최신→오래된(내림차순) 0..N-1 (물리)
   private warmTotal = 0; // 가상 total(예: 2000)
   // ── Filter(호스트 적용) ────────────────────────────────────────────────
-  private filter: { pid?: string; src?: string; proc?: string; msg?: string } | null = null;
+  private filter: LogFilter | null = null;
+  private severityFloor?: LogLevel;
   private filteredTotalCache?: number;
   private filteredCacheKey?: string;
   // (참고) 기능 변경 없음. 로깅/버전 관리/캐시 무효화는 Host에서 refresh를 보냄으로써 보완됨.
@@ -55,6 +123,16 @@ class PaginationService {
     this.invalidateFilterCache();
   }
 
+  /** 취소 등으로 폐기되는 병합 결과를 더 이상 읽지 않도록 분리(같은 dir일 때만) */
+  detachManifestDir(dir: string) {
+    if (this.manifestDir !== dir) return;
+    this.reader = undefined;
+    this.manifestDir = undefined;
+    this.clearWarmup();
+    this.bump('detach');
+    this.invalidateFilterCache();
+  }
+
   getManifestDir() {
     return this.manifestDir;
   }
@@ -76,8 +154,9 @@ class PaginationService {
     } catch {}
     return this.reader?.getTotalLines();
   }
-  /** 필터 상태 */
+  /** 필터 상태(심각도 하한 포함) */
   isFilterActive() {
+    if (this.severityFloor) return true;
     return !!(this.filter && Object.values(this.filter).some((v) => !!(v && String(v).trim())));
   }
   /** 현재 필터가 활성인지 외부에서 사용하기 쉽도록 boolean 반환 */
@@ -87,7 +166,7 @@ class PaginationService {
   getFilter() {
     return this.filter;
   }
-  setFilter(f: { pid?: string; src?: string; proc?: string; msg?: string } | null) {
+  setFilter(f: LogFilter | null) {
     const norm = this.normalizeFilter(f);
     const prevKey = this.filter ? JSON.stringify(this.filter) : undefined;
     const nextKey = norm ? JSON.stringify(norm) : undefined;
@@ -103,14 +182,32 @@ class PaginationService {
       this.log.debug?.(`pagination.filter.set no-change key=${nextKey ?? '∅'}`);
     }
   }
+  getSeverityFloor() {
+    return this.severityFloor;
+  }
+  /** 심각도 하한 설정(null=해제) — 텍스트 필터와 독립적으로 유지되고 AND로 결합 */
+  setSeverityFloor(floor: LogLevel | null) {
+    const next = floor && floor in LEVEL_RANK ? floor : undefined;
+    if (next === this.severityFloor) return;
+    this.log.debug?.(`pagination.severity.set ${this.severityFloor ?? '∅'} → ${next ?? '∅'}`);
+    this.severityFloor = next;
+    this.invalidateFilterCache('severity.set');
+    this.bump('severity.set');
+  }
+  /** 심각도 하한 통과 여부(레벨이 없으면 본문에서 추정) */
+  meetsSeverityFloor(e: LogEntry): boolean {
+    if (!this.severityFloor) return true;
+    const lv = e.level ?? guessLevel(String(e.text || ''));
+    return LEVEL_RANK[lv] >= LEVEL_RANK[this.severityFloor];
+  }
   /** 필터 활성 시 총 라인 수 계산 */
   async getFilteredTotal(): Promise<number | undefined> {
-    if (!this.filter) {
+    if (!this.filter && !this.severityFloor) {
       // 필터가 없으면 warm/file 총계를 그대로 반환
       return this.warmActive ? this.getWarmTotal() : (this.getFileTotal() ?? 0);
     }
-    // 캐시 키는 "필터 + 데이터셋 버전 + 모드(warm/file)"로 구성
-    const baseKey = JSON.stringify(this.filter);
+    // 캐시 키는 "필터 + 심각도 하한 + 데이터셋 버전 + 모드(warm/file)"로 구성
+    const baseKey = JSON.stringify({ ...this.filter, floor: this.severityFloor });
     const key = `${baseKey}@v${this.version}${this.warmActive ? ':warm' : ':file'}`;
     if (this.filteredCacheKey === key && typeof this.filteredTotalCache === 'number') {
       this.log.debug?.(
@@ -151,6 +248,18 @@ class PaginationService {
       warmTotal: this.getWarmTotal(),
       fileTotal: this.getFileTotal(),
       filter: this.filter || null,
+      severityFloor: this.severityFloor ?? null,
+    };
+  }
+
+  /** 버퍼 통계(웹뷰 상태 표시용). 전달 유실 수는 브리지가 채운다 */
+  async getStats(): Promise<Omit<LogStats, 'dropped'>> {
+    return {
+      total: toCount(await this.getFilteredTotal()),
+      memory: toCount(this.warmBuffer?.length),
+      file: toCount(this.getFileTotal()),
+      rotated: toCount(this.reader?.getFirstLine()),
+      warm: this.warmActive,
     };
   }
 
@@ -267,12 +376,9 @@ class PaginationService {
     const s = Math.max(1, Math.min(total, startIdx));
     const e = Math.max(1, Math.min(total, endIdx));
     if (e < s) return [];
-    // 논리(오름차순) → 물리(내림차순 저장) 매핑
-    const { physStart, physEndExcl } = mapAscToDescRange(total, s, e);
-    if (physEndExcl <= physStart) return [];
-    const rowsDesc = await this.reader.readLineRange(physStart, physEndExcl, { skipInvalid: true });
-    const rowsAsc = rowsDesc.slice().reverse();
-    if (rowsAsc.length === 0 && e - s + 1 > 0) {
+    const rowsAsc = await this.readFileAsc(total, s, e);
+    if (rowsAsc.length === 0 && e - s + 1 > 0 && !this.isFileAsc()) {
+      const { physStart, physEndExcl } = mapAscToDescRange(total, s, e);
       // 디버깅 지원: total(=mergedLines 우선)과 실제 커버리지 괴리 탐지
       try {
         const mf = (this.reader as any).getManifest?.();
@@ -284,14 +390,32 @@ class PaginationService {
         );
       } catch {}
     }
+    // append 순서 파일은 회전으로 앞쪽이 비어 있을 수 있어 실제 첫 라인부터 센다
+    const base = this.isFileAsc() ? Math.max(s, this.reader.getFirstLine() + 1) : s;
     for (let i = 0; i < rowsAsc.length; i++) {
       const eRow = rowsAsc[i] as any;
-      eRow.idx = s + i; // 논리 오름차순 인덱스
+      eRow.idx = base + i; // 논리 오름차순 인덱스
     }
     // quiet
     return rowsAsc;
   }
 
+  /** 파일이 append 순서(과거→최신)로 저장됐는지 — 실시간 세션 manifest의 order: 'asc' */
+  private isFileAsc(): boolean {
+    return this.reader?.getManifest().order === 'asc';
+  }
+
+  /** 논리 오름차순 [s..e](1-based) 행을 파일 저장 순서에 맞춰 읽는다 */
+  private async readFileAsc(total: number, s: number, e: number): Promise<LogEntry[]> {
+    if (!this.reader || e < s) return [];
+    if (this.isFileAsc()) return this.reader.readLineRange(s - 1, e, { skipInvalid: true });
+    // 논리(오름차순) → 물리(내림차순 저장) 매핑
+    const { physStart, physEndExcl } = mapAscToDescRange(total, s, e);
+    if (physEndExcl <= physStart) return [];
+    const rowsDesc = await this.reader.readLineRange(physStart, physEndExcl, { skipInvalid: true });
+    return rowsDesc.slice().reverse();
+  }
+
   /** 필터 활성 시, "필터 결과 인덱스(오름차순)" 기준으로 [startIdx,endIdx] 구간을 반환 */
   async readRangeFiltered(startIdx: number, endIdx: number): Promise<LogEntry[]> {
     if (startIdx > endIdx) return [];
@@ -321,14 +445,12 @@ class PaginationService {
     const total = this.getFileTotal() ?? 0;
     if (total <= 0) return [];
 
-    // ⬇️ 논리 오름차순을 보장하기 위해 "물리 끝→앞"으로 창을 옮겨 읽고, 각 창을 reverse
+    // ⬇️ 논리 오름차순 창 단위로 읽는다(물리 순서 매핑은 readFileAsc)
     const WINDOW = 2000;
     let v = 0; // 필터 공간(오름차순)에서의 누적 카운트
-    for (let tail = total; tail > 0 && out.length < want; tail -= WINDOW) {
-      const from = Math.max(0, tail - WINDOW);
-      const toEx = tail;
-      const partDesc = await this.reader.readLineRange(from, toEx, { skipInvalid: true });
-      const partAsc = partDesc.slice().reverse();
+    const first = this.isFileAsc() ? this.reader.getFirstLine() + 1 : 1;
+    for (let s = first; s <= total && out.length < want; s += WINDOW) {
+      const partAsc = await this.readFileAsc(total, s, Math.min(total, s + WINDOW - 1));
       for (const e of partAsc) {
         if (!matches(e)) continue;
         v++;
@@ -347,9 +469,10 @@ class PaginationService {
       src: s(f?.src),
       proc: s(f?.proc),
       msg: s(f?.msg),
+      exclude: s(f?.exclude),
     };
     // 전부 빈 문자열이면 null 취급(필터 미적용)
-    if (!norm.pid && !norm.src && !norm.proc && !norm.msg) return null;
+    if (!norm.pid && !norm.src && !norm.proc && !norm.msg && !norm.exclude) return null;
     return norm;
   }
   /** 필터 총계 캐시 무효화(이유 로깅 포함) */
@@ -375,12 +498,16 @@ class PaginationService {
           .filter(Boolean),
       );
   }
+  /** 토큰 하나 매칭: `=이름`은 전체 일치(칩으로 고른 프로세스/파일), 그 외는 부분 포함 */
+  private matchToken(s: string, tok: string): boolean {
+    return tok.length > 1 && tok.startsWith('=') ? s === tok.slice(1) : s.includes(tok);
+  }
   /** 단일 문자열 대상: 그룹 중 하나라도(OR) 해당 문자열이 모든 토큰(AND)을 포함하면 true */
   private matchTextByGroups(haystack: string, q?: string): boolean {
     const groups = this.parseGroups(q);
     if (groups.length === 0) return true;
     const s = String(haystack || '').toLowerCase();
-    return groups.some((andTokens) => andTokens.every((tok) => s.includes(tok)));
+    return groups.some((andTokens) => andTokens.every((tok) => this.matchToken(s, tok)));
   }
   /** 여러 후보 문자열 대상(src용): 후보 중 하나라도 그룹을 만족하면 true */
   private matchAnyCandidateByGroups(candidates: string[], q?: string): boolean {
@@ -388,25 +515,31 @@ class PaginationService {
     if (groups.length === 0) return true;
     const cands = (candidates || []).map((v) => String(v || '').toLowerCase()).filter(Boolean);
     if (cands.length === 0) return false;
-    return groups.some((andTokens) => cands.some((c) => andTokens.every((tok) => c.includes(tok))));
+    return groups.some((andTokens) =>
+      cands.some((c) => andTokens.every((tok) => this.matchToken(c, tok))),
+    );
   }
 
   private matchesFilter(e: LogEntry): boolean {
+    if (!this.meetsSeverityFloor(e)) return false;
     if (!this.filter) return true;
     const f = this.filter;
     const parsed = this.parseLine(String(e.text || ''));
     const msg = String(parsed.msg || '');
     const proc = String(parsed.proc || '');
     const pid = String(parsed.pid || '');
-    // ⬇︎ 세그먼트 키 일관성: file → basename(path)만을 후보로 사용 (source 미사용)
+    // ⬇︎ 세그먼트 키 일관성: file → basename(path)만을 후보로 사용
+    //    (file이 없는 실시간 라인만 source를 후보로 사용)
     const file = String((e as any).file ?? '');
     const p = String((e as any).path ?? '');
-    const srcCands = [file.toLowerCase()];
+    const srcCands = [(file || String(e.source ?? '')).toLowerCase()];
     const has = (s?: string) => !!(s && String(s).trim());
     if (has(f.msg) && !this.matchTextByGroups(msg, f.msg)) return false;
     if (has(f.proc) && !this.matchTextByGroups(proc, f.proc)) return false;
     if (has(f.pid) && !this.matchTextByGroups(pid, f.pid)) return false;
     if (has(f.src) && !this.matchAnyCandidateByGroups(srcCands, f.src)) return false;
+    // 제외어: 라인 전체에서 그룹 하나라도 매칭되면 숨김(긍정 필터보다 우선)
+    if (has(f.exclude) && this.matchTextByGroups(String(e.text || ''), f.exclude)) return false;
     return true;
   }
 
@@ -451,62 +584,106 @@ class PaginationService {
   // ────────────────────────────────────────────────────────────────────
   async searchAll(
     q: string,
-    opts?: { regex?: boolean; range?: [number, number]; top?: number },
-  ): Promise<{ idx: number; text: string }[]> {
-    const hits: { idx: number; text: string }[] = [];
-    const regex = opts?.regex && q ? new RegExp(q, 'i') : null;
-    const ql = (q || '').toLowerCase();
+    opts?: { regex?: boolean; range?: [number, number]; top?: number } & SearchOptions,
+  ): Promise<SearchHit[]> {
+    const { hits } = await this.searchPage(q, { ...opts, pageSize: opts?.top });
+    return hits;
+  }
+
+  /**
+   * 페이지 단위 검색 — cursor(이전 페이지의 nextCursor)부터 이어서 스캔.
+   * nextCursor가 없으면 더 이상 결과가 없음. pageSize 미지정 시 끝까지 스캔
+   */
+  async searchPage(
+    q: string,
+    opts?: {
+      regex?: boolean;
+      range?: [number, number];
+      cursor?: SearchCursor;
+      pageSize?: number;
+    } & SearchOptions,
+  ): Promise<{ hits: SearchHit[]; nextCursor?: SearchCursor }> {
+    const hits: SearchHit[] = [];
+    const test = buildSearchMatcher(q, opts);
+    const ranges = buildSearchRanger(q, opts);
     const inRange = (k: number) =>
       !opts?.range || (k >= Math.max(1, opts.range[0]) && k <= Math.max(1, opts.range[1]));
-    const wantMore = () => !opts?.top || hits.length < opts.top!;
+    const pageSize = opts?.pageSize && opts.pageSize > 0 ? opts.pageSize : Infinity;
 
-    const test = (txt: string) => {
-      if (!q) return true;
-      return regex ? regex.test(txt) : txt.toLowerCase().includes(ql);
-    };
-
-    // 1) 워밍업 메모리 버퍼
+    // 1) 워밍업 메모리 버퍼 — cursor.next = 다음에 볼 오름차순 위치(0-based)
     if (this.warmActive) {
       // 오름차순 스캔을 위해 뒤집어서 탐색
       const asc = (this.warmBuffer ?? [])
         .filter((e) => this.matchesFilter(e))
         .slice()
         .reverse();
-      for (let i = 0; i < asc.length; i++) {
+      for (let i = opts?.cursor?.next ?? 0; i < asc.length; i++) {
         const e = asc[i];
         const idx = i + 1;
         if (!inRange(idx)) continue;
-        if (test(String(e.text || ''))) {
-          hits.push({ idx, text: String(e.text || '') });
-          if (!wantMore()) break;
+        const txt = String(e.text || '');
+        if (test(txt)) {
+          hits.push({ idx, text: txt, ranges: ranges(txt) });
+          if (hits.length >= pageSize) {
+            const next = i + 1;
+            return { hits, nextCursor: next < asc.length ? { next, v: next } : undefined };
+          }
         }
       }
-      return hits;
+      return { hits };
     }
 
-    // 2) 파일 기반 모드 — 한 번의 선형 스캔
-    if (!this.reader) return hits;
+    // 2) 파일 기반 모드 — 최신 쪽부터 선형 스캔, cursor.next = 이어서 볼 물리 라인(미포함 상한)
+    if (!this.reader) return { hits };
     const total = this.getFileTotal() ?? 0;
-    if (total <= 0) return hits;
+    if (total <= 0) return { hits };
     const WINDOW = 4000;
-    let v = 0;
-    for (let tail = total; tail > 0 && wantMore(); tail -= WINDOW) {
-      const from = Math.max(0, tail - WINDOW);
-      const toEx = tail;
-      const partDesc = await this.reader.readLineRange(from, toEx, { skipInvalid: true });
-      const partAsc = partDesc.slice().reverse();
-      for (const e of partAsc) {
+    // 회전으로 사라진 앞쪽 구간은 스캔하지 않음
+    const first = this.reader.getFirstLine();
+    let v = opts?.cursor?.v ?? 0;
+    if (this.isFileAsc()) {
+      // append 순서(실시간) — 물리 = 논리 오름차순. 필터가 없으면 v는 전역 idx(회전 구간 포함)와 같게 맞춘다
+      const filtered = this.isFilterActive();
+      for (let from = opts?.cursor?.next ?? first; from < total; from += WINDOW) {
+        const part = await this.reader.readLineRange(from, Math.min(total, from + WINDOW), {
+          skipInvalid: true,
+        });
+        for (let k = 0; k < part.length; k++) {
+          const e = part[k];
+          if (!this.matchesFilter(e)) continue;
+          v = filtered ? v + 1 : from + k + 1;
+          if (!inRange(v)) continue;
+          const txt = String(e.text || '');
+          if (test(txt)) {
+            hits.push({ idx: v, text: txt, ranges: ranges(txt) });
+            if (hits.length >= pageSize) {
+              const next = from + k + 1;
+              return { hits, nextCursor: next < total ? { next, v } : undefined };
+            }
+          }
+        }
+      }
+      return { hits };
+    }
+    for (let tail = opts?.cursor?.next ?? total; tail > first; tail -= WINDOW) {
+      const from = Math.max(first, tail - WINDOW);
+      const partDesc = await this.reader.readLineRange(from, tail, { skipInvalid: true });
+      for (let k = partDesc.length - 1; k >= 0; k--) {
+        const e = partDesc[k];
         if (!this.matchesFilter(e)) continue;
         v++;
         if (!inRange(v)) continue;
         const txt = String(e.text || '');
         if (test(txt)) {
-          hits.push({ idx: v, text: txt });
-          if (!wantMore()) break;
+          hits.push({ idx: v, text: txt, ranges: ranges(txt) });
+          if (hits.length >= pageSize) {
+            const next = from + k;
+            return { hits, nextCursor: next > first ? { next, v } : undefined };
+          }
         }
       }
     }
-    return hits;
+    return { hits };
   }
 }
 
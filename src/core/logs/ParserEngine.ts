@@ -1,9 +1,9 @@
 import type { ParsedPayload } from '@ipc/messages';
-import * as fs from 'fs';
 import * as path from 'path';
 
 import type { ParserConfig, ParserPreflight, ParserRequirements } from '../config/schema.js';
 import { getLogger } from '../logging/extension-logger.js';
+import { openLogReadStream, stripGzExt } from './CompressedLog.js';
 import { parseTs } from './time/TimeParser.js';
 import { guessLevel } from './time/TimeParser.js'; // same module에서 export 중이면 병합, 아니면 적절히 import
 
@@ -110,19 +110,14 @@ function escapeRe(s: string) {
 /** 파일 토큰 → "파일명(basename) 전용" 정규식(들)로 컴파일
  *  - 형태: "<base>.{n|0|k|:k}" 또는 리터럴/정규식("^" 시작)
  *  - 매칭 대상은 항상 **basename**
+ *  - 잘못된 정규식/알 수 없는 `{…}` 지정은 던진다(호출부가 실패로 보고)
  */
 function fileDescriptorToRegexes(token: string): RegExp[] {
   if (!token || typeof token !== 'string') return [];
   const t = token.trim();
   if (!t) return [];
   // 사용자가 직접 정규식을 준 경우(하위호환)
-  if (t.startsWith('^')) {
-    try {
-      return [new RegExp(t, 'i')];
-    } catch {
-      return [];
-    }
-  }
+  if (t.startsWith('^')) return [new RegExp(t, 'i')];
   const m = t.match(/^(.+)\.\{([^}]+)\}$/);
   if (!m) {
     // 순수 리터럴 파일명
@@ -144,12 +139,8 @@ function fileDescriptorToRegexes(token: string): RegExp[] {
     const k = parseInt(spec, 10);
     pattern = k === 0 ? `^${base}$` : `^${base}\\.${k}$`;
   }
-  if (!pattern) return [];
-  try {
-    return [new RegExp(pattern, 'i')];
-  } catch {
-    return [];
-  }
+  if (!pattern) throw new Error(`unknown file spec {${spec}}`);
+  return [new RegExp(pattern, 'i')];
 }
 
 export type CompiledRule = {
@@ -167,8 +158,17 @@ export type CompiledParser = {
   requirements: Required<ParserRequirements>;
   preflight: Required<ParserPreflight> & { hardSkip: RegExp[] };
   rules: CompiledRule[];
+  /** configure.level_from_message(기본 true) */
+  levelFromMessage: boolean;
+  /** 컴파일에 실패해 제외된 규칙(파일 토큰/정규식) */
+  failures: ParserCompileFailure[];
 };
 
+const PARSER_FIELD_KEYS = ['time', 'process', 'pid', 'message'] as const;
+
+/** 사용자 정규식 컴파일 실패 정보(규칙 file 토큰 + 필드명 + 에러 메시지) */
+export type ParserCompileFailure = { file: string; field: string; error: string };
+
 export function compileParserConfig(cfg?: ParserConfig): CompiledParser | undefined {
   if (!cfg || !Array.isArray(cfg.parser) || cfg.parser.length === 0) return undefined;
   const reqDefault: Required<ParserRequirements> = {
@@ -194,32 +194,61 @@ export function compileParserConfig(cfg?: ParserConfig): CompiledParser | undefi
   const hardSkip = (preflight.hard_skip_if_any_line_matches ?? []).map((p) => {
     try {
       return new RegExp(p, 'i'); // Windows/대소문자 무시
-    } catch {
+    } catch (e: any) {
+      getLogger('ParserEngine').warn(
+        `compileParserConfig: invalid hard_skip pattern ignored "${p}": ${e?.message ?? e}`,
+      );
       return new RegExp('a^'); // never
     }
   });
 
   const rules: CompiledRule[] = [];
+  const failures: ParserCompileFailure[] = [];
   for (const rAny of cfg.parser) {
     // need === false 규칙은 완전히 제외
     if ((rAny as any)?.need === false) continue;
 
-    const fileRegexes: RegExp[] = (rAny as any)?.file
-      ? fileDescriptorToRegexes(String((rAny as any).file))
-      : [];
+    let fileRegexes: RegExp[];
+    try {
+      fileRegexes = (rAny as any)?.file ? fileDescriptorToRegexes(String((rAny as any).file)) : [];
+    } catch (e: any) {
+      failures.push({
+        file: String((rAny as any).file),
+        field: 'file',
+        error: e?.message ?? String(e),
+      });
+      continue;
+    }
     if (!fileRegexes.length) continue;
 
     const regexSrc = (rAny as any).regex ?? {};
-    const cr: CompiledRule = {
-      fileRegexes,
-      regex: {
-        time: regexSrc.time ? new RegExp(regexSrc.time) : undefined,
-        process: regexSrc.process ? new RegExp(regexSrc.process) : undefined,
-        pid: regexSrc.pid ? new RegExp(regexSrc.pid) : undefined,
-        message: regexSrc.message ? new RegExp(regexSrc.message) : undefined,
-      },
-    };
-    rules.push(cr);
+    const regex: CompiledRule['regex'] = {};
+    let ruleOk = true;
+    for (const key of PARSER_FIELD_KEYS) {
+      const src = regexSrc[key];
+      if (!src) continue;
+      try {
+        regex[key] = new RegExp(String(src));
+      } catch (e: any) {
+        ruleOk = false;
+        failures.push({
+          file: String((rAny as any).file),
+          field: key,
+          error: e?.message ?? String(e),
+        });
+      }
+    }
+    // 하나라도 컴파일 실패한 규칙은 통째로 제외(부분 적용 시 필드 누락 매칭 방지)
+    if (!ruleOk) continue;
+    rules.push({ fileRegexes, regex });
+  }
+  if (failures.length) {
+    const log = getLogger('ParserEngine');
+    for (const f of failures) {
+      log.warn(
+        `compileParserConfig: invalid regex, rule skipped file=${f.file} field=${f.field}: ${f.error}`,
+      );
+    }
   }
   if (!rules.length) return undefined;
   const compiled = {
@@ -227,6 +256,8 @@ export function compileParserConfig(cfg?: ParserConfig): CompiledParser | undefi
     requirements: requirements || reqDefault,
     preflight: { ...preflight, hardSkip },
     rules,
+    levelFromMessage: cfg.configure?.level_from_message !== false,
+    failures,
   };
   // 요약 로그 추가
   const log = getLogger('ParserEngine');
@@ -240,7 +271,8 @@ export function matchRuleForPath(
 ): CompiledRule | undefined {
   // 경로 → POSIX 슬래시 → basename만 추출하여 파일명만으로 매칭
   const norm = relOrAbsPath.replace(/\\/g, '/');
-  const base = norm.includes('/') ? norm.slice(norm.lastIndexOf('/') + 1) : norm;
+  // 압축 로테이션(.gz)은 확장자를 뗀 이름으로 매칭
+  const base = stripGzExt(norm.includes('/') ? norm.slice(norm.lastIndexOf('/') + 1) : norm);
   for (const r of cp.rules) {
     if (r.fileRegexes.some((rx) => rx.test(base))) return r;
   }
@@ -270,13 +302,14 @@ async function readSampleLines(filePath: string, maxLines: number): Promise<stri
     let residual = '';
     let finished = false;
     let bomStripped = false;
-    const rs = fs.createReadStream(filePath, { encoding: 'utf8' });
+    const rs = openLogReadStream(filePath);
+    rs.setEncoding('utf8');
 
     const done = () => {
       if (finished) return;
       finished = true;
       try {
-        rs.close();
+        (rs as any).destroy?.();
       } catch {}
       // 표본은 '완전한 라인'만 사용 (잔여는 버림)
       resolve(out.slice(0, maxLines));
@@ -365,10 +398,123 @@ export async function shouldUseParserForFile(
   return decision;
 }
 
+/** 스택트레이스 등 멀티라인 엔트리의 연속 라인 판별
+ *  - 헤더 시간이 없고, 들여쓰기 되었거나 `at ` / `Caused by` 로 시작하면 연속 라인
+ */
+const CONTINUATION_RE = /^(?:\s+\S|at\s|Caused by\b)/;
+export function isContinuationLine(line: string): boolean {
+  const s = stripBomStart(String(line ?? ''));
+  if (!s.trim() || !CONTINUATION_RE.test(s)) return false;
+  return parseTs(s) === undefined;
+}
+
+/** 연속 라인들을 선행 엔트리 본문(text / parsed.message)에 이어 붙임 */
+export function appendContinuationLines(
+  entry: import('@ipc/messages').LogEntry,
+  lines: string[],
+): void {
+  if (!lines.length) return;
+  const tail = '\n' + lines.join('\n');
+  entry.text = (entry.text ?? '') + tail;
+  if (entry.parsed && entry.parsed.message != null) {
+    entry.parsed = { ...entry.parsed, message: entry.parsed.message + tail };
+  }
+}
+
 export function extractByCompiledRule(line: string, rule: CompiledRule): ParsedFields {
   return extractFieldsByCompiledRule(line, rule.regex);
 }
 
+/* ────────────────────────────────────────────────────────────
+ * JSON 구조화 로그 (HOMEY_APP_LOG_TO_CONSOLE 등)
+ *  - `{ "time": ..., "level": ..., "msg": ... }` 형태를 정규식 파서보다 먼저 시도
+ *  - 알 수 없는 추가 필드는 `key=value` 로 message 뒤에 평탄화
+ * ──────────────────────────────────────────────────────────── */
+const JSON_TIME_KEYS = ['time', 'timestamp', 'ts', '@timestamp', 'date'];
+const JSON_LEVEL_KEYS = ['level', 'lvl', 'severity'];
+const JSON_MSG_KEYS = ['msg', 'message', 'text'];
+const JSON_PROC_KEYS = ['name', 'app', 'process'];
+const JSON_PID_KEYS = ['pid'];
+
+export type JsonLineFields = {
+  ts?: number;
+  time?: string;
+  level?: 'D' | 'I' | 'W' | 'E';
+  process?: string;
+  pid?: string;
+  message: string;
+};
+
+function pickKey(obj: Record<string, unknown>, keys: string[]): string | undefined {
+  return keys.find((k) => Object.prototype.hasOwnProperty.call(obj, k) && obj[k] != null);
+}
+
+function jsonLevelToLevel(v: unknown): 'D' | 'I' | 'W' | 'E' | undefined {
+  if (typeof v === 'number') {
+    // pino/bunyan 수치 레벨(10 trace, 20 debug, 30 info, 40 warn, 50 error, 60 fatal)
+    if (v >= 50) return 'E';
+    if (v >= 40) return 'W';
+    if (v >= 30) return 'I';
+    return 'D';
+  }
+  const s = String(v ?? '')
+    .trim()
+    .toLowerCase();
+  if (!s) return undefined;
+  // 전체 일치만 인정 — "information"이 I로, "errorless" 같은 단어가 E로 잘못 가지 않게
+  if (/^(e|err|error|fatal|crit|critical|alert|emerg|emergency|panic)$/.test(s)) return 'E';
+  if (/^(w|warn|warning)$/.test(s)) return 'W';
+  if (/^(d|debug|trace|verbose|v)$/.test(s)) return 'D';
+  if (/^(i|info|information|informational|notice|log)$/.test(s)) return 'I';
+  return undefined;
+}
+
+function jsonTimeToTs(v: unknown): number | undefined {
+  if (typeof v === 'number' && Number.isFinite(v)) {
+    // 초 단위 epoch 보정
+    return v < 1e12 ? Math.round(v * 1000) : v;
+  }
+  const s = String(v ?? '').trim();
+  if (!s) return undefined;
+  return parseTs(`[${s}]`) ?? (Number.isNaN(Date.parse(s)) ? undefined : Date.parse(s));
+}
+
+/** JSON 객체 라인이면 필드 추출, 아니면 undefined */
+export function parseJsonLogLine(line: string): JsonLineFields | undefined {
+  const s = stripBomStart(String(line ?? '')).trim();
+  if (s.length < 2 || s[0] !== '{' || s[s.length - 1] !== '}') return undefined;
+  let obj: Record<string, unknown>;
+  try {
+    obj = JSON.parse(s);
+  } catch {
+    return undefined;
+  }
+  if (!obj || typeof obj !== 'object' || Array.isArray(obj)) return undefined;
+  const timeKey = pickKey(obj, JSON_TIME_KEYS);
+  const levelKey = pickKey(obj, JSON_LEVEL_KEYS);
+  const msgKey = pickKey(obj, JSON_MSG_KEYS);
+  // 구조화 로그로 볼 최소 조건: 메시지 + (시간 또는 레벨)
+  if (!msgKey || (!timeKey && !levelKey)) return undefined;
+  const procKey = pickKey(obj, JSON_PROC_KEYS);
+  const pidKey = pickKey(obj, JSON_PID_KEYS);
+
+  const used = new Set([timeKey, levelKey, msgKey, procKey, pidKey]);
+  const extras: string[] = [];
+  for (const [k, v] of Object.entries(obj)) {
+    if (used.has(k) || v == null) continue;
+    extras.push(`${k}=${typeof v === 'object' ? JSON.stringify(v) : String(v)}`);
+  }
+  const msg = String(obj[msgKey]);
+  return {
+    ts: timeKey ? jsonTimeToTs(obj[timeKey]) : undefined,
+    time: timeKey ? String(obj[timeKey]) : undefined,
+    level: levelKey ? jsonLevelToLevel(obj[levelKey]) : undefined,
+    process: procKey ? String(obj[procKey]) : undefined,
+    pid: pidKey ? String(obj[pidKey]) : undefined,
+    message: extras.length ? `${msg} ${extras.join(' ')}` : msg,
+  };
+}
+
 export function lineToEntryWithParser(
   filePath: string,
   line: string,
@@ -387,7 +533,19 @@ export function lineToEntryWithParser(
   let text = line;
   let parsed: ParsedPayload | undefined;
 
-  if (cp) {
+  // JSON 구조화 라인은 정규식 규칙보다 우선
+  const json = parseJsonLogLine(line);
+  if (json) {
+    ts = json.ts ?? ts;
+    level = json.level ?? guessLevel(json.message);
+    // text는 원본 JSON 라인을 유지하고, 추출한 메시지는 parsed.message로만 전달
+    parsed = {
+      time: json.time ?? null,
+      process: json.process ?? null,
+      pid: json.pid ?? null,
+      message: json.message,
+    };
+  } else if (cp) {
     // warmup/T1 모두 basename 기준 일관 매칭
     const rule = matchRuleForPath(bn, cp);
     if (rule) {
@@ -396,8 +554,8 @@ export function lineToEntryWithParser(
       if (fields.time) ts = parseTs(`[${fields.time}]`) ?? ts;
       // message
       if (fields.message) text = fields.message;
-      // 레벨은 메시지/라인에서 휴리스틱
-      level = guessLevel(fields.message ?? line);
+      // 레벨 필드가 없는 포맷: 메시지 키워드로 추정(configure.level_from_message=false면 I 고정)
+      level = cp.levelFromMessage ? guessLevel(fields.message ?? line) : 'I';
       // ⬇️ 파싱 필드 보관(대괄호 제거/ANSI 제거된 최소 정규화 상태)
       parsed = {
         time: fields.time ?? null,
@@ -0,0 +1,135 @@
+// === src/core/logs/RealtimeSource.ts ===
+/**
+ * 실시간 로그 소스 체인(journalctl || docker ...)의 출처 표시
+ * - 각 소스 명령 앞에 마커 라인(`__edgetool_src__ <name>`)을 출력하게 해서
+ *   폴백으로 실제 어떤 소스가 흐르는지 스트림 안에서 알 수 있게 한다.
+ * - 마커 라인은 로그로 저장하지 않고, 이후 엔트리의 file(=뷰어 src 컬럼)로만 쓴다.
+ * - 사용자가 고른 원격 파일 하나를 따라가는 소스(tail -f)도 여기서 만든다.
+ * - journalctl 소스는 `--since/--until` 시간 구간을 받아 과거 로그 조회에도 쓴다.
+ * - 빠른 필터(`wifi !heartbeat`)는 원격 grep 대신 호스트에서 라인 단위로 적용한다
+ *   (busybox/toybox grep의 버퍼링·옵션 차이와 셸 이스케이프 문제를 피하고, 소스 마커도 보존).
+ */
+import { shellQuote } from '../connection/shellQuote.js';
+
+export type RealtimeSource = { name: string; cmd: string };
+
+export const SOURCE_MARK_PREFIX = '__edgetool_src__ ';
+
+/** 폴백 체인 명령 구성 — 앞 소스가 실패(비정상 종료)하면 다음 소스로 넘어간다 */
+export function composeSourceChain(sources: readonly RealtimeSource[]): string {
+  const parts = sources.map((s) => `{ echo ${SOURCE_MARK_PREFIX}${s.name}; ${s.cmd}; }`);
+  return `sh -lc ${shellQuote(parts.join(' || '))}`;
+}
+
+/** 원격 파일 하나를 따라가는 소스(`logging --tail <경로>`) — 이름은 파일 basename */
+export function tailFileSource(remotePath: string, tailLines = 0): RealtimeSource {
+  const name = remotePath.replace(/\/+$/, '').split('/').pop() || remotePath;
+  return { name, cmd: `tail -n ${Math.max(0, tailLines)} -f ${shellQuote(remotePath)}` };
+}
+
+/** journalctl 시간 구간 — until이 있으면 구간만 읽고 끝나며, since만 있으면 이어서 follow */
+export type JournalWindow = { since?: string; until?: string };
+
+const JOURNAL_KEYWORD_RE = /^(now|today|yesterday|tomorrow)$/i;
+const JOURNAL_ABS_RE = /^(\d{4})-(\d{2})-(\d{2})(?:[ T](\d{2}):(\d{2})(?::(\d{2}))?)?$/;
+const JOURNAL_CLOCK_RE = /^(\d{2}):(\d{2})(?::(\d{2}))?$/;
+const JOURNAL_EPOCH_RE = /^@\d{1,12}$/;
+// `-2h`, `+30min`, `1h 30min ago`, `3 days ago`
+const JOURNAL_UNITS = 's|sec|seconds?|m|min|minutes?|h|hours?|d|days?|w|weeks?|months?|y|years?';
+const JOURNAL_REL_RE = new RegExp(`^[+-]?(?:\\d+\\s*(?:${JOURNAL_UNITS})\\s*)+(?:ago)?$`, 'i');
+
+/** 절대 시각(날짜/`@epoch`)이면 비교용 ms(로컬 기준), 상대/키워드/시:분이면 undefined */
+function journalTimeMs(expr: string): number | undefined {
+  if (JOURNAL_EPOCH_RE.test(expr)) return Number(expr.slice(1)) * 1000;
+  const m = JOURNAL_ABS_RE.exec(expr);
+  if (!m) return undefined;
+  const [y, mo, d, h, mi, sec] = m.slice(1).map((v) => Number(v ?? 0));
+  return new Date(y, mo - 1, d, h, mi, sec).getTime();
+}
+
+/** journalctl이 받아들이는 범위의 시간 표현인지(키워드/날짜[ 시각]/시:분/상대값/@epoch) */
+export function isJournalTime(expr: string): boolean {
+  const s = expr.trim();
+  if (JOURNAL_KEYWORD_RE.test(s) || JOURNAL_EPOCH_RE.test(s) || JOURNAL_REL_RE.test(s)) return true;
+  const clock = JOURNAL_CLOCK_RE.exec(s);
+  if (clock) return Number(clock[1]) < 24 && Number(clock[2]) < 60 && Number(clock[3] ?? 0) < 60;
+  const m = JOURNAL_ABS_RE.exec(s);
+  if (!m) return false;
+  // 2025-02-30 / 25:00 같은 값은 Date가 넘겨 버리므로 되돌려 비교해 거른다
+  const [y, mo, d, h, mi, sec] = m.slice(1).map((v) => Number(v ?? 0));
+  const t = new Date(y, mo - 1, d, h, mi, sec);
+  return (
+    t.getFullYear() === y &&
+    t.getMonth() === mo - 1 &&
+    t.getDate() === d &&
+    t.getHours() === h &&
+    t.getMinutes() === mi &&
+    t.getSeconds() === sec
+  );
+}
+
+/** 구간 검증 — 문제가 있으면 오류 문구, 없으면 undefined */
+export function journalWindowError(w: JournalWindow): string | undefined {
+  for (const [k, v] of [
+    ['--since', w.since],
+    ['--until', w.until],
+  ] as const) {
+    if (v !== undefined && !isJournalTime(v)) return `${k}: 알 수 없는 시간 표현 ${v}`;
+  }
+  const since = w.since !== undefined ? journalTimeMs(w.since.trim()) : undefined;
+  const until = w.until !== undefined ? journalTimeMs(w.until.trim()) : undefined;
+  if (since !== undefined && until !== undefined && since > until) {
+    return `--since(${w.since})가 --until(${w.until})보다 늦습니다`;
+  }
+  return undefined;
+}
+
+/**
+ * journalctl 소스 — 구간이 없으면 기존처럼 직전 tail줄부터 follow.
+ * 구간이 있으면 -n 없이 구간 전체를 읽고, until이 없을 때만 -f를 붙여 이어서 따라간다.
+ */
+export function journalctlSource(tailLines = 0, w?: JournalWindow): RealtimeSource {
+  const unit = `-u "homey*" 2>/dev/null`;
+  if (!w?.since && !w?.until) {
+    return { name: 'journalctl', cmd: `journalctl -f -o short-iso -n ${tailLines} ${unit}` };
+  }
+  const args = [
+    w.since ? `--since=${shellQuote(w.since.trim())}` : '',
+    w.until ? `--until=${shellQuote(w.until.trim())}` : '-f',
+  ].filter(Boolean);
+  return { name: 'journalctl', cmd: `journalctl ${args.join(' ')} -o short-iso ${unit}` };
+}
+
+/** 존재 확인용 명령(일반 파일이 아니면 종료코드 ≠ 0) */
+export function remoteFileTestCommand(remotePath: string): string {
+  return `test -f ${shellQuote(remotePath)}`;
+}
+
+/** 빠른 필터: include는 모두 포함(AND), exclude는 하나라도 포함되면 제외 — 대소문자 무시 */
+export type LineFilter = { include: string[]; exclude: string[] };
+
+/**
+ * `wifi !heartbeat "scan done"` → include [wifi, scan done], exclude [heartbeat]
+ * - 큰따옴표로 공백 포함 구문, `!` 접두사로 제외
+ * - 조건이 하나도 없으면 undefined(필터 없음)
+ */
+export function parseLineFilter(filter?: string): LineFilter | undefined {
+  const f: LineFilter = { include: [], exclude: [] };
+  for (const m of String(filter ?? '').matchAll(/(!?)(?:"([^"]*)"|(\S+))/g)) {
+    const term = (m[2] ?? m[3] ?? '').trim().toLowerCase();
+    if (term) (m[1] ? f.exclude : f.include).push(term);
+  }
+  return f.include.length || f.exclude.length ? f : undefined;
+}
+
+export function matchesLineFilter(line: string, f?: LineFilter): boolean {
+  if (!f) return true;
+  const s = line.toLowerCase();
+  return f.include.every((t) => s.includes(t)) && !f.exclude.some((t) => s.includes(t));
+}
+
+/** 마커 라인이면 소스 이름, 아니면 undefined */
+export function readSourceMark(line: string): string | undefined {
+  if (!line.startsWith(SOURCE_MARK_PREFIX)) return undefined;
+  return line.slice(SOURCE_MARK_PREFIX.length).trim() || undefined;
+}
@@ -0,0 +1,84 @@
+// === src/core/logs/RepeatCoalescer.ts ===
+/**
+ * 실시간 중복 라인 합치기(옵션: realtimeCoalesce / EDGE_TOOL_REALTIME_COALESCE=1)
+ * - 직전과 같은 메시지가 연속으로 오면 저장하지 않고 반복 횟수만 센다.
+ * - 반복 중에는 첫 엔트리(head)에 현재 횟수(repeat)를 붙여 뷰어로 보낸다(매 flush마다 갱신, 디스크엔 없음).
+ * - 다른 메시지가 오거나 스트림이 끝나면 `last message repeated N times` 엔트리를 저장해 기록을 남긴다.
+ * - 비교 키: logcat이면 레벨·태그·PID·본문, 그 외엔 앞쪽 타임스탬프를 뗀 원문
+ */
+import type { LogEntry } from '@ipc/messages';
+
+/** 줄 앞 타임스탬프(ISO / syslog `Mon DD` / logcat `MM-DD`) + 뒤 공백 */
+const LEADING_TS_RX = new RegExp(
+  String.raw`^\[?(?:\d{4}-\d{2}-\d{2}[T ]|\d{2}-\d{2}\s+|[A-Z][a-z]{2}\s+\d{1,2}\s+)?` +
+    String.raw`\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?\]?\s+`,
+);
+
+export function repeatKey(e: LogEntry): string {
+  const msg = e.parsed?.message;
+  if (typeof msg === 'string') return `${e.level}|${e.process ?? ''}|${e.pid ?? ''}|${msg}`;
+  return String(e.text ?? '').replace(LEADING_TS_RX, '');
+}
+
+export function repeatSummaryText(n: number): string {
+  return `last message repeated ${n} time${n === 1 ? '' : 's'}`;
+}
+
+type Run = { key: string; head: LogEntry; count: number; idx?: number };
+
+export class RepeatCoalescer {
+  private run?: Run;
+
+  /**
+   * 새 엔트리 투입
+   * - absorbed: 직전 반복으로 흡수됨(호출부는 저장하지 않음)
+   * - summary: 이전 반복이 끝났으면 그 요약 엔트리(새 엔트리보다 먼저 저장)
+   */
+  offer(e: LogEntry): { absorbed: boolean; summary?: LogEntry } {
+    const key = repeatKey(e);
+    if (this.run && this.run.key === key) {
+      this.run.count++;
+      return { absorbed: true };
+    }
+    const summary = this.end();
+    this.run = { key, head: e, count: 0 };
+    return { absorbed: false, summary };
+  }
+
+  /** 진행 중 반복을 끝내고 요약 엔트리 반환(반복이 없었으면 undefined) */
+  end(): LogEntry | undefined {
+    const r = this.run;
+    this.run = undefined;
+    if (!r || r.count === 0) return undefined;
+    const now = Date.now();
+    return {
+      id: now,
+      ts: now,
+      level: r.head.level,
+      type: r.head.type,
+      source: r.head.source,
+      file: r.head.file,
+      text: repeatSummaryText(r.count),
+      repeat: r.count,
+    };
+  }
+
+  /** head가 이미 flush되어 뷰어 갱신이 필요한지(반복 중) */
+  get pendingRepeat(): boolean {
+    return !!this.run && this.run.count > 0 && this.run.idx !== undefined;
+  }
+
+  /** flush된 배치에서 head의 전역 인덱스를 기록(baseIdx = 배치 직전까지의 총 라인 수) */
+  noteFlushed(batch: readonly LogEntry[], baseIdx: number) {
+    if (!this.run || this.run.idx !== undefined) return;
+    const i = batch.indexOf(this.run.head);
+    if (i >= 0) this.run.idx = baseIdx + i + 1;
+  }
+
+  /** 뷰어로 보낼 페이지에서 head 행에 현재 반복 횟수를 붙인다(원본 엔트리는 건드리지 않음) */
+  annotate(page: LogEntry[]): LogEntry[] {
+    const r = this.run;
+    if (!r || !r.count || r.idx === undefined) return page;
+    return page.map((e) => (e.idx === r.idx ? { ...e, repeat: r.count } : e));
+  }
+}
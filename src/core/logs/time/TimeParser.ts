@@ -134,11 +134,25 @@ function toMs3(frac: string | undefined): number {
   return parseInt(ms3, 10) || 0;
 }
 
+/** "no error", "0 failures", "errors=0" 처럼 문제가 없다는 뜻의 구절 — 레벨 추정 전에 지운다 */
+const NEGATED_LEVEL_RES = [
+  /\b(?:no|0|zero|without|non)[\s-]+(?:errors?|failures?|warnings?)\b/gi,
+  /\b(?:errors?|failures?|warnings?)\s*[:=]\s*0\b/gi,
+];
+
+/**
+ * 레벨 필드가 없는 라인(Homey 패턴 등)의 심각도 추정 — 단어 경계로만 매칭하는 보수적 규칙
+ * - E: error, err, fatal, panic, fail, failed, failure, exception
+ * - W: warn, warning
+ * - D: debug, trace
+ * - 그 외 I. 복수형(errors)·합성어(stderr, onError)는 매칭하지 않고, 부정 구절은 무시
+ */
 export function guessLevel(line: string): 'D' | 'I' | 'W' | 'E' {
   return measureBlock('TimeParser.guessLevel', () => {
-    if (/\b(error|err|fail|fatal)\b/i.test(line)) return 'E';
-    if (/\bwarn(ing)?\b/i.test(line)) return 'W';
-    if (/\b(debug|trace)\b/i.test(line)) return 'D';
+    const s = NEGATED_LEVEL_RES.reduce((acc, re) => acc.replace(re, ' '), line);
+    if (/\b(?:error|err|fatal|panic|fail(?:ed|ure)?|exception)\b/i.test(s)) return 'E';
+    if (/\bwarn(?:ing)?\b/i.test(s)) return 'W';
+    if (/\b(?:debug|trace)\b/i.test(s)) return 'D';
     return 'I';
   });
 }
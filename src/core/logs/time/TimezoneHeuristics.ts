@@ -3,44 +3,88 @@ import { getLogger } from '../../logging/extension-logger.js';
 // 로그 스팸 완화: per-line 출력 최소화, 집계로 대체
 import { measure } from '../../logging/perf.js';
 
+/**
+ * 타임존/단조 보정 옵션(custom_log_parser.json 의 configure.timezone_correction)
+ * - enabled=false 면 보정 자체를 건너뛴다(이미 일관된 로그용).
+ * - offsetHours 를 지정하면 실측 점프 대신 해당 오프셋(예: 5.5)을 고정 적용한다.
+ */
+export type TimezoneCorrectionOptions = {
+  enabled?: boolean;
+  /** 점프 의심 임계(시간) */
+  minJumpHours?: number;
+  /** 복귀 판정 최소 차이(시간) */
+  minReturnHours?: number;
+  /** 보정 오프셋 상한(시간) — 초과 점프는 보정하지 않고 해당 라인만 클램프 */
+  maxOffsetHours?: number;
+  /** 고정 오프셋(시간). 미지정 시 로그에서 실측한 점프를 15분 단위로 반올림 */
+  offsetHours?: number;
+  /** 작은 역전 허용 폭(ms) — 초과 역전만 점프로 집계 */
+  smallDeltaMs?: number;
+};
+
+export const DEFAULT_TZ_CORRECTION: Required<Omit<TimezoneCorrectionOptions, 'offsetHours'>> = {
+  enabled: true,
+  minJumpHours: 3,
+  minReturnHours: 1,
+  maxOffsetHours: 14,
+  smallDeltaMs: 1500,
+};
+
+/** configure.timezone_correction(JSON, snake_case) → 옵션 정규화 */
+export function readTimezoneCorrectionOptions(raw: any): TimezoneCorrectionOptions {
+  const num = (v: any) => {
+    const n = Number(v);
+    return v != null && Number.isFinite(n) && n > 0 ? n : undefined;
+  };
+  if (!raw || typeof raw !== 'object') return {};
+  return {
+    enabled: raw.enabled === false ? false : undefined,
+    minJumpHours: num(raw.min_jump_hours),
+    minReturnHours: num(raw.min_return_hours),
+    maxOffsetHours: num(raw.max_offset_hours),
+    offsetHours: num(raw.offset_hours),
+    smallDeltaMs: num(raw.small_delta_ms),
+  };
+}
+
 /**
  * 파일 타입별(Time series 단위) 단조 보정기.
  * - 병합은 "최신 → 오래된" 순으로 진행된다고 가정한다.
  * - 정수 그라뉼러리티(밀리초) 슬롯 단위로 단조비증가(desc)를 보장.
- * - 글로벌 offset 누적 적용.
- * - 작은 역전(≤ SMALL_DELTA_MS)은 전역 오프셋을 바꾸지 않고
- *   **해당 라인만** 1ms 로컬 클램프한다(표시 문자열은 원본 유지).
+ * - 역전(직전 반환값보다 큰 ts)은 **해당 라인만** 직전값-1ms로 클램프한다(표시 문자열은 원본 유지).
+ *   뒤 라인의 실제 ts가 직전 반환값 아래로 내려오면 그대로 돌려준다 — 파일 전체를 밀지 않는다.
  */
 export class MonotonicCorrector {
   private lastCorrected?: number; // 직전 반환한 보정 ts(단조감소 체크용)
   private readonly log = getLogger('MonotonicCorrector');
   /**
-   * 작은 역전 허용 폭(1~2s 권장). 이내면 글로벌 offset 조정 금지.
+   * 작은 역전 허용 폭(1~2s 권장). 이보다 큰 역전만 점프로 집계한다.
    */
-  private readonly SMALL_DELTA_MS = 1500;
+  private readonly SMALL_DELTA_MS: number;
   // 집계 카운터(스팸 억제용)
   private clampCount = 0; // per-line 클램프 누적
-  private shiftCount = 0; // per-line 시프트 누적
-  private worstShiftHours = 0; // 가장 큰 시프트(시간)
-  private worstShiftAt?: number; // 가장 큰 시프트 최초 관측 시각
+  private jumpCount = 0; // SMALL_DELTA_MS 초과 역전 수
+  private worstJumpHours = 0; // 가장 큰 역전(시간)
+  private worstJumpAt?: number; // 가장 큰 역전 최초 관측 시각
+  private cappedCount = 0; // 상한(maxOffsetHours) 초과 역전 수
 
   // (선택) per-line 샘플 로그를 원하면 >0으로 조정
   private readonly LOG_SAMPLE_N = 0;
 
-  // 과도한 보정을 방지하기 위한 안전 캡(필요 시 조정/제거 가능)
-  private readonly MAX_SHIFT_HOURS = 14;
-
-  // 글로벌 누적 오프셋(단조 보정용)
-  private globalOffsetMs = 0;
+  // 타임존 보정 상한 — 이보다 큰 역전은 보정 대상이 아니었으므로 첫 건을 경고
+  private readonly MAX_SHIFT_HOURS: number;
 
   constructor(
     public readonly label: string,
     private readonly granularityMs: number = 1,
-  ) {}
+    opts: TimezoneCorrectionOptions = {},
+  ) {
+    this.SMALL_DELTA_MS = opts.smallDeltaMs ?? DEFAULT_TZ_CORRECTION.smallDeltaMs;
+    this.MAX_SHIFT_HOURS = opts.maxOffsetHours ?? DEFAULT_TZ_CORRECTION.maxOffsetHours;
+  }
 
   /**
    * 최신→오래된 순으로 rawTs가 들어온다.
-   * index는 현재 처리 중인 로그의 인덱스(0부터).
    * 반환: 보정된 ts(단조비증가 보장)
    */
   @measure()
@@ -49,37 +93,35 @@ export class MonotonicCorrector {
 
     // 정수 그라뉼러리티(밀리초) 슬롯으로 클램프
     const slotMs = Math.max(1, this.granularityMs);
-    const clamped = Math.floor(rawTs / slotMs) * slotMs;
-    if (clamped !== rawTs) this.clampCount++; // 집계: 슬롯 클램프 발생(소수 ms 등)
-
-    // 글로벌 오프셋 적용
-    let corrected = clamped + this.globalOffsetMs;
+    let corrected = Math.floor(rawTs / slotMs) * slotMs;
 
-    // 단조비증가(desc) 위반 시 보정
+    // 단조비증가(desc) 위반: 이 라인만 1ms 내리고, 기준(lastCorrected)은 실제 값이 다시 넘을 때까지 유지
     if (this.lastCorrected !== undefined && corrected > this.lastCorrected) {
       const diffMs = corrected - this.lastCorrected;
-      // ⬇️ 작은 역전은 전역 오프셋을 건드리지 않고 해당 라인만 1ms 내림
-      if (diffMs <= this.SMALL_DELTA_MS) {
-        corrected = this.lastCorrected - 1; // 로컬 1ms 클램프
-        this.clampCount++;
-      } else {
-        // 큰 점프 → 글로벌 오프셋으로 보정(기존 동작)
-        const shiftHours = diffMs / (60 * 60 * 1000);
-        if (shiftHours > this.worstShiftHours) {
-          this.worstShiftHours = shiftHours;
-          this.worstShiftAt = rawTs;
+      if (diffMs > this.SMALL_DELTA_MS) {
+        this.jumpCount++;
+        const hours = diffMs / (60 * 60 * 1000);
+        if (hours > this.worstJumpHours) {
+          this.worstJumpHours = hours;
+          this.worstJumpAt = rawTs;
         }
-        this.globalOffsetMs -= diffMs;
-        corrected = clamped + this.globalOffsetMs;
-        this.shiftCount++;
-        if (this.LOG_SAMPLE_N > 0 && this.shiftCount % this.LOG_SAMPLE_N === 0) {
-          this.log.debug?.(
-            `Monotonic shift [${this.label}]: raw=${new Date(rawTs).toISOString()} ` +
-              `clamped=${new Date(clamped).toISOString()} corrected=${new Date(corrected).toISOString()} ` +
-              `shift=${shiftHours.toFixed(2)}h globalOffset=${this.globalOffsetMs / (60 * 60 * 1000)}h`,
+        if (hours > this.MAX_SHIFT_HOURS && this.cappedCount++ === 0) {
+          // 조용히 넘기지 않도록 첫 건은 즉시 경고(나머지는 summary에서 집계)
+          this.log.warn(
+            `Monotonic cap [${this.label}]: jump ${hours.toFixed(1)}h > ` +
+              `max_offset_hours=${this.MAX_SHIFT_HOURS} @ ${new Date(rawTs).toISOString()} ` +
+              '— clamped, not shifted',
           );
         }
       }
+      corrected = this.lastCorrected - 1; // 로컬 1ms 클램프
+      this.clampCount++;
+      if (this.LOG_SAMPLE_N > 0 && this.clampCount % this.LOG_SAMPLE_N === 0) {
+        this.log.debug?.(
+          `Monotonic clamp [${this.label}]: raw=${new Date(rawTs).toISOString()} ` +
+            `corrected=${new Date(corrected).toISOString()}`,
+        );
+      }
     }
 
     this.lastCorrected = corrected;
@@ -89,20 +131,21 @@ export class MonotonicCorrector {
   // 집계 요약을 출력하고 카운터 초기화
   @measure()
   summary() {
-    if (this.shiftCount || this.clampCount) {
+    if (this.clampCount) {
       const worst =
-        this.worstShiftAt != null
-          ? `${this.worstShiftHours.toFixed(1)}h @ ${new Date(this.worstShiftAt).toISOString()}`
+        this.worstJumpAt != null
+          ? `${this.worstJumpHours.toFixed(1)}h @ ${new Date(this.worstJumpAt).toISOString()}`
           : 'n/a';
       this.log.info(
-        `Monotonic summary [${this.label}] shifts=${this.shiftCount}, clamped=${this.clampCount}, ` +
-          `worst_shift=${worst}, global_offset=${this.globalOffsetMs / (60 * 60 * 1000)}h`,
+        `Monotonic summary [${this.label}] clamped=${this.clampCount}, jumps=${this.jumpCount}, ` +
+          `capped=${this.cappedCount}, worst_jump=${worst}`,
       );
     }
+    this.cappedCount = 0;
     this.clampCount = 0;
-    this.shiftCount = 0;
-    this.worstShiftHours = 0;
-    this.worstShiftAt = undefined;
+    this.jumpCount = 0;
+    this.worstJumpHours = 0;
+    this.worstJumpAt = undefined;
   }
 }
 
@@ -135,7 +178,9 @@ export class TimezoneCorrector {
   private readonly LOG_SAMPLE_N = 0;
 
   // 과도한 보정을 방지하기 위한 안전 캡(필요 시 조정/제거 가능)
-  private readonly MAX_TZ_OFFSET_HOURS = 14;
+  private readonly MAX_TZ_OFFSET_HOURS: number;
+  // 고정 오프셋(시간). undefined 면 실측 점프 사용
+  private readonly FIXED_OFFSET_HOURS?: number;
 
   // 점프 의심 상태(최대 1건)
   private suspected:
@@ -152,13 +197,19 @@ export class TimezoneCorrector {
   private retroSegments: { start: number; end: number; deltaMs: number }[] = [];
 
   // 임계값
-  private readonly MIN_JUMP_HOURS = 3; // 점프 의심 임계값(현행 3h 유지)
-  private readonly MIN_RETURN_HOURS = 1; // 복귀 최소 차이(요청하신 1h)
+  private readonly MIN_JUMP_HOURS: number; // 점프 의심 임계값(기본 3h)
+  private readonly MIN_RETURN_HOURS: number; // 복귀 최소 차이(기본 1h)
 
   constructor(
     public readonly label: string,
     private readonly expectDescFeed = true,
-  ) {}
+    opts: TimezoneCorrectionOptions = {},
+  ) {
+    this.MIN_JUMP_HOURS = opts.minJumpHours ?? DEFAULT_TZ_CORRECTION.minJumpHours;
+    this.MIN_RETURN_HOURS = opts.minReturnHours ?? DEFAULT_TZ_CORRECTION.minReturnHours;
+    this.MAX_TZ_OFFSET_HOURS = opts.maxOffsetHours ?? DEFAULT_TZ_CORRECTION.maxOffsetHours;
+    this.FIXED_OFFSET_HOURS = opts.offsetHours;
+  }
 
   /**
    * 최신→오래된 순으로 rawTs가 들어온다.
@@ -190,13 +241,11 @@ export class TimezoneCorrector {
 
       if (returned) {
         // 복귀 확정 → suspected 구간(start..index-1)에만 Δoffset 적용하는 retro segment 생성
-        // Δoffset은 "실측 jump 크기(hourDiff)"를 정수 시간으로 반올림하여 적용한다(분 단위 무시).
-        // 안전을 위해 1h~MAX_TZ_OFFSET_HOURS 범위로 클램프.
+        // Δoffset은 고정 오프셋(설정) 또는 "실측 jump 크기(hourDiff)"를 15분 단위로 반올림하여 적용한다
+        // (+05:30, +05:45 등 비정시 타임존 대응). 안전을 위해 0.25h~MAX_TZ_OFFSET_HOURS 범위로 클램프.
         const sign = s.direction === 'positive' ? -1 : +1; // 시계가 +로 튀었으면 과거 방향(-)으로 보정
-        const roundedHours = Math.min(
-          this.MAX_TZ_OFFSET_HOURS,
-          Math.max(1, Math.round(s.hourDiff)),
-        );
+        const measuredHours = this.FIXED_OFFSET_HOURS ?? Math.round(s.hourDiff * 4) / 4;
+        const roundedHours = Math.min(this.MAX_TZ_OFFSET_HOURS, Math.max(0.25, measuredHours));
         const deltaMs = sign * roundedHours * hour;
         this.log.debug?.(
           `TZ measured: jump=${s.hourDiff.toFixed(2)}h -> apply=${roundedHours}h, Δ=${deltaMs / 3600000}h`,
@@ -332,6 +381,28 @@ export class TimezoneCorrector {
   }
 }
 
+/**
+ * 한 타입의 엔트리(최신→오래된 물리 순서)에 타임존 점프 소급 보정 → 단조 보정을 차례로 적용
+ * - TimezoneCorrector: 점프 후 복귀가 확인된 구간만 Δ(offset_hours 또는 실측)만큼 되돌림
+ * - MonotonicCorrector: 남은 역전은 해당 라인만 클램프해 단조비증가로 정리
+ */
+export function correctTimestamps(
+  label: string,
+  entries: { ts: number }[],
+  opts: TimezoneCorrectionOptions = {},
+): void {
+  if (opts.enabled === false || !entries.length) return;
+  const tz = new TimezoneCorrector(label, true, opts);
+  entries.forEach((e, i) => tz.adjust(e.ts, i));
+  tz.finalizeSuspected();
+  for (const seg of tz.drainRetroSegments()) {
+    for (let i = seg.start; i <= seg.end && i < entries.length; i++) entries[i].ts += seg.deltaMs;
+  }
+  const mc = new MonotonicCorrector(label, 1, opts); // 1ms 단위 클램프
+  for (const e of entries) e.ts = mc.adjust(e.ts);
+  mc.summary();
+}
+
 /** (이전 호환) no-op */
 export function identity<T>(v: T): T {
   return v;
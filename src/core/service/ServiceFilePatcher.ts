@@ -1,11 +1,13 @@
 import * as vscode from 'vscode';
 
+import { ErrorCategory, RemoteCommandError, XError } from '../../shared/errors.js';
 import {
   readUserHomeyConfig,
   readUserHomeyConfigLoose,
   resolveServiceFilePath,
 } from '../config/userconfig.js';
-import { connectionManager } from '../connection/ConnectionManager.js';
+import { connectionManager, type ShellExecutor } from '../connection/ConnectionManager.js';
+import { shellQuote as q } from '../connection/shellQuote.js';
 import { getLogger } from '../logging/extension-logger.js';
 
 const log = getLogger('SvcPatcher');
@@ -15,6 +17,7 @@ export class ServiceFilePatcher {
   constructor(
     private unit: string,
     private svcPathGuess = '/lib/systemd/system/homey-pro@.service',
+    private sh: ShellExecutor = connectionManager,
   ) {}
 
   /**
@@ -56,8 +59,8 @@ export class ServiceFilePatcher {
 
     // 2) systemctl FragmentPath 조회
     try {
-      const { stdout } = await connectionManager.run(
-        `sh -lc 'systemctl show -p FragmentPath "${this.unit}" 2>/dev/null | cut -d= -f2'`,
+      const { stdout } = await this.sh.run(
+        `sh -lc ${q(`systemctl show -p FragmentPath ${q(this.unit)} 2>/dev/null | cut -d= -f2`)}`,
       );
       const p = String(stdout || '').trim();
       if (p) {
@@ -75,13 +78,34 @@ export class ServiceFilePatcher {
     return this.svcPathGuess;
   }
 
-  /** 작업용 디렉터리 생성 (/tmp/edgetool-<unit>-<ts>) */
+  /**
+   * 작업용 디렉터리 생성 (/tmp/edgetool-<unit>-<ts>)
+   * - 만든 뒤 쓰기 시험(touch + rm)까지 통과해야 반환한다. 러너는 이 단계를 백업/컨테이너 정리
+   *   같은 파괴적 단계보다 먼저 돌리므로, /tmp가 가득 찼거나 ro여도 기기를 건드리기 전에 멈춘다.
+   */
   async makeWorkdir(): Promise<string> {
     if (this.workdir) return this.workdir;
     const safeUnit = this.unit.replace(/[^a-zA-Z0-9_.@-]/g, '_');
     const ts = Date.now();
     const dir = `/tmp/edgetool-${safeUnit}-${ts}`;
-    await connectionManager.run(`sh -lc 'mkdir -p ${q(dir)} && chmod 700 ${q(dir)}'`);
+    const probe = `${dir}/.write-probe`;
+    const script = [
+      `mkdir -p ${q(dir)}`,
+      `chmod 700 ${q(dir)}`,
+      `touch ${q(probe)}`,
+      `rm -f ${q(probe)}`,
+    ].join(' && ');
+    const r = await this.sh.run(`sh -lc ${q(`${script} 2>&1`)}`);
+    if ((r.code ?? 0) !== 0) {
+      const why = String(r.stdout || r.stderr || '').trim();
+      log.error(`[SvcPatcher] workdir not usable: ${dir} code=${r.code} ${why}`);
+      throw new XError(
+        ErrorCategory.Permission,
+        `작업 디렉터리를 쓸 수 없습니다: ${dir} (기기 /tmp가 가득 찼거나 읽기 전용일 수 있습니다)` +
+          (why ? `: ${why}` : ''),
+        r,
+      );
+    }
     this.workdir = dir;
     return dir;
   }
@@ -90,9 +114,13 @@ export class ServiceFilePatcher {
   async stageToWorkCopy(origFile: string): Promise<string> {
     const dir = await this.makeWorkdir();
     const work = `${dir}/homey.service.work`;
-    const cmd = `sh -lc 'cp -p ${q(origFile)} ${q(work)}'`;
+    const cmd = `sh -lc ${q(`cp -p ${q(origFile)} ${q(work)} 2>&1`)}`;
     log.debug(`stageToWorkCopy: cmd=${cmd}`);
-    await connectionManager.run(cmd);
+    const r = await this.sh.run(cmd);
+    if ((r.code ?? 0) !== 0) {
+      const out = `${r.stdout ?? ''}${r.stderr ?? ''}`;
+      throw new RemoteCommandError(cmd, r.code, out, `서비스 파일 작업본 복사 실패: ${origFile}`);
+    }
     return work;
   }
 
@@ -102,7 +130,7 @@ export class ServiceFilePatcher {
     try {
       const beforeHash = await this.computeHash(origFile).catch(() => '');
       const workHash = await this.computeHash(workFile).catch(() => '');
-      await connectionManager.run(`sh -lc 'mv -f ${q(workFile)} ${q(origFile)}'`);
+      await this.sh.run(`sh -lc ${q(`mv -f ${q(workFile)} ${q(origFile)}`)}`);
       const afterHash = await this.computeHash(origFile).catch(() => '');
       log.debug(
         `[SvcPatcher] replace: hash before=${beforeHash} work=${workHash} after=${afterHash}`,
@@ -111,7 +139,7 @@ export class ServiceFilePatcher {
       // 마지막 수단: mv 재시도 성공 시에는 오류를 전파하지 않는다.
       // eslint-disable-next-line no-useless-catch
       try {
-        await connectionManager.run(`sh -lc 'mv -f ${q(workFile)} ${q(origFile)}'`);
+        await this.sh.run(`sh -lc ${q(`mv -f ${q(workFile)} ${q(origFile)}`)}`);
         log.warn(
           `[SvcPatcher] replace: fallback mv succeeded after error: ${
             e instanceof Error ? e.message : String(e)
@@ -129,21 +157,21 @@ export class ServiceFilePatcher {
     const d = this.workdir;
     this.workdir = undefined;
     // 필요시 워크디렉터리 전체 정리하고 싶으면 아래 주석 해제
-    const cmd = `sh -lc 'rm -rf ${q(d)}'`;
+    const cmd = `sh -lc ${q(`rm -rf ${q(d)}`)}`;
     log.debug(`cleanupWorkdir: cmd=${cmd}`);
-    await connectionManager.run(cmd);
+    await this.sh.run(cmd);
   }
 
   async backup(file: string): Promise<string> {
     const bak = `${file}.bak.${Date.now()}`;
-    const cmd = `cp -f "${file}" "${bak}"`;
-    await connectionManager.run(`sh -lc ${q(cmd)}`);
+    const cmd = `cp -f ${q(file)} ${q(bak)}`;
+    await this.sh.run(`sh -lc ${q(cmd)}`);
     return bak;
   }
 
   async restore(file: string, backupPath: string): Promise<void> {
-    const cmd = `cp -f "${backupPath}" "${file}"`;
-    await connectionManager.run(`sh -lc ${q(cmd)}`);
+    const cmd = `cp -f ${q(backupPath)} ${q(file)}`;
+    await this.sh.run(`sh -lc ${q(cmd)}`);
   }
 
   async insertAfterExecStart(file: string, line: string) {
@@ -182,11 +210,14 @@ export class ServiceFilePatcher {
       log.debug(`[SvcPatcher] ${label}: ${cmd}`);
       // 더블쿼트 안심 래핑: ", $, `, \ 를 이스케이프
       const escaped = cmd.replace(/(["$`\\])/g, '\\$1');
-      const res = await connectionManager.run(`sh -lc "${escaped}"`);
+      const res = await this.sh.run(`sh -lc "${escaped}"`);
       log.debug(`[SvcPatcher] ${label}: exit=${res.code}`);
       if (res.stdout) log.debug(`[SvcPatcher] ${label}[stdout]\n${String(res.stdout)}`);
       if (res.stderr) log.debug(`[SvcPatcher] ${label}[stderr]\n${String(res.stderr)}`);
-      if ((res.code ?? 0) !== 0) throw new Error(`${label} failed (code=${res.code})`);
+      if ((res.code ?? 0) !== 0) {
+        const out = `${res.stdout ?? ''}${res.stderr ?? ''}`;
+        throw new RemoteCommandError(cmd, res.code, out, `${label} failed (code=${res.code})`);
+      }
       return res;
     };
 
@@ -226,8 +257,8 @@ export class ServiceFilePatcher {
       // }
     } finally {
       // 7) 청소 (임시 파일 제거)
-      await connectionManager
-        .run(`sh -lc ${q(`rm -f ${sedScript} ${tmp} 2>/dev/null || true`)}`)
+      await this.sh
+        .run(`sh -lc ${q(`rm -f ${q(sedScript)} ${q(tmp)} 2>/dev/null || true`)}`)
         .catch(() => {});
     }
   }
@@ -237,34 +268,42 @@ export class ServiceFilePatcher {
     if (!patterns?.length) return;
     const sedScripts = patterns.map((rx) => `-e ${q(`/${rx}/d`)}`).join(' ');
     const cmd = `sed -E -i ${sedScripts} ${q(file)}`;
-    await connectionManager.run(`sh -lc ${q(cmd)}`);
+    await this.sh.run(`sh -lc ${q(cmd)}`);
   }
 
   async daemonReload() {
-    await connectionManager.run(`sh -lc 'systemctl daemon-reload'`);
+    await this.sh.run(`sh -lc 'systemctl daemon-reload'`);
   }
   async restart() {
-    await connectionManager.run(
-      `sh -lc 'SYSTEMD_PAGER= systemctl restart --no-pager --no-ask-password ${q(this.unit)}'`,
-    );
+    const cmd = `SYSTEMD_PAGER= systemctl restart --no-pager --no-ask-password ${q(this.unit)}`;
+    await this.sh.run(`sh -lc ${q(cmd)}`);
   }
 
   async contains(file: string, markerRe: string): Promise<boolean> {
     // $1: 정규식, $2: 파일경로 — 중첩 싱글쿼트 문제 회피
     const cmd = `sh -lc 'grep -E "$1" "$2" >/dev/null 2>&1' _ ${q(markerRe)} ${q(file)}`;
     log.debug(`contains: cmd=${cmd}`);
-    const { code } = await connectionManager.run(cmd);
+    const { code } = await this.sh.run(cmd);
     return (code ?? 1) === 0;
   }
 
+  /** 파일 본문(읽기 실패 시 빈 문자열) */
+  async read(file: string): Promise<string> {
+    const { stdout } = await this.sh.run(`sh -lc ${q(`cat ${q(file)} 2>/dev/null`)}`);
+    return String(stdout || '');
+  }
+
   // alias
   async exists(file: string, markerRe: string): Promise<boolean> {
     return this.contains(file, markerRe);
   }
 
   async computeHash(file: string): Promise<string> {
-    const { stdout } = await connectionManager.run(
-      `sh -lc 'if command -v sha256sum >/dev/null 2>&1; then sha256sum ${q(file)} | cut -d" " -f1; else md5sum ${q(file)} | cut -d" " -f1; fi'`,
+    const { stdout } = await this.sh.run(
+      `sh -lc ${q(
+        `if command -v sha256sum >/dev/null 2>&1; then sha256sum ${q(file)} | cut -d" " -f1; ` +
+          `else md5sum ${q(file)} | cut -d" " -f1; fi`,
+      )}`,
     );
     return String(stdout || '').trim();
   }
@@ -278,10 +317,6 @@ export class ServiceFilePatcher {
 function esc(s: string) {
   return String(s).replace(/\\/g, '\\\\');
 }
-function q(s: string) {
-  return "'" + String(s).replace(/'/g, `'\\''`) + "'";
-}
-
 // 삽입 라인 존재 검증용 정규식 생성기
 // 예: line='--volume="homey-app:/app:rw"' → ^[[:space:]]*--volume\="homey\-app:\/app:rw"[[:space:]]*\\?[[:space:]]*$
 function buildMarkerRegex(line: string): string {
@@ -1,8 +1,10 @@
 // === src/core/service/serviceDiscovery.ts ===
 import * as vscode from 'vscode';
 
+import { ErrorCategory, XError } from '../../shared/errors.js';
 import { readUserHomeyConfig, writeUserHomeyConfig } from '../config/userconfig.js';
 import { connectionManager } from '../connection/ConnectionManager.js';
+import { shellQuote as q } from '../connection/shellQuote.js';
 import { getLogger } from '../logging/extension-logger.js';
 import { measureBlock } from '../logging/perf.js';
 
@@ -25,35 +27,26 @@ export async function discoverHomeyServiceName(
       return cfg.homey_service_name.trim();
     }
 
-    // 2) 시스템에서 검색
-    const { stdout } = await connectionManager.run(
-      `sh -lc 'SYSTEMD_PAGER= systemctl list-units --type=service --all --no-legend --plain --no-pager 2>/dev/null | grep -E "^homey-(pro|bridge).*\\.service" | sed -E "s/[[:space:]].*$//" || true'`,
-    );
-    const list = String(stdout || '')
-      .split(/\r?\n/)
-      .map((s) => s.trim())
-      .filter(Boolean);
-
+    // 2) 시스템에서 검색 — 여러 개면 사용자 선택
+    const list = await listHomeyUnits();
     if (list.length === 0) {
       vscode.window.showWarningMessage('Homey 관련 systemd 서비스가 감지되지 않았습니다.');
       return undefined;
     }
-    if (list.length === 1) {
-      await writeUserHomeyConfig(ctx, { homey_service_name: list[0] });
-      log.info(`detected service: ${list[0]}`);
-      return list[0];
-    }
+    const unit = list.length === 1 ? list[0] : await pickHomeyUnit(list);
+    if (!unit) return undefined;
+    await writeUserHomeyConfig(ctx, { homey_service_name: unit });
+    log.info(`${list.length === 1 ? 'detected' : 'user selected'} service: ${unit}`);
+    return unit;
+  });
+}
 
-    // 3) 복수 후보 → QuickPick
-    const pick = await vscode.window.showQuickPick(list, {
-      title: 'Homey 서비스 선택',
-      placeHolder: 'homey-(pro|bridge)*.service',
-      ignoreFocusOut: true,
-    });
-    if (!pick) return undefined;
-    await writeUserHomeyConfig(ctx, { homey_service_name: pick });
-    log.info(`user selected service: ${pick}`);
-    return pick;
+/** 복수 후보 → QuickPick (취소 시 undefined) */
+export async function pickHomeyUnit(units: string[]): Promise<string | undefined> {
+  return await vscode.window.showQuickPick(units, {
+    title: 'Homey 서비스 선택',
+    placeHolder: `Homey 서비스가 ${units.length}개 있습니다 — 작업할 인스턴스를 고르세요`,
+    ignoreFocusOut: true,
   });
 }
 
@@ -67,8 +60,9 @@ export async function checkServiceFileExists(
     let fileCur = fileGuess;
 
     for (;;) {
+      const full = `${pathCur}${pathCur.endsWith('/') ? '' : '/'}${fileCur}`;
       const { stdout } = await connectionManager.run(
-        `sh -lc 'if [ -f "${pathCur}${pathCur.endsWith('/') ? '' : '/'}${fileCur}" ]; then echo OK; else echo NO; fi'`,
+        `sh -lc ${q(`if [ -f ${q(full)} ]; then echo OK; else echo NO; fi`)}`,
       );
       if (String(stdout).trim() === 'OK') return { path: pathCur, file: fileCur };
 
@@ -101,19 +95,75 @@ export async function checkServiceFileExists(
 // ─────────────────────────────────────────────────────────────
 // SSOT: Homey systemd unit 해상/검증/캐시
 // ─────────────────────────────────────────────────────────────
-export async function resolveHomeyUnit(ctx?: vscode.ExtensionContext): Promise<string> {
-  // 1) 컨텍스트 결정(없으면 전역에서 가져옴)
-  const context =
-    ctx ?? ((vscode as any).extensions?.extensionContext as vscode.ExtensionContext | undefined);
-  // 2) 캐시된 서비스명 재사용(+유효성 검사)
+// 연결별 메모리 캐시 — 연결 세대가 바뀌면 무효, 사용 전 유닛 존재 여부 재검증
+let unitCache: { generation: number; unit: string } | undefined;
+// 동시에 들어온 해상 요청(버튼 상태 갱신 등)이 선택 창을 여러 번 띄우지 않도록 공유
+let unitPending: Promise<string> | undefined;
+// 선택 결과를 저장할 확장 컨텍스트(활성화 시 등록)
+let activationCtx: vscode.ExtensionContext | undefined;
+
+/** 캐시된 Homey 유닛명 폐기(연결 전환/업데이트/강제 재탐지 시) */
+export function invalidateHomeyUnitCache(reason = 'manual') {
+  if (unitCache) log.info(`homey unit cache invalidated (${reason}): ${unitCache.unit}`);
+  unitCache = undefined;
+}
+
+/**
+ * 활성화 시 1회: 연결 전환 시 유닛 캐시를 비우도록 구독하고,
+ * 유닛 선택을 사용자 설정에 남길 컨텍스트를 등록한다(ctx 없는 호출부도 같은 설정을 쓴다)
+ */
+export function watchConnectionForUnitCache(ctx?: vscode.ExtensionContext): { dispose(): void } {
+  if (ctx) activationCtx = ctx;
+  return connectionManager.onDidChangeConnection((next) =>
+    invalidateHomeyUnitCache(`connection changed → ${next?.id ?? 'none'}`),
+  );
+}
+
+export async function resolveHomeyUnit(
+  ctx?: vscode.ExtensionContext,
+  opts?: { force?: boolean },
+): Promise<string> {
+  if (unitPending && !opts?.force) return unitPending;
+  const p = resolveHomeyUnitOnce(ctx, opts);
+  unitPending = p;
   try {
-    if (context) {
-      const cached = await discoverHomeyServiceName(context);
-      if (cached && (await isUnitValid(cached))) return cached;
+    return await p;
+  } finally {
+    if (unitPending === p) unitPending = undefined;
+  }
+}
+
+async function resolveHomeyUnitOnce(
+  ctx?: vscode.ExtensionContext,
+  opts?: { force?: boolean },
+): Promise<string> {
+  const generation = connectionManager.getGeneration();
+  if (opts?.force) invalidateHomeyUnitCache('force');
+  // 0) 메모리 캐시: 같은 연결 세대이고 유닛이 아직 존재할 때만 신뢰(구독 누락 대비 이중 확인)
+  if (unitCache) {
+    if (unitCache.generation !== generation) {
+      invalidateHomeyUnitCache('connection changed');
+    } else if (await isUnitValid(unitCache.unit)) {
+      return unitCache.unit;
+    } else {
+      invalidateHomeyUnitCache('unit no longer exists');
+    }
+  }
+  // 1) 컨텍스트 결정(없으면 활성화 시 등록된 것)
+  const context = ctx ?? activationCtx;
+  // 2) 저장된 서비스명(이전 선택/--service 지정) 재사용(+유효성 검사) — 강제 재탐지면 건너뜀
+  try {
+    if (context && !opts?.force) {
+      const stored = (await readUserHomeyConfig(context)).homey_service_name?.trim();
+      if (stored && (await isUnitValid(stored))) {
+        unitCache = { generation, unit: stored };
+        return stored;
+      }
     }
   } catch {}
-  // 3) 자동 탐색 → 캐시 저장
-  const detected = await detectHomeyUnit();
+  // 3) 자동 탐색 → 캐시 저장 (여러 개면 사용자 선택, 취소하면 오류 — 첫 항목을 임의로 고르지 않음)
+  const detected = await detectHomeyUnit(pickHomeyUnit);
+  unitCache = { generation, unit: detected };
   try {
     if (context) await writeUserHomeyConfig(context, { homey_service_name: detected });
   } catch {}
@@ -122,35 +172,74 @@ export async function resolveHomeyUnit(ctx?: vscode.ExtensionContext): Promise<s
 
 export async function isUnitValid(unit: string): Promise<boolean> {
   const { stdout } = await connectionManager.run(
-    `sh -lc 'systemctl show -p FragmentPath "${unit}" 2>/dev/null | cut -d= -f2'`,
+    `sh -lc ${q(`systemctl show -p FragmentPath ${q(unit)} 2>/dev/null | cut -d= -f2`)}`,
   );
   return Boolean(String(stdout || '').trim());
 }
 
-export async function detectHomeyUnit(): Promise<string> {
+// 허용: homey-pro / homey-bridge ... (중간은 자유) ... .service
+const HOMEY_UNIT_RE = /^homey-(pro|bridge).*\.service$/;
+
+/** 기기의 Homey 유닛 전체(템플릿 인스턴스가 여러 개일 수 있음, 중복 제거) */
+export async function listHomeyUnits(): Promise<string[]> {
   const cmd =
     'SYSTEMD_PAGER= systemctl list-units --type=service --all --no-legend --plain --no-pager 2>/dev/null | ' +
-    'grep -E "^homey-(pro|bridge).*\\.service" | sed -E "s/[[:space:]].*$//" | head -n1';
+    'grep -E "^homey-(pro|bridge).*\\.service" | sed -E "s/[[:space:]].*$//"';
   const { stdout } = await connectionManager.run(`sh -lc ${q(cmd)}`);
-  const unit = sanitizeUnit(stdout);
+  const units = String(stdout || '')
+    .split('\n')
+    .map(sanitizeUnit)
+    .filter((u) => HOMEY_UNIT_RE.test(u));
+  return Array.from(new Set(units));
+}
 
-  // 허용: homey-pro / homey-bridge ... (중간은 자유) ... .service
-  if (!/^homey-(pro|bridge).*\.service$/.test(unit)) {
-    throw new Error(`homey unit not found (got: ${unit || 'empty'})`);
+/**
+ * Homey 유닛 탐지
+ * - 하나면 그대로, 여러 개면 pick(대화형 선택)으로 고른다
+ * - pick이 없거나 취소되면 후보 목록과 함께 오류(명령 실행에서 `homeyServiceRedetect --service <name>`로 지정)
+ */
+export async function detectHomeyUnit(
+  pick?: (units: string[]) => Promise<string | undefined>,
+): Promise<string> {
+  const units = await listHomeyUnits();
+  if (!units.length) {
+    throw new XError(ErrorCategory.HomeyNotInstalled, 'homey unit not found (got: empty)');
   }
+  if (units.length === 1) return units[0];
+  const chosen = pick ? await pick(units) : undefined;
+  if (chosen) return chosen;
+  throw new XError(
+    ErrorCategory.Unknown,
+    `multiple homey units found: ${units.join(', ')} — ` +
+      'choose one with `homeyServiceRedetect --service <name>` (Homey Edge: Run Command…)',
+    { units },
+  );
+}
+
+/**
+ * 사용할 유닛을 직접 지정(`homeyServiceRedetect --service <name>`)
+ * - `.service`는 생략 가능. 기기에 있는 유닛이어야 하며, 캐시/설정에 저장돼 이후 restart·mount·env 작업이 모두 이 유닛을 쓴다
+ */
+export async function setHomeyUnit(name: string, ctx?: vscode.ExtensionContext): Promise<string> {
+  const raw = name.trim();
+  const unit = raw.endsWith('.service') ? raw : `${raw}.service`;
+  if (!/^[\w@.:-]+$/.test(unit)) {
+    throw new XError(ErrorCategory.Unknown, `invalid unit name: ${name}`);
+  }
+  if (!(await isUnitValid(unit))) {
+    throw new XError(ErrorCategory.HomeyNotInstalled, `unit not found on device: ${unit}`);
+  }
+  unitCache = { generation: connectionManager.getGeneration(), unit };
+  const context = ctx ?? activationCtx;
+  if (context) await writeUserHomeyConfig(context, { homey_service_name: unit });
+  log.info(`homey unit set by user: ${unit}`);
   return unit;
 }
 
 function sanitizeUnit(s: string): string {
-  // ANSI color 제거 + CR 제거 + 첫 줄 + 첫 토큰
-  const line =
-    String(s || '')
-      .replace(ANSI_COLOR_RE, '')
-      .replace(/\r/g, '')
-      .split('\n')[0] ?? '';
+  // ANSI color 제거 + CR 제거 + 첫 토큰
+  const line = String(s || '')
+    .replace(ANSI_COLOR_RE, '')
+    .replace(/\r/g, '');
   return line.trim().split(/\s+/)[0] ?? '';
 }
-function q(s: string) {
-  // shell-safe single-quote wrapper (템플릿 리터럴의 불필요 이스케이프 회피)
-  return "'" + String(s).replace(/'/g, `'\\''`) + "'";
-}
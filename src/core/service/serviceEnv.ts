@@ -0,0 +1,53 @@
+// === src/core/service/serviceEnv.ts ===
+/**
+ * 서비스 유닛 파일의 `--env="KEY=VALUE"` 라인 해석/생성 (`homeyEnv list|set|unset`)
+ * - HOMEY_APP_LOG / HOMEY_DEV_TOKEN 토글도 같은 규칙(`--env="KEY=1"`)을 쓴다.
+ * - 따옴표는 "…", '…', 없음 모두 읽지만 새로 넣을 때는 항상 큰따옴표 형태.
+ * - 같은 키가 여러 번 나오면 docker와 같이 마지막 값이 이긴다.
+ */
+import { ErrorCategory, XError } from '../../shared/errors.js';
+
+export type ServiceEnvVar = { key: string; value: string };
+
+const ENV_KEY_RE = /^[A-Za-z_][A-Za-z0-9_]*$/;
+const ENV_LINE_RX = /--env=(?:"([^"\n]*)"|'([^'\n]*)'|([^\s"'\\]+))/g;
+
+/** 유닛 파일 본문 → env 목록(키 등장 순서, 중복 키는 마지막 값) */
+export function parseServiceEnv(text: string): ServiceEnvVar[] {
+  const out = new Map<string, string>();
+  for (const m of String(text ?? '').matchAll(ENV_LINE_RX)) {
+    const kv = m[1] ?? m[2] ?? m[3] ?? '';
+    const eq = kv.indexOf('=');
+    if (eq <= 0) continue;
+    out.set(kv.slice(0, eq), kv.slice(eq + 1));
+  }
+  return [...out].map(([key, value]) => ({ key, value }));
+}
+
+/**
+ * 키/값 검증 — 유닛 파일과 sed 스크립트에 그대로 들어가므로 보수적으로 제한
+ * - 키: 셸 변수 이름 규칙
+ * - 값: 줄바꿈/큰따옴표/역슬래시 금지(ExecStart 라인-컨티뉴와 인용이 깨짐),
+ *   `$`/`%` 금지(systemd 변수 확장/지정자), `&`/`/` 금지(sed `a\` 삽입 텍스트가 깨짐)
+ */
+export function validateServiceEnv(key: string, value?: string) {
+  if (!ENV_KEY_RE.test(key)) {
+    throw new XError(ErrorCategory.Unknown, `잘못된 환경변수 이름: '${key}' (영문/숫자/_ 만 허용)`);
+  }
+  if (value !== undefined && /["\\\r\n$%&/]/.test(value)) {
+    throw new XError(
+      ErrorCategory.Unknown,
+      `환경변수 값에 쓸 수 없는 문자가 있습니다(큰따옴표, 역슬래시, 줄바꿈, $ % & /): ${key}`,
+    );
+  }
+}
+
+/** 서비스 파일에 넣을 라인 */
+export function serviceEnvLine(key: string, value: string): string {
+  return `--env="${key}=${value}"`;
+}
+
+/** grep -E / sed -E 공용: 해당 키의 `--env=` 라인(따옴표 유무 무관) */
+export function serviceEnvKeyRegex(key: string): string {
+  return `--env=["']?${key}=`;
+}
@@ -6,27 +6,57 @@ import * as path from 'path';
 
 import {
   DEFAULT_BATCH_SIZE,
+  DEFAULT_MEMORY_MODE_THRESHOLD,
   LOG_WINDOW_SIZE,
   MERGED_CHUNK_MAX_LINES,
   MERGED_DIR_NAME,
   MERGED_MANIFEST_FILENAME,
+  REALTIME_FLUSH_INTERVAL_MS,
+  REALTIME_FLUSH_MAX_LINES,
+  REALTIME_MAX_BYTES_DEFAULT,
+  REALTIME_MAX_CHUNKS_DEFAULT,
+  REALTIME_TAIL_LINES_DEFAULT,
+  REALTIME_TAIL_LINES_MAX,
 } from '../../shared/const.js';
-import { ErrorCategory, XError } from '../../shared/errors.js';
+import { ErrorCategory, notConnectedError, XError } from '../../shared/errors.js';
 import type { ParserConfig } from '../config/schema.js';
+import { adbShell, adbStream, assertAdbDevice } from '../connection/adbClient.js';
 import { connectionManager } from '../connection/ConnectionManager.js';
 import { getLogger } from '../logging/extension-logger.js';
 import { measure } from '../logging/perf.js';
 import { ChunkWriter } from '../logs/ChunkWriter.js';
 import { HybridLogBuffer } from '../logs/HybridLogBuffer.js';
+import { type LogCaptureOptions, LogCaptureWriter } from '../logs/LogCaptureWriter.js';
+import { parseLogcatLine } from '../logs/LogcatParser.js';
 import {
   compileWhitelistPathRegexes,
+  countLinesInFile,
   countTotalLinesInDir,
   mergeDirectory,
+  readSingleLogFile,
+  type SingleFileResult,
   warmupTailPrepass,
 } from '../logs/LogFileIntegration.js';
 import { ManifestWriter } from '../logs/ManifestWriter.js';
-import { paginationService } from '../logs/PaginationService.js';
-import { compileParserConfig } from '../logs/ParserEngine.js';
+import { PaginationService, paginationService } from '../logs/PaginationService.js';
+import {
+  appendContinuationLines,
+  compileParserConfig,
+  isContinuationLine,
+} from '../logs/ParserEngine.js';
+import {
+  composeSourceChain,
+  journalctlSource,
+  type JournalWindow,
+  journalWindowError,
+  matchesLineFilter,
+  parseLineFilter,
+  readSourceMark,
+  type RealtimeSource,
+  remoteFileTestCommand,
+  tailFileSource,
+} from '../logs/RealtimeSource.js';
+import { RepeatCoalescer } from '../logs/RepeatCoalescer.js';
 
 export type SessionCallbacks = {
   onBatch: (logs: LogEntry[], total?: number, seq?: number) => void;
@@ -47,6 +77,8 @@ export type SessionCallbacks = {
     active?: boolean;
     reset?: boolean;
   }) => void;
+  /** 병합 진행률(0~100, 1% 단위 변화 시) — 알림/상태바 등 간단한 렌더링용 */
+  onPercent?: (percent: number) => void;
   /** 병합 단계 텍스트/상태 */
   onStage?: (text: string, kind?: 'start' | 'done' | 'info') => void;
   /** 정식 병합(T1) 완료 후 하드리프레시 지시 */
@@ -59,6 +91,8 @@ export class LogSessionManager {
   private seq = 0;
   private rtAbort?: AbortController;
   private rtFlushTimer?: NodeJS.Timeout;
+  // 파일 병합 세션 취소(stopAll 또는 외부 signal)
+  private mergeAbort?: AbortController;
 
   // 진행률 스로틀 관련
   private lastProgressUpdate = 0;
@@ -66,7 +100,8 @@ export class LogSessionManager {
   private readonly PROGRESS_THROTTLE_MS = 250; // 250ms 간격
   private readonly PROGRESS_PERCENT_THRESHOLD = 1; // 1% 변화
 
-  constructor() {}
+  /** pages: 이 세션이 채울 페이지 서비스(뷰어 패널마다 하나, 기본은 공용 싱글톤) */
+  constructor(private pages: PaginationService = paginationService) {}
 
   // 진행률 스로틀 메서드
   private throttledOnProgress(
@@ -84,6 +119,9 @@ export class LogSessionManager {
       now - this.lastProgressUpdate > this.PROGRESS_THROTTLE_MS ||
       !current.active
     ) {
+      if (current.total && newPercent !== this.lastProgressPercent) {
+        opts.onPercent?.(Math.min(100, newPercent));
+      }
       this.lastProgressPercent = newPercent;
       this.lastProgressUpdate = now;
       opts.onProgress?.(current);
@@ -103,22 +141,158 @@ export class LogSessionManager {
     }
   }
 
+  /**
+   * 실시간 스트리밍 명령 구성
+   * - 연결 타입별 후보 소스(logcat / journalctl / docker)를 `command -v`로 검증
+   * - 없는 소스는 건너뛰고 onStage로 알림, 전부 없으면 명확한 에러
+   * - 검증 자체가 실패하면(권한/셸 문제 등) 기존 명령 그대로 시도
+   */
+  private async buildRealtimeCommand(
+    type: string | undefined,
+    opts: {
+      tailLines?: number;
+      tailFile?: string;
+      device?: string;
+      window?: JournalWindow;
+    } & SessionCallbacks,
+  ): Promise<{ cmd: string; label: string }> {
+    const tail = Math.min(
+      REALTIME_TAIL_LINES_MAX,
+      Math.max(0, Math.floor(Number(opts.tailLines ?? REALTIME_TAIL_LINES_DEFAULT) || 0)),
+    );
+    // 시간 구간 조회(--since/--until)는 journalctl 전용 — docker 폴백/logcat/파일 tail과는 섞지 않는다
+    const window = opts.window?.since || opts.window?.until ? opts.window : undefined;
+    if (window) {
+      const bad = journalWindowError(window);
+      if (bad) throw new XError(ErrorCategory.Unknown, bad);
+      if (opts.tailFile || opts.device || type === 'ADB') {
+        throw new XError(
+          ErrorCategory.Unknown,
+          '--since/--until은 SSH 연결의 journalctl 로그에서만 사용할 수 있습니다',
+        );
+      }
+      const avail = await connectionManager.checkCommands(['journalctl']).catch(() => undefined);
+      if (avail && !avail.journalctl) {
+        throw new XError(ErrorCategory.ToolMissing, '기기에서 journalctl 명령을 찾을 수 없습니다');
+      }
+      const src = journalctlSource(0, window);
+      return { cmd: src.cmd, label: src.name };
+    }
+    // 사용자가 지정한 원격 파일 하나만 따라감(logging --tail <경로>) — 존재 먼저 확인
+    if (opts.tailFile) {
+      const test = remoteFileTestCommand(opts.tailFile);
+      const { code } = opts.device
+        ? await adbShell(test, { serial: opts.device })
+        : await connectionManager.run(test);
+      if (code !== 0) {
+        throw new XError(ErrorCategory.Path, `원격 파일을 찾을 수 없습니다: ${opts.tailFile}`);
+      }
+      const src = tailFileSource(opts.tailFile, tail);
+      return { cmd: src.cmd, label: src.name };
+    }
+    // tail=0이면 기존과 동일하게 연결 시점부터, N>0이면 직전 N줄을 먼저 흘려보낸 뒤 follow
+    const sources: RealtimeSource[] =
+      type === 'ADB'
+        ? [
+            {
+              name: 'logcat',
+              cmd: tail > 0 ? `logcat -v threadtime -T ${tail}` : `logcat -v threadtime`,
+            },
+          ]
+        : [
+            journalctlSource(tail),
+            {
+              name: 'docker',
+              cmd: `docker ps --format "{{.Names}}" | awk "/homey/{print}" | xargs -r -n1 docker logs -f ${
+                tail > 0 ? `--tail ${tail}` : `--since 0s`
+              }`,
+            },
+          ];
+
+    // 지정 기기(--device)는 현재 연결로 검사할 수 없고, logcat은 Android 기본 명령이라 바로 사용
+    if (opts.device) return { cmd: sources[0].cmd, label: sources[0].name };
+
+    let valid = sources;
+    try {
+      const avail = await connectionManager.checkCommands(sources.map((s) => s.name));
+      valid = sources.filter((s) => avail[s.name]);
+      const missing = sources.filter((s) => !avail[s.name]).map((s) => s.name);
+      if (missing.length) {
+        this.log.warn(`realtime: skip unavailable log source(s): ${missing.join(', ')}`);
+        if (valid.length) {
+          opts.onStage?.(`로그 소스 없음(건너뜀): ${missing.join(', ')}`, 'info');
+        }
+      }
+    } catch (e) {
+      this.log.warn(`realtime: log source check failed; trying all sources (${String(e)})`);
+    }
+    if (!valid.length) {
+      throw new XError(
+        ErrorCategory.ToolMissing,
+        `사용 가능한 로그 소스가 없습니다: ${sources.map((s) => s.name).join(', ')} 명령을 기기에서 찾을 수 없습니다.`,
+      );
+    }
+
+    // label: 뷰어 src 컬럼에 표시할 소스 이름(SSH 폴백 체인은 마커 라인으로 갱신)
+    if (type === 'ADB') return { cmd: valid[0].cmd, label: valid[0].name };
+    return { cmd: composeSourceChain(valid), label: valid[0].name };
+  }
+
   @measure()
   async startRealtimeSession(
-    opts: { signal?: AbortSignal; filter?: string; indexOutDir?: string } & SessionCallbacks,
+    opts: {
+      signal?: AbortSignal;
+      filter?: string;
+      indexOutDir?: string;
+      /** 배치 flush 최소 간격(ms, 기본 REALTIME_FLUSH_INTERVAL_MS) */
+      flushIntervalMs?: number;
+      /** 배치 최대 라인 수(기본 REALTIME_FLUSH_MAX_LINES) */
+      flushMaxLines?: number;
+      /** 시작 시 함께 전달할 직전 로그 라인 수(기본 REALTIME_TAIL_LINES_DEFAULT=0) */
+      tailLines?: number;
+      /** 청크 보관 개수 상한(기본 REALTIME_MAX_CHUNKS_DEFAULT, 0 = 무제한) */
+      maxChunks?: number;
+      /** 청크 총 용량 상한(bytes, 기본 REALTIME_MAX_BYTES_DEFAULT, 0 = 무제한) */
+      maxBytes?: number;
+      /** 이전 세션 로그 보존: 같은 디렉터리에 이어서 기록하고, 시작 시 기존 로그를 바로 표시 */
+      preserve?: boolean;
+      /** 설정된 소스 대신 이 원격 파일을 `tail -f`로 따라감 */
+      tailFile?: string;
+      /** 연속 중복 라인 합치기(반복 횟수만 표시하고 `last message repeated N times`로 기록) */
+      coalesce?: boolean;
+      /** 현재 연결 대신 이 ADB 기기(serial)에서 스트리밍 — 현재 연결은 바꾸지 않음 */
+      device?: string;
+      /** 뷰어와 함께 flush되는 라인을 로컬 파일에도 덧붙임(raw/jsonl, 크기 회전) */
+      capture?: LogCaptureOptions;
+      /** journalctl 시간 구간(과거 로그 조회) — until이 있으면 구간 끝에서 세션이 끝난다 */
+      window?: JournalWindow;
+    } & SessionCallbacks,
   ) {
     this.log.info('realtime: start (file-backed + pagination)');
-    // 활성 연결 확보(없으면 recent 로더로 자동 시도)
-    await connectionManager.connect();
-    if (!connectionManager.isConnected()) {
-      throw new XError(
-        ErrorCategory.Connection,
-        '활성 연결이 없습니다. 먼저 "기기 연결"을 수행하세요.',
-      );
+    let connType: string | undefined = 'ADB';
+    if (opts.device) {
+      // 지정 기기: `adb devices`에 올라와 있는지만 확인(활성 연결과 무관)
+      await assertAdbDevice(opts.device);
+      this.log.info(`realtime: device=${opts.device} (current connection untouched)`);
+    } else {
+      // 활성 연결 확보(없으면 recent 로더로 자동 시도)
+      await connectionManager.connect();
+      if (!connectionManager.isConnected()) {
+        throw notConnectedError();
+      }
+      connType = connectionManager.getSnapshot().active?.type;
     }
-    const snap = connectionManager.getSnapshot();
-    const active = snap.active;
-    const sourceType = active?.type ?? 'unknown';
+    const sourceType = connType ?? 'unknown';
+
+    // ── 로그 소스 검증: 없는 명령으로 죽은 스트림을 띄우지 않도록 사전 확인 ──
+    const { cmd, label } = await this.buildRealtimeCommand(connType, opts);
+    let srcLabel = label;
+    const lineFilter = parseLineFilter(opts.filter);
+    if (lineFilter) this.log.info(`realtime: filter ${JSON.stringify(lineFilter)}`);
+    let filteredOut = false;
+    const coalescer = opts.coalesce ? new RepeatCoalescer() : undefined;
+    // 반복 횟수만 바뀐 경우(새 라인 없음)에도 flush로 뷰어를 갱신
+    let repeatDirty = false;
 
     this.rtAbort = new AbortController();
     if (opts.signal) opts.signal.addEventListener('abort', () => this.rtAbort?.abort());
@@ -126,52 +300,114 @@ export class LogSessionManager {
     // ── 출력 디렉터리(실시간) 준비 ────────────────────────────────────────
     // - caller가 indexOutDir을 준 경우 우선
     // - 그 외에는 OS temp 하위에 <MERGED_DIR_NAME>-rt-<pid> 고정 사용
+    //   (--device 세션은 나란히 뜬 다른 뷰어와 겹치지 않게 -<serial>을 붙임)
+    // - preserve: 프로세스와 무관한 기기별 고정 디렉터리를 비우지 않고 그대로 이어 씀(크래시 후 재확인용)
+    const devSuffix = opts.device ? `-${opts.device.replace(/[^\w.-]+/g, '_')}` : '';
     const baseOut =
-      opts.indexOutDir || path.join(os.tmpdir(), `${MERGED_DIR_NAME}-rt-${process.pid}`);
-    const outDir = await this.prepareCleanOutputDir(baseOut);
-    this.log.info(`realtime: outDir=${outDir}`);
+      opts.indexOutDir ||
+      path.join(
+        os.tmpdir(),
+        opts.preserve
+          ? `${MERGED_DIR_NAME}-rt-${realtimePreserveKey(opts.device)}`
+          : `${MERGED_DIR_NAME}-rt-${process.pid}${devSuffix}`,
+      );
+    const outDir = opts.preserve ? baseOut : await this.prepareCleanOutputDir(baseOut);
+    this.log.info(`realtime: outDir=${outDir}${opts.preserve ? ' (preserve)' : ''}`);
+    const capture = opts.capture ? await LogCaptureWriter.open(opts.capture) : undefined;
+    if (capture) this.log.info(`realtime: capture → ${capture.file} (${capture.format})`);
 
-    // manifest / chunk writer
+    // manifest / chunk writer (깨진 manifest는 loadOrCreate가 청크로부터 재구성)
     const manifest = await ManifestWriter.loadOrCreate(outDir);
+    // 실시간 청크는 append 순서(과거→최신) — 보존된 이전 manifest에도 표시해 같은 idx로 다시 연다
+    manifest.setOrder('asc');
+    if (opts.preserve) {
+      await manifest.adoptOrphanChunks();
+      await manifest.save();
+    }
     const chunkWriter = new ChunkWriter(outDir, MERGED_CHUNK_MAX_LINES, manifest.data.chunkCount);
     let mergedSoFar = manifest.data.mergedLines ?? 0;
+    // 청크 버퍼 포함 지금까지 넘긴 라인 수(= 다음 엔트리의 전역 인덱스 - 1)
+    let appendedSoFar = mergedSoFar;
     let paginationOpened = false;
+    // 보존된 이전 로그가 있으면 새 라인을 기다리지 않고 바로 뷰어에 연다
+    if (opts.preserve && mergedSoFar > 0) {
+      try {
+        await this.pages.setManifestDir(outDir);
+        paginationOpened = true;
+        this.log.info(`realtime: resumed ${mergedSoFar} preserved line(s)`);
+        opts.onRefresh?.({ total: mergedSoFar, version: this.pages.getVersion() });
+        const startIdx = Math.max(1, mergedSoFar - LOG_WINDOW_SIZE + 1);
+        const page = await this.pages.readRangeByIdx(startIdx, mergedSoFar);
+        if (page.length) opts.onBatch(page, mergedSoFar, ++this.seq);
+      } catch (e) {
+        this.log.warn(`realtime: failed to open preserved logs: ${String(e)}`);
+      }
+    }
+    const retention = {
+      maxChunks: opts.maxChunks ?? REALTIME_MAX_CHUNKS_DEFAULT,
+      maxBytes: opts.maxBytes ?? REALTIME_MAX_BYTES_DEFAULT,
+    };
 
     // flush 코얼레서
-    const PULSE_MS = 250;
+    const PULSE_MS = Math.max(0, opts.flushIntervalMs ?? REALTIME_FLUSH_INTERVAL_MS);
+    const BURST_FLUSH_LINES = Math.max(1, opts.flushMaxLines ?? REALTIME_FLUSH_MAX_LINES);
     let pending: LogEntry[] = [];
+    let lastFlushAt = 0;
+    let lastLineAt = 0;
     const doFlush = async (reason: string) => {
-      if (!pending.length) return;
-      const batch = pending;
-      pending = [];
+      // 막 들어온 마지막 엔트리는 뒤따를 연속 라인(스택트레이스)이 붙도록 다음 flush로 넘긴다
+      const recent = Date.now() - lastLineAt < PULSE_MS;
+      const hold = reason !== 'final' && pending.length && recent ? 1 : 0;
+      if (pending.length <= hold && !repeatDirty) {
+        if (hold) lastFlushAt = Date.now(); // 다음 펄스는 PULSE_MS 뒤에
+        return;
+      }
+      const batch = pending.splice(0, pending.length - hold);
+      repeatDirty = false;
+      lastFlushAt = Date.now();
+
+      // 0) 전역 인덱스/ID 부여 — 메모리·청크 파일·리로드 어디서 읽어도 같은 값
+      for (let i = 0; i < batch.length; i++) batch[i].id = batch[i].idx = appendedSoFar + i + 1;
 
-      // 1) 메모리 메트릭
+      // 1) 메모리 메트릭 (+ 로컬 캡처는 뒤에서 순서대로 기록)
       this.hb.addBatch(batch);
+      void capture?.append(batch);
 
       // 2) 디스크 청크 append + manifest 스냅샷
-      const parts = await chunkWriter.appendBatch(batch);
+      const parts = batch.length ? await chunkWriter.appendBatch(batch) : [];
+      coalescer?.noteFlushed(batch, appendedSoFar);
+      appendedSoFar += batch.length;
       for (const p of parts) {
-        manifest.addChunk(p.file, p.lines, mergedSoFar);
+        manifest.addChunk(p.file, p.lines, mergedSoFar, p.bytes);
         mergedSoFar += p.lines;
       }
-      manifest.setTotal(mergedSoFar);
-      await manifest.save();
+      // 보관 정책 초과분(가장 오래된 part) 정리 — 전역 인덱스는 유지되어 앞쪽 구간만 비게 됨
+      if (parts.length) {
+        const removed = await manifest.rotate(retention);
+        if (removed.length) {
+          this.log.debug?.(`realtime: rotated out ${removed.length} chunk(s)`);
+        }
+      }
+      if (batch.length) {
+        manifest.setTotal(mergedSoFar);
+        await manifest.save();
+      }
 
       // 3) 페이지네이션 오픈/리로드
       try {
         if (!paginationOpened) {
-          await paginationService.setManifestDir(outDir);
+          await this.pages.setManifestDir(outDir);
           paginationOpened = true;
           // ✅ 파일기반 세션 버전을 웹뷰에 전달(웹뷰가 페이지 요청을 바로 시작하도록)
           try {
             opts.onRefresh?.({
               total: mergedSoFar,
-              version: paginationService.getVersion(),
+              version: this.pages.getVersion(),
             });
           } catch {}
         } else if (parts.length) {
           // 새 청크가 만들어진 경우에만 리로드(비용 절감)
-          await paginationService.reload();
+          await this.pages.reload();
         }
       } catch (e) {
         this.log.warn(`realtime: pagination prepare failed: ${String(e)}`);
@@ -182,9 +418,9 @@ export class LogSessionManager {
         const total = mergedSoFar;
         const endIdx = Math.max(1, total);
         const startIdx = Math.max(1, endIdx - LOG_WINDOW_SIZE + 1);
-        const page = await paginationService.readRangeByIdx(startIdx, endIdx);
+        const page = await this.pages.readRangeByIdx(startIdx, endIdx);
         if (page.length) {
-          opts.onBatch(page, total, ++this.seq);
+          opts.onBatch(coalescer ? coalescer.annotate(page) : page, total, ++this.seq);
         }
       } catch (e) {
         this.log.warn(`realtime: failed to deliver last page: ${String(e)}`);
@@ -198,60 +434,122 @@ export class LogSessionManager {
       this.log.debug?.(`realtime.flush[${reason}] batch=${batch.length} total=${mergedSoFar}`);
     };
 
+    // flush 직렬화: 펄스/버스트/최종 flush가 겹쳐도 청크·manifest·카운터 순서를 보장
+    let flushChain: Promise<void> = Promise.resolve();
+    const flush = (reason: string) => {
+      const next = flushChain.then(() => doFlush(reason));
+      flushChain = next.catch((e) => this.log.warn(`realtime: flush[${reason}] failed: ${e}`));
+      return next;
+    };
+    // 버스트 시 pending이 무한히 쌓이지 않도록 BURST_FLUSH_LINES에서 즉시 flush(중복 예약 방지)
+    let burstQueued = false;
+
     const schedulePulse = () => {
       if (this.rtFlushTimer) return;
+      // 한산한 로그는 직전 flush 이후 간격이 지났으면 바로, 버스트는 PULSE_MS 단위로 묶는다.
+      const delay = Math.max(0, PULSE_MS - (Date.now() - lastFlushAt));
       this.rtFlushTimer = setTimeout(async () => {
         this.rtFlushTimer = undefined;
         try {
-          await doFlush('pulse');
+          await flush('pulse');
+        } catch {
+          // flushChain에서 로깅됨
         } finally {
           // 지속적으로 입력이 올 수 있으므로 다음 펄스는 필요 시 다시 예약
           if (pending.length) schedulePulse();
         }
-      }, PULSE_MS);
+      }, delay);
     };
 
-    const cmd =
-      active?.type === 'ADB'
-        ? `logcat -v time`
-        : `sh -lc 'journalctl -f -o short-iso -n 0 -u "homey*" 2>/dev/null || docker ps --format "{{.Names}}" | awk "/homey/{print}" | xargs -r -n1 docker logs -f --since 0s'`;
-
     this.log.debug?.(`realtime: streaming cmd="${cmd}"`);
-    await connectionManager.stream(
+    const device = opts.device;
+    const stream = device
+      ? (c: string, onLine: (line: string) => void, abort: AbortSignal) =>
+          adbStream(c, { serial: device, signal: abort }, onLine)
+      : (c: string, onLine: (line: string) => void, abort: AbortSignal) =>
+          connectionManager.stream(c, onLine, abort);
+    await stream(
       cmd,
       (line: string) => {
-        // 실시간은 "전체 라인"을 파일에 보존(필터는 PaginationService 경로에서 처리)
+        // 소스 마커: 폴백 체인에서 실제로 흐르는 소스로 라벨 전환(라인 자체는 저장 안 함)
+        const mark = readSourceMark(line);
+        if (mark) {
+          srcLabel = mark;
+          return;
+        }
+        lastLineAt = Date.now();
+        // 스택트레이스 연속 라인은 아직 flush 전인 직전 엔트리에 이어 붙임(직전이 걸러졌으면 함께 버림)
+        if (isContinuationLine(line) && (filteredOut || pending.length)) {
+          if (!filteredOut) appendContinuationLines(pending[pending.length - 1], [line]);
+          return;
+        }
+        // 빠른 필터(include AND / !exclude)는 저장 전에 적용
+        filteredOut = !matchesLineFilter(line, lineFilter);
+        if (filteredOut) return;
+        // 실시간은 "전체 라인"을 파일에 보존(뷰어 필터는 PaginationService 경로에서 처리)
         const e: LogEntry = {
           id: Date.now(),
           ts: Date.now(),
           level: 'I',
           type: 'system',
           source: sourceType,
+          file: srcLabel,
           text: line,
         };
+        // ADB logcat은 헤더(시간/PID/TID/레벨/태그)를 파싱해 레벨·시간·태그를 채움
+        const lc = srcLabel === 'logcat' ? parseLogcatLine(line) : undefined;
+        if (lc) {
+          e.ts = lc.ts;
+          e.level = lc.level;
+          e.pid = lc.pid;
+          e.process = lc.process;
+          e.parsed = { time: lc.time, process: lc.process, pid: lc.pid, message: lc.message };
+        }
+        // 중복 합치기: 직전 반복에 흡수되면 저장하지 않음(연속 라인도 함께 버림)
+        if (coalescer) {
+          const { absorbed, summary } = coalescer.offer(e);
+          if (absorbed) {
+            filteredOut = true;
+            if (coalescer.pendingRepeat) {
+              repeatDirty = true;
+              schedulePulse();
+            }
+            return;
+          }
+          if (summary) pending.push(summary);
+        }
         pending.push(e);
+        if (pending.length >= BURST_FLUSH_LINES && !burstQueued) {
+          burstQueued = true;
+          void flush('burst')
+            .catch(() => {})
+            .finally(() => (burstQueued = false));
+          return;
+        }
         // 첫 라인이 들어오면 즉시 펄스 예약(뭉텅이로 처리)
         schedulePulse();
       },
       this.rtAbort.signal,
     );
 
-    // 스트림 종료 시 잔여 플러시
+    // 스트림 종료 시 잔여 플러시(진행 중인 펄스 flush 뒤에 이어서 실행)
     try {
-      await doFlush('final');
+      const lastRepeat = coalescer?.end();
+      if (lastRepeat) pending.push(lastRepeat);
+      await flush('final');
       const rem = await chunkWriter.flushRemainder();
       if (rem) {
-        manifest.addChunk(rem.file, rem.lines, mergedSoFar);
+        manifest.addChunk(rem.file, rem.lines, mergedSoFar, rem.bytes);
         mergedSoFar += rem.lines;
         manifest.setTotal(mergedSoFar);
         await manifest.save();
-        await paginationService.reload();
+        await this.pages.reload();
       }
       // 마지막 페이지 재전송(세션 종료 전 정합)
       const total = mergedSoFar;
       const endIdx = Math.max(1, total);
       const startIdx = Math.max(1, endIdx - LOG_WINDOW_SIZE + 1);
-      const tail = await paginationService.readRangeByIdx(startIdx, endIdx);
+      const tail = await this.pages.readRangeByIdx(startIdx, endIdx);
       if (tail.length) opts.onBatch(tail, total, ++this.seq);
     } catch (e) {
       this.log.warn(`realtime: final flush failed: ${String(e)}`);
@@ -261,6 +559,7 @@ export class LogSessionManager {
         clearTimeout(this.rtFlushTimer);
         this.rtFlushTimer = undefined;
       }
+      await capture?.close();
     }
   }
 
@@ -281,6 +580,15 @@ export class LogSessionManager {
     } & SessionCallbacks,
   ) {
     this.log.info(`[debug] LogSessionManager.startFileMergeSession: start dir=${opts.dir}`);
+    // 외부 signal과 stopAll()을 하나의 병합 전용 signal로 묶는다.
+    this.mergeAbort?.abort();
+    const mergeAbort = new AbortController();
+    this.mergeAbort = mergeAbort;
+    if (opts.signal) {
+      if (opts.signal.aborted) mergeAbort.abort();
+      else opts.signal.addEventListener('abort', () => mergeAbort.abort(), { once: true });
+    }
+    const signal = mergeAbort.signal;
     let seq = 0;
     // 진행 누적(세션 로컬)
     let progressDone = 0;
@@ -288,14 +596,7 @@ export class LogSessionManager {
     opts.onStage?.('병합 세션 시작', 'info');
 
     // 파서 설정에서 conservative 메모리 모드 문턱 추출
-    const configuredThreshold = Number(
-      (opts.parserConfig as any)?.configure?.memory_mode_threshold,
-    );
-    const DEFAULT_THRESHOLD = 10_000;
-    const threshold =
-      Number.isFinite(configuredThreshold) && configuredThreshold > 0
-        ? configuredThreshold
-        : DEFAULT_THRESHOLD;
+    const threshold = memoryModeThreshold(opts.parserConfig);
 
     // 테스트 오버라이드(있으면)
     const warmupEnabled =
@@ -317,7 +618,7 @@ export class LogSessionManager {
       try {
         const warm = await warmupTailPrepass({
           dir: opts.dir,
-          signal: opts.signal,
+          signal,
           // 보수적 기준의 정확도를 높이기 위해 per-type cap 제거(무한)
           warmupPerTypeLimit: perTypeLimit,
           // 메모리 모드 문턱만큼만 웜업하여 UI 최초 화면 품질을 맞춤
@@ -328,13 +629,13 @@ export class LogSessionManager {
         const warmLogs = warm.logs;
         if (warmLogs.length) {
           // 메모리/웹뷰 준비
-          paginationService.seedWarmupBuffer(warmLogs, warmLogs.length);
+          this.pages.seedWarmupBuffer(warmLogs, warmLogs.length);
           this.hb.addBatch(warmLogs);
           // ✅ 초기 전달: "마지막 페이지(최신 영역)"을 오름차순으로 보냄
           const totalWarm = warmLogs.length;
           const endIdx = totalWarm;
           const startIdx = Math.max(1, endIdx - LOG_WINDOW_SIZE + 1);
-          const lastPage = await paginationService.readRangeByIdx(startIdx, endIdx);
+          const lastPage = await this.pages.readRangeByIdx(startIdx, endIdx);
           if (lastPage.length) {
             this.log.info(
               `warmup(T0): deliver last-page ${startIdx}-${endIdx} (${lastPage.length}/${totalWarm})`,
@@ -388,7 +689,7 @@ export class LogSessionManager {
     // manifest / chunk writer 준비
     const manifest = await ManifestWriter.loadOrCreate(outDir);
     // ⬇️ 빈 데이터셋이어도 manifest.json이 존재하도록 선 저장
-    //    - 이후 paginationService.setManifestDir(outDir)에서 ENOENT 방지
+    //    - 이후 this.pages.setManifestDir(outDir)에서 ENOENT 방지
     manifest.setTotal(typeof total === 'number' ? total : 0);
     await manifest.save();
     const chunkWriter = new ChunkWriter(outDir, MERGED_CHUNK_MAX_LINES, manifest.data.chunkCount);
@@ -414,10 +715,12 @@ export class LogSessionManager {
 
     let skippedToMemory = false;
 
+    if (signal.aborted) return this.finishCancelledMerge(opts, outDir);
+
     await mergeDirectory({
       dir: opts.dir,
       reverse: false,
-      signal: opts.signal,
+      signal,
       batchSize: DEFAULT_BATCH_SIZE,
       mergedDirPath: jsonlDir,
       // RAW 기록은 플래그가 true일 때만 활성화
@@ -447,8 +750,8 @@ export class LogSessionManager {
       // 스킵 경로에서도 warm 버퍼가 보장되도록(테스트/설정에 따라 T0가 비활성일 수 있음)
       onWarmupBatch: (logs) => {
         try {
-          if (!paginationService.isWarmupActive() && logs?.length) {
-            paginationService.seedWarmupBuffer(logs, logs.length);
+          if (!this.pages.isWarmupActive() && logs?.length) {
+            this.pages.seedWarmupBuffer(logs, logs.length);
           }
         } catch {}
       },
@@ -456,11 +759,11 @@ export class LogSessionManager {
       onFinalize: (r) => {
         if (r.mode === 'memory') {
           skippedToMemory = true;
-          const totalMem = r.total ?? paginationService.getWarmTotal();
+          const totalMem = r.total ?? this.pages.getWarmTotal();
           opts.onProgress?.({ done: totalMem, total: totalMem, active: false });
           opts.onRefresh?.({
             total: totalMem,
-            version: paginationService.getVersion(),
+            version: this.pages.getVersion(),
             warm: true,
           });
         }
@@ -470,14 +773,14 @@ export class LogSessionManager {
         this.hb.addBatch(logs);
 
         // 2) 최초 LOG_WINDOW_SIZE줄만 UI에 전달 (그 이후는 전달 금지)
-        if (!sentInitial && !paginationService.isWarmupActive()) {
+        if (!sentInitial && !this.pages.isWarmupActive()) {
           initialBuffer.push(...logs);
           if (initialBuffer.length >= LOG_WINDOW_SIZE) {
             // 최신부터 쌓인 버퍼이므로, 오름차순 표시를 위해 뒤집어서 보냄
             const slice = initialBuffer.slice(0, LOG_WINDOW_SIZE).slice().reverse();
-            const t = paginationService.isWarmupActive() ? paginationService.getWarmTotal() : total;
+            const t = this.pages.isWarmupActive() ? this.pages.getWarmTotal() : total;
             this.log.info(
-              `T1: initial deliver(len=${slice.length}) total=${t ?? 'unknown'} (warm=${paginationService.isWarmupActive()}, window=${LOG_WINDOW_SIZE})`,
+              `T1: initial deliver(len=${slice.length}) total=${t ?? 'unknown'} (warm=${this.pages.isWarmupActive()}, window=${LOG_WINDOW_SIZE})`,
             );
             // 워밍업이 이미 초기 500을 보냈다면 보통 여긴 실행되지 않지만,
             // 안전하게 가드 없이도 동일 total로 동작하도록 유지
@@ -489,7 +792,7 @@ export class LogSessionManager {
         // 3) 청크 파일 쓰기
         const createdParts = await chunkWriter.appendBatch(logs);
         for (const p of createdParts) {
-          manifest.addChunk(p.file, p.lines, mergedSoFar);
+          manifest.addChunk(p.file, p.lines, mergedSoFar, p.bytes);
           mergedSoFar += p.lines;
         }
         // 4) manifest 스냅샷
@@ -498,7 +801,7 @@ export class LogSessionManager {
         // 4-1) T0: 첫 청크 생성/manifest 저장 직후, Pagination을 즉시 오픈해 스크롤 요청 가능하게 함
         if (!paginationOpened && manifest.data.chunkCount > 0) {
           try {
-            await paginationService.setManifestDir(outDir);
+            await this.pages.setManifestDir(outDir);
           } catch (e) {
             this.log.warn(`T1: early pagination open failed: ${String(e)}`);
           }
@@ -526,6 +829,9 @@ export class LogSessionManager {
         });
       },
     });
+    // 취소된 병합은 절반짜리 결과를 저장/표시하지 않는다.
+    if (signal.aborted) return this.finishCancelledMerge(opts, outDir);
+
     // mergeDirectory에서 메모리 모드 스킵으로 종료된 경우, 파일 기반 후처리를 건너뛴다.
     if (skippedToMemory) {
       this.log.info(
@@ -537,7 +843,7 @@ export class LogSessionManager {
     // 남은 버퍼 플러시
     const remainder = await chunkWriter.flushRemainder();
     if (remainder) {
-      manifest.addChunk(remainder.file, remainder.lines, mergedSoFar);
+      manifest.addChunk(remainder.file, remainder.lines, mergedSoFar, remainder.bytes);
       mergedSoFar += remainder.lines;
       this.log.debug?.(`T1: remainder flushed lines=${remainder.lines}`);
       await manifest.save();
@@ -549,15 +855,15 @@ export class LogSessionManager {
     try {
       // (앞서 part 생성이 없어 아직 열지 못했다면 여기서 1회 오픈)
       if (!paginationOpened) {
-        await paginationService.setManifestDir(outDir);
+        await this.pages.setManifestDir(outDir);
         paginationOpened = true;
         // ※ 일부 환경에서 setManifestDir만 호출되고 reload가 누락되면
         //    서비스가 계속 'warm' 모드에 머물러 페이징이 꼬일 수 있다.
         //    (로그에서 관찰된 현상: out-of-range 요청이 항상 워밍업 꼬리로 clamp)
         //    따라서 최종 완료 시점에는 무조건 reload를 수행해 파일 기반으로 전환한다.
-        await paginationService.reload();
+        await this.pages.reload();
       } else {
-        await paginationService.reload();
+        await this.pages.reload();
       }
     } catch (e) {
       this.log.warn(`T1: pagination finalize failed (possibly empty dataset): ${String(e)}`);
@@ -566,7 +872,7 @@ export class LogSessionManager {
       `T1: pagination ready dir=${outDir} total=${manifest.data.totalLines ?? 'unknown'} merged=${manifest.data.mergedLines}`,
     );
     // 파일 기반으로 스위치되면 워밍업 버퍼는 내부적으로 clear됨(reload에서 처리)
-    if (!paginationService.isWarmupActive()) {
+    if (!this.pages.isWarmupActive()) {
       this.log.info(`T1: switched to file-backed pagination (warm buffer cleared)`);
     }
     // 파일 기반 최신 head 재전송(정렬/보정 최종 결과로 UI 정합 맞춤)
@@ -575,7 +881,7 @@ export class LogSessionManager {
       const totalLines = manifest.data.mergedLines ?? manifest.data.totalLines ?? total ?? 0;
       const endIdx = Math.max(1, totalLines);
       const startIdx = Math.max(1, endIdx - LOG_WINDOW_SIZE + 1);
-      const freshTail = await paginationService.readRangeByIdx(startIdx, endIdx);
+      const freshTail = await this.pages.readRangeByIdx(startIdx, endIdx);
       if (freshTail.length) {
         this.log.info(
           `T1: deliver refreshed last-page ${startIdx}-${endIdx} (${freshTail.length}) (file-backed, window=${LOG_WINDOW_SIZE})`,
@@ -607,11 +913,73 @@ export class LogSessionManager {
     opts.onRefresh?.({
       // total은 mergedLines로 고정 (UI 스크롤/점프 총량 일치)
       total: manifest.data.mergedLines ?? manifest.data.totalLines,
-      version: paginationService.getVersion(),
+      version: this.pages.getVersion(),
     });
     this.log.info(`[debug] LogSessionManager.startFileMergeSession: end`);
   }
 
+  /**
+   * 단일 파일 보기: 한 파일만 읽어 메모리(warm) 버퍼로 띄운다.
+   * 타입 그룹/타임존 보정/정식 병합(T1)은 여러 회전 파일용이므로 건너뛴다.
+   * 메모리 모드 문턱을 넘는 파일은 디렉터리 병합과 같은 웜업 + 디스크 페이징 경로로 연다.
+   */
+  @measure()
+  async startSingleFileSession(
+    opts: {
+      filePath: string;
+      parserConfig?: ParserConfig;
+      indexOutDir?: string;
+    } & SessionCallbacks,
+  ): Promise<SingleFileResult> {
+    this.log.info(`[debug] LogSessionManager.startSingleFileSession: start file=${opts.filePath}`);
+    const threshold = memoryModeThreshold(opts.parserConfig);
+    const lines = await countLinesInFile(opts.filePath).catch(() => 0);
+    if (lines > threshold) {
+      this.log.info(`single-file: ${lines} lines > threshold=${threshold} — paged merge path`);
+      opts.onStage?.(`대용량 파일(${lines}줄): 페이징 모드로 엽니다`, 'info');
+      let total = 0;
+      // 같은 폴더의 이 파일 하나만(화이트리스트는 베이스네임 정확 일치, 비재귀)
+      await this.startFileMergeSession({
+        ...opts,
+        dir: path.dirname(opts.filePath),
+        whitelistGlobs: [path.basename(opts.filePath)],
+        onRefresh: (info) => {
+          total = info.total ?? total;
+          opts.onRefresh?.(info);
+        },
+      });
+      return { logs: [], parsed: total, skipped: 0, paged: true };
+    }
+    this.mergeAbort?.abort();
+    const mergeAbort = new AbortController();
+    this.mergeAbort = mergeAbort;
+    opts.onStage?.(`파일 읽는 중: ${path.basename(opts.filePath)}`, 'start');
+
+    const result = await readSingleLogFile(opts.filePath, {
+      parser: opts.parserConfig,
+      signal: mergeAbort.signal,
+    });
+    if (mergeAbort.signal.aborted) {
+      opts.onStage?.('파일 읽기 취소됨', 'done');
+      return result;
+    }
+
+    const total = result.logs.length;
+    this.pages.seedWarmupBuffer(result.logs, total);
+    this.hb.addBatch(result.logs);
+    if (total > 0) {
+      const startIdx = Math.max(1, total - LOG_WINDOW_SIZE + 1);
+      const lastPage = await this.pages.readRangeByIdx(startIdx, total);
+      opts.onBatch(lastPage, total, 1);
+    }
+    opts.onStage?.(`파일 읽기 완료: ${total}줄 (건너뜀 ${result.skipped})`, 'done');
+    opts.onRefresh?.({ total, version: this.pages.getVersion(), warm: true });
+    this.log.info(
+      `[debug] LogSessionManager.startSingleFileSession: end parsed=${total} skipped=${result.skipped}`,
+    );
+    return result;
+  }
+
   @measure()
   stopAll() {
     this.log.info('session: stopAll');
@@ -621,6 +989,24 @@ export class LogSessionManager {
       this.rtFlushTimer = undefined;
     }
     this.rtAbort?.abort();
+    this.mergeAbort?.abort();
+  }
+
+  /**
+   * 병합 취소 마무리: 진행바 닫기 + 단계 알림(onSaved/onRefresh는 보내지 않음).
+   * 부분 산출물(outDir)은 지워서 재시도가 처음부터 시작되도록 한다.
+   */
+  private async finishCancelledMerge(opts: SessionCallbacks, outDir?: string) {
+    this.log.warn('T1: file merge cancelled — discarding partial result');
+    this.hb.clear();
+    if (outDir) {
+      this.pages.detachManifestDir(outDir);
+      await fs.promises.rm(outDir, { recursive: true, force: true }).catch((e) => {
+        this.log.warn(`T1: failed to remove partial outDir=${outDir}: ${String(e)}`);
+      });
+    }
+    opts.onProgress?.({ done: 0, total: 0, active: false });
+    opts.onStage?.('파일 병합 취소됨', 'done');
   }
 
   @measure()
@@ -678,6 +1064,21 @@ export class LogSessionManager {
   }
 }
 
+/**
+ * 실시간 보존(preserve) 디렉터리 이름에 붙일 키 — --device면 serial, 아니면 활성 연결 id
+ * (재시작해도 같은 기기는 같은 디렉터리를 이어 쓰고, 다른 기기의 세션과는 겹치지 않는다)
+ */
+export function realtimePreserveKey(device?: string): string {
+  const id = device || connectionManager.getSnapshot()?.active?.id || 'default';
+  return String(id).replace(/[^\w.-]+/g, '_');
+}
+
+/** configure.memory_mode_threshold(양수) 또는 기본값 — 웜업 목표이자 단일 파일 메모리 모드 문턱 */
+function memoryModeThreshold(parserConfig?: ParserConfig): number {
+  const n = Number((parserConfig as any)?.configure?.memory_mode_threshold);
+  return Number.isFinite(n) && n > 0 ? n : DEFAULT_MEMORY_MODE_THRESHOLD;
+}
+
 // -------------------- tests only overrides (featureFlags 제거 대체) --------------------
 let _testWarmupEnabledOverride: boolean | undefined = undefined;
 let _testWarmupPerTypeLimitOverride: number | undefined = undefined;
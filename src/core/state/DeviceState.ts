@@ -1,6 +1,8 @@
 import * as vscode from 'vscode';
 
+import { notConnectedError } from '../../shared/errors.js';
 import { connectionManager } from '../connection/ConnectionManager.js';
+import { shellQuote as q } from '../connection/shellQuote.js';
 import { getLogger } from '../logging/extension-logger.js';
 import { resolveHomeyUnit } from '../service/serviceDiscovery.js';
 import { ServiceFilePatcher } from '../service/ServiceFilePatcher.js';
@@ -21,16 +23,7 @@ export async function getMountState(_ctx?: vscode.ExtensionContext): Promise<Mou
     if (!connectionManager.isConnected()) return 'unknown';
 
     // 볼륨 목록 조회(이름만)
-    const { stdout } = await connectionManager.run(
-      `sh -lc 'docker volume ls --format "{{.Name}}" 2>/dev/null || true'`,
-    );
-
-    const names = new Set(
-      String(stdout || '')
-        .split(/\r?\n/)
-        .map((s) => s.trim())
-        .filter(Boolean),
-    );
+    const names = await listDockerVolumes();
 
     // UnmountTaskRunner의 기본 패턴과 동일한 이름을 기준으로 판정
     const targets = ['homey-app', 'homey-node'];
@@ -72,3 +65,58 @@ export async function getEnvToggleEnabled(
     return false;
   }
 }
+
+/** 서비스 파일 편집 항목(마운트 볼륨/환경 토글) */
+export type ServiceEditItem = {
+  name: string;
+  kind: 'volume' | 'env';
+  /** 서비스 파일에 해당 토큰이 들어 있는지 */
+  inServiceFile: boolean;
+  /** kind=volume일 때 docker 볼륨 존재 여부 */
+  volumeExists?: boolean;
+};
+
+export type ServiceEditStatus = { unit: string; path: string; items: ServiceEditItem[] };
+
+// Mount/Toggle 러너가 서비스 파일에 넣는 토큰과 동일(중간 문구 기준)
+const SERVICE_EDIT_MARKERS: { name: string; kind: ServiceEditItem['kind'] }[] = [
+  { name: 'homey-app', kind: 'volume' },
+  { name: 'homey-node', kind: 'volume' },
+  { name: 'HOMEY_APP_LOG', kind: 'env' },
+  { name: 'HOMEY_DEV_TOKEN', kind: 'env' },
+];
+
+/**
+ * 현재 서비스 유닛 파일을 한 번 읽어, 알려진 마운트/토글 항목이 들어 있는지 보고한다.
+ * - 유닛은 resolveHomeyUnit(캐시+검증)로 해상
+ * - 볼륨 항목은 docker 볼륨 존재 여부도 함께
+ */
+export async function getServiceEditStatus(): Promise<ServiceEditStatus> {
+  if (!connectionManager.isConnected()) throw notConnectedError();
+  const unit = await resolveHomeyUnit();
+  const svc = new ServiceFilePatcher(unit);
+  const path = await svc.resolveServicePath();
+  const { stdout } = await connectionManager.run(`sh -lc ${q(`cat ${q(path)} 2>/dev/null`)}`);
+  const text = String(stdout || '');
+  const volumes = await listDockerVolumes();
+  const items = SERVICE_EDIT_MARKERS.map(({ name, kind }) => ({
+    name,
+    kind,
+    inServiceFile: text.includes(name),
+    ...(kind === 'volume' ? { volumeExists: volumes.has(name) } : {}),
+  }));
+  log.debug(`[DeviceState] service edit status unit=${unit} path=${path}`, items);
+  return { unit, path, items };
+}
+
+async function listDockerVolumes(): Promise<Set<string>> {
+  const { stdout } = await connectionManager.run(
+    `sh -lc 'docker volume ls --format "{{.Name}}" 2>/dev/null || true'`,
+  );
+  return new Set(
+    String(stdout || '')
+      .split(/\r?\n/)
+      .map((s) => s.trim())
+      .filter(Boolean),
+  );
+}
@@ -1,30 +1,38 @@
 // === src/core/tasks/MountTaskRunner.ts ===
 
-import { connectionManager } from '../connection/ConnectionManager.js';
+import { connectionManager, type ShellExecutor } from '../connection/ConnectionManager.js';
 import { getLogger } from '../logging/extension-logger.js';
 import { resolveHomeyUnit } from '../service/serviceDiscovery.js';
 import { ServiceFilePatcher } from '../service/ServiceFilePatcher.js';
 import { HostStateGuard } from './guards/HostStateGuard.js';
+import type { TaskDeps } from './TaskDeps.js';
 import { WorkflowEngine } from './workflow/workflowEngine.js';
 
 export type Mode = 'pro' | 'core' | 'sdk' | 'bridge';
 
 export class MountTaskRunner {
   private log = getLogger('MountRunner');
-  private guard = new HostStateGuard();
+  private guard: HostStateGuard;
+  private sh: ShellExecutor;
 
   // ✅ 정책 변경: 기본적으로 homey-app(pro) + homey-node(core) 둘 다 삽입
-  constructor(private modes: Mode[] = ['pro', 'core']) {}
+  constructor(
+    private modes: Mode[] = ['pro', 'core'],
+    private deps: TaskDeps = {},
+  ) {
+    this.sh = deps.sh ?? connectionManager;
+    this.guard = new HostStateGuard(this.sh, undefined, deps.relink);
+  }
 
-  async run() {
-    const unit = await resolveHomeyUnit();
-    const svc = new ServiceFilePatcher(unit);
+  async run(signal?: AbortSignal) {
+    const unit = this.deps.unit ?? (await resolveHomeyUnit());
+    const svc = new ServiceFilePatcher(unit, undefined, this.sh);
     const steps: any[] = [];
 
     steps.push({
       name: 'INIT',
       run: async () => {
-        await connectionManager.run(`sh -lc 'id >/dev/null'`);
+        await this.sh.run(`sh -lc 'id >/dev/null'`);
         return 'ok';
       },
     });
@@ -74,6 +82,13 @@ export class MountTaskRunner {
         ctx.bag.backup = await svc.backup(ctx.bag.svcPath);
         return 'ok';
       },
+      // 이후 단계(패치/재시작/검증) 실패 시 원본 서비스 파일로 복구
+      rollback: async (ctx: any) => {
+        if (!ctx.bag.backup) return;
+        await this.guard.ensureFsRemountRW('/');
+        await svc.restore(ctx.bag.svcPath, ctx.bag.backup);
+        await svc.daemonReload();
+      },
     });
 
     steps.push({
@@ -113,7 +128,8 @@ export class MountTaskRunner {
       name: 'RESTART_SERVICE',
       run: async () => {
         await svc.restart();
-        const ok = await this.guard.waitForUnitActive(unit, 30_000, 1500);
+        await this.guard.waitForLinkAfterRestart(undefined, signal);
+        const ok = await this.guard.waitForUnitActive(unit, 30_000, 1500, { signal });
         return ok ? 'ok' : 'fail';
       },
     });
@@ -143,7 +159,12 @@ export class MountTaskRunner {
     });
 
     const wf = new WorkflowEngine(steps);
-    await wf.runAll(`mount-${Date.now()}`);
+    try {
+      await wf.runAll(`mount-${Date.now()}`, undefined, signal);
+    } finally {
+      // 작업 전 ro였다면 기기 기본 상태(ro)로 원복
+      await this.guard.restoreFsReadOnly();
+    }
   }
 }
 
@@ -1,15 +1,23 @@
 // === src/core/tasks/RestartTaskRunner.ts ===
+import { connectionManager, type ShellExecutor } from '../connection/ConnectionManager.js';
 import { resolveHomeyUnit } from '../service/serviceDiscovery.js';
 import { ServiceFilePatcher } from '../service/ServiceFilePatcher.js';
 import { HostStateGuard } from './guards/HostStateGuard.js';
+import type { TaskDeps } from './TaskDeps.js';
 import { WorkflowEngine } from './workflow/workflowEngine.js';
 
 export class RestartTaskRunner {
-  private guard = new HostStateGuard();
+  private guard: HostStateGuard;
+  private sh: ShellExecutor;
 
-  async run() {
-    const unit = await resolveHomeyUnit();
-    const svc = new ServiceFilePatcher(unit);
+  constructor(private deps: TaskDeps = {}) {
+    this.sh = deps.sh ?? connectionManager;
+    this.guard = new HostStateGuard(this.sh, undefined, deps.relink);
+  }
+
+  async run(signal?: AbortSignal) {
+    const unit = this.deps.unit ?? (await resolveHomeyUnit());
+    const svc = new ServiceFilePatcher(unit, undefined, this.sh);
     const steps: any[] = [];
 
     steps.push({ name: 'INIT', run: async () => 'ok' });
@@ -17,8 +25,9 @@ export class RestartTaskRunner {
       name: 'RESTART_SERVICE',
       run: async () => {
         await svc.restart();
+        await this.guard.waitForLinkAfterRestart(undefined, signal);
         // edge-go: 첫 재시작 직후 서브상태 settling을 감안해 여유를 조금 준다
-        const ok = await this.guard.waitForUnitActive(unit, 35_000, 1500);
+        const ok = await this.guard.waitForUnitActive(unit, 35_000, 1500, { signal });
         return ok ? 'ok' : 'retry';
       },
       maxIterations: 3,
@@ -26,6 +35,6 @@ export class RestartTaskRunner {
     steps.push({ name: 'POST_VERIFY', run: async () => 'ok' });
 
     const wf = new WorkflowEngine(steps);
-    await wf.runAll(`restart-${Date.now()}`);
+    await wf.runAll(`restart-${Date.now()}`, undefined, signal);
   }
 }
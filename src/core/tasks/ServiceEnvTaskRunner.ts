@@ -0,0 +1,169 @@
+// === src/core/tasks/ServiceEnvTaskRunner.ts ===
+import { connectionManager, type ShellExecutor } from '../connection/ConnectionManager.js';
+import { getLogger } from '../logging/extension-logger.js';
+import { resolveHomeyUnit } from '../service/serviceDiscovery.js';
+import {
+  parseServiceEnv,
+  serviceEnvKeyRegex,
+  serviceEnvLine,
+  validateServiceEnv,
+} from '../service/serviceEnv.js';
+import { ServiceFilePatcher } from '../service/ServiceFilePatcher.js';
+import { HostStateGuard } from './guards/HostStateGuard.js';
+import type { TaskDeps } from './TaskDeps.js';
+import { WorkflowEngine } from './workflow/workflowEngine.js';
+
+/**
+ * 서비스 파일의 `--env="KEY=VALUE"` 한 개를 설정/제거(백업 → sed → daemon-reload → 재시작 → 검증)
+ * - value가 undefined면 제거, 있으면 기존 라인을 지우고 새 값으로 삽입
+ * - 이미 원하는 상태면 아무 것도 하지 않는다(재시작 없음)
+ * - HOMEY_APP_LOG / HOMEY_DEV_TOKEN 토글은 value '1' / undefined
+ */
+export class ServiceEnvTaskRunner {
+  private log = getLogger('EnvRunner');
+  private guard: HostStateGuard;
+  private sh: ShellExecutor;
+  private rx: string;
+
+  constructor(
+    private varName: string,
+    private value: string | undefined,
+    private deps: TaskDeps = {},
+  ) {
+    validateServiceEnv(varName, value);
+    this.sh = deps.sh ?? connectionManager;
+    this.guard = new HostStateGuard(this.sh, undefined, deps.relink);
+    // ✅ 존재 체크/삭제는 키 기준(값/따옴표/백슬래시 등은 무시)
+    this.rx = serviceEnvKeyRegex(varName);
+  }
+
+  /** 현재 값(없으면 undefined) */
+  private async currentValue(svc: ServiceFilePatcher, file: string) {
+    return parseServiceEnv(await svc.read(file)).find((v) => v.key === this.varName)?.value;
+  }
+
+  async run(signal?: AbortSignal) {
+    const unit = this.deps.unit ?? (await resolveHomeyUnit());
+    const svc = new ServiceFilePatcher(unit, undefined, this.sh);
+    const steps: any[] = [];
+    const tag = `[env ${this.varName}]`;
+    const want = this.value === undefined ? 'unset' : `set=${this.value}`;
+
+    const svcPath = await svc.resolveServicePath();
+    const current = await this.currentValue(svc, svcPath);
+    if (current === this.value) {
+      this.log.info(`${tag} ${want} — already applied, nothing to do (file=${svcPath})`);
+      return;
+    }
+
+    steps.push({ name: 'INIT', run: async () => 'ok' });
+
+    steps.push({
+      name: 'READ_SERVICE_FILE',
+      run: async (ctx: any) => {
+        ctx.bag.svcPath = svcPath;
+        ctx.bag.workPath = await svc.stageToWorkCopy(svcPath);
+        this.log.info(`${tag} unit=${unit} file=${ctx.bag.svcPath}`);
+        ctx.bag.exists = current !== undefined;
+        ctx.bag.hashBefore = await svc.computeHash(svcPath);
+        return 'ok';
+      },
+    });
+
+    steps.push({
+      name: 'DRY_RUN_DIFF',
+      run: async () => {
+        this.log.info(`${tag} ${want} — current: ${current ?? '(none)'}`);
+        return 'ok';
+      },
+    });
+
+    steps.push({
+      name: 'BACKUP',
+      run: async (ctx: any) => {
+        // edge-go: 변경 전 루트 RW 리마운트
+        await this.guard.ensureFsRemountRW('/');
+        ctx.bag.backup = await svc.backup(ctx.bag.svcPath);
+        return 'ok';
+      },
+      // 이후 단계(패치/재시작/검증) 실패 시 원본 서비스 파일로 복구
+      rollback: async (ctx: any) => {
+        if (!ctx.bag.backup) return;
+        await this.guard.ensureFsRemountRW('/');
+        await svc.restore(ctx.bag.svcPath, ctx.bag.backup);
+        await svc.daemonReload();
+      },
+    });
+
+    steps.push({
+      name: 'APPLY_PATCH',
+      run: async (ctx: any) => {
+        const path = ctx.bag.svcPath as string;
+        const work = ctx.bag.workPath as string;
+        if (ctx.bag.exists) await svc.deleteByRegexPatterns(work, [this.rx]); // 키 라인 삭제
+        if (this.value !== undefined) {
+          await svc.insertAfterExecStart(work, serviceEnvLine(this.varName, this.value));
+        }
+        await this.guard.ensureFsRemountRW('/');
+        await svc.replaceOriginalWith(path, work);
+        const changed = await this.guard.waitForServiceFileChange(
+          path,
+          8000,
+          500,
+          ctx.bag.hashBefore,
+        );
+        if (!changed) {
+          this.log.error(`${tag} service file did not change: ${path}`);
+          throw new Error('patch not applied (no file change detected)');
+        }
+        return 'ok';
+      },
+    });
+
+    steps.push({
+      name: 'DAEMON_RELOAD',
+      run: async () => {
+        await svc.daemonReload();
+        return 'ok';
+      },
+    });
+
+    steps.push({
+      name: 'RESTART_SERVICE',
+      run: async () => {
+        await svc.restart();
+        await this.guard.waitForLinkAfterRestart(undefined, signal);
+        const ok = await this.guard.waitForUnitActive(unit, 30_000, 1500, { signal });
+        return ok ? 'ok' : 'fail';
+      },
+    });
+
+    steps.push({
+      name: 'POST_VERIFY',
+      run: async (ctx: any) => {
+        const now = await this.currentValue(svc, ctx.bag.svcPath as string);
+        if (now !== this.value) {
+          this.log.error(`${tag} verification failed (now=${now ?? '(none)'})`);
+          throw new Error('verification failed (service env mismatch)');
+        }
+        return 'ok';
+      },
+    });
+
+    steps.push({
+      name: 'CLEANUP',
+      run: async () => {
+        await svc.cleanupWorkdir();
+        return 'ok';
+      },
+    });
+
+    const wf = new WorkflowEngine(steps);
+    try {
+      await wf.runAll(`env-${this.varName}-${Date.now()}`, undefined, signal);
+    } finally {
+      // 작업 전 ro였다면 기기 기본 상태(ro)로 원복
+      await this.guard.restoreFsReadOnly();
+    }
+  }
+}
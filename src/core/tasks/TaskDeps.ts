@@ -0,0 +1,12 @@
+// === src/core/tasks/TaskDeps.ts ===
+import type { ShellExecutor } from '../connection/ConnectionManager.js';
+
+/** 태스크 러너 주입 의존성 — 생략 시 실제 연결/유닛 탐지를 사용(테스트에서 가짜로 대체) */
+export type TaskDeps = {
+  /** 원격 명령 실행기(기본 connectionManager) */
+  sh?: ShellExecutor;
+  /** Homey systemd 유닛명(생략 시 resolveHomeyUnit로 탐지) */
+  unit?: string;
+  /** 재시작 후 연결 복귀 대기(생략 시 sh가 connectionManager일 때만 waitUntilLive 사용) */
+  relink?: (timeoutMs: number, signal?: AbortSignal) => Promise<boolean>;
+};
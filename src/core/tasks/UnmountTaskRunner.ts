@@ -1,26 +1,35 @@
 // === src/core/tasks/UnmountTaskRunner.ts ===
 
-import { connectionManager } from '../connection/ConnectionManager.js';
+import { connectionManager, type ShellExecutor } from '../connection/ConnectionManager.js';
+import { shellQuote as q } from '../connection/shellQuote.js';
 import { getLogger } from '../logging/extension-logger.js';
 import { resolveHomeyUnit } from '../service/serviceDiscovery.js';
 import { ServiceFilePatcher } from '../service/ServiceFilePatcher.js';
 import { HostStateGuard } from './guards/HostStateGuard.js';
+import type { TaskDeps } from './TaskDeps.js';
 import { WorkflowEngine } from './workflow/workflowEngine.js';
 
 export class UnmountTaskRunner {
   private log = getLogger('UnmountRunner');
-  private guard = new HostStateGuard();
-  constructor(private deletePatterns: string[] = DEFAULT_PATTERNS) {}
+  private guard: HostStateGuard;
+  private sh: ShellExecutor;
+  constructor(
+    private deletePatterns: string[] = DEFAULT_PATTERNS,
+    private deps: TaskDeps = {},
+  ) {
+    this.sh = deps.sh ?? connectionManager;
+    this.guard = new HostStateGuard(this.sh, undefined, deps.relink);
+  }
 
-  async run() {
-    const unit = await resolveHomeyUnit();
-    const svc = new ServiceFilePatcher(unit);
+  async run(signal?: AbortSignal) {
+    const unit = this.deps.unit ?? (await resolveHomeyUnit());
+    const svc = new ServiceFilePatcher(unit, undefined, this.sh);
     const steps: any[] = [];
 
     steps.push({
       name: 'INIT',
       run: async () => {
-        await connectionManager.run(`sh -lc 'id >/dev/null'`);
+        await this.sh.run(`sh -lc 'id >/dev/null'`);
         return 'ok';
       },
     });
@@ -45,15 +54,31 @@ export class UnmountTaskRunner {
         // BusyBox 호환: nl/sed 조합 대신 grep -nE 로 미리보기
         const path = ctx.bag.workPath as string;
         const patterns = this.deletePatterns;
-        const cmd = `sh -lc 'grep -nE ${patterns.map((p) => `-e ${q(p)}`).join(' ')} -- ${q(path)} 2>/dev/null || true'`;
-        const { stdout } = await connectionManager.run(cmd);
+        const grep = `grep -nE ${patterns.map((p) => `-e ${q(p)}`).join(' ')} -- ${q(path)}`;
+        const cmd = `sh -lc ${q(`${grep} 2>/dev/null || true`)}`;
+        const { stdout } = await this.sh.run(cmd);
         const lines = String(stdout || '')
           .split(/\r?\n/)
           .filter(Boolean);
         lines.forEach((ln) => this.log.info('[unmount.dryrun] ' + ln));
         ctx.bag.dryRunCount = lines.length;
+        // 실제로 존재하는 대상 볼륨만 제거 대상으로 남김
+        const { stdout: vs } = await this.sh.run(
+          `sh -lc 'docker volume ls --format "{{.Name}}" 2>/dev/null || true'`,
+        );
+        const present = new Set(
+          String(vs || '')
+            .split(/\r?\n/)
+            .map((v) => v.trim())
+            .filter(Boolean),
+        );
+        ctx.bag.volumes = (ctx.bag.volumes as string[]).filter((v) => present.has(v));
+        // 서비스 파일에 마운트 토큰도 없고 볼륨도 없으면 할 일이 없음 → 바로 정리 단계로
+        ctx.bag.nothingToDo = lines.length === 0 && ctx.bag.volumes.length === 0;
+        if (ctx.bag.nothingToDo) this.log.info('[unmount] nothing mounted — skip to cleanup');
         return 'ok';
       },
+      next: (_r: string, ctx: any) => (ctx.bag.nothingToDo ? 'CLEANUP' : undefined),
     });
 
     steps.push({
@@ -67,12 +92,14 @@ export class UnmountTaskRunner {
 
     steps.push({
       name: 'STOP_AND_REMOVE_CONTAINERS',
+      // 재시도는 엔진에 맡김: 1회 정지/제거 → 남아 있으면 'retry'(최대 3회), 예외는 1회 재시도
       run: async () => {
-        await this.guard.stopContainersByMatch('homey', 3, 2000);
+        await this.guard.stopContainersByMatch('homey', 1, 500);
         const ok = await this.guard.waitForNoContainers('homey', 15_000, 1000);
         return ok ? 'ok' : 'retry';
       },
-      maxIterations: 2,
+      maxIterations: 3,
+      retries: 1,
     });
 
     steps.push({
@@ -118,7 +145,8 @@ export class UnmountTaskRunner {
       name: 'RESTART_SERVICE',
       run: async () => {
         await svc.restart();
-        const ok = await this.guard.waitForUnitActive(unit, 30_000, 1500);
+        await this.guard.waitForLinkAfterRestart(undefined, signal);
+        const ok = await this.guard.waitForUnitActive(unit, 30_000, 1500, { signal });
         return ok ? 'ok' : 'fail';
       },
     });
@@ -148,14 +176,14 @@ export class UnmountTaskRunner {
     });
 
     const wf = new WorkflowEngine(steps);
-    await wf.runAll(`unmount-${Date.now()}`);
+    try {
+      await wf.runAll(`unmount-${Date.now()}`, undefined, signal);
+    } finally {
+      // 작업 전 ro였다면 기기 기본 상태(ro)로 원복
+      await this.guard.restoreFsReadOnly();
+    }
   }
 }
 
 // ✅ 삭제 패턴은 mount 대상 2개만: env 토글(-e ...)은 여기서 건드리지 않음
 const DEFAULT_PATTERNS = [String.raw`homey-app`, String.raw`homey-node`];
-
-// 안전 싱글쿼트: ' -> '\'' 로 치환
-function q(s: string) {
-  return "'" + String(s).replace(/'/g, `'\\''`) + "'";
-}
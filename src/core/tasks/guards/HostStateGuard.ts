@@ -1,13 +1,57 @@
-import { connectionManager } from '../../connection/ConnectionManager.js';
+import { RELINK_TIMEOUT_MS } from '../../../shared/const.js';
+import { ErrorCategory, XError } from '../../../shared/errors.js';
+import {
+  type AdbElevation,
+  connectionManager,
+  type ShellExecutor,
+} from '../../connection/ConnectionManager.js';
+import { throwIfAborted } from '../../connection/deadline.js';
+import { shellQuote as q } from '../../connection/shellQuote.js';
 import { getLogger } from '../../logging/extension-logger.js';
 
 const log = getLogger('HostGuard');
 
 export class HostStateGuard {
+  /** 작업 전 ro였던 마운트 지점 — restoreFsReadOnly()에서 ro로 되돌림 */
+  private roRoots = new Set<string>();
+
+  /**
+   * @param elevate 파일시스템 수정 전 권한 상승(ADB: adb root/remount, SSH: no-op).
+   *                기본은 실제 연결(connectionManager)을 쓸 때만 활성화
+   * @param relink  재시작 뒤 연결 복귀 대기(ADB: wait-for-device, SSH: backoff 재접속). 기본은 elevate와 같음
+   */
+  constructor(
+    private sh: ShellExecutor = connectionManager,
+    private elevate?: () => Promise<AdbElevation | undefined>,
+    private relink?: (timeoutMs: number, signal?: AbortSignal) => Promise<boolean>,
+  ) {
+    if (!elevate && sh === connectionManager) {
+      this.elevate = () => connectionManager.ensureAdbRoot();
+    }
+    if (!relink && sh === connectionManager) {
+      this.relink = (ms, signal) => connectionManager.waitUntilLive(ms, signal);
+    }
+  }
+
+  /**
+   * 서비스/컨테이너 재시작 직후 호출: ADB 기기가 잠깐 버스에서 빠지거나 SSH 세션이 끊겨도
+   * 다음 단계(상태 폴링, 검증)가 실패하지 않도록 연결이 돌아올 때까지 기다린다.
+   * 한도 안에 돌아오지 않으면 Connection 오류로 중단.
+   */
+  async waitForLinkAfterRestart(timeoutMs = RELINK_TIMEOUT_MS, signal?: AbortSignal) {
+    if (!this.relink) return;
+    if (await this.relink(timeoutMs, signal)) return;
+    throw new XError(
+      ErrorCategory.Connection,
+      `재시작 후 ${Math.round(timeoutMs / 1000)}초 안에 기기 연결이 돌아오지 않았습니다` +
+        ' (ADB: `adb devices`로 상태 확인, SSH: 네트워크/전원 확인)',
+    );
+  }
+
   /** 안전 실행: sh -lc '<script>' _ 'arg1' 'arg2' … 로 전달해 인자 이스케이프 문제 제거 */
   private async runShArgs(script: string, ...args: string[]) {
     const tail = args.map((a) => q(a)).join(' ');
-    return await connectionManager.run(`sh -lc ${q(script)} ${tail ? ` _ ${tail}` : ''}`);
+    return await this.sh.run(`sh -lc ${q(script)} ${tail ? ` _ ${tail}` : ''}`);
   }
   /**
    * unit이 활성화되었는지 대기.
@@ -27,6 +71,8 @@ export class HostStateGuard {
       fallbackIsActive?: boolean;
       /** 성공 판정에 ExecMainPID>0 조건을 추가로 요구 */
       requirePid?: boolean;
+      /** 취소 시 대기를 멈추고 취소 오류를 던진다 */
+      signal?: AbortSignal;
     },
   ) {
     const o = {
@@ -42,8 +88,9 @@ export class HostStateGuard {
     log.debug(`waitForUnitActive: showCmd=${showCmd}`);
 
     while (Date.now() < deadline) {
+      throwIfAborted(o.signal);
       // 1) key=value로 안정 파싱(순서 비의존)
-      const { stdout } = await connectionManager.run(`sh -lc ${q(showCmd)}`);
+      const { stdout } = await this.sh.run(`sh -lc ${q(showCmd)}`, [], { signal: o.signal });
       const map: Record<string, string> = {};
       String(stdout || '')
         .trim()
@@ -75,8 +122,8 @@ export class HostStateGuard {
 
       // 2) 폴백: is-active (항상 안전망으로 확인)
       if (o.fallbackIsActive) {
-        const { stdout: s2 } = await connectionManager.run(
-          `sh -lc 'systemctl is-active ${q(unit)} 2>/dev/null || true'`,
+        const { stdout: s2 } = await this.sh.run(
+          `sh -lc ${q(`systemctl is-active ${q(unit)} 2>/dev/null || true`)}`,
         );
         if (String(s2).trim() === 'active') return true;
       }
@@ -98,13 +145,70 @@ export class HostStateGuard {
     return false;
   }
 
+  /**
+   * 루트 파일시스템을 RW로 리마운트하고 실제 쓰기 가능 여부를 확인한다.
+   * - 작업 전 ro였으면 기억해 두었다가 restoreFsReadOnly()에서 원복
+   * - 리마운트 실패 또는 임시 파일 생성 실패 시 "read-only" 에러로 즉시 중단(sed 단계까지 가지 않음)
+   */
   async ensureFsRemountRW(root: string = '/') {
-    try {
-      await connectionManager.run(`sh -lc 'mount -o remount,rw ${root} || true'`);
-      return true;
-    } catch {
-      return false;
+    const elevation = await this.elevate?.();
+    // 실패 메시지에 덧붙일 adb root/remount 사유
+    const hint = elevation?.reason ? ` [${elevation.reason}]` : '';
+    const wasReadOnly = await this.isMountReadOnly(root);
+    if (wasReadOnly) {
+      const r = await this.sh.run(`sh -lc ${q(`mount -o remount,rw ${q(root)} 2>&1`)}`);
+      if (r.code !== 0) {
+        const why = String(r.stdout || r.stderr || '').trim();
+        log.error(`ensureFsRemountRW: remount failed root=${root} code=${r.code} ${why}`);
+        throw new XError(
+          ErrorCategory.Permission,
+          `filesystem is read-only: ${root} 를 rw로 리마운트하지 못했습니다` +
+            ` (root 권한이 없거나 squashfs/overlay 등 읽기 전용 이미지일 수 있습니다)${why ? `: ${why}` : ''}` +
+            hint,
+          r,
+        );
+      }
+      this.roRoots.add(root);
     }
+
+    // 리마운트 결과와 무관하게 실제 쓰기 가능 여부를 임시 파일로 검증
+    const probe = `${root.replace(/\/+$/, '')}/.edgetool-rw-probe`;
+    const t = await this.sh.run(
+      `sh -lc ${q(`touch ${q(probe)} 2>&1 && rm -f ${q(probe)}`)}`,
+    );
+    if (t.code !== 0) {
+      const why = String(t.stdout || t.stderr || '').trim();
+      log.error(`ensureFsRemountRW: write probe failed root=${root} ${why}`);
+      throw new XError(
+        ErrorCategory.Permission,
+        `filesystem is read-only: ${root} 에 쓸 수 없습니다` +
+          ` (리마운트가 적용되지 않았거나 디스크 오류로 커널이 ro 전환했을 수 있습니다)${why ? `: ${why}` : ''}` +
+          hint,
+        t,
+      );
+    }
+    return { wasReadOnly };
+  }
+
+  /** ensureFsRemountRW() 이전에 ro였던 마운트 지점을 다시 ro로 되돌린다(실패는 경고만) */
+  async restoreFsReadOnly() {
+    for (const root of Array.from(this.roRoots)) {
+      try {
+        const r = await this.sh.run(`sh -lc ${q(`mount -o remount,ro ${q(root)} 2>&1`)}`);
+        if (r.code !== 0) log.warn(`restoreFsReadOnly: remount ro failed root=${root}`);
+      } catch (e) {
+        log.warn(`restoreFsReadOnly: ${root} ${String(e)}`);
+      }
+      this.roRoots.delete(root);
+    }
+  }
+
+  /** /proc/mounts 기준 마운트 옵션에 ro가 있는지(판별 불가 시 false) */
+  private async isMountReadOnly(root: string) {
+    const script = 'awk -v m="$1" \'$2==m {o=$4} END {print o}\' /proc/mounts 2>/dev/null || true';
+    const { stdout } = await this.runShArgs(script, root);
+    const opts = String(stdout || '').trim().split(',');
+    return opts.includes('ro');
   }
 
   /** 이름이 정규식에 매칭되는 컨테이너를 '정지 후 제거'한다. (docker stop → docker rm)
@@ -133,7 +237,7 @@ export class HostStateGuard {
   async waitForVolumesGone(names: string[], timeoutMs = 10_000, pollMs = 1000) {
     const deadline = Date.now() + timeoutMs;
     while (Date.now() < deadline) {
-      const { stdout } = await connectionManager.run(
+      const { stdout } = await this.sh.run(
         `sh -lc 'docker volume ls --format "{{.Name}}" 2>/dev/null || true'`,
       );
       const set = new Set(
@@ -156,7 +260,7 @@ export class HostStateGuard {
     for (let i = 0; i < tries; i++) {
       // 개별 삭제 시도 (존재하지 않으면 무시)
       for (const v of vols) {
-        await connectionManager.run(`sh -lc 'docker volume rm ${q(v)} >/dev/null 2>&1 || true'`);
+        await this.sh.run(`sh -lc ${q(`docker volume rm ${q(v)} >/dev/null 2>&1 || true`)}`);
       }
       const ok = await this.waitForVolumesGone(vols, backoffMs, 400);
       if (ok) return true;
@@ -173,14 +277,15 @@ export class HostStateGuard {
     pollMs = 1500,
     beforeHash?: string,
   ) {
-    const hashCmd = `sh -lc 'if command -v sha256sum >/dev/null 2>&1; then sha256sum ${q(
-      file,
-    )} | cut -d" " -f1; else md5sum ${q(file)} | cut -d" " -f1; fi'`;
+    const hashCmd = `sh -lc ${q(
+      `if command -v sha256sum >/dev/null 2>&1; then sha256sum ${q(file)} | cut -d" " -f1; ` +
+        `else md5sum ${q(file)} | cut -d" " -f1; fi`,
+    )}`;
     log.debug(`waitForServiceFileChange: hashCmd=${hashCmd}`);
-    const before = beforeHash ?? String((await connectionManager.run(hashCmd)).stdout || '').trim();
+    const before = beforeHash ?? String((await this.sh.run(hashCmd)).stdout || '').trim();
     const deadline = Date.now() + timeoutMs;
     while (Date.now() < deadline) {
-      const now = String((await connectionManager.run(hashCmd)).stdout || '').trim();
+      const now = String((await this.sh.run(hashCmd)).stdout || '').trim();
       if (now && before && now !== before) return true;
       await sleep(pollMs);
     }
@@ -188,13 +293,6 @@ export class HostStateGuard {
   }
 }
 
-function sq(s: string) {
-  return String(s).replace(/'/g, `'\\''`);
-}
-// 안전 싱글쿼트 래퍼: 'foo bar' 형태로 감싼다
-function q(s: string) {
-  return "'" + String(s).replace(/'/g, `'\\''`) + "'";
-}
 function sleep(ms: number) {
   return new Promise((r) => setTimeout(r, ms));
 }
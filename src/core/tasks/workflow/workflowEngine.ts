@@ -1,5 +1,6 @@
 // === src/core/tasks/workflow/workflowEngine.ts ===
 
+import { throwIfAborted } from '../../connection/deadline.js';
 import { getLogger } from '../../logging/extension-logger.js';
 
 export type StepResult = 'ok' | 'retry' | 'fail' | 'skip';
@@ -8,15 +9,19 @@ export interface StepCtx {
   runId: string;
   // 임의의 공유 데이터
   bag: Record<string, any>;
-  // 취소 신호(필요시 확장)
-  aborted?: boolean;
+  // 취소 신호 — 긴 대기/원격 명령을 하는 스텝은 이 신호를 넘긴다
+  signal?: AbortSignal;
 }
 
 export interface Step {
   name: string;
   timeoutMs?: number;
   maxIterations?: number; // retry 루프 상한 (기본 1)
+  /** 예외/'fail' 시 스텝 전체를 다시 시도할 횟수 (기본 0) */
+  retries?: number;
   run(ctx: StepCtx): Promise<StepResult>;
+  /** 이후 스텝이 최종 실패하면 완료된 스텝부터 역순으로 호출되는 되돌리기 */
+  rollback?(ctx: StepCtx): Promise<void>;
   next?(last: StepResult, ctx: StepCtx): string | undefined;
   onErrorPolicy?: 'stop' | 'continue';
 }
@@ -25,17 +30,27 @@ export class WorkflowEngine {
   private log = getLogger('Workflow');
   constructor(private steps: Step[]) {}
 
-  async runAll(runId: string, start?: string) {
-    const ctx: StepCtx = { runId, bag: {} };
+  /**
+   * signal이 abort되면 다음 스텝/재시도로 넘어가지 않고, 완료된 스텝을 되돌린 뒤 취소 오류를 던진다.
+   * (실행 중인 스텝은 ctx.signal을 따르는 만큼만 빨리 끝난다)
+   */
+  async runAll(runId: string, start?: string, signal?: AbortSignal) {
+    const ctx: StepCtx = { runId, bag: {}, signal };
     const index = new Map(this.steps.map((s, i) => [s.name, i]));
+    // 완료된 스텝(rollback 대상, 마지막 완료 순서) — next()로 되돌아가 다시 돌아도 한 번만
+    const done = new Set<Step>();
     let i = typeof start === 'string' ? (index.get(start) ?? 0) : 0;
     for (; i < this.steps.length; i++) {
       const s = this.steps[i];
       const max = Math.max(1, s.maxIterations ?? 1);
+      const retries = Math.max(0, s.retries ?? 0);
       let iter = 0 as number;
+      let failures = 0;
       this.log.info(`[wf:${runId}] step=${s.name}`);
-      while (iter++ < max) {
+      for (;;) {
+        iter++;
         try {
+          throwIfAborted(signal);
           const r = await withTimeout(s.run(ctx), s.timeoutMs);
           if (r === 'retry') {
             this.log.warn(`[wf:${runId}] step=${s.name} → retry (${iter}/${max})`);
@@ -46,6 +61,8 @@ export class WorkflowEngine {
           if (r === 'fail') {
             throw new Error(`step returned fail: ${s.name}`);
           }
+          done.delete(s);
+          done.add(s);
           const nxt = s.next?.(r, ctx);
           if (typeof nxt === 'string') {
             const j = index.get(nxt);
@@ -53,16 +70,46 @@ export class WorkflowEngine {
           }
           break;
         } catch (e) {
-          this.log.error(
-            `[wf:${runId}] step=${s.name} failed: ${e instanceof Error ? e.message : String(e)}`,
-          );
-          if (s.onErrorPolicy !== 'continue') throw e;
+          const msg = e instanceof Error ? e.message : String(e);
+          if (signal?.aborted) {
+            this.log.warn(`[wf:${runId}] step=${s.name} cancelled`);
+            await this.rollback(done, ctx);
+            throw e;
+          }
+          if (failures++ < retries) {
+            this.log.warn(
+              `[wf:${runId}] step=${s.name} error → retry (${failures}/${retries}): ${msg}`,
+            );
+            iter = 0;
+            await sleep(1000 * Math.min(failures, 3));
+            continue;
+          }
+          this.log.error(`[wf:${runId}] step=${s.name} failed: ${msg}`);
+          if (s.onErrorPolicy !== 'continue') {
+            await this.rollback(done, ctx);
+            throw e;
+          }
           break;
         }
       }
     }
     return ctx.bag;
   }
+
+  /** 완료된 스텝을 역순으로 되돌림 — 롤백 실패는 로그만 남기고 계속 */
+  private async rollback(done: Set<Step>, ctx: StepCtx) {
+    for (const s of [...done].reverse()) {
+      if (!s.rollback) continue;
+      this.log.warn(`[wf:${ctx.runId}] rollback step=${s.name}`);
+      try {
+        await s.rollback(ctx);
+      } catch (e) {
+        this.log.error(
+          `[wf:${ctx.runId}] rollback step=${s.name} failed: ${e instanceof Error ? e.message : String(e)}`,
+        );
+      }
+    }
+  }
 }
 
 function sleep(ms: number) {
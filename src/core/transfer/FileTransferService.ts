@@ -2,7 +2,14 @@ import * as fsp from 'fs/promises';
 import * as os from 'os';
 import * as path from 'path';
 
-import { DEFAULT_TRANSFER_TIMEOUT_MS } from '../../shared/const.js';
+import {
+  DEFAULT_ADB_PULL_CONCURRENCY,
+  DEFAULT_TRANSFER_TIMEOUT_MS,
+  MAX_ADB_PULL_CONCURRENCY,
+  TRANSFER_SKIP_NAMES,
+  WINDOWS_ILLEGAL_NAME_CHARS,
+  WINDOWS_RESERVED_NAMES,
+} from '../../shared/const.js';
 import { ErrorCategory, XError } from '../../shared/errors.js';
 // ✅ ADB 전송 경로에서 사용하는 헬퍼들 가져오기
 import {
@@ -13,26 +20,232 @@ import {
   adbPushFile,
 } from '../connection/adbClient.js';
 import type { IConnectionManager } from '../connection/ConnectionManager.js';
+import { throwIfAborted } from '../connection/deadline.js';
 import { runCommandLine } from '../connection/ExecRunner.js';
+import { shellQuote } from '../connection/shellQuote.js';
 import { getLogger } from '../logging/extension-logger.js';
 import { measure } from '../logging/perf.js';
+import {
+  fmtBytes,
+  formatTransferRate,
+  parseDuKiB,
+  TransferMeter,
+  type TransferRate,
+} from './TransferMeter.js';
+
+/** 전송 진행/결과 요약(파일 단위) */
+export type TransferSummary = {
+  total: number;
+  done: number;
+  skipped: number;
+  failed: number;
+  /** Windows 금지 문자로 이름을 바꿔 저장한 파일 수(다운로드) */
+  renamed: number;
+  /** 실제로 받은 바이트와 걸린 시간(다운로드, 완료 시 처리량 보고용) */
+  bytes?: number;
+  elapsedMs?: number;
+};
+
+export type TransferProgress = TransferSummary & {
+  current?: string;
+  /** 바이트 진행률/속도/ETA(다운로드) */
+  rate?: TransferRate;
+};
+
+/**
+ * 다운로드 시 Windows에서 만들 수 없는 파일명 처리
+ * - skip: 건너뛰고 요약에 집계 / rename: 금지 문자를 '_'로 바꿔 저장 / keep: 검사 안 함
+ */
+export type IllegalNamePolicy = 'skip' | 'rename' | 'keep';
 
 export type TransferOptions = {
   timeoutMs?: number;
+  /** 취소/전체 마감 — abort 시 진행 중 원격 명령까지 끊고 사유(XError)를 던진다 */
   signal?: AbortSignal;
-};
+  /**
+   * 파일 하나 처리될 때마다 호출(ADB는 파일 단위, SSH는 아카이브 완료 시 1회)
+   * - 다운로드는 전송 중에도 rate(바이트/속도/ETA)를 담아 주기적으로 호출
+   */
+  onProgress?: (p: TransferProgress) => void;
+  /** 기본: win32면 EDGE_TOOL_PULL_ILLEGAL_NAMES(skip|rename, 기본 skip), 그 외 keep */
+  illegalNames?: IllegalNamePolicy;
+  /** ADB 다운로드 동시 pull 수(1~MAX). 기본: EDGE_TOOL_ADB_PULL_CONCURRENCY 또는 DEFAULT */
+  concurrency?: number;
+} & PathSelect;
+
+/**
+ * 다운로드 대상 선택(glob, 여러 개 가능) — exclude에 걸리면 include와 상관없이 제외.
+ * ADB는 파일 목록 단계에서, SSH는 원격 find 결과로 tar 멤버를 골라 고르지 않은 파일은 받지 않는다.
+ */
+export type PathSelect = { include?: string[]; exclude?: string[] };
 
 export interface IFileTransferService {
   uploadViaTarBase64(
     localDir: string,
     remoteDir: string,
     opts?: TransferOptions & { paths?: string[] }, // 상대경로 목록(선택)
-  ): Promise<void>;
+  ): Promise<TransferSummary>;
   downloadViaTarBase64(
     remoteDir: string,
     localDir: string,
     opts?: TransferOptions & { paths?: string[] }, // 상대경로 목록(선택)
-  ): Promise<void>;
+  ): Promise<TransferSummary>;
+}
+
+type UploadOpts = TransferOptions & { paths?: string[] };
+
+/** 경로 세그먼트 중 하나라도 스킵 대상이면 true (.git, node_modules 등) */
+export function shouldSkipFile(rel: string): boolean {
+  const segs = String(rel ?? '')
+    .replace(/\\/g, '/')
+    .split('/');
+  return segs.some((s) => (TRANSFER_SKIP_NAMES as readonly string[]).includes(s));
+}
+
+/** glob 하나 → 정규식. `/`가 있으면 경로 앞부분에 고정, 없으면 아무 세그먼트 이름과 비교 */
+function selectGlob(glob: string): { rx: RegExp; anchored: boolean } | undefined {
+  const raw = glob.trim().replace(/\\/g, '/').replace(/\/+$/, '');
+  const g = raw.replace(/^\.?\/+/, '');
+  if (!g) return undefined;
+  const body = g
+    .split(/(\*\*\/?|\*|\?)/)
+    .map((t) => {
+      if (t === '**/') return '(?:.*/)?';
+      if (t === '**') return '.*';
+      if (t === '*') return '[^/]*';
+      if (t === '?') return '[^/]';
+      return t.replace(/[.+^${}()|[\]\\]/g, '\\$&');
+    })
+    .join('');
+  return { rx: new RegExp(`^${body}$`), anchored: raw.includes('/') };
+}
+
+/**
+ * include/exclude glob → 상대경로 판정 함수(선택 조건이 없으면 undefined = 전부 통과)
+ * - `*`/`?`는 세그먼트 안, `**`는 여러 세그먼트
+ * - `node_modules`, `*.map`처럼 `/`가 없는 패턴은 어느 깊이의 이름이든 매칭
+ * - 디렉터리에 맞으면 그 아래 파일 전체가 맞는다(`lib`, `lib/`, `src/lib` = 하위 전체)
+ */
+export function compilePathSelect(sel?: PathSelect): ((rel: string) => boolean) | undefined {
+  const compile = (globs?: string[]) =>
+    (globs ?? []).map(selectGlob).filter((g): g is NonNullable<typeof g> => !!g);
+  const inc = compile(sel?.include);
+  const exc = compile(sel?.exclude);
+  if (!inc.length && !exc.length) return undefined;
+  const hits = (rel: string, globs: typeof inc) => {
+    const segs = rel.replace(/\\/g, '/').replace(/^\.\//, '').split('/').filter(Boolean);
+    const prefixes = segs.map((_, i) => segs.slice(0, i + 1).join('/'));
+    return globs.some((g) =>
+      g.anchored ? prefixes.some((p) => g.rx.test(p)) : segs.some((s) => g.rx.test(s)),
+    );
+  };
+  return (rel) => (!inc.length || hits(rel, inc)) && !hits(rel, exc);
+}
+
+/**
+ * 원격 선택 경로(remoteDir 기준 상대) 펼치기: 파일은 그대로, 디렉터리는 하위 파일로 확장
+ * - kindOf/listFiles에는 인용하지 않은 절대 경로를 넘긴다(인용은 각 헬퍼에서 한 번만)
+ */
+export async function expandRemotePaths(
+  remoteDir: string,
+  rels: string[],
+  kindOf: (abs: string) => Promise<string>,
+  listFiles: (absDir: string) => Promise<string[]>,
+): Promise<string[]> {
+  const base = remoteDir.replace(/\/+$/, '');
+  const out: string[] = [];
+  for (const rel of rels) {
+    const abs = `${base}/${rel}`;
+    const kind = await kindOf(abs);
+    if (kind === 'FILE') out.push(rel);
+    else if (kind === 'DIR') {
+      for (const sub of await listFiles(abs)) out.push(path.posix.join(rel, sub));
+    }
+  }
+  return out;
+}
+
+/**
+ * 파일 단위 전송(ADB)에서 실패한 파일이 있으면 오류 — 일부만 옮긴 결과를 성공으로 보고하거나
+ * 다운로드 커밋으로 남기지 않도록 호출부(HostController)가 결과마다 확인한다
+ */
+export function assertTransferComplete(op: string, s: TransferSummary): TransferSummary {
+  if (s.failed > 0) {
+    throw new XError(ErrorCategory.Connection, `${op}: ${s.failed}/${s.total} file(s) failed`, {
+      summary: s,
+    });
+  }
+  return s;
+}
+
+/** 경로 세그먼트 중 하나라도 Windows에서 쓸 수 없는 이름이면 true */
+export function isWindowsIllegalPath(rel: string): boolean {
+  return sanitizeWindowsPath(rel) !== String(rel ?? '').replace(/\\/g, '/');
+}
+
+/** 세그먼트별로 금지 문자 → '_', 예약 이름 → 뒤에 '_', 끝의 점/공백 → '_' */
+export function sanitizeWindowsPath(rel: string): string {
+  return String(rel ?? '')
+    .replace(/\\/g, '/')
+    .split('/')
+    .map((s) => {
+      let t = s.replace(WINDOWS_ILLEGAL_NAME_CHARS, '_');
+      if (s !== '.' && s !== '..') t = t.replace(/[. ]$/, '_');
+      if (WINDOWS_RESERVED_NAMES.test(t)) t = t.replace(/^[^.]+/, (b) => `${b}_`);
+      return t;
+    })
+    .join('/');
+}
+
+/** 동시 pull 수 결정: 옵션 → 환경변수 → 기본값, 1~MAX로 제한 */
+export function resolvePullConcurrency(requested?: number): number {
+  const env = Number(process.env.EDGE_TOOL_ADB_PULL_CONCURRENCY);
+  const n = requested ?? (Number.isFinite(env) && env > 0 ? env : DEFAULT_ADB_PULL_CONCURRENCY);
+  return Math.min(MAX_ADB_PULL_CONCURRENCY, Math.max(1, Math.floor(n) || 1));
+}
+
+/**
+ * items를 최대 limit개씩 동시에 처리(작업 순서대로 꺼내 감) — abort 시 새 작업을 꺼내지 않는다.
+ * worker 예외는 호출부가 처리해야 한다(여기서 던지면 나머지 작업자도 멈춘다).
+ */
+export async function forEachLimit<T>(
+  items: readonly T[],
+  limit: number,
+  worker: (item: T, index: number) => Promise<void>,
+  signal?: AbortSignal,
+): Promise<void> {
+  let next = 0;
+  const lane = async () => {
+    while (next < items.length && !signal?.aborted) {
+      const i = next++;
+      await worker(items[i], i);
+    }
+  };
+  const lanes = Math.max(1, Math.min(limit, items.length));
+  await Promise.all(Array.from({ length: lanes }, lane));
+}
+
+/** 진행 알림용 한 줄: `3/10 a.log · 12.3 MiB / 45.6 MiB (27%) · 1.2 MiB/s · ETA 27s` */
+export function describeTransferProgress(p: TransferProgress): string {
+  const parts: string[] = [];
+  if (p.total > 0) {
+    parts.push(`${p.done + p.skipped + p.failed}/${p.total}${p.current ? ` ${p.current}` : ''}`);
+  }
+  if (p.rate && p.rate.bytes > 0) parts.push(formatTransferRate(p.rate));
+  return parts.join(' · ');
+}
+
+/** `, 12.3 MiB in 4.1s (3.0 MiB/s)` — 바이트 정보가 없으면 빈 문자열 */
+function fmtThroughput(s: TransferSummary): string {
+  if (!s.bytes) return '';
+  const sec = (s.elapsedMs ?? 0) / 1000;
+  const rate = sec > 0 ? ` (${fmtBytes(Math.round(s.bytes / sec))}/s)` : '';
+  return `, ${fmtBytes(s.bytes)} in ${sec.toFixed(1)}s${rate}`;
+}
+
+function defaultIllegalNamePolicy(): IllegalNamePolicy {
+  if (process.platform !== 'win32') return 'keep';
+  return process.env.EDGE_TOOL_PULL_ILLEGAL_NAMES === 'rename' ? 'rename' : 'skip';
 }
 
 export class FileTransferService implements IFileTransferService {
@@ -45,26 +258,34 @@ export class FileTransferService implements IFileTransferService {
   private toPosix(p: string) {
     return p.replace(/\\/g, '/');
   }
-  private sq(s: string) {
-    // POSIX single-quote safe: 'a'\''b'
-    return String(s).replace(/'/g, `'\\''`);
-  }
   private wrap(cmd: string) {
     // ADB: 이 레벨에선 감싸지 않음(이중 래핑 방지). ConnectionManager.run → adbShell()에서 한 번만 감쌈.
     // SSH: 로그인 셸(-lc)
     const flat = cmd.replace(/\n/g, ' ').trim();
     const t = this.cm.getSnapshot()?.active?.type;
     if (t === 'ADB') return flat;
-    return `sh -lc '${this.sq(flat)}'`;
+    return `sh -lc ${shellQuote(flat)}`;
+  }
+  private async remoteRun(cmd: string, signal?: AbortSignal) {
+    await this.cm.run(this.wrap(cmd), [], { signal });
   }
-  private async remoteRun(cmd: string) {
-    await this.cm.run(this.wrap(cmd));
+  private async remoteStream(cmd: string, onLine: (line: string) => void, signal?: AbortSignal) {
+    await this.cm.stream(this.wrap(cmd), onLine, signal);
   }
-  private async remoteStream(cmd: string, onLine: (line: string) => void) {
-    await this.cm.stream(this.wrap(cmd), onLine);
+  private async readRemoteFile(absPath: string, signal?: AbortSignal): Promise<Buffer> {
+    const lines: string[] = [];
+    await this.remoteStream(
+      `base64 ${shellQuote(absPath)}`,
+      (ln) => {
+        const t = String(ln ?? '').trim();
+        if (t) lines.push(t);
+      },
+      signal,
+    );
+    return Buffer.from(lines.join(''), 'base64');
   }
-  private async ensureRemoteDir(absDir: string) {
-    await this.remoteRun(`mkdir -p '${this.sq(absDir)}'`);
+  private async ensureRemoteDir(absDir: string, signal?: AbortSignal) {
+    await this.remoteRun(`mkdir -p ${shellQuote(absDir)}`, signal);
   }
   private chunkString(s: string, chunkLen = 48 * 1024) {
     const out: string[] = [];
@@ -73,7 +294,7 @@ export class FileTransferService implements IFileTransferService {
   }
   private printfAppendCmd(dst: string, payload: string) {
     // 작은 따옴표 안전하게 감싸서 printf %s '...' >> dst
-    return `printf %s '${this.sq(payload)}' >> '${this.sq(dst)}'`;
+    return `printf %s ${shellQuote(payload)} >> ${shellQuote(dst)}`;
   }
   /**
    * 로컬 기준(-C localDir)으로 tar에 넘길 안전한 상대 엔트리 목록 생성.
@@ -151,14 +372,79 @@ export class FileTransferService implements IFileTransferService {
     return Array.from(out);
   }
 
+  private fmtSummary(s: TransferSummary) {
+    const renamed = s.renamed ? `, ${s.renamed} renamed` : '';
+    const failed = s.failed ? `, ${s.failed} failed` : '';
+    return `[${s.done} ok, ${s.skipped} skipped${renamed}${failed}${fmtThroughput(s)}]`;
+  }
+
+  /** 다운로드 예상 총량(원격 `du -sk` 근사) — 진행률을 받을 때만, 실패하면 undefined */
+  private async remoteSizeBytes(remoteDir: string, rels: string[], signal?: AbortSignal) {
+    try {
+      const cmd = `cd ${shellQuote(remoteDir)} && du -sk ${this.quoteListPosix(rels)} 2>/dev/null`;
+      const { stdout } = await this.cm.run(this.wrap(cmd), [], { signal });
+      return parseDuKiB(stdout);
+    } catch {
+      return undefined;
+    }
+  }
+
+  /** 원격 find 결과(파일 + 빈 디렉터리)를 include/exclude로 걸러 tar 멤버 목록으로 */
+  private async listSelectedRemote(
+    remoteDir: string,
+    rels: string[],
+    select: (rel: string) => boolean,
+    signal?: AbortSignal,
+  ): Promise<string[]> {
+    const found: string[] = [];
+    const list = this.quoteListPosix(rels);
+    await this.remoteStream(
+      `cd ${shellQuote(remoteDir)} && find ${list} ! -type d -o -type d -empty`,
+      (ln) => {
+        const t = String(ln ?? '')
+          .trim()
+          .replace(/^\.\//, '');
+        if (t && t !== '.') found.push(t);
+      },
+      signal,
+    );
+    return found.filter(select);
+  }
+
+  /**
+   * tar -T 용 멤버 목록을 원격 임시 파일에 쓴다 — `printf '%s\n'` 한 번에 약 48KB씩 나눠 추가.
+   * '-'로 시작하는 이름은 GNU tar가 옵션으로 읽지 않도록 './'를 붙인다.
+   */
+  private async writeRemoteList(members: string[], signal?: AbortSignal): Promise<string> {
+    const dst = `/tmp/edge-list-${Date.now()}.txt`;
+    const batches: string[][] = [[]];
+    let size = 0;
+    for (const m of members) {
+      const q = shellQuote(m.startsWith('-') ? `./${m}` : m);
+      if (size + q.length > 48 * 1024 && batches[batches.length - 1].length) {
+        batches.push([]);
+        size = 0;
+      }
+      batches[batches.length - 1].push(q);
+      size += q.length + 1;
+    }
+    try {
+      await this.remoteRun(`: > ${shellQuote(dst)}`, signal);
+      for (const b of batches) {
+        throwIfAborted(signal);
+        await this.remoteRun(`printf '%s\\n' ${b.join(' ')} >> ${shellQuote(dst)}`, signal);
+      }
+    } catch (e) {
+      await this.remoteRun(`rm -f ${shellQuote(dst)}`).catch(() => {});
+      throw e;
+    }
+    return dst;
+  }
+
   // 인자 인용 유틸: 원격(POSIX 셸) / 로컬(cmd/쉘) 분리
   private quoteListPosix(list: string[]) {
     // 원격은 sh -c/-lc 안에서 단일따옴표 안전 인용
-    return list.map((p) => `'${this.sq(p)}'`).join(' ');
-  }
-  private quoteListLocal(list: string[]) {
-    // 로컬 runCommandLine용: 이스케이프 후 큰따옴표로 감쌈
-    return list.map((p) => `"${String(p).replace(/"/g, '\\"')}"`).join(' ');
+    return list.map((p) => shellQuote(p)).join(' ');
   }
   // ───────────────────────────────────────────────────────────
   // ADB 분기 헬퍼
@@ -172,70 +458,160 @@ export class FileTransferService implements IFileTransferService {
     return { serial, timeoutMs: DEFAULT_TRANSFER_TIMEOUT_MS };
   }
 
-  // ───────────────── ADB 업로드(파일 단위) ────────────────────
-  private async uploadViaAdb(localDir: string, remoteDir: string, paths?: string[]) {
+  /** 스킵 규칙으로 분리(keep/skipped 개수) */
+  private partition(relFiles: string[]) {
+    const keep = relFiles.filter((r) => !shouldSkipFile(r));
+    return { keep, summary: this.newSummary(relFiles.length, relFiles.length - keep.length) };
+  }
+  private newSummary(total: number, skipped: number): TransferSummary {
+    return { total, done: 0, skipped, failed: 0, renamed: 0 };
+  }
+
+  /**
+   * 다운로드 대상의 로컬 이름 결정 — Windows 금지 이름은 정책에 따라 스킵/개명.
+   * 반환: 로컬 상대경로 매핑(rel → localRel). 스킵/개명 수는 summary에 반영
+   */
+  private screenIllegalNames(keep: string[], summary: TransferSummary, opts?: UploadOpts) {
+    const policy = opts?.illegalNames ?? defaultIllegalNamePolicy();
+    const out = new Map<string, string>();
+    for (const rel of keep) {
+      if (policy === 'keep' || !isWindowsIllegalPath(rel)) {
+        out.set(rel, rel);
+      } else if (policy === 'rename') {
+        const to = sanitizeWindowsPath(rel);
+        this.log.warn(`[download] renamed (invalid name on Windows): ${rel} -> ${to}`);
+        out.set(rel, to);
+        summary.renamed++;
+      } else {
+        this.log.warn(`[download] skipped (invalid name on Windows): ${rel}`);
+        summary.skipped++;
+      }
+    }
+    return out;
+  }
+
+  /** 로컬 파일 목록(localDir 기준 상대경로). 심볼릭 링크 디렉터리는 실경로 visited로 루프 방지 */
+  private async collectLocalFiles(localDir: string, paths?: string[]): Promise<string[]> {
     const safeList = this.buildLocalTarList(localDir, paths); // 상대경로만
-    // '.' 이면 전체 walk
     const relFiles: string[] = [];
-    const walk = async (absDir: string, base: string) => {
+    const visited = new Set<string>();
+    const walk = async (absDir: string) => {
+      const real = await fsp.realpath(absDir).catch(() => absDir);
+      if (visited.has(real)) return;
+      visited.add(real);
       const ents = await fsp.readdir(absDir, { withFileTypes: true });
       for (const e of ents) {
         const abs = path.join(absDir, e.name);
-        const rel = this.toPosix(path.relative(base, abs));
-        if (e.isDirectory()) await walk(abs, base);
-        else if (e.isFile()) relFiles.push(rel);
+        const rel = this.toPosix(path.relative(localDir, abs));
+        const st = e.isSymbolicLink() ? await fsp.stat(abs).catch(() => undefined) : e;
+        if (st?.isDirectory()) await walk(abs);
+        else if (st?.isFile()) relFiles.push(rel);
       }
     };
-    if (safeList.length === 1 && safeList[0] === '.') {
-      await walk(localDir, localDir);
-    } else {
-      for (const rel of safeList) {
-        const abs = path.join(localDir, rel);
-        const st = await fsp.stat(abs);
-        if (st.isDirectory()) await walk(abs, abs.replace(/[\\/]+$/, ''));
-        else if (st.isFile()) relFiles.push(rel);
-      }
+    // '.' 이면 전체 walk
+    for (const rel of safeList) {
+      const abs = rel === '.' ? localDir : path.join(localDir, rel);
+      const st = await fsp.stat(abs);
+      if (st.isDirectory()) await walk(abs);
+      else if (st.isFile()) relFiles.push(rel);
     }
+    return relFiles;
+  }
+
+  // ───────────────── ADB 업로드(파일 단위) ────────────────────
+  private async uploadViaAdb(localDir: string, remoteDir: string, opts?: UploadOpts) {
+    const { keep, summary } = this.partition(await this.collectLocalFiles(localDir, opts?.paths));
     const adbOpts = this.getAdbOpts();
-    await this.remoteRun(`mkdir -p '${this.sq(remoteDir)}'`);
-    for (const rel of relFiles) {
+    await this.remoteRun(`mkdir -p ${shellQuote(remoteDir)}`, opts?.signal);
+    for (const rel of keep) {
+      if (opts?.signal?.aborted) break;
       const localFs = path.join(localDir, rel);
       const remoteFs = path.posix.join(remoteDir, rel);
-      await adbMkdirP(path.posix.dirname(remoteFs), adbOpts);
-      await adbPushFile(localFs, remoteFs, adbOpts);
+      try {
+        await adbMkdirP(path.posix.dirname(remoteFs), adbOpts);
+        await adbPushFile(localFs, remoteFs, adbOpts);
+        summary.done++;
+      } catch (e) {
+        summary.failed++;
+        this.log.warn(`[upload] skip failed file ${rel}: ${e instanceof Error ? e.message : e}`);
+      }
+      opts?.onProgress?.({ ...summary, current: rel });
     }
+    throwIfAborted(opts?.signal);
+    return summary;
   }
 
   // ───────────────── ADB 다운로드(파일 단위) ──────────────────
-  private async downloadViaAdb(remoteDir: string, localDir: string, paths?: string[]) {
+  private async downloadViaAdb(remoteDir: string, localDir: string, opts?: UploadOpts) {
     const adbOpts = this.getAdbOpts();
     let relFiles: string[] = [];
-    const safe = this.buildRemoteTarList(paths);
+    const safe = this.buildRemoteTarList(opts?.paths);
     if (safe.length === 1 && safe[0] === '.') {
       relFiles = await adbListFilesRec(remoteDir, adbOpts);
     } else {
-      const expanded: string[] = [];
-      for (const rel of safe) {
-        const rp = `${remoteDir.replace(/\/+$/, '')}/${rel}`;
-        const { stdout } = await this.cm.run(
-          this.wrap(`[ -d "${rp}" ] && echo DIR || { [ -f "${rp}" ] && echo FILE || echo NONE; }`),
-        );
-        const kind = (stdout || '').trim();
-        if (kind === 'FILE') expanded.push(rel);
-        else if (kind === 'DIR') {
-          const subs = await adbListFilesRec(rp, adbOpts);
-          for (const s of subs) expanded.push(this.toPosix(path.posix.join(rel, s)));
-        }
-      }
-      relFiles = expanded;
+      relFiles = await expandRemotePaths(
+        remoteDir,
+        safe,
+        async (abs) => {
+          const rp = shellQuote(abs);
+          const { stdout } = await this.cm.run(
+            this.wrap(`[ -d ${rp} ] && echo DIR || { [ -f ${rp} ] && echo FILE || echo NONE; }`),
+            [],
+            { signal: opts?.signal },
+          );
+          return (stdout || '').trim();
+        },
+        (abs) => adbListFilesRec(abs, adbOpts),
+      );
     }
+    // include/exclude: 고르지 않은 파일은 받지도, 스킵으로 집계하지도 않는다
+    const select = compilePathSelect(opts);
+    if (select) relFiles = relFiles.filter(select);
+    const { keep, summary } = this.partition(relFiles);
+    const names = this.screenIllegalNames(keep, summary, opts);
     await fsp.mkdir(localDir, { recursive: true });
-    for (const rel of relFiles) {
-      const remoteFs = path.posix.join(remoteDir, rel);
-      const localFs = path.join(localDir, rel);
-      await fsp.mkdir(path.dirname(localFs), { recursive: true });
-      await adbPullFile(remoteFs, localFs, adbOpts);
-    }
+    // 선택 조건이 있으면 디렉터리 du로는 총량을 알 수 없어 ETA 없이 진행
+    const totalBytes =
+      opts?.onProgress && !select
+        ? await this.remoteSizeBytes(remoteDir, safe, opts?.signal)
+        : undefined;
+    const meter = new TransferMeter(totalBytes);
+    // 파일 단위 pull을 제한된 동시성으로 — 요약 카운터는 단일 스레드 이벤트 루프라 안전
+    const concurrency = resolvePullConcurrency(opts?.concurrency);
+    this.log.debug(`[download] adb pull ${names.size} files, concurrency=${concurrency}`);
+    await forEachLimit(
+      Array.from(names),
+      concurrency,
+      async ([rel, localRel]) => {
+        const remoteFs = path.posix.join(remoteDir, rel);
+        const localFs = path.join(localDir, localRel);
+        // progress 이벤트는 파일별 누적값 → 증가분만 합산
+        let seen = 0;
+        const onBytes = (n: number) => {
+          meter.add(n - seen);
+          seen = n;
+          if (meter.shouldReport()) {
+            opts?.onProgress?.({ ...summary, current: rel, rate: meter.snapshot() });
+          }
+        };
+        try {
+          await fsp.mkdir(path.dirname(localFs), { recursive: true });
+          await adbPullFile(remoteFs, localFs, adbOpts, onBytes);
+          summary.done++;
+        } catch (e) {
+          summary.failed++;
+          this.log.warn(
+            `[download] skip failed file ${rel}: ${e instanceof Error ? e.message : e}`,
+          );
+        }
+        opts?.onProgress?.({ ...summary, current: rel, rate: meter.snapshot() });
+      },
+      opts?.signal,
+    );
+    throwIfAborted(opts?.signal);
+    summary.bytes = meter.snapshot().bytes;
+    summary.elapsedMs = meter.elapsedMs();
+    return summary;
   }
   // ───────────────────────────────────────────────────────────
 
@@ -243,24 +619,30 @@ export class FileTransferService implements IFileTransferService {
   async uploadViaTarBase64(
     localDir: string,
     remoteDir: string,
-    opts?: { timeoutMs?: number; signal?: AbortSignal; paths?: string[] },
-  ) {
+    opts?: UploadOpts,
+  ): Promise<TransferSummary> {
     this.log.debug('[debug] FileTransferService uploadViaTarBase64: start');
     // ADB면 tar/base64 경로를 쓰지 않고 adbkit 스트림으로 전환
     if (this.isAdb()) {
-      await this.uploadViaAdb(localDir, remoteDir, opts?.paths);
-      this.log.info(`upload(adb): ${localDir} -> ${remoteDir}`);
-      return;
+      const s = await this.uploadViaAdb(localDir, remoteDir, opts);
+      this.log.info(`upload(adb): ${localDir} -> ${remoteDir} ${this.fmtSummary(s)}`);
+      return s;
     }
     const timeoutMs = opts?.timeoutMs ?? DEFAULT_TRANSFER_TIMEOUT_MS;
     try {
-      // 1) tar 생성 (로컬)
+      // 1) tar 생성 (로컬) — 스킵 규칙 적용된 파일 목록을 -T 로 전달
       const safeList = this.buildLocalTarList(localDir, opts?.paths);
-      const list = this.quoteListLocal(safeList);
+      const { keep, summary } = this.partition(await this.collectLocalFiles(localDir, opts?.paths));
+      if (keep.length === 0) {
+        this.log.info(`upload: nothing to send from ${localDir} ${this.fmtSummary(summary)}`);
+        return summary;
+      }
       const tmpDir = await fsp.mkdtemp(path.join(os.tmpdir(), 'edge-up-'));
       const tarPath = path.join(tmpDir, 'payload.tar');
+      const listPath = path.join(tmpDir, 'files.txt');
       try {
-        await runCommandLine(`tar -C "${localDir}" -cf "${tarPath}" ${list}`, {
+        await fsp.writeFile(listPath, keep.join('\n') + '\n', 'utf8');
+        await runCommandLine(`tar -C "${localDir}" -cf "${tarPath}" -T "${listPath}"`, {
           timeoutMs,
           signal: opts?.signal,
         });
@@ -269,26 +651,41 @@ export class FileTransferService implements IFileTransferService {
         const chunks = this.chunkString(b64, 48 * 1024);
 
         // 2) 원격 준비
-        await this.ensureRemoteDir(remoteDir);
+        const signal = opts?.signal;
+        await this.ensureRemoteDir(remoteDir, signal);
         const remoteTmp = `/tmp/edge-upload-${Date.now()}.b64`;
-        // truncate
-        await this.remoteRun(`: > '${this.sq(remoteTmp)}'`);
-        // append in chunks
-        for (const ch of chunks) {
-          await this.remoteRun(this.printfAppendCmd(remoteTmp, ch));
+        try {
+          // truncate
+          await this.remoteRun(`: > ${shellQuote(remoteTmp)}`, signal);
+          // append in chunks
+          for (const ch of chunks) {
+            throwIfAborted(signal);
+            await this.remoteRun(this.printfAppendCmd(remoteTmp, ch), signal);
+          }
+          // 3) decode & extract
+          await this.remoteRun(
+            `base64 -d ${shellQuote(remoteTmp)} | tar -C ${shellQuote(remoteDir)} -xpf - && rm -f ${shellQuote(remoteTmp)}`,
+            signal,
+          );
+        } catch (e) {
+          // 중단/실패 시 원격 스테이징 파일 정리(마감 신호와 무관하게 시도)
+          await this.remoteRun(`rm -f ${shellQuote(remoteTmp)}`).catch(() => {});
+          throw e;
         }
-        // 3) decode & extract
-        await this.remoteRun(
-          `base64 -d '${this.sq(remoteTmp)}' | tar -C '${this.sq(remoteDir)}' -xpf - && rm -f '${this.sq(remoteTmp)}'`,
+        summary.done = keep.length;
+        opts?.onProgress?.({ ...summary });
+        this.log.info(
+          `upload: ${localDir} -> ${remoteDir} (${safeList.join(', ') || '.'}) ${this.fmtSummary(summary)}`,
         );
-        this.log.info(`upload: ${localDir} -> ${remoteDir} (${safeList.join(', ') || '.'})`);
       } finally {
         try {
           await fsp.rm(tmpDir, { recursive: true, force: true });
         } catch {}
       }
       this.log.debug('[debug] FileTransferService uploadViaTarBase64: end');
+      return summary;
     } catch (e) {
+      throwIfAborted(opts?.signal);
       throw new XError(
         ErrorCategory.Connection,
         `Upload failed: ${e instanceof Error ? e.message : String(e)}`,
@@ -301,52 +698,130 @@ export class FileTransferService implements IFileTransferService {
   async downloadViaTarBase64(
     remoteDir: string,
     localDir: string,
-    opts?: { timeoutMs?: number; signal?: AbortSignal; paths?: string[] },
-  ) {
+    opts?: UploadOpts,
+  ): Promise<TransferSummary> {
     this.log.debug('[debug] FileTransferService downloadViaTarBase64: start');
     //  ADB면 tar/base64 경로 대신 adbkit 스트림
     if (this.isAdb()) {
-      await this.downloadViaAdb(remoteDir, localDir, opts?.paths);
-      this.log.info(`download(adb): ${remoteDir} -> ${localDir}`);
-      return;
+      const s = await this.downloadViaAdb(remoteDir, localDir, opts);
+      this.log.info(`download(adb): ${remoteDir} -> ${localDir} ${this.fmtSummary(s)}`);
+      return s;
     }
     try {
       const timeoutMs = opts?.timeoutMs ?? DEFAULT_TRANSFER_TIMEOUT_MS;
 
       // ✅ 강제 상대 엔트리(+ 경계 방어)
       const safeList = this.buildRemoteTarList(opts?.paths);
-      const list = this.quoteListPosix(safeList);
+      const select = compilePathSelect(opts);
+      // include/exclude는 원격 tar 단계에서 적용 → 고르지 않은 파일은 전송하지 않는다
+      const members = select
+        ? await this.listSelectedRemote(remoteDir, safeList, select, opts?.signal)
+        : safeList;
+      if (!members.length) {
+        this.log.info(`[download] nothing selected in ${remoteDir} (${safeList.join(', ')})`);
+        return this.newSummary(0, 0);
+      }
 
-      // 1) 원격에서 base64 생성 (ssh2/adb stream 사용)
+      // 1) 원격에서 base64 생성 (ssh2/adb stream 사용) — 받은 글자 수로 바이트 진행률 계산
+      // 선택 조건이 있으면 디렉터리 du로는 총량을 알 수 없어 ETA 없이 진행
+      const totalBytes =
+        opts?.onProgress && !select
+          ? await this.remoteSizeBytes(remoteDir, safeList, opts?.signal)
+          : undefined;
+      const meter = new TransferMeter(totalBytes);
       const lines: string[] = [];
-      await this.remoteStream(`tar -C '${this.sq(remoteDir)}' -cf - ${list} | base64`, (ln) => {
-        const t = String(ln ?? '').trim();
-        if (t) lines.push(t);
-      });
+      // 선택된 멤버는 수천 개일 수 있어 명령 인자 대신 원격 임시 목록(-T)으로 넘긴다
+      const listTmp = select ? await this.writeRemoteList(members, opts?.signal) : undefined;
+      const from = listTmp ? `-T ${shellQuote(listTmp)}` : `-- ${this.quoteListPosix(members)}`;
+      // 스킵 이름(.git, node_modules 등)은 원격 tar에서 빼서 받지도 않는다
+      const skip = TRANSFER_SKIP_NAMES.map((n) => `--exclude=${shellQuote(n)}`).join(' ');
+      try {
+        await this.remoteStream(
+          `tar -C ${shellQuote(remoteDir)} ${skip} -cf - ${from} | base64`,
+          (ln) => {
+            const t = String(ln ?? '').trim();
+            if (!t) return;
+            lines.push(t);
+            meter.add((t.length * 3) / 4);
+            if (meter.shouldReport()) {
+              opts?.onProgress?.({ ...this.newSummary(0, 0), rate: meter.snapshot() });
+            }
+          },
+          opts?.signal,
+        );
+      } finally {
+        if (listTmp) await this.remoteRun(`rm -f ${shellQuote(listTmp)}`).catch(() => {});
+      }
+      throwIfAborted(opts?.signal);
       const b64 = lines.join('');
       if (!b64) {
         this.log.info(`[download] empty archive from ${remoteDir} (${safeList.join(', ') || '.'})`);
-        return;
+        return this.newSummary(0, 0);
       }
 
       // 2) 로컬에 임시 tar 저장 후 풀기
       const tmpDir = await fsp.mkdtemp(path.join(os.tmpdir(), 'edge-down-'));
       const tarPath = path.join(tmpDir, 'payload.tar');
+      let summary = this.newSummary(0, 0);
       try {
-        await fsp.writeFile(tarPath, Buffer.from(b64, 'base64'));
+        const payload = Buffer.from(b64, 'base64');
+        const elapsedMs = meter.elapsedMs();
+        await fsp.writeFile(tarPath, payload);
         await fsp.mkdir(localDir, { recursive: true });
-        await runCommandLine(`tar -C "${localDir}" -xpf "${tarPath}"`, {
+        // 아카이브 목록에서 스킵 규칙 적용 → 남은 파일만 -T 로 추출
+        const { stdout } = await runCommandLine(`tar -tf "${tarPath}"`, {
           timeoutMs,
           signal: opts?.signal,
         });
-        this.log.info(`[download] ${remoteDir} -> ${localDir} (${safeList.join(', ') || '.'})`);
+        const archived = stdout
+          .split(/\r?\n/)
+          .map((s) => s.trim())
+          .filter((s) => s && (!select || select(s)));
+        const entries = archived.filter((s) => !s.endsWith('/'));
+        const part = this.partition(entries);
+        summary = part.summary;
+        const names = this.screenIllegalNames(part.keep, summary, opts);
+        const keep = [...names].filter(([rel, to]) => rel === to).map(([rel]) => rel);
+        const renamed = [...names].filter(([rel, to]) => rel !== to);
+        if (keep.length) {
+          const listPath = path.join(tmpDir, 'files.txt');
+          await fsp.writeFile(listPath, keep.join('\n') + '\n', 'utf8');
+          await runCommandLine(`tar -C "${localDir}" -xpf "${tarPath}" -T "${listPath}"`, {
+            timeoutMs,
+            signal: opts?.signal,
+          });
+        }
+        // -T 목록은 파일만 담으므로 빈 디렉터리는 따로 만든다(스킵 규칙/이름 정책은 파일과 동일)
+        const policy = opts?.illegalNames ?? defaultIllegalNamePolicy();
+        for (const dir of archived.filter((s) => s.endsWith('/') && !shouldSkipFile(s))) {
+          const illegal = policy !== 'keep' && isWindowsIllegalPath(dir);
+          if (illegal && policy === 'skip') continue;
+          const rel = illegal ? sanitizeWindowsPath(dir) : dir;
+          await fsp.mkdir(path.join(localDir, rel), { recursive: true });
+        }
+        // 개명 대상은 로컬 tar 추출이 불가하므로 원격에서 파일 단위로 받아 새 이름으로 저장
+        for (const [rel, to] of renamed) {
+          const buf = await this.readRemoteFile(path.posix.join(remoteDir, rel), opts?.signal);
+          const localFs = path.join(localDir, to);
+          await fsp.mkdir(path.dirname(localFs), { recursive: true });
+          await fsp.writeFile(localFs, buf);
+        }
+        summary.done = keep.length + renamed.length;
+        summary.bytes = payload.length;
+        summary.elapsedMs = elapsedMs;
+        opts?.onProgress?.({ ...summary, rate: meter.snapshot(true) });
+        this.log.info(
+          `[download] ${remoteDir} -> ${localDir} (${safeList.join(', ') || '.'}) ${this.fmtSummary(summary)}`,
+        );
       } finally {
         try {
           await fsp.rm(tmpDir, { recursive: true, force: true });
         } catch {}
       }
       this.log.debug('[debug] FileTransferService downloadViaTarBase64: end');
+      return summary;
     } catch (e) {
+      throwIfAborted(opts?.signal);
       throw new XError(
         ErrorCategory.Connection,
         `Download failed: ${e instanceof Error ? e.message : String(e)}`,
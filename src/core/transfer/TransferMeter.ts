@@ -0,0 +1,100 @@
+// === src/core/transfer/TransferMeter.ts ===
+/**
+ * 전송 바이트 계측(진행률/속도/ETA)
+ * - ADB: adbkit pull 스트림의 progress 이벤트(bytesTransferred)
+ * - SSH: `tar | base64` 스트림에서 받은 base64 글자 수 × 3/4
+ * - 총량은 원격 `du -sk` 근사치(블록 단위/tar 헤더로 오차) → 완료 전에는 99%에서 멈춘다.
+ * - onProgress가 라인마다 불리지 않도록 REPORT_INTERVAL_MS 간격으로만 보고
+ */
+export const TRANSFER_REPORT_INTERVAL_MS = 250;
+
+export type TransferRate = {
+  bytes: number;
+  totalBytes?: number;
+  /** 시작 이후 평균 속도(bytes/s), 측정 전이면 undefined */
+  bytesPerSec?: number;
+  /** 남은 시간(ms), 총량/속도를 모르면 undefined */
+  etaMs?: number;
+  /** 0~100, 총량을 모르면 undefined */
+  percent?: number;
+};
+
+export class TransferMeter {
+  private bytes = 0;
+  private readonly startedAt: number;
+  private lastReportAt = 0;
+
+  constructor(
+    public totalBytes?: number,
+    private now: () => number = Date.now,
+  ) {
+    this.startedAt = now();
+  }
+
+  add(n: number) {
+    if (n > 0) this.bytes += n;
+  }
+
+  /** 보고 간격이 지났으면 true(그리고 타이머 갱신) */
+  shouldReport(): boolean {
+    const t = this.now();
+    if (t - this.lastReportAt < TRANSFER_REPORT_INTERVAL_MS) return false;
+    this.lastReportAt = t;
+    return true;
+  }
+
+  elapsedMs(): number {
+    return Math.max(0, this.now() - this.startedAt);
+  }
+
+  snapshot(done = false): TransferRate {
+    const elapsed = this.elapsedMs();
+    const bytesPerSec = elapsed > 0 && this.bytes > 0 ? (this.bytes * 1000) / elapsed : undefined;
+    const total = this.totalBytes && this.totalBytes > 0 ? this.totalBytes : undefined;
+    if (!total) return { bytes: this.bytes, bytesPerSec };
+    const percent = done ? 100 : Math.min(99, Math.floor((this.bytes / total) * 100));
+    const left = Math.max(0, total - this.bytes);
+    const etaMs = done ? 0 : bytesPerSec ? (left / bytesPerSec) * 1000 : undefined;
+    return { bytes: this.bytes, totalBytes: total, bytesPerSec, etaMs, percent };
+  }
+}
+
+export function fmtBytes(n: number): string {
+  if (n < 1024) return `${Math.round(n)} B`;
+  if (n < 1024 * 1024) return `${(n / 1024).toFixed(1)} KiB`;
+  if (n < 1024 * 1024 * 1024) return `${(n / 1024 / 1024).toFixed(1)} MiB`;
+  return `${(n / 1024 / 1024 / 1024).toFixed(2)} GiB`;
+}
+
+function fmtEta(ms: number): string {
+  const s = Math.ceil(ms / 1000);
+  if (s < 60) return `${s}s`;
+  const m = Math.floor(s / 60);
+  return m < 60 ? `${m}m${String(s % 60).padStart(2, '0')}s` : `${Math.floor(m / 60)}h${m % 60}m`;
+}
+
+/** `12.3 MiB / 45.6 MiB (27%) · 1.2 MiB/s · ETA 27s` (모르는 항목은 생략) */
+export function formatTransferRate(r: TransferRate): string {
+  const parts: string[] = [];
+  parts.push(
+    r.totalBytes
+      ? `${fmtBytes(r.bytes)} / ${fmtBytes(r.totalBytes)} (${r.percent ?? 0}%)`
+      : fmtBytes(r.bytes),
+  );
+  if (r.bytesPerSec) parts.push(`${fmtBytes(Math.round(r.bytesPerSec))}/s`);
+  if (r.etaMs !== undefined && r.percent !== 100) parts.push(`ETA ${fmtEta(r.etaMs)}`);
+  return parts.join(' · ');
+}
+
+/** `du -sk` 출력(여러 줄 가능) → bytes 합계. 숫자가 하나도 없으면 undefined */
+export function parseDuKiB(stdout: string): number | undefined {
+  let sum = 0;
+  let any = false;
+  for (const line of String(stdout ?? '').split(/\r?\n/)) {
+    const m = /^\s*(\d+)\s/.exec(line + ' ');
+    if (!m) continue;
+    sum += Number(m[1]) * 1024;
+    any = true;
+  }
+  return any ? sum : undefined;
+}
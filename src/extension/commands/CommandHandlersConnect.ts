@@ -2,24 +2,53 @@
 import * as vscode from 'vscode';
 
 import {
+  type AdbDetails,
+  applyConnectionEdit,
+  type ConnectionConfigFile,
+  type ConnectionField,
   type ConnectionInfo,
+  connectionTarget,
+  editableFields,
+  findConnection,
+  formatConnectionTable,
+  formatFieldValue,
+  formatLastUsed,
+  inspectConnectionConfig,
   markRecent,
   readConnectionConfig,
+  redactConnectionConfig,
   saveConnectionConfig,
+  setDefaultConnection,
+  type SshDetails,
+  startupConnection,
   upsertConnection,
+  validateConnectionConfig,
 } from '../../core/config/connection-config.js';
+import {
+  listSshConfigAliases,
+  readUserSshConfig,
+  resolveSshTarget,
+  userSshConfigPath,
+} from '../../core/config/ssh-config.js';
 import { getCurrentWorkspacePathFs } from '../../core/config/userdata.js';
 import {
+  adbStateHint,
   getState as adbGetState,
   listDevices as adbListDevices,
+  waitForDevice as adbWaitForDevice,
 } from '../../core/connection/adbClient.js';
 import { connectionManager } from '../../core/connection/ConnectionManager.js';
 import { execQuickCheck as sshQuickCheck } from '../../core/connection/sshClient.js';
 import { getLogger } from '../../core/logging/extension-logger.js';
 import { measure } from '../../core/logging/perf.js';
+import { MAX_SSH_PORT, MIN_SSH_PORT } from '../../shared/const.js';
+import { CommandFailedError } from '../../shared/errors.js';
 
 const log = getLogger('cmd.connect');
 
+/** unauthorized/offline 기기를 기다려 주는 최대 시간(RSA 허용, 케이블 재연결) */
+const ADB_READY_WAIT_MS = 60_000;
+
 export class CommandHandlersConnect {
   constructor(private context?: vscode.ExtensionContext) {
     // ConnectionManager가 recent 자동 활성화를 할 수 있도록 로더 등록
@@ -28,8 +57,14 @@ export class CommandHandlersConnect {
         try {
           const base = await getCurrentWorkspacePathFs(this.context!);
           const cfg = await readConnectionConfig(base);
-          if (!cfg.recent) return undefined;
-          return cfg.connections.find((c) => c.id === cfg.recent);
+          // EDGE_TOOL_CONNECTION=<alias|id>: 시작 시 대상 기기를 고정(스크립트/다중 기기용)
+          const pinned = String(process.env.EDGE_TOOL_CONNECTION ?? '').trim();
+          if (pinned) {
+            const hit = findConnection(cfg, pinned);
+            if (hit) return hit;
+            log.warn(`[warn] EDGE_TOOL_CONNECTION: no saved connection '${pinned}'`);
+          }
+          return startupConnection(cfg);
         } catch {
           return undefined;
         }
@@ -41,7 +76,6 @@ export class CommandHandlersConnect {
   @measure()
   async connectDevice() {
     const base = await this._resolveWorkspacePath();
-    if (!base) return;
     const cfg = await readConnectionConfig(base);
 
     const pick = await vscode.window.showQuickPick(
@@ -60,20 +94,283 @@ export class CommandHandlersConnect {
     }
   }
 
+  /**
+   * 저장된 연결을 alias/ID로 바로 활성화(프롬프트 없음) — `connect <alias|id>`
+   * - 도달 가능 여부(ADB 상태/SSH 접속)를 확인한 뒤 최근 항목으로 저장하고 활성화한다.
+   */
+  @measure()
+  async connectByAlias(args = '') {
+    const key = args.trim().replace(/^"(.*)"$/, '$1');
+    if (!key) throw new CommandFailedError('connect <alias|id>');
+    const base = await this._resolveWorkspacePath();
+    const cfg = await readConnectionConfig(base);
+    const selected = findConnection(cfg, key);
+    if (!selected) {
+      const known = cfg.connections.map((c) => c.alias || c.id).join(', ') || '(없음)';
+      throw new CommandFailedError(`connect: 저장된 연결이 없습니다: ${key} — 저장된 연결: ${known}`);
+    }
+    await this._activate(base, cfg, selected);
+    return { id: selected.id, alias: selected.alias, type: selected.type };
+  }
+
+  /**
+   * 시작 시 자동 연결 대상(기본 연결) 지정/해제 — 연결하지 않고 설정만 저장
+   * - 인자 없음: 저장된 연결 목록에서 선택(해제 항목 포함)
+   * - <alias|id>: 해당 연결을 기본으로 / --clear: 기본·최근 포인터 해제
+   */
+  @measure()
+  async setDefaultConnection(args = '') {
+    const base = await this._resolveWorkspacePath();
+    const cfg = await readConnectionConfig(base);
+    const key = args.trim().replace(/^"(.*)"$/, '$1');
+
+    let id: string | undefined;
+    if (key === '--clear') {
+      id = undefined;
+    } else if (key) {
+      const hit = findConnection(cfg, key);
+      if (!hit) throw new CommandFailedError(`connectDefault: 저장된 연결이 없습니다: ${key}`);
+      id = hit.id;
+    } else {
+      if (!cfg.connections.length) {
+        vscode.window.showInformationMessage('저장된 연결이 없습니다.');
+        return;
+      }
+      const clear = '(자동 연결 안 함)';
+      const pick = await vscode.window.showQuickPick(
+        [
+          ...cfg.connections.map((c) => ({
+            label: c.alias || c.id,
+            description: `${c.type}${cfg.default === c.id ? ' · 기본' : ''}`,
+            detail: c.id,
+          })),
+          { label: clear, description: '기본/최근 연결 해제', detail: '' },
+        ],
+        { placeHolder: `시작 시 자동 연결할 기기 (현재: ${cfg.default ?? cfg.recent ?? '없음'})` },
+      );
+      if (!pick) return;
+      id = pick.label === clear ? undefined : pick.detail;
+    }
+
+    setDefaultConnection(cfg, id);
+    await saveConnectionConfig(base, cfg);
+    log.info(`[info] connectDefault: ${id ?? '(cleared)'}`);
+    vscode.window.showInformationMessage(
+      id ? `기본 연결 지정: ${id}` : '기본/최근 연결을 해제했습니다(시작 시 자동 연결 안 함).',
+    );
+    return { default: id ?? null };
+  }
+
+  /**
+   * 연결 정보(정적 목록, 도달 확인 없음) — `connectInfo [--all]`
+   * - 인자 없음: 현재 활성 연결 / --all: 저장된 연결 전체 표(비밀번호는 가림)
+   */
+  @measure()
+  async connectInfo(args = '') {
+    const all = args.trim();
+    if (all && all !== '--all') throw new CommandFailedError('connectInfo [--all]');
+    const base = await this._resolveWorkspacePath();
+    const cfg = await readConnectionConfig(base);
+    const active = connectionManager.getSnapshot()?.active;
+
+    // 반환값은 `connectInfo --json`의 result(비밀번호 등 details는 넣지 않음)
+    const summary = (c: ConnectionInfo) => ({
+      id: c.id,
+      alias: c.alias,
+      type: c.type,
+      target: connectionTarget(c),
+      lastUsed: cfg.connections.find((x) => x.id === c.id)?.lastUsed ?? c.lastUsed,
+      active: c.id === active?.id,
+    });
+    if (all) {
+      const lines = cfg.connections.length
+        ? formatConnectionTable(cfg, active?.id)
+        : ['저장된 연결이 없습니다.'];
+      for (const line of lines) log.result(`[connect.info] ${line}`);
+      return cfg.connections.map(summary);
+    }
+    if (!active) {
+      log.result('[connect.info] 활성 연결 없음 (저장 목록: connectInfo --all)');
+      return null;
+    }
+    const info = summary(active);
+    log.result(`[connect.info] ${active.alias || '-'} ${active.type} ${info.target}`);
+    log.result(`[connect.info] id=${active.id} lastUsed=${formatLastUsed(info.lastUsed)}`);
+    return info;
+  }
+
+  /**
+   * 연결 설정 파일 점검 — `connectConfig show|validate`
+   * - show: 파싱된 설정(비밀번호 가림)을 그대로 출력
+   * - validate: 불변식 점검 결과(문제마다 한 줄, 고치는 방법 포함)
+   * - 깨진 파일도 덮어쓰지 않고 그대로 보고한다(connect 계열은 빈 설정으로 다시 만든다)
+   */
+  @measure()
+  async connectConfig(args = '') {
+    const sub = args.trim() || 'show';
+    if (sub !== 'show' && sub !== 'validate') {
+      throw new CommandFailedError('connectConfig [show|validate]');
+    }
+    const base = await this._resolveWorkspacePath();
+    const { file, cfg, error } = await inspectConnectionConfig(base);
+    log.result(`[connect.config] ${file}`);
+    if (!cfg) throw new CommandFailedError(`connectConfig: ${error}`);
+
+    if (sub === 'show') {
+      const redacted = redactConnectionConfig(cfg);
+      for (const line of JSON.stringify(redacted, null, 2).split('\n')) {
+        log.result(`[connect.config] ${line}`);
+      }
+      return redacted;
+    }
+    const problems = validateConnectionConfig(cfg);
+    if (!problems.length) {
+      const n = Array.isArray(cfg.connections) ? cfg.connections.length : 0;
+      log.result(`[connect.config] OK — 연결 ${n}개, 문제 없음`);
+      return { problems };
+    }
+    for (const p of problems) log.result(`[connect.config] ✗ ${p}`);
+    throw new CommandFailedError(`connectConfig: 문제 ${problems.length}건`, problems);
+  }
+
+  /**
+   * 활성 SSH 연결의 sudo 사용 설정(useSudo) 변경 후 저장
+   * - 인자: on | off (없으면 토글)
+   * - 켜면 mount/devtoken/unmount 등 모든 원격 명령이 `sudo -n`으로 실행된다.
+   */
+  @measure()
+  async setSshSudo(args = '') {
+    const active = connectionManager.getSnapshot()?.active;
+    if (!active || active.type !== 'SSH') {
+      vscode.window.showErrorMessage('활성 SSH 연결이 없습니다. 먼저 SSH로 연결하세요.');
+      throw new CommandFailedError('활성 SSH 연결이 없습니다.', undefined, true);
+    }
+    const arg = args.trim().toLowerCase();
+    if (arg && arg !== 'on' && arg !== 'off') throw new CommandFailedError('hostSudo [on|off]');
+    const details = active.details as SshDetails;
+    const useSudo = arg ? arg === 'on' : !details.useSudo;
+
+    const base = await this._resolveWorkspacePath();
+    const cfg = await readConnectionConfig(base);
+    const entry: ConnectionInfo = { ...active, details: { ...details, useSudo } };
+    upsertConnection(cfg, entry);
+    await saveConnectionConfig(base, cfg);
+    connectionManager.setActive(entry);
+
+    const note =
+      useSudo && details.user === 'root' ? ' (root 로그인이라 실제로는 감싸지 않음)' : '';
+    log.info(`[info] hostSudo: ${active.id} useSudo=${useSudo}${note}`);
+    vscode.window.showInformationMessage(
+      `SSH sudo 사용: ${useSudo ? '켜짐' : '꺼짐'} — ${active.alias || active.id}${note}`,
+    );
+    return { id: active.id, useSudo };
+  }
+
+  /**
+   * 저장된 연결 편집 — `connectEdit [<alias|id>]`(인자 없으면 목록에서 선택)
+   * - 메뉴는 editableFields()에서 만든다: 필드를 고르고 값 입력(선택 필드는 비우면 삭제)
+   * - host/user/port/serial이 바뀌면 id도 새로 만들고 recent/default를 옮긴다
+   * - 편집한 연결이 활성 연결이면 바로 새 설정으로 다시 활성화
+   */
+  @measure()
+  async editConnection(args = '') {
+    const base = await this._resolveWorkspacePath();
+    const cfg = await readConnectionConfig(base);
+    if (!cfg.connections.length) return log.result('[connect.edit] 저장된 연결이 없습니다.');
+
+    let target = args.trim() ? findConnection(cfg, args) : undefined;
+    if (args.trim() && !target) {
+      throw new CommandFailedError(`connectEdit: 연결 없음 '${args.trim()}'`);
+    }
+    if (!target) {
+      const pick = await vscode.window.showQuickPick(
+        cfg.connections.map((c) => ({
+          label: c.alias || c.id,
+          description: `${c.type} · ${connectionTarget(c)}`,
+          id: c.id,
+        })),
+        { placeHolder: '편집할 연결을 선택하세요' },
+      );
+      target = pick && cfg.connections.find((c) => c.id === pick.id);
+      if (!target) return;
+    }
+
+    const draft: ConnectionInfo = JSON.parse(JSON.stringify(target));
+    const SAVE = '$(check) 저장';
+    for (;;) {
+      const fields = editableFields(draft);
+      const pick = await vscode.window.showQuickPick(
+        [
+          ...fields.map((f) => ({ label: f.label, description: formatFieldValue(f, draft), f })),
+          { label: SAVE, description: connectionTarget(draft), f: undefined },
+        ],
+        { placeHolder: `${draft.alias || draft.id} — 고칠 항목을 선택하세요(Esc: 취소)` },
+      );
+      if (!pick) return;
+      if (!pick.f) break;
+      await this._editField(pick.f, draft);
+    }
+
+    let saved: ConnectionInfo;
+    try {
+      saved = applyConnectionEdit(cfg, target.id, draft);
+    } catch (e: any) {
+      vscode.window.showErrorMessage(`연결을 저장할 수 없습니다: ${e?.message || e}`);
+      throw new CommandFailedError(`connectEdit: ${e?.message || e}`, e, true);
+    }
+    await saveConnectionConfig(base, cfg);
+    if (connectionManager.getSnapshot()?.active?.id === target.id) {
+      connectionManager.setActive(saved);
+    }
+    const renamed = saved.id !== target.id ? ` (id ${target.id} → ${saved.id})` : '';
+    log.info(`[info] connectEdit: ${saved.id}${renamed}`);
+    vscode.window.showInformationMessage(`연결 저장: ${saved.alias || saved.id}${renamed}`);
+    return { id: saved.id, previousId: target.id };
+  }
+
+  /** 필드 하나 입력받아 draft에 반영(취소하면 그대로) */
+  private async _editField(f: ConnectionField, draft: ConnectionInfo) {
+    if (f.kind === 'flag') {
+      f.set(draft, !f.get(draft));
+      return;
+    }
+    const current = f.get(draft);
+    const v = await vscode.window.showInputBox({
+      prompt: f.optional ? `${f.label} (비우면 삭제)` : f.label,
+      value: f.kind === 'secret' ? '' : String(current ?? ''),
+      password: f.kind === 'secret',
+      placeHolder: f.kind === 'secret' && current ? '(저장됨) 새 값 입력 또는 비워서 삭제' : undefined,
+      ignoreFocusOut: true,
+      validateInput(s) {
+        if (!s.trim() && !f.optional) return '필수 항목입니다';
+        if (!s.trim()) return undefined;
+        const n = Number(s);
+        if (f.kind === 'count') return Number.isInteger(n) && n >= 0 ? undefined : '0 이상의 정수';
+        if (f.kind !== 'port') return undefined;
+        return !Number.isInteger(n) || n < MIN_SSH_PORT || n > MAX_SSH_PORT
+          ? `${MIN_SSH_PORT}~${MAX_SSH_PORT} 숫자`
+          : undefined;
+      },
+    });
+    if (v === undefined) return;
+    f.set(draft, f.kind === 'secret' ? v : v.trim());
+  }
+
   // ─────────────────────────────────────────────────────────────
   // 내부 구현
   // ─────────────────────────────────────────────────────────────
-  private async _resolveWorkspacePath(): Promise<string | undefined> {
+  /** 작업폴더 경로 — 확인할 수 없으면 알리고 실패로 던진다 */
+  private async _resolveWorkspacePath(): Promise<string> {
     if (!this.context) {
       vscode.window.showErrorMessage('확장 컨텍스트가 없습니다.');
-      return;
+      throw new CommandFailedError('확장 컨텍스트가 없습니다.', undefined, true);
     }
     try {
       return await getCurrentWorkspacePathFs(this.context);
     } catch (e) {
       log.error('workspace path resolve failed', e as any);
       vscode.window.showErrorMessage('작업폴더를 확인할 수 없습니다.');
-      return;
+      throw new CommandFailedError('작업폴더를 확인할 수 없습니다.', e, true);
     }
   }
 
@@ -89,22 +386,21 @@ export class CommandHandlersConnect {
         let ok = false,
           status = '';
         if (c.type === 'ADB') {
-          ok = (await adbGetState((c.details as any).deviceID)) === 'device';
-          status = ok ? '정상(ADB)' : '오프라인/미인증(ADB)';
+          const state = await adbGetState((c.details as any).deviceID);
+          ok = state === 'device';
+          status = ok ? '정상(ADB)' : adbStateHint(state);
         } else {
-          const d = c.details as any;
-          ok = await sshQuickCheck({
-            host: d.host,
-            user: d.user,
-            port: d.port,
-            password: d.password,
-            timeoutMs: 5000,
-          });
-          status = ok ? '정상(SSH)' : d.password ? '오프라인/인증실패(SSH)' : '비밀번호 없음';
+          const d = c.details as SshDetails;
+          ok = await this._sshReachable(d, 5000);
+          status = ok
+            ? '정상(SSH)'
+            : d.password || d.configAlias
+              ? '오프라인/인증실패(SSH)'
+              : '비밀번호 없음';
         }
         return {
           label,
-          description: `${c.type} · ${status}`,
+          description: `${c.type} · ${status}${cfg.default === c.id ? ' · 기본' : ''}`,
           detail: c.id,
           picked: cfg.recent === c.id,
         } as vscode.QuickPickItem & { detail: string };
@@ -117,16 +413,24 @@ export class CommandHandlersConnect {
     if (!chosen) return;
     const selected = cfg.connections.find((c: any) => c.id === (chosen as any).detail);
     if (!selected) return;
+    await this._activate(base, cfg, selected);
+  }
 
-    let ok = false;
-    if (selected.type === 'ADB') ok = (await adbGetState(selected.details.deviceID)) === 'device';
-    else ok = await sshQuickCheck(selected.details);
-
-    if (!ok) {
+  /** 도달 확인 → 최근 항목 저장 → ConnectionManager 활성화 */
+  private async _activate(base: string, cfg: ConnectionConfigFile, selected: ConnectionInfo) {
+    if (selected.type === 'ADB') {
+      const serial = (selected.details as AdbDetails).deviceID;
+      const state = await adbGetState(serial);
+      // 사유 안내와 대기는 _offerAdbWait에서 처리
+      if (state !== 'device' && !(await this._offerAdbWait(serial, state))) {
+        throw new CommandFailedError(`connect: adb ${serial} not ready`, undefined, true);
+      }
+    } else if (!(await this._sshReachable(selected.details as SshDetails))) {
+      log.warn(`[warn] connect: unreachable ${selected.id}`);
       vscode.window.showWarningMessage(
         '연결할 수 없습니다. 장치 상태 또는 인증(ID/Password)을 확인하세요.',
       );
-      return;
+      throw new CommandFailedError(`connect: unreachable ${selected.id}`, undefined, true);
     }
 
     // 1) persist
@@ -134,6 +438,7 @@ export class CommandHandlersConnect {
     await saveConnectionConfig(base, cfg);
     // 2) activate via ConnectionManager (단일 소스오브트루스)
     connectionManager.setActive(selected);
+    log.info(`[info] connect: active ${selected.alias || selected.id}`);
     // 3) optional health update (비동기)
     connectionManager.checkHealth().catch(() => {});
     vscode.window.showInformationMessage(
@@ -141,30 +446,88 @@ export class CommandHandlersConnect {
     );
   }
 
+  /**
+   * 'device'가 아닌 ADB 기기: 상태별 사유를 알리고, 원하면 허용/재연결될 때까지 기다린다.
+   * @returns 대기 중 'device' 상태가 되었으면 true
+   */
+  private async _offerAdbWait(serial: string, state: string): Promise<boolean> {
+    log.warn(`[warn] connect: adb ${serial} ${adbStateHint(state)}`);
+    const WAIT = '대기 후 재시도';
+    const choice = await vscode.window.showWarningMessage(
+      `ADB 장치 ${serial}: ${adbStateHint(state)}`,
+      WAIT,
+    );
+    if (choice !== WAIT) return false;
+
+    const ready = await vscode.window.withProgress(
+      {
+        location: vscode.ProgressLocation.Notification,
+        title: `ADB 장치 대기 중: ${serial} (기기에서 허용하거나 다시 연결하세요)`,
+        cancellable: true,
+      },
+      async (_p, token) => {
+        const ac = new AbortController();
+        token.onCancellationRequested(() => ac.abort());
+        return adbWaitForDevice(serial, ADB_READY_WAIT_MS, ac.signal);
+      },
+    );
+    if (!ready) {
+      const now = await adbGetState(serial).catch(() => 'unknown');
+      log.warn(`[warn] connect: adb ${serial} still ${now}`);
+      vscode.window.showWarningMessage(
+        `ADB 장치가 아직 준비되지 않았습니다: ${serial} (${adbStateHint(now)})`,
+      );
+    }
+    return ready;
+  }
+
+  /** ssh config 별칭 해석 실패(없는 Host, ProxyJump 등)도 도달 불가로 보고 경고만 남긴다 */
+  private async _sshReachable(d: SshDetails, timeoutMs?: number): Promise<boolean> {
+    try {
+      return await sshQuickCheck({ ...resolveSshTarget(d), timeoutMs });
+    } catch (e: any) {
+      log.warn(`[warn] connect: ssh target unresolved: ${e?.message || e}`);
+      return false;
+    }
+  }
+
   private async _pickNew(base: string, cfg: any) {
     const branch = await vscode.window.showQuickPick(
-      [{ label: 'ADB 연결' }, { label: 'SSH 연결' }],
+      [
+        { label: 'ADB 연결' },
+        { label: 'SSH 연결' },
+        {
+          label: 'SSH 연결 (~/.ssh/config 별칭 사용)',
+          description: 'Host/User/Port/키 재입력 없음',
+        },
+      ],
       { placeHolder: '새 연결 방식을 선택하세요' },
     );
     if (!branch) return;
     if (branch.label.startsWith('ADB')) return this._newAdb(base, cfg);
+    if (branch.label.includes('config')) return this._newSshConfigAlias(base, cfg);
     return this._newSsh(base, cfg);
   }
 
   private async _newAdb(base: string, cfg: any) {
     try {
+      // unauthorized/offline 기기도 사유와 함께 보여준다(선택 시 허용 대기 제안)
       const list = await adbListDevices({});
-      const candidates = list.filter((d) => d.state === 'device');
-      if (candidates.length === 0) {
+      if (list.length === 0) {
         vscode.window.showWarningMessage('연결 가능한 ADB 장치가 없습니다. (adb devices 확인)');
         return;
       }
       const pick = await vscode.window.showQuickPick(
-        candidates.map((d) => ({ label: d.id, description: 'ADB device' })),
+        list.map((d) => ({
+          label: d.id,
+          description: d.state === 'device' ? 'ADB device' : adbStateHint(d.state),
+          state: d.state,
+        })),
         { placeHolder: 'ADB 장치를 선택하세요' },
       );
       if (!pick) return;
       const deviceID = pick.label;
+      if (pick.state !== 'device' && !(await this._offerAdbWait(deviceID, pick.state))) return;
       const alias = await vscode.window.showInputBox({
         prompt: '별칭(선택)',
         placeHolder: '예) Homey-Dev-01',
@@ -187,6 +550,7 @@ export class CommandHandlersConnect {
     } catch (e: any) {
       log.error('ADB list failed', e);
       vscode.window.showErrorMessage(`ADB 조회 실패: ${e?.message || e}`);
+      throw new CommandFailedError(`ADB 조회 실패: ${e?.message || e}`, e, true);
     }
   }
 
@@ -248,4 +612,69 @@ export class CommandHandlersConnect {
     connectionManager.checkHealth().catch(() => {});
     vscode.window.showInformationMessage(`SSH 연결 항목 저장 및 활성화: ${alias || id}`);
   }
+
+  /**
+   * ~/.ssh/config의 Host 별칭으로 SSH 연결 생성
+   * - host/user/port/IdentityFile은 접속 시점마다 ssh config에서 해석(저장값은 표시용 스냅샷)
+   * - 인증은 IdentityFile/ssh-agent 사용(비밀번호 입력 없음)
+   */
+  private async _newSshConfigAlias(base: string, cfg: any) {
+    const text = readUserSshConfig();
+    const aliases = listSshConfigAliases(text);
+    if (!aliases.length) {
+      vscode.window.showWarningMessage(`${userSshConfigPath()}에 사용할 Host 항목이 없습니다.`);
+      return;
+    }
+    const items = aliases.map((a) => {
+      let description = '';
+      try {
+        const t = resolveSshTarget({ host: a, user: '', port: 22, configAlias: a }, text);
+        description = `${t.user}@${t.host}:${t.port}${t.keyPath ? ' · key' : ''}`;
+      } catch (e: any) {
+        description = `사용 불가: ${e?.message || e}`;
+      }
+      return { label: a, description };
+    });
+    const pick = await vscode.window.showQuickPick(items, {
+      placeHolder: `${userSshConfigPath()}의 Host 별칭을 선택하세요`,
+    });
+    if (!pick) return;
+    const configAlias = pick.label;
+
+    let details: SshDetails;
+    try {
+      const t = resolveSshTarget({ host: configAlias, user: '', port: 22, configAlias }, text);
+      details = { host: t.host, user: t.user, port: t.port, configAlias };
+    } catch (e: any) {
+      vscode.window.showErrorMessage(e?.message || String(e));
+      return;
+    }
+    const alias = await vscode.window.showInputBox({
+      prompt: '별칭(선택)',
+      value: configAlias,
+      ignoreFocusOut: true,
+    });
+    if (alias === undefined) return; // 취소
+
+    if (!(await this._sshReachable(details, 5000))) {
+      vscode.window.showWarningMessage(
+        `SSH 접속 테스트 실패(${configAlias}). IdentityFile/ssh-agent 및 방화벽을 확인하세요.`,
+      );
+      return;
+    }
+
+    const id = `ssh:config:${configAlias}`;
+    const entry: ConnectionInfo = {
+      id,
+      type: 'SSH',
+      details,
+      alias: alias || undefined,
+      lastUsed: new Date().toISOString(),
+    };
+    upsertConnection(cfg, entry);
+    await saveConnectionConfig(base, cfg);
+    connectionManager.setActive(entry);
+    connectionManager.checkHealth().catch(() => {});
+    vscode.window.showInformationMessage(`SSH 연결 항목 저장 및 활성화: ${alias || id}`);
+  }
 }
@@ -1,33 +1,174 @@
 // === src/extension/commands/CommandHandlersGit.ts ===
+import * as path from 'path';
 import * as vscode from 'vscode';
 
 import { getCurrentWorkspacePathFs } from '../../core/config/userdata.js';
 import { connectionManager } from '../../core/connection/ConnectionManager.js';
-import { GitController } from '../../core/controller/GitController.js';
+import {
+  createDeadline,
+  defaultDeadlineMs,
+  parseDurationMs,
+} from '../../core/connection/deadline.js';
+import { isToolMissingError, toolMissingError } from '../../core/connection/toolPreflight.js';
+import {
+  type CommitKind,
+  type DeviceDiffStatus,
+  GitController,
+  type PullResult,
+  SYNC_CATEGORIES,
+  type SyncCategory,
+} from '../../core/controller/GitController.js';
 import { HomeyController } from '../../core/controller/HomeyController.js';
-import { HostController } from '../../core/controller/HostController.js';
+import {
+  HostController,
+  isPseudoFsPath,
+  type RemoteEntry,
+} from '../../core/controller/HostController.js';
 import { getLogger } from '../../core/logging/extension-logger.js';
 import { measure } from '../../core/logging/perf.js';
+import { describeTransferProgress } from '../../core/transfer/FileTransferService.js';
+import { fmtBytes } from '../../core/transfer/TransferMeter.js';
+import { BROWSE_CONFIRM_BYTES, REMOTE_PSEUDO_FS_DIRS } from '../../shared/const.js';
+import {
+  CommandFailedError,
+  describeError,
+  ErrorCategory,
+  isXError,
+  NOT_CONNECTED_MESSAGE,
+} from '../../shared/errors.js';
 
 const log = getLogger('cmd.git');
+const GIT_FLOW_USAGE =
+  'gitFlow [--deadline <초|Nm>] [--dry-run] [--include <glob>]… [--exclude <glob>]…';
 type QPItem<T extends string> = vscode.QuickPickItem & { value: T };
+type BrowseItem = vscode.QuickPickItem & {
+  action: 'pull' | 'open';
+  path: string;
+  type?: RemoteEntry['type'];
+};
 
 export class CommandHandlersGit {
   constructor(private context?: vscode.ExtensionContext) {}
 
+  /**
+   * `git <args>` — status/commit은 요약 출력, 그 외는 git 출력을 그대로 표시
+   * (log/diff/branch 등 읽기 전용 명령용)
+   */
   @measure()
-  async gitFlow() {
+  async gitCommand(args: string, signal?: AbortSignal) {
+    const ws = await this.workspaceOrFail();
+    const argv = splitArgs(args);
+    if (!argv.length) throw new CommandFailedError('git <args>');
+    const git = new GitController(new HostController(connectionManager, ws), ws);
+
+    try {
+      if (argv[0] === 'history') return await this.printHistory(git, Number(argv[1]) || 50);
+      if (argv[0] === 'diff-device') return await this.printDeviceDiff(git, ws, argv[1], signal);
+      if (argv[0] === 'status' && argv.length === 1) return await git.printStatusSummary();
+      const mi = argv.indexOf('-m');
+      if (argv[0] === 'commit' && !argv.includes('--amend') && mi >= 0 && argv[mi + 1]) {
+        const r = await git.commitAsync(argv[mi + 1]);
+        log.result(`[info] git commit: ${r.fileCount} file(s) (${r.durationMs}ms)`);
+        return r;
+      }
+      const { stdout, stderr } = await git.runRaw(argv);
+      if (stdout.trim()) log.result(stdout.replace(/\s+$/, ''));
+      // git은 진행/안내 메시지를 stderr로 내보내므로 성공 시에도 표시
+      if (stderr.trim()) log.info(stderr.replace(/\s+$/, ''));
+      return { stdout, stderr };
+    } catch (e: any) {
+      if (e instanceof CommandFailedError || signal?.aborted) throw e;
+      if (isToolMissingError(e)) throw new CommandFailedError(toolMissingError('git').message, e);
+      const out = [e?.stdout, e?.stderr].map((x) => String(x ?? '').trim()).filter(Boolean);
+      const detail = out.length ? `:\n${out.join('\n')}` : '';
+      throw new CommandFailedError(`git ${argv.join(' ')} failed${detail}`, e);
+    }
+  }
+
+  /** 연결 확인(필요 시 연결 시도) — 안 되면 알리고 실패로 던진다 */
+  private async connectOrFail() {
+    await connectionManager.connect();
+    if (connectionManager.isConnected()) return;
+    vscode.window.showErrorMessage(NOT_CONNECTED_MESSAGE);
+    throw new CommandFailedError(NOT_CONNECTED_MESSAGE, undefined, true);
+  }
+
+  /** 현재 작업폴더 — 없으면 알리고 실패로 던진다 */
+  private async workspaceOrFail(): Promise<string> {
     const ws = this.context ? await getCurrentWorkspacePathFs(this.context) : undefined;
-    if (!ws) {
-      vscode.window.showErrorMessage('작업폴더를 확인할 수 없습니다.');
-      return;
+    if (ws) return ws;
+    vscode.window.showErrorMessage('작업폴더를 확인할 수 없습니다.');
+    throw new CommandFailedError('작업폴더를 확인할 수 없습니다.', undefined, true);
+  }
+
+  /** 커밋 이력 출력 — 패널은 [dl]/[skip]/[user] 태그로 색을 구분한다 */
+  private async printHistory(git: GitController, limit: number) {
+    const entries = await git.getHistory(limit);
+    if (!entries.length) {
+      log.result('[info] git history: no commits');
+      return entries;
+    }
+    log.result(`=== git history (last ${limit}) ===`);
+    const tag: Record<CommitKind, string> = { download: '[dl]', skip: '[skip]', user: '[user]' };
+    for (const e of entries) {
+      if (!e.hash) log.result(e.graph);
+      else log.result(`${e.graph}${tag[e.kind!]} ${e.hash} ${e.date} ${e.subject}`);
     }
+    return entries;
+  }
 
+  /** `git diff-device <category>` — 파일별 A(기기에 없음)/M(내용 다름)/=(동일) 목록 */
+  private async printDeviceDiff(
+    git: GitController,
+    ws: string,
+    category: string | undefined,
+    signal?: AbortSignal,
+  ) {
+    if (!SYNC_CATEGORIES.includes(category as SyncCategory)) {
+      throw new CommandFailedError(`git diff-device <${SYNC_CATEGORIES.join('|')}>`);
+    }
     await connectionManager.connect();
-    if (!connectionManager.isConnected()) {
-      vscode.window.showErrorMessage('활성 연결이 없습니다. 먼저 "기기 연결"을 수행하세요.');
-      return;
+    if (!connectionManager.isConnected()) throw new CommandFailedError(NOT_CONNECTED_MESSAGE);
+    try {
+      const entries = await git.diffDevice(category as SyncCategory, signal);
+      if (!entries.length) {
+        log.result(`[info] git diff-device ${category}: no local files`);
+        return entries;
+      }
+      const mark: Record<DeviceDiffStatus, string> = { added: 'A', modified: 'M', identical: '=' };
+      const count: Record<DeviceDiffStatus, number> = { added: 0, modified: 0, identical: 0 };
+      log.result(`=== git diff-device ${category} ===`);
+      for (const e of entries) {
+        count[e.status]++;
+        log.result(`${mark[e.status]} ${path.relative(ws, e.local)} → ${e.remote}`);
+      }
+      log.result(
+        `[info] git diff-device ${category}: ${count.modified} modified, ${count.added} added, ` +
+          `${count.identical} identical`,
+      );
+      return entries;
+    } catch (e) {
+      if (signal?.aborted) throw e;
+      throw new CommandFailedError(`git diff-device ${category}: ${describeError(e)}`, e);
     }
+  }
+
+  /**
+   * Pull/Push 대화형 흐름
+   * - `--deadline <초|Nm>`: 전송 전체 마감 — 초과 시 진행 중 원격 명령까지 끊고 "operation deadline exceeded"
+   *   (생략 시 EDGE_TOOL_DEADLINE — 버튼으로 실행할 때도 적용)
+   * - `--dry-run`: Push 시 업로드/삭제 계획만 출력(기기 변경 없음)
+   * - `--include/--exclude <glob[,glob…]>`(반복 가능): 디렉터리 Pull에서 받을 파일 선택(exclude 우선)
+   */
+  @measure()
+  async gitFlow(args = '', signal?: AbortSignal) {
+    const m = /(?:^|\s)--deadline\s+(\S+)/.exec(args);
+    const deadlineMs = m ? parseDurationMs(m[1]) : defaultDeadlineMs();
+    if (m && !deadlineMs) throw new CommandFailedError(GIT_FLOW_USAGE);
+    const dryRun = /(?:^|\s)--dry-run(?:\s|$)/.test(args);
+    const select = { include: globOption(args, 'include'), exclude: globOption(args, 'exclude') };
+    const ws = await this.workspaceOrFail();
+    await this.connectOrFail();
 
     const host = new HostController(connectionManager, ws);
     const git = new GitController(host, ws);
@@ -51,18 +192,24 @@ export class CommandHandlersGit {
       });
       const arg = raw === undefined ? undefined : raw.trim(); // ''(전체 푸시) 보전
       const hostPath = await vscode.window.showInputBox({
-        prompt: 'HostPath (선택) — host_sync 업로드 대상 절대경로를 직접 지정',
+        prompt:
+          'HostPath (선택, 한 카테고리만) — host_sync 파일 하나의 대상 경로 / homey_* 원격 베이스',
         placeHolder: '예) /etc/homey/config.json',
         ignoreFocusOut: true,
       });
       log.debug('[debug] gitFlow:push-args', { arg, hostPath });
-      await vscode.window.withProgress(
-        { location: vscode.ProgressLocation.Notification, title: 'Push', cancellable: false },
-        async () => {
-          await git.push(arg, { hostPath: hostPath || undefined });
-        },
+      const done = await this.withDeadline('Push', deadlineMs, signal, (_p, signal) =>
+        git.push(arg, {
+          hostPath: hostPath || undefined,
+          signal,
+          dryRun,
+          confirmDelete: confirmRemoteDelete,
+        }),
       );
-      return;
+      // `--json` 결과: 올린/지운 파일(dry-run이면 계획)
+      const files = (deleted: boolean) =>
+        done.filter((p) => p.deleted === deleted).map(({ local, remote }) => ({ local, remote }));
+      return { op: 'push', dryRun, pushed: files(false), deleted: files(true) };
     }
 
     // ── Pull ─────────────────────────────────────────────────
@@ -117,16 +264,16 @@ export class CommandHandlersGit {
       if (localPath === undefined) return;
       log.debug('[debug] gitFlow:host-pull-args', { hostAbsPath, localPath });
 
-      await vscode.window.withProgress(
-        { location: vscode.ProgressLocation.Notification, title: 'Pull: Host', cancellable: false },
-        async (p) => {
-          p.report({ message: '전송 중…' });
-          await git.pull('host', hostAbsPath, {
-            localPath: localPath || undefined, // 빈 문자열이면 undefined로
-          });
-        },
-      );
-      return;
+      const pulled = await this.withDeadline('Pull: Host', deadlineMs, signal, (p, signal) => {
+        p.report({ message: '전송 중…' });
+        return git.pull('host', hostAbsPath, {
+          localPath: localPath || undefined, // 빈 문자열이면 undefined로
+          ...select,
+          signal,
+          onProgress: (s) => p.report({ message: describeTransferProgress(s) || '전송 중…' }),
+        });
+      });
+      return { op: 'pull', pulled: [pulled] };
     }
     // Homey
     const picks = await vscode.window.showQuickPick(
@@ -139,17 +286,236 @@ export class CommandHandlersGit {
       { placeHolder: '받을 대상을 선택하세요 (다중 선택 가능)', canPickMany: true },
     );
     if (!picks || picks.length === 0) return;
-    log.debug('[debug] gitFlow:homey-picks', { picks: picks.map((p) => p.label) });
-
-    await vscode.window.withProgress(
-      { location: vscode.ProgressLocation.Notification, title: 'Pull: Homey', cancellable: false },
-      async (p) => {
-        for (const it of picks) {
-          const kind = it.label as 'pro' | 'core' | 'sdk' | 'bridge';
-          p.report({ message: `downloading ${kind}…` });
-          await git.pull(kind, undefined);
+    const localPath = await vscode.window.showInputBox({
+      prompt: '로컬 저장 경로(선택) — 없으면 레이아웃 기본 디렉터리(homey_pro 등)에 저장',
+      placeHolder: '예) ./tmp/homey (여러 대상 선택 시 하위에 대상별 폴더 생성)',
+      ignoreFocusOut: true,
+    });
+    if (localPath === undefined) return;
+    log.debug('[debug] gitFlow:homey-picks', { picks: picks.map((p) => p.label), localPath });
+
+    const title = 'Pull: Homey';
+    const pulled = await this.withDeadline(title, deadlineMs, signal, async (p, signal) => {
+      const out: PullResult[] = [];
+      for (const it of picks) {
+        const kind = it.label as 'pro' | 'core' | 'sdk' | 'bridge';
+        p.report({ message: `downloading ${kind}…` });
+        // 다중 선택 시 같은 경로에 겹치지 않도록 대상별 하위 폴더 사용
+        const dest = !localPath
+          ? undefined
+          : picks.length > 1
+            ? path.join(localPath, kind)
+            : localPath;
+        out.push(
+          await git.pull(kind, undefined, {
+            localPath: dest,
+            ...select,
+            signal,
+            onProgress: (s) => {
+              const detail = describeTransferProgress(s);
+              p.report({ message: `downloading ${kind}…${detail ? ` ${detail}` : ''}` });
+            },
+          }),
+        );
+      }
+      return out;
+    });
+    return { op: 'pull', pulled };
+  }
+
+  /**
+   * `browse [path]` — 기기 디렉터리를 `ls -la`로 한 단계씩 탐색하고, 고른 파일/디렉터리를 host_sync로 Pull
+   * - 디렉터리(디렉터리를 가리키는 링크 포함)는 선택하면 들어가고 `..`로 올라간다
+   * - 맨 위 항목은 지금 보고 있는 디렉터리 전체를 받는다
+   */
+  @measure()
+  async browse(args = '', signal?: AbortSignal) {
+    const start = args.trim().replace(/^(['"])(.*)\1$/, '$2') || '/';
+    if (!start.startsWith('/')) throw new CommandFailedError('browse [/절대/경로]');
+    const ws = await this.workspaceOrFail();
+    await this.connectOrFail();
+
+    const host = new HostController(connectionManager, ws);
+    let dir = path.posix.normalize(start).replace(/(.)\/+$/, '$1');
+    let prev: string | undefined;
+    let target: string | undefined;
+    while (!target) {
+      let entries: RemoteEntry[];
+      try {
+        entries = await host.listDir(dir, signal);
+      } catch (e) {
+        if (signal?.aborted) throw e;
+        // 처음 경로가 안 열리면 실패, 탐색 중이면 이전 디렉터리로 돌아간다
+        const msg = `browse ${dir}: ${describeError(e)}`;
+        if (prev === undefined) throw new CommandFailedError(msg, e);
+        log.error(`[error] ${msg}`);
+        vscode.window.showWarningMessage(`열 수 없습니다: ${dir}`);
+        [dir, prev] = [prev, undefined];
+        continue;
+      }
+      const pick = await vscode.window.showQuickPick(browseItems(dir, entries), {
+        placeHolder: `${dir} — 디렉터리는 들어가고, 파일은 선택하면 host_sync로 받습니다`,
+        matchOnDescription: true,
+        ignoreFocusOut: true,
+      });
+      if (!pick || signal?.aborted) return;
+      if (pick.type === 'other') {
+        vscode.window.showWarningMessage(`일반 파일/디렉터리가 아닙니다: ${pick.path}`);
+        continue;
+      }
+      // 링크는 가리키는 대상의 종류로 판단
+      const kind = pick.type === 'link' ? await host.statType(pick.path) : undefined;
+      if (pick.action === 'open' || kind === 'DIR') {
+        [prev, dir] = [dir, pick.path];
+      } else if (kind === 'NONE') {
+        vscode.window.showWarningMessage(`링크 대상이 없습니다: ${pick.path}`);
+      } else {
+        target = pick.path;
+      }
+    }
+
+    const picked = target;
+    if (!(await confirmBrowsePull(host, picked, signal))) return;
+    const git = new GitController(host, ws);
+    log.debug('[debug] browse:pull', { target: picked });
+    return this.withDeadline(`Pull: ${picked}`, defaultDeadlineMs(), signal, async (p, signal) => {
+      p.report({ message: '전송 중…' });
+      const pulled = await git.pull('host', picked, {
+        signal,
+        // `/`를 받을 때도 가상 파일시스템은 건너뛴다
+        exclude: picked === '/' ? [...REMOTE_PSEUDO_FS_DIRS] : undefined,
+        onProgress: (s) => p.report({ message: describeTransferProgress(s) || '전송 중…' }),
+      });
+      log.result(`[info] browse: ${picked} → ${host.toLocalFromHost(picked)}`);
+      return pulled;
+    });
+  }
+
+  /**
+   * 진행 알림 + 취소 버튼 + 전체 마감(선택) + 명령 취소(parent)를 하나의 AbortSignal로 묶어 작업에 넘긴다.
+   * 알림의 취소 버튼/마감 초과는 안내 후 CommandFailedError로, 명령 취소(parent)와 그 외 오류는 그대로 전파한다.
+   */
+  private async withDeadline<T>(
+    title: string,
+    deadlineMs: number | undefined,
+    parent: AbortSignal | undefined,
+    task: (p: vscode.Progress<{ message?: string }>, signal: AbortSignal) => Promise<T>,
+  ): Promise<T> {
+    return vscode.window.withProgress(
+      { location: vscode.ProgressLocation.Notification, title, cancellable: true },
+      async (p, token) => {
+        const ac = new AbortController();
+        token.onCancellationRequested(() => ac.abort());
+        const onParent = () => ac.abort();
+        if (parent?.aborted) onParent();
+        else parent?.addEventListener('abort', onParent, { once: true });
+        const dl = createDeadline(deadlineMs, ac.signal);
+        try {
+          return await task(p, dl.signal);
+        } catch (e) {
+          if (parent?.aborted) throw e;
+          if (ac.signal.aborted) {
+            log.info(`[info] ${title}: cancelled`);
+            throw new CommandFailedError(`${title}: cancelled`, e, true);
+          }
+          if (!isXError(e, ErrorCategory.Timeout) || !dl.signal.aborted) throw e;
+          log.error(`[error] ${title}: ${describeError(e)}`);
+          vscode.window.showErrorMessage(`${title}: ${describeError(e)}`);
+          throw new CommandFailedError(`${title}: ${describeError(e)}`, e, true);
+        } finally {
+          dl.dispose();
+          parent?.removeEventListener('abort', onParent);
         }
       },
     );
   }
 }
+
+/** browse 목록: [현재 디렉터리 받기] [..] 디렉터리 → 파일 순, 크기/종류는 설명란에 */
+function browseItems(dir: string, entries: RemoteEntry[]): BrowseItem[] {
+  const icon: Record<RemoteEntry['type'], string> = {
+    dir: '$(folder)',
+    file: '$(file)',
+    link: '$(file-symlink-file)',
+    other: '$(circle-slash)',
+  };
+  const items: BrowseItem[] = [
+    { label: '$(cloud-download) 이 디렉터리 받기', description: dir, action: 'pull', path: dir },
+  ];
+  if (dir !== '/') {
+    items.push({ label: '$(arrow-up) ..', action: 'open', path: path.posix.dirname(dir) });
+  }
+  for (const e of entries) {
+    const description =
+      e.type === 'file'
+        ? `file · ${fmtBytes(e.size)}`
+        : e.type === 'link'
+          ? `link → ${e.target ?? '?'}`
+          : e.type;
+    items.push({
+      label: `${icon[e.type]} ${e.name}`,
+      description,
+      action: e.type === 'dir' ? 'open' : 'pull',
+      path: path.posix.join(dir, e.name),
+      type: e.type,
+    });
+  }
+  return items;
+}
+
+/**
+ * browse pull 전 점검: /proc·/sys·/dev(링크로 가리키는 경우 포함)는 거부,
+ * `/`와 BROWSE_CONFIRM_BYTES 이상 디렉터리는 크기를 보여 주고 확인을 받는다
+ */
+async function confirmBrowsePull(
+  host: HostController,
+  target: string,
+  signal?: AbortSignal,
+): Promise<boolean> {
+  const real = await host.realPath(target, signal);
+  if (isPseudoFsPath(target) || isPseudoFsPath(real)) {
+    log.error(`[error] browse: ${target} is a pseudo filesystem (/proc, /sys, /dev) — refused`);
+    vscode.window.showErrorMessage(`가상 파일시스템은 받을 수 없습니다: ${target}`);
+    return false;
+  }
+  if ((await host.statType(real)) !== 'DIR') return true;
+  // `/`는 du가 전체를 훑어야 하므로 크기를 재지 않고 바로 확인
+  let message = '기기의 루트(/) 전체를 받습니다(/proc, /sys, /dev 제외). 계속할까요?';
+  if (real !== '/') {
+    const size = await host.sizeBytes(real, signal);
+    if (size === undefined || size < BROWSE_CONFIRM_BYTES) return true;
+    message = `${target} 디렉터리는 ${fmtBytes(size)}입니다. 받을까요?`;
+  }
+  const act = await vscode.window.showWarningMessage(message, { modal: true }, '받기');
+  return act === '받기';
+}
+
+/** 로컬에서 지운 파일을 기기에서도 지울지 모달로 확인(목록은 앞쪽 일부만 표시) */
+async function confirmRemoteDelete(remotes: string[]): Promise<boolean> {
+  const SHOWN = 10;
+  const more = remotes.length > SHOWN ? `\n… 외 ${remotes.length - SHOWN}개` : '';
+  const act = await vscode.window.showWarningMessage(
+    `로컬에서 삭제된 파일 ${remotes.length}개를 기기에서도 삭제할까요?`,
+    { modal: true, detail: remotes.slice(0, SHOWN).join('\n') + more },
+    '기기에서 삭제',
+  );
+  return act === '기기에서 삭제';
+}
+
+/** `--include a --include "b,c"` → ['a', 'b', 'c'] (따옴표 한 겹은 벗김) */
+function globOption(args: string, name: 'include' | 'exclude'): string[] {
+  const re = new RegExp(`(?:^|\\s)--${name}\\s+("[^"]*"|'[^']*'|\\S+)`, 'g');
+  return [...args.matchAll(re)]
+    .flatMap((m) => m[1].replace(/^(['"])(.*)\1$/, '$2').split(','))
+    .map((g) => g.trim())
+    .filter(Boolean);
+}
+
+/** 공백 기준 인자 분리(작은/큰따옴표로 묶인 구간은 하나로 유지) */
+function splitArgs(line: string): string[] {
+  const out: string[] = [];
+  const re = /"([^"]*)"|'([^']*)'|(\S+)/g;
+  let m: RegExpExecArray | null;
+  while ((m = re.exec(line))) out.push(m[1] ?? m[2] ?? m[3]);
+  return out;
+}
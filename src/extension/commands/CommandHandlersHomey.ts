@@ -4,66 +4,154 @@ import * as vscode from 'vscode';
 import { HomeyController } from '../../core/controller/HomeyController.js';
 import { getLogger } from '../../core/logging/extension-logger.js';
 import { measure } from '../../core/logging/perf.js';
-import { getEnvToggleEnabled, getMountState } from '../../core/state/DeviceState.js';
+import {
+  getEnvToggleEnabled,
+  getMountState,
+  getServiceEditStatus,
+} from '../../core/state/DeviceState.js';
+import { CommandFailedError, describeError } from '../../shared/errors.js';
 
 const log = getLogger('cmd.homey');
 
 export class CommandHandlersHomey {
   constructor() {}
 
+  /**
+   * 실패 경계: 로그 + 에러 종류별(미연결/Homey 없음/원격 명령 실패) 사용자 안내 후 실패로 던진다
+   * - 사용자 취소(signal abort)는 알림 없이 그대로 던진다(route가 cancelled로 안내)
+   */
+  private fail(op: string, e: unknown, signal?: AbortSignal): never {
+    if (signal?.aborted) throw e;
+    log.error(`${op} failed`, e as any);
+    vscode.window.showErrorMessage(describeError(e));
+    throw new CommandFailedError(`${op}: ${describeError(e)}`, e, true);
+  }
+
   @measure()
-  async homeyRestart() {
+  async homeyRestart(signal?: AbortSignal) {
     log.debug('[debug] CommandHandlersHomey homeyRestart: start');
     try {
       const controller = new HomeyController();
-      await controller.restart();
+      await controller.restart(signal);
       log.debug('[debug] CommandHandlersHomey homeyRestart: end');
     } catch (e) {
-      log.error('homeyRestart failed', e as any);
+      this.fail('homeyRestart', e, signal);
+    }
+  }
+
+  @measure()
+  async homeyServiceRedetect(args = '') {
+    log.debug('[debug] CommandHandlersHomey homeyServiceRedetect: start');
+    const m = /^--service\s+(\S+)$/.exec(args.trim());
+    if (args.trim() && !m) throw new CommandFailedError('homeyServiceRedetect [--service <name>]');
+    try {
+      const unit = await new HomeyController().redetectServiceUnit(m?.[1]);
+      vscode.window.showInformationMessage(`Homey 서비스 유닛: ${unit}`);
+    } catch (e) {
+      this.fail('homeyServiceRedetect', e);
+    }
+  }
+
+  /** 서비스 파일에 마운트/토글 편집이 들어가 있는지 표로 출력 */
+  @measure()
+  async homeyMountStatus() {
+    log.debug('[debug] CommandHandlersHomey homeyMountStatus: start');
+    try {
+      const st = await getServiceEditStatus();
+      const yn = (b?: boolean) => (b === undefined ? '-' : b ? 'yes' : 'no');
+      const row = (a: string, b: string, c: string, d: string) =>
+        `[mount.status] ${a.padEnd(16)}${b.padEnd(8)}${c.padEnd(9)}${d}`;
+      log.result(`[mount.status] unit=${st.unit} file=${st.path}`);
+      log.result(row('item', 'kind', 'service', 'volume'));
+      for (const it of st.items) {
+        log.result(row(it.name, it.kind, yn(it.inServiceFile), yn(it.volumeExists)));
+      }
+      const on = st.items.filter((i) => i.inServiceFile).map((i) => i.name);
+      vscode.window.showInformationMessage(
+        on.length ? `서비스 파일 편집 적용됨: ${on.join(', ')}` : '서비스 파일 편집 없음(원본 상태)',
+      );
+      log.debug('[debug] CommandHandlersHomey homeyMountStatus: end');
+      return st;
+    } catch (e) {
+      this.fail('homeyMountStatus', e);
     }
   }
 
   // ── 새 토글 핸들러들 ──────────────────────────────────────────────
   @measure()
-  async homeyVolumeToggle() {
+  async homeyVolumeToggle(signal?: AbortSignal) {
     log.debug('[debug] CommandHandlersHomey homeyVolumeToggle: start');
     try {
       const state = await getMountState();
       const controller = new HomeyController();
       if (state === 'mounted') {
-        await controller.unmount();
+        await controller.unmount(signal);
       } else {
-        await controller.mount();
+        await controller.mount(signal);
       }
       log.debug('[debug] CommandHandlersHomey homeyVolumeToggle: end');
     } catch (e) {
-      log.error('homeyVolumeToggle failed', e as any);
+      this.fail('homeyVolumeToggle', e, signal);
     }
   }
 
   @measure()
-  async homeyAppLogToggle() {
+  async homeyAppLogToggle(signal?: AbortSignal) {
     log.debug('[debug] CommandHandlersHomey homeyAppLogToggle: start');
     try {
       const enabled = await getEnvToggleEnabled('HOMEY_APP_LOG');
       const controller = new HomeyController();
-      await controller.toggleAppLog(!enabled);
+      await controller.toggleAppLog(!enabled, signal);
       log.debug('[debug] CommandHandlersHomey homeyAppLogToggle: end');
     } catch (e) {
-      log.error('homeyAppLogToggle failed', e as any);
+      this.fail('homeyAppLogToggle', e, signal);
     }
   }
 
   @measure()
-  async homeyDevTokenToggle() {
+  async homeyDevTokenToggle(signal?: AbortSignal) {
     log.debug('[debug] CommandHandlersHomey homeyDevTokenToggle: start');
     try {
       const enabled = await getEnvToggleEnabled('HOMEY_DEV_TOKEN');
       const controller = new HomeyController();
-      await controller.toggleDevToken(!enabled);
+      await controller.toggleDevToken(!enabled, signal);
       log.debug('[debug] CommandHandlersHomey homeyDevTokenToggle: end');
     } catch (e) {
-      log.error('homeyDevTokenToggle failed', e as any);
+      this.fail('homeyDevTokenToggle', e, signal);
+    }
+  }
+
+  /** 서비스 파일 env: `list` | `set KEY VALUE` | `unset KEY` (set/unset은 재시작 포함) */
+  @measure()
+  async homeyEnv(args = '', signal?: AbortSignal) {
+    log.debug('[debug] CommandHandlersHomey homeyEnv: start', { args });
+    const line = args.trim();
+    const set = /^set\s+(\S+)\s+(.+)$/.exec(line);
+    const unset = /^unset\s+(\S+)$/.exec(line);
+    if (line && line !== 'list' && !set && !unset) {
+      throw new CommandFailedError('homeyEnv list | set <KEY> <VALUE> | unset <KEY>');
+    }
+    try {
+      const controller = new HomeyController();
+      if (set) {
+        const value = set[2].trim().replace(/^(["'])(.*)\1$/, '$2');
+        await controller.setServiceEnv(set[1], value, signal);
+        log.result(`[homey.env] set ${set[1]}=${value}`);
+        return { key: set[1], value };
+      }
+      if (unset) {
+        await controller.setServiceEnv(unset[1], undefined, signal);
+        log.result(`[homey.env] unset ${unset[1]}`);
+        return { key: unset[1], value: null };
+      }
+      const st = await controller.listServiceEnv();
+      log.result(`[homey.env] unit=${st.unit} file=${st.path}`);
+      if (!st.vars.length) log.result('[homey.env] (no --env= entries)');
+      for (const v of st.vars) log.result(`[homey.env] ${v.key}=${v.value}`);
+      log.debug('[debug] CommandHandlersHomey homeyEnv: end');
+      return st;
+    } catch (e) {
+      this.fail('homeyEnv', e, signal);
     }
   }
 
@@ -73,7 +161,7 @@ export class CommandHandlersHomey {
     try {
       log.debug('[debug] CommandHandlersHomey homeyDockerUpdate: end');
     } catch (e) {
-      log.error('homeyDockerUpdate failed', e as any);
+      this.fail('homeyDockerUpdate', e);
     }
   }
 }
@@ -1,22 +1,194 @@
 // === src/extension/commands/CommandHandlersHost.ts ===
+import * as path from 'path';
 import * as vscode from 'vscode';
 
 import { connectionManager } from '../../core/connection/ConnectionManager.js';
+import { HostController } from '../../core/controller/HostController.js';
+import { wrapSudo } from '../../core/connection/sudo.js';
 import { getLogger } from '../../core/logging/extension-logger.js';
 import { measure } from '../../core/logging/perf.js';
+import {
+  describeTransferProgress,
+  type TransferOptions,
+  type TransferSummary,
+} from '../../core/transfer/FileTransferService.js';
+import { CommandFailedError } from '../../shared/errors.js';
 import { createAdbTerminal } from '../terminals/AdbTerminal.js';
 import { createSshTerminal } from '../terminals/SshTerminal.js';
 
 const log = getLogger('cmd.host');
 
+/** exec 결과에 담는 출력 줄 수 상한(스트림별, 넘으면 최근 줄만 유지) — tail -f 등 장시간 명령 대비 */
+const EXEC_CAPTURE_MAX_LINES = 5000;
+
+export type HostCommandResult = { code: number | null; stdout: string; stderr: string };
+
 export class CommandHandlersHost {
   constructor() {}
 
+  /**
+   * 현재 활성 연결(ADB/SSH)에서 임의 셸 명령을 실행하고 출력(stdout+stderr)을 실시간으로 로그에 흘린다.
+   * - 인자가 없으면 입력창으로 명령을 받는다.
+   * - 장시간 명령(tail -f 등)은 진행 알림의 '취소'로 원격 프로세스를 종료한다.
+   * - stderr는 경고로, 0이 아닌 종료코드는 끝에 경고로 알린다.
+   * - `--sudo <명령>`: 이 명령만 `sudo -n`으로 실행(연결 설정 useSudo와 무관)
+   * - 반환: `{ code, stdout, stderr }` — 0이 아닌 종료코드는 같은 값을 detail에 담아 실패로 던진다
+   */
   @measure()
-  async hostCommand(cmd: string) {
+  async hostCommand(cmd?: string, signal?: AbortSignal): Promise<HostCommandResult> {
     log.debug('[debug] CommandHandlersHost hostCommand: start');
-    if (!cmd) return log.error('[error] host <command>');
-    log.info(`[info] host passthrough: ${cmd} (stub)`);
+    let line = String(cmd ?? '').trim();
+    if (!line) {
+      line =
+        (
+          await vscode.window.showInputBox({
+            prompt: '기기에서 실행할 명령',
+            placeHolder: 'ls -al /data',
+            ignoreFocusOut: true,
+          })
+        )?.trim() ?? '';
+    }
+    const sudo = /^--sudo(?:\s+|$)/.test(line);
+    if (sudo) line = line.replace(/^--sudo\s*/, '');
+    if (!line) throw new CommandFailedError('exec [--sudo] <command>');
+    if (!connectionManager.isConnected() && !connectionManager.getSnapshot()?.active) {
+      noActiveHost();
+    }
+
+    // ADB 셸은 sudo 대신 adb root로 권한을 얻는다 — SSH에서만 감싼다
+    const isSsh = connectionManager.getSnapshot()?.active?.type === 'SSH';
+    if (sudo && !isSsh) log.warn('[warn] exec: --sudo는 SSH 연결에서만 적용됩니다');
+    const remote = sudo && isSsh ? wrapSudo(line) : line;
+
+    log.info(`[info] exec: ${line}`);
+    const ac = new AbortController();
+    // 명령 취소(Ctrl+C)도 알림의 취소 버튼과 같게 처리
+    signal?.addEventListener('abort', () => ac.abort(), { once: true });
+    const stdout: string[] = [];
+    const stderr: string[] = [];
+    const keep = (buf: string[], l: string) => {
+      buf.push(l);
+      if (buf.length > EXEC_CAPTURE_MAX_LINES) buf.shift();
+    };
+    return vscode.window.withProgress(
+      {
+        location: vscode.ProgressLocation.Notification,
+        title: `exec: ${line}`,
+        cancellable: true,
+      },
+      async (_progress, token) => {
+        token.onCancellationRequested(() => {
+          log.info('[info] exec: cancel requested');
+          ac.abort();
+        });
+        try {
+          const code = await connectionManager.stream(
+            remote,
+            (l) => {
+              keep(stdout, l);
+              log.result(l);
+            },
+            ac.signal,
+            (l) => {
+              keep(stderr, l);
+              log.warn(l);
+            },
+          );
+          if (ac.signal.aborted) throw new Error('cancelled');
+          const result = { code, stdout: stdout.join('\n'), stderr: stderr.join('\n') };
+          if (code !== 0 && code !== null) {
+            log.warn(`[warn] exec exited with code ${code}`);
+            throw new CommandFailedError(`exec exited with code ${code}`, result, true);
+          }
+          log.info('[info] exec: done');
+          return result;
+        } catch (e) {
+          // 명령 취소는 route가 안내, 알림의 취소 버튼은 여기서 안내 후 실패로 던진다
+          if (signal?.aborted || e instanceof CommandFailedError) throw e;
+          if (ac.signal.aborted) {
+            log.info('[info] exec: cancelled');
+            throw new CommandFailedError('exec: cancelled', e, true);
+          }
+          throw new CommandFailedError(
+            `exec failed: ${e instanceof Error ? e.message : String(e)}`,
+            e,
+          );
+        }
+      },
+    );
+  }
+
+  /**
+   * 기기 디렉터리 → 로컬 디렉터리 재귀 다운로드(스킵 규칙 적용, 진행률 + 완료/건너뜀 요약)
+   * - 인자: `<기기 절대 경로> <로컬 절대 경로>` (공백이 있으면 따옴표)
+   */
+  @measure()
+  async hostPull(args = '', signal?: AbortSignal): Promise<TransferSummary> {
+    const [remoteDir, localDir] = splitPathArgs(args);
+    if (!remoteDir?.startsWith('/') || !localDir || !path.isAbsolute(localDir)) {
+      throw new CommandFailedError('hostPull </기기/디렉터리> <로컬 절대 경로>');
+    }
+    return this.transferDir(`pull ${remoteDir} → ${localDir}`, signal, (host, onProgress, s) =>
+      host.pullDir(remoteDir, localDir, onProgress, s),
+    );
+  }
+
+  /**
+   * 로컬 디렉터리 → 기기 디렉터리 재귀 업로드(스킵 규칙 적용, 진행률 + 완료/건너뜀 요약)
+   * - 인자: `<로컬 절대 경로> <기기 절대 경로>` (공백이 있으면 따옴표)
+   */
+  @measure()
+  async hostPush(args = '', signal?: AbortSignal): Promise<TransferSummary> {
+    const [localDir, remoteDir] = splitPathArgs(args);
+    if (!localDir || !path.isAbsolute(localDir) || !remoteDir?.startsWith('/')) {
+      throw new CommandFailedError('hostPush <로컬 절대 경로> </기기/디렉터리>');
+    }
+    return this.transferDir(`push ${localDir} → ${remoteDir}`, signal, (host, onProgress, s) =>
+      host.pushDir(localDir, remoteDir, onProgress, s),
+    );
+  }
+
+  /** 디렉터리 전송 공통: 진행 알림(취소 가능) + 명령 취소 연동 + 결과 요약 */
+  private async transferDir(
+    title: string,
+    signal: AbortSignal | undefined,
+    run: (
+      host: HostController,
+      onProgress: TransferOptions['onProgress'],
+      signal: AbortSignal,
+    ) => Promise<TransferSummary>,
+  ): Promise<TransferSummary> {
+    if (!connectionManager.isConnected() && !connectionManager.getSnapshot()?.active) {
+      noActiveHost();
+    }
+    const host = new HostController(connectionManager, '');
+    const ac = new AbortController();
+    signal?.addEventListener('abort', () => ac.abort(), { once: true });
+    return vscode.window.withProgress(
+      { location: vscode.ProgressLocation.Notification, title, cancellable: true },
+      async (progress, token) => {
+        token.onCancellationRequested(() => ac.abort());
+        try {
+          const s = await run(
+            host,
+            (p) => progress.report({ message: describeTransferProgress(p) || '전송 중…' }),
+            ac.signal,
+          );
+          log.result(`[info] ${title}: ${s.done} succeeded, ${s.skipped} skipped`);
+          return s;
+        } catch (e) {
+          if (signal?.aborted) throw e;
+          if (ac.signal.aborted) {
+            log.info(`[info] ${title}: cancelled`);
+            throw new CommandFailedError(`${title}: cancelled`, e, true);
+          }
+          throw new CommandFailedError(
+            `${title} failed: ${e instanceof Error ? e.message : String(e)}`,
+            e,
+          );
+        }
+      },
+    );
   }
 
   // 현재 활성 연결(ADB/SSH)로 셸을 연다.
@@ -24,10 +196,7 @@ export class CommandHandlersHost {
   async openHostShell() {
     const snap = connectionManager.getSnapshot?.();
     const active = snap?.active;
-    if (!active) {
-      vscode.window.showErrorMessage('연결된 호스트가 없습니다. 먼저 연결하세요.');
-      return;
-    }
+    if (!active) noActiveHost();
 
     // ADB: VS Code Pseudoterminal로 통일
     if (active.type === 'ADB') {
@@ -43,10 +212,23 @@ export class CommandHandlersHost {
     const tty = createSshTerminal();
     if (!tty) {
       vscode.window.showErrorMessage('SSH 연결 정보를 확인할 수 없습니다.');
-      return;
+      throw new CommandFailedError('SSH 연결 정보를 확인할 수 없습니다.', undefined, true);
     }
     const t = vscode.window.createTerminal({ name: tty.title, pty: tty.pty });
     t.show();
     log.info('openHostShell(ssh): pty terminal opened', { title: tty.title });
   }
 }
+
+/** 활성 연결 없음 — 알리고 실패로 던진다 */
+function noActiveHost(): never {
+  vscode.window.showErrorMessage('연결된 호스트가 없습니다. 먼저 연결하세요.');
+  throw new CommandFailedError('연결된 호스트가 없습니다.', undefined, true);
+}
+
+/** 공백으로 나눈 경로 인자(따옴표로 감싼 경로는 공백 포함) */
+function splitPathArgs(args: string): string[] {
+  return (String(args ?? '').match(/"[^"]*"|'[^']*'|\S+/g) ?? []).map((t) =>
+    t.replace(/^(['"])(.*)\1$/, '$2'),
+  );
+}
@@ -1,64 +1,356 @@
 // === src/extension/commands/CommandHandlersLogging.ts ===
+import * as path from 'path';
+import * as vscode from 'vscode';
+
+import {
+  type AdbDetails,
+  logSourceFilePaths,
+  readConnectionConfig,
+} from '../../core/config/connection-config.js';
+import { resolveWorkspaceInfo } from '../../core/config/userdata.js';
+import { listDevices } from '../../core/connection/adbClient.js';
+import { connectionManager } from '../../core/connection/ConnectionManager.js';
+import { shellQuote } from '../../core/connection/shellQuote.js';
+import { HostController } from '../../core/controller/HostController.js';
 import { getLogger } from '../../core/logging/extension-logger.js';
 import { measure } from '../../core/logging/perf.js';
+import { isQuietMode } from '../../core/logging/quiet-mode.js';
+import {
+  type LogCaptureFormat,
+  type LogCaptureOptions,
+  parseCaptureSize,
+} from '../../core/logs/LogCaptureWriter.js';
+import { type JournalWindow, journalWindowError } from '../../core/logs/RealtimeSource.js';
+import {
+  assertTransferComplete,
+  describeTransferProgress,
+  FileTransferService,
+} from '../../core/transfer/FileTransferService.js';
+import { fmtBytes } from '../../core/transfer/TransferMeter.js';
+import { DEVICE_LOGS_DIR_NAME } from '../../shared/const.js';
+import { CommandFailedError } from '../../shared/errors.js';
 import type { EdgePanelProvider } from '../panels/extensionPanel.js';
 
 const log = getLogger('cmd.logging');
 
+/** `base` 자신이거나 그 로테이션 파일(base.1, base.2.gz …)이면 true */
+function isRotationOf(base: string, name: string): boolean {
+  if (!name.startsWith(base)) return false;
+  return /^(?:\.\d+)?(?:\.gz)?$/.test(name.slice(base.length));
+}
+
+const LIVE_USAGE =
+  'homeyLoggingLive [--device [<serial>]] [--append <파일> [--append-format raw|jsonl] ' +
+  '[--append-max <크기 예: 10M>] [--append-keep <N>]] ' +
+  '[--since <시각> [--until <시각>]] [필터]';
+const LIVE_OPTIONS = [
+  '--device',
+  '--append',
+  '--append-format',
+  '--append-max',
+  '--append-keep',
+  '--since',
+  '--until',
+];
+
 export class CommandHandlersLogging {
   constructor(
     private provider?: EdgePanelProvider, // 🔁 Provider 주입
+    private context?: vscode.ExtensionContext,
   ) {}
 
-  /** 새 버튼: 실시간 로그 보기 (필터 입력 없이 바로 시작) */
+  /**
+   * 새 버튼: 실시간 로그 보기 (버튼은 필터 입력 없이 바로 시작)
+   * - 콘솔 인자로 빠른 필터: `wifi !heartbeat` → wifi 포함 AND heartbeat 제외(대소문자 무시)
+   */
   @measure()
-  async startRealtime() {
+  async startRealtime(filter = '') {
     log.debug('CommandHandlersLogging.startRealtime: start');
-    if (!this.provider) {
-      log.error('logging: provider not ready');
-      return;
+    if (!this.provider) throw new CommandFailedError('logging: provider not ready');
+    // 앞쪽 옵션만 소비하고 나머지는 필터로 넘김
+    // --device [serial]: 현재 연결을 바꾸지 않고 다른 ADB 기기의 logcat을 옆 뷰어에서 본다(값 없으면 선택)
+    // --append <파일>: 보는 동안 라인을 로컬 파일에도 기록(상대 경로는 워크스페이스 기준)
+    // --since/--until <시각>: journalctl 과거 구간 조회(until 없으면 since부터 이어서 follow)
+    const parsed = this._parseLiveOptions(filter);
+    if (typeof parsed === 'string') throw new CommandFailedError(`${parsed}\n${LIVE_USAGE}`);
+    const { window, rest } = parsed;
+    const device = parsed.device === '' ? await this._pickAdbDevice() : parsed.device;
+    if (parsed.device === '' && !device) return;
+    let capture = parsed.capture;
+    if (capture && !path.isAbsolute(capture.file) && this.context) {
+      const ws = await resolveWorkspaceInfo(this.context);
+      capture = { ...capture, file: path.join(ws.wsDirFsPath, capture.file) };
     }
     try {
-      await this.provider.startRealtime(undefined);
-      log.info('logging: started realtime session');
+      // 인자 전체를 감싼 따옴표 한 겹은 벗김: "wifi !heartbeat" == wifi !heartbeat
+      const f = rest.trim().replace(/^(['"])(.*)\1$/, '$2') || undefined;
+      await this.provider.startRealtime(f, undefined, device, capture, window);
+      const where = device ? ` on ${device}` : '';
+      const until = window?.until ?? '(follow)';
+      const span = window ? ` since=${window.since ?? '-'} until=${until}` : '';
+      log.info(`logging: started realtime session${where}${span}${f ? ` (filter: ${f})` : ''}`);
+      if (capture) log.result(`[logging] capturing to ${capture.file}`);
     } catch (e: any) {
       const msg = e?.message ?? String(e);
       log.error('logging: startRealtime failed', { error: msg });
-      throw e;
+      throw new CommandFailedError(`homeyLoggingLive: ${msg}`, e, true);
     }
   }
 
-  /** 새 버튼: 로그 파일 열기 (폴더 선택 다이얼로그 → 병합 시작) */
+  /** `adb devices`에서 'device' 상태인 기기 하나를 고른다(현재 연결 기기는 설명에 표시) */
+  private async _pickAdbDevice(): Promise<string | undefined> {
+    const ready = (await listDevices()).filter((d) => d.state === 'device');
+    if (!ready.length) {
+      vscode.window.showWarningMessage('"device" 상태인 ADB 기기가 없습니다 (adb devices 확인).');
+      return undefined;
+    }
+    const active = connectionManager.getSnapshot()?.active;
+    const current = active?.type === 'ADB' ? (active.details as AdbDetails).deviceID : undefined;
+    const pick = await vscode.window.showQuickPick(
+      ready.map((d) => ({ label: d.id, description: d.id === current ? '현재 연결' : undefined })),
+      { placeHolder: '실시간 로그를 옆 뷰어로 볼 ADB 기기', ignoreFocusOut: true },
+    );
+    return pick?.label;
+  }
+
+  /** homeyLoggingLive 앞쪽 옵션 파싱. 잘못된 값이면 오류 문구(string)를 돌려준다 */
+  private _parseLiveOptions(
+    args: string,
+  ):
+    | { device?: string; capture?: LogCaptureOptions; window?: JournalWindow; rest: string }
+    | string {
+    let rest = args.trim();
+    const vals = new Map<string, string>();
+    for (;;) {
+      const m = /^(--[\w-]+)(?:\s+("[^"]*"|'[^']*'|\S+))?(?:\s+|$)/.exec(rest);
+      if (!m || !LIVE_OPTIONS.includes(m[1])) break;
+      const v = m[2]?.replace(/^(['"])(.*)\1$/, '$2');
+      if (m[1] === '--device' && (!v || v.startsWith('--'))) {
+        // 값 없는 --device: 시작할 때 연결된 ADB 기기 중에서 고른다
+        vals.set(m[1], '');
+        rest = rest.slice(m[1].length).trimStart();
+        continue;
+      }
+      if (!v || v.startsWith('--')) return `${m[1]}: 값이 필요합니다`;
+      vals.set(m[1], v);
+      rest = rest.slice(m[0].length);
+    }
+
+    const since = vals.get('--since');
+    const until = vals.get('--until');
+    const window = since || until ? { since, until } : undefined;
+    if (window) {
+      const bad = journalWindowError(window);
+      if (bad) return bad;
+      if (vals.has('--device')) return '--since/--until은 --device(logcat)와 함께 쓸 수 없습니다';
+    }
+
+    const file = vals.get('--append');
+    const format = vals.get('--append-format');
+    const max = vals.get('--append-max');
+    const keep = vals.get('--append-keep');
+    if (!file) {
+      if (format || max || keep) return '--append-* 옵션은 --append <파일>과 함께 사용하세요';
+      return { device: vals.get('--device'), window, rest };
+    }
+    if (format && format !== 'raw' && format !== 'jsonl') {
+      return `--append-format: raw 또는 jsonl (입력: ${format})`;
+    }
+    const maxBytes = max !== undefined ? parseCaptureSize(max) : undefined;
+    if (max !== undefined && maxBytes === undefined) return `--append-max: 잘못된 크기 ${max}`;
+    if (keep !== undefined && !/^\d+$/.test(keep)) return `--append-keep: 잘못된 개수 ${keep}`;
+    const capture: LogCaptureOptions = {
+      file,
+      format: format as LogCaptureFormat | undefined,
+      maxBytes,
+      keep: keep !== undefined ? Number(keep) : undefined,
+    };
+    return { device: vals.get('--device'), capture, window, rest };
+  }
+
+  /**
+   * 새 버튼: 로그 파일 열기
+   * - 인자 없음 / --dir [경로]: 폴더 선택(또는 지정) → 병합 시작
+   * - --file [경로]: 단일 파일 보기(타입 그룹/타임존 병합 없이)
+   * - --tail [원격 경로]: 연결된 기기의 파일 하나를 실시간으로 따라감(tail -f)
+   */
   @measure()
-  async startFileMerge() {
+  async startFileMerge(args = '') {
     log.debug('CommandHandlersLogging.startFileMerge: start');
-    if (!this.provider) {
-      log.error('logging: provider not ready');
-      return;
+    if (!this.provider) throw new CommandFailedError('logging: provider not ready');
+    const m = /^(--dir|--file|--tail)(?:\s+(.+))?$/.exec(args.trim());
+    if (args.trim() && !m) {
+      throw new CommandFailedError('homeyLoggingFile [--dir <경로>|--file <경로>|--tail <원격 경로>]');
     }
+    if (m?.[1] === '--tail') return this.startRemoteTail(m[2]?.trim().replace(/^"(.*)"$/, '$1'));
+    const single = m?.[1] === '--file';
+    const given = m?.[2]?.trim().replace(/^"(.*)"$/, '$1');
     try {
       // 1) 웹 로그 뷰어를 먼저 오픈 (QuickPick 없이)
       await this.provider.handleHomeyLoggingCommand();
 
-      // 2) 폴더 선택 다이얼로그 표시
-      const picked = await (
-        await import('vscode')
-      ).window.showOpenDialog({
-        canSelectFiles: false,
-        canSelectFolders: true,
-        canSelectMany: false,
-        title: '병합할 로그 디렉터리를 선택하세요',
-      });
-      if (picked && picked[0]) {
-        await this.provider.startFileMerge(picked[0].fsPath);
-        log.info('logging: started file-merge session');
-      } else {
+      // 2) 경로가 없으면 선택 다이얼로그 표시
+      let target = given;
+      if (!target) {
+        const picked = await vscode.window.showOpenDialog({
+          canSelectFiles: single,
+          canSelectFolders: !single,
+          canSelectMany: false,
+          title: single ? '열 로그 파일을 선택하세요' : '병합할 로그 디렉터리를 선택하세요',
+        });
+        target = picked?.[0]?.fsPath;
+      }
+      if (!target) {
         log.debug('logging: startFileMerge cancelled by user');
+        return;
+      }
+      if (single) {
+        await this.provider.startSingleFile(target);
+        log.info(`logging: opened single log file ${target}`);
+      } else {
+        await this.provider.startFileMerge(target);
+        log.info('logging: started file-merge session');
       }
     } catch (e: any) {
       const msg = e?.message ?? String(e);
       log.error('logging: startFileMerge failed', { error: msg });
-      throw e;
+      throw new CommandFailedError(`homeyLoggingFile: ${msg}`, e, true);
+    }
+  }
+
+  /** 원격 파일 하나를 실시간 뷰어로 따라감 — 경로가 없으면 입력받는다 */
+  private async startRemoteTail(given?: string) {
+    const remotePath =
+      given ||
+      (await vscode.window.showInputBox({
+        prompt: '실시간으로 볼 원격 파일 경로',
+        placeHolder: '예) /var/log/messages',
+        ignoreFocusOut: true,
+      }));
+    if (!remotePath) return;
+    try {
+      await this.provider!.startRealtime(undefined, remotePath);
+      log.info(`logging: tailing remote file ${remotePath}`);
+    } catch (e: any) {
+      throw new CommandFailedError(`logging --tail ${remotePath}: ${e?.message ?? String(e)}`, e);
+    }
+  }
+
+  /**
+   * 기기 로그 보관: 연결 설정 log_sources의 `file:` 소스와 그 로테이션 파일(.N, .N.gz)을 로컬로 가져온다.
+   * - 저장: <workspace>/device_logs/<연결ID>_<YYYYMMDD-HHmmss>/
+   * - 한 파일이라도 전송에 실패하면 일부만 받은 보관본을 성공으로 보고하지 않고 오류로 끝낸다.
+   * - 완료 후 선택 시 파일 병합 뷰로 바로 연다.
+   */
+  @measure()
+  async saveDeviceLogs(signal?: AbortSignal) {
+    log.debug('CommandHandlersLogging.saveDeviceLogs: start');
+    const active = connectionManager.getSnapshot()?.active;
+    if (!active) {
+      vscode.window.showErrorMessage('연결된 호스트가 없습니다. 먼저 연결하세요.');
+      throw new CommandFailedError('연결된 호스트가 없습니다.', undefined, true);
     }
+    if (!this.context) throw new CommandFailedError('logging: context not ready');
+
+    // 1) 로그 소스 → 디렉터리별 대상 파일(원본 + 로테이션)
+    const ws = await resolveWorkspaceInfo(this.context);
+    const sources = logSourceFilePaths(await readConnectionConfig(ws.wsDirFsPath));
+    if (!sources.length) {
+      const msg = '연결 설정(defaultLoggingConfig.log_sources)에 file: 로그 소스가 없습니다.';
+      vscode.window.showWarningMessage(msg);
+      throw new CommandFailedError(msg, undefined, true);
+    }
+    const host = new HostController(connectionManager, '');
+    const byDir = new Map<string, string[]>();
+    for (const src of sources) {
+      const dir = path.posix.dirname(src);
+      const { stdout } = await host.execOut(`ls -1 ${shellQuote(dir)} 2>/dev/null`);
+      const picked = byDir.get(dir) ?? [];
+      for (const name of String(stdout || '').split(/\r?\n/)) {
+        const n = name.trim();
+        if (isRotationOf(path.posix.basename(src), n) && !picked.includes(n)) picked.push(n);
+      }
+      if (picked.length) byDir.set(dir, picked);
+    }
+    const count = [...byDir.values()].reduce((n, l) => n + l.length, 0);
+    if (!count) {
+      const msg = `가져올 로그 파일이 없습니다: ${sources.join(', ')}`;
+      vscode.window.showWarningMessage(msg);
+      throw new CommandFailedError(msg, undefined, true);
+    }
+
+    // 2) 로컬 보관 폴더
+    const stamp = new Date()
+      .toISOString()
+      .replace(/[-:]/g, '')
+      .replace('T', '-')
+      .replace(/\..*$/, '');
+    const connId = String(active.id ?? 'device').replace(/[^\w.-]+/g, '_');
+    const localDir = path.join(ws.wsDirFsPath, DEVICE_LOGS_DIR_NAME, `${connId}_${stamp}`);
+
+    // 소스 디렉터리마다 하위 폴더(/var/log → var_log) — 디렉터리가 달라도 같은 이름의 파일이 있다
+    const subDirs = new Map<string, string>();
+    for (const dir of byDir.keys()) {
+      const base = dir.replace(/^\/+/, '').replace(/[^\w.-]+/g, '_') || 'root';
+      let name = base;
+      for (let i = 2; [...subDirs.values()].includes(name); i++) name = `${base}_${i}`;
+      subDirs.set(dir, name);
+    }
+
+    // 3) 전송(진행률 표시) — 디렉터리마다 한 번, 실패가 있으면 즉시 오류
+    const summary = await vscode.window.withProgress(
+      {
+        // 조용한 모드: 알림 팝업 대신 상태 표시줄에만 진행 표시
+        location: isQuietMode()
+          ? vscode.ProgressLocation.Window
+          : vscode.ProgressLocation.Notification,
+        title: `로그 가져오기: ${active.alias ?? active.id}`,
+      },
+      async (progress) => {
+        const sum = { done: 0, skipped: 0, bytes: 0 };
+        for (const [dir, files] of byDir) {
+          progress.report({ message: `${dir} (${files.length} files)` });
+          const s = await new FileTransferService(connectionManager).downloadViaTarBase64(
+            dir,
+            path.join(localDir, subDirs.get(dir)!),
+            {
+              paths: files,
+              signal,
+              onProgress: (p) =>
+                progress.report({ message: describeTransferProgress(p) || dir }),
+            },
+          );
+          assertTransferComplete(`pull ${dir}`, s);
+          sum.done += s.done;
+          sum.skipped += s.skipped;
+          sum.bytes += s.bytes ?? 0;
+        }
+        return sum;
+      },
+    );
+    const moved = summary.bytes ? `, ${fmtBytes(summary.bytes)}` : '';
+    log.info(
+      `logging: saved device logs ${[...byDir.keys()].join(', ')} -> ${localDir} ` +
+        `(${summary.done} ok, ${summary.skipped} skipped${moved})`,
+    );
+
+    // 4) 병합 보기(선택) — 알림 응답을 기다리지 않고 결과를 바로 돌려준다(runScript/--json)
+    //    병합은 폴더 하나(비재귀) 단위라 소스 디렉터리가 여럿이면 어느 폴더를 볼지 고른다
+    const open = '병합해서 보기';
+    void vscode.window
+      .showInformationMessage(`로그 ${summary.done}개를 저장했습니다: ${localDir}`, open)
+      .then(async (pick) => {
+        if (pick !== open || !this.provider) return;
+        const names = [...subDirs.values()];
+        const sub =
+          names.length > 1
+            ? await vscode.window.showQuickPick(names, { placeHolder: '병합해서 볼 로그 폴더' })
+            : names[0];
+        if (!sub) return;
+        await this.provider.handleHomeyLoggingCommand();
+        await this.provider.startFileMerge(path.join(localDir, sub));
+      })
+      .then(undefined, (e) => log.error(`logging: open saved logs failed: ${e?.message ?? e}`));
+    return { dir: localDir, ...summary };
   }
 }
@@ -1,8 +1,14 @@
 // === src/extension/commands/CommandHandlersUpdate.ts ===
 import * as vscode from 'vscode';
 
+import {
+  createDeadline,
+  defaultDeadlineMs,
+  parseDurationMs,
+} from '../../core/connection/deadline.js';
 import { getLogger } from '../../core/logging/extension-logger.js';
 import { measure } from '../../core/logging/perf.js';
+import { CommandFailedError } from '../../shared/errors.js';
 import { checkLatestVersion, downloadAndInstall } from '../update/updater.js';
 
 const log = getLogger('cmd.update');
@@ -10,9 +16,17 @@ const log = getLogger('cmd.update');
 export class CommandHandlersUpdate {
   constructor(private extensionUri?: vscode.Uri) {}
 
+  /**
+   * updateNow [--deadline <초|Nm>]
+   * - 다운로드 전체 마감(생략 시 EDGE_TOOL_DEADLINE) — 초과/취소 시 진행 중 다운로드를 끊는다
+   */
   @measure()
-  async updateNow() {
+  async updateNow(args = '', signal?: AbortSignal) {
     log.debug('[debug] CommandHandlersUpdate updateNow: start');
+    const m = /(?:^|\s)--deadline\s+(\S+)/.exec(args);
+    const deadlineMs = m ? parseDurationMs(m[1]) : defaultDeadlineMs();
+    if (m && !deadlineMs) throw new CommandFailedError('updateNow [--deadline <초|Nm>]');
+    const dl = createDeadline(deadlineMs, signal);
     try {
       const version =
         vscode.extensions.getExtension('lge.homey-edgetool')?.packageJSON?.version ?? '0.0.0';
@@ -20,31 +34,37 @@ export class CommandHandlersUpdate {
       if (!latest.hasUpdate || !latest.url || !latest.sha256) {
         vscode.window.showInformationMessage('No update available or invalid update info.');
         log.debug('[debug] CommandHandlersUpdate updateNow: end');
-        return;
+        return { updated: false, version };
       }
-      await downloadAndInstall(latest.url, latest.sha256);
+      await downloadAndInstall(latest.url, latest.sha256, undefined, dl.signal);
       log.debug('[debug] CommandHandlersUpdate updateNow: end');
+      return { updated: true, version, url: latest.url };
     } catch (e) {
+      if (signal?.aborted) throw e;
       log.error('updateNow failed', e as any);
       vscode.window.showErrorMessage('Update failed: ' + (e as Error).message);
+      throw new CommandFailedError(`updateNow: ${(e as Error).message}`, e, true);
+    } finally {
+      dl.dispose();
     }
   }
 
   @measure()
   async openHelp() {
     log.debug('[debug] CommandHandlersUpdate openHelp: start');
-    if (!this.extensionUri) return log.error('[error] internal: no extension uri');
+    if (!this.extensionUri) throw new CommandFailedError('internal: no extension uri');
     try {
       const helpUri = vscode.Uri.joinPath(this.extensionUri, 'media', 'resources', 'help.md');
       await vscode.workspace.fs.stat(helpUri);
       const doc = await vscode.workspace.openTextDocument(helpUri);
       await vscode.commands.executeCommand('markdown.showPreview', doc.uri);
       log.debug('[debug] CommandHandlersUpdate openHelp: end');
-    } catch {
+    } catch (e) {
       log.error('help.md를 찾을 수 없습니다: media/resources/help.md');
       vscode.window.showWarningMessage(
         'help.md를 찾을 수 없습니다. media/resources/help.md 위치에 파일이 있는지 확인하세요.',
       );
+      throw new CommandFailedError('help.md를 찾을 수 없습니다.', e, true);
     }
   }
 }
@@ -1,10 +1,15 @@
 // === src/extension/commands/CommandHandlersWorkspace.ts ===
 import { execFile as execFileCb } from 'child_process';
+import * as fsp from 'fs/promises';
 import * as path from 'path';
 import { promisify } from 'util';
 import * as vscode from 'vscode';
 
+import { loadHomeyLayout } from '../../core/config/homeyLayout.js';
 import { changeWorkspaceBaseDir, resolveWorkspaceInfo } from '../../core/config/userdata.js';
+import { connectionManager } from '../../core/connection/ConnectionManager.js';
+import { isToolMissingError, toolInstallHint } from '../../core/connection/toolPreflight.js';
+import { SKIP_COMMIT_PREFIX } from '../../core/controller/GitController.js';
 import { getLogger } from '../../core/logging/extension-logger.js';
 import { measure } from '../../core/logging/perf.js';
 import {
@@ -15,7 +20,7 @@ import {
   PARSER_TEMPLATE_REL,
   RAW_DIR_NAME,
 } from '../../shared/const.js';
-import { ErrorCategory, XError } from '../../shared/errors.js';
+import { CommandFailedError, ErrorCategory, XError } from '../../shared/errors.js';
 import { PerfMonitorPanel } from '../editors/PerfMonitorPanel.js';
 import { migrateParserConfigIfNeeded } from '../setup/parserConfigSeeder.js';
 import { ensureUserConfigExists, migrateUserConfigIfNeeded } from '../setup/userConfigSeeder.js';
@@ -43,7 +48,7 @@ export class CommandHandlersWorkspace {
   @measure()
   async changeWorkspaceQuick() {
     log.debug('[debug] CommandHandlersWorkspace changeWorkspaceQuick: start');
-    if (!this.context) return log.error('[error] internal: no extension context');
+    if (!this.context) throw new CommandFailedError('internal: no extension context');
 
     const startTime = Date.now();
 
@@ -120,6 +125,7 @@ export class CommandHandlersWorkspace {
     } catch (e: any) {
       const duration = Date.now() - startTime;
       log.error(`changeWorkspaceQuick failed after ${duration}ms`, e);
+      throw new CommandFailedError(`changeWorkspaceQuick: ${e?.message ?? String(e)}`, e, true);
     }
   }
 
@@ -127,7 +133,7 @@ export class CommandHandlersWorkspace {
   @measure()
   async openWorkspace() {
     log.debug('[debug] CommandHandlersWorkspace openWorkspace: start');
-    if (!this.context) return log.error('[error] internal: no extension context');
+    if (!this.context) throw new CommandFailedError('internal: no extension context');
     try {
       const info = await resolveWorkspaceInfo(this.context);
       await vscode.env.openExternal(info.wsDirUri);
@@ -135,15 +141,14 @@ export class CommandHandlersWorkspace {
     } catch (e: any) {
       log.error(`workspace open failed: ${e?.message || String(e)}`);
       vscode.window.showWarningMessage('Workspace가 아직 설정되지 않았습니다.');
+      throw new CommandFailedError(`openWorkspace: ${e?.message || String(e)}`, e, true);
     }
   }
 
   @measure()
   async openWorkspaceShell() {
     log.debug('[debug] CommandHandlersWorkspace openWorkspaceShell: start');
-    if (!this.context) {
-      return log.error('[error] internal: no extension context');
-    }
+    if (!this.context) throw new CommandFailedError('internal: no extension context');
     try {
       const info = await resolveWorkspaceInfo(this.context);
       const t = vscode.window.createTerminal({
@@ -155,6 +160,7 @@ export class CommandHandlersWorkspace {
     } catch (e: any) {
       log.error(`openWorkspaceShell failed: ${e?.message || String(e)}`);
       vscode.window.showErrorMessage('작업폴더를 확인할 수 없습니다.');
+      throw new CommandFailedError(`openWorkspaceShell: ${e?.message || String(e)}`, e, true);
     }
   }
 
@@ -162,7 +168,7 @@ export class CommandHandlersWorkspace {
   // (옵션) 현재 상태 확인용
   async showWorkspace() {
     log.debug('[debug] CommandHandlersWorkspace showWorkspace: start');
-    if (!this.context) return log.error('[error] internal: no extension context');
+    if (!this.context) throw new CommandFailedError('internal: no extension context');
     const info = await resolveWorkspaceInfo(this.context);
     if (info.source === 'user') {
       log.debug(`workspace (사용자 지정) base=${info.baseDirFsPath}`);
@@ -212,8 +218,8 @@ export class CommandHandlersWorkspace {
       await execFile('git', ['init'], { cwd: wsDir });
       log.debug('git init done');
     } catch (e: any) {
-      if (e?.code === 'ENOENT') {
-        log.error('git init failed: git executable not found on PATH (ENOENT)');
+      if (isToolMissingError(e)) {
+        log.error(`git init failed: git executable not found on PATH — ${toolInstallHint('git')}`);
       } else {
         log.error(`git init failed: ${e?.message || String(e)}`);
       }
@@ -223,18 +229,114 @@ export class CommandHandlersWorkspace {
   @measure()
   async togglePerformanceMonitoring(extensionUri?: vscode.Uri) {
     log.debug('[debug] CommandHandlersWorkspace togglePerformanceMonitoring: start');
-    if (!this.context || !extensionUri)
-      return log.error('[error] internal: no context or extension uri');
+    if (!this.context || !extensionUri) {
+      throw new CommandFailedError('internal: no context or extension uri');
+    }
 
     const panel = new PerfMonitorPanel(extensionUri, this.context);
     panel.createPanel();
     log.debug('[debug] CommandHandlersWorkspace togglePerformanceMonitoring: end');
   }
 
+  /**
+   * workspaceClean [--dirs|--git] [--wipe-history] [--force]
+   * - 기본: 동기화 폴더(host_sync, homey_*)를 비우고 삭제를 커밋(이력 유지)
+   * - --git: 폴더는 두고 git 저장소만 정리 / --dirs: 폴더만 비우고 커밋하지 않음
+   * - --wipe-history: .git 삭제 후 재초기화(.gitignore/.config만 첫 커밋)
+   * - 커밋되지 않은 변경이 있으면 --force 없이는 거부(--force여도 정리 대상 폴더만 커밋)
+   */
+  @measure()
+  async workspaceClean(args = '') {
+    if (!this.context) throw new CommandFailedError('internal: no extension context');
+    const flags = new Set(args.split(/\s+/).filter(Boolean));
+    const unknown = [...flags].filter(
+      (f) => !['--dirs', '--git', '--wipe-history', '--force'].includes(f),
+    );
+    if (unknown.length || (flags.has('--dirs') && flags.has('--git'))) {
+      throw new CommandFailedError('workspaceClean [--dirs|--git] [--wipe-history] [--force]');
+    }
+    const cleanDirs = !flags.has('--git');
+    const resetGit = !flags.has('--dirs');
+    const wipe = flags.has('--wipe-history');
+
+    const info = await resolveWorkspaceInfo(this.context);
+    const ws = info.wsDirFsPath;
+    const layout = await loadHomeyLayout(ws, connectionManager.getSnapshot()?.active?.id);
+    const dirs = ['host_sync', ...Object.values(layout.local_dirs)];
+
+    // 커밋되지 않은 변경 확인(저장소가 없으면 통과)
+    const dirty = await execFile('git', ['status', '--porcelain'], { cwd: ws })
+      .then(({ stdout }) => String(stdout).trim())
+      .catch(() => '');
+    if (dirty && !flags.has('--force')) {
+      const n = dirty.split(/\r?\n/).length;
+      vscode.window.showErrorMessage(
+        `커밋되지 않은 변경이 ${n}개 있습니다. 커밋하거나 --force로 실행하세요.`,
+      );
+      throw new CommandFailedError(
+        `workspaceClean: ${n} uncommitted change(s) — commit or use --force`,
+      );
+    }
+
+    const what = [
+      cleanDirs && `폴더 비우기(${dirs.join(', ')})`,
+      resetGit && (wipe ? 'git 이력 삭제 후 재초기화' : '정리 내용 커밋'),
+    ].filter(Boolean);
+    const ok = await vscode.window.showWarningMessage(
+      `워크스페이스를 정리합니다: ${what.join(' / ')}\n${ws}`,
+      { modal: true },
+      '정리',
+    );
+    if (ok !== '정리') return;
+
+    try {
+      if (cleanDirs) {
+        for (const d of dirs) {
+          await fsp.rm(path.join(ws, d), { recursive: true, force: true });
+          await fsp.mkdir(path.join(ws, d), { recursive: true });
+        }
+        log.info(`[info] workspaceClean: emptied ${dirs.join(', ')}`);
+      }
+      if (resetGit && wipe) {
+        await fsp.rm(path.join(ws, '.git'), { recursive: true, force: true });
+        await this.ensureGitInitAsync(ws);
+        await execFile('git', ['add', '.gitignore', '.config'], { cwd: ws }).catch(() => {});
+        await this.commitIfChanged(ws, `${SKIP_COMMIT_PREFIX} reset workspace`);
+        log.info('[info] workspaceClean: git history wiped and re-initialized');
+      } else if (resetGit) {
+        // 정리 대상 폴더만 스테이징 → --force여도 사용자의 다른 미커밋 변경은 커밋하지 않음
+        await execFile('git', ['add', '-A', '--', ...dirs], { cwd: ws });
+        await this.commitIfChanged(ws, `${SKIP_COMMIT_PREFIX} clean workspace`);
+        log.info('[info] workspaceClean: committed cleanup (history kept)');
+      }
+      vscode.window.showInformationMessage('워크스페이스 정리 완료');
+      const git = resetGit ? (wipe ? 'wiped' : 'committed') : null;
+      return { workspace: ws, emptied: cleanDirs ? dirs : [], git };
+    } catch (e: any) {
+      log.error('workspaceClean failed', e);
+      vscode.window.showErrorMessage('워크스페이스 정리 실패: ' + (e?.message ?? String(e)));
+      throw new CommandFailedError(`workspaceClean: ${e?.message ?? String(e)}`, e, true);
+    }
+  }
+
+  /** 스테이징된 변경이 있을 때만 커밋한다. 커밋 실패는 호출자에게 그대로 전달 */
+  private async commitIfChanged(cwd: string, message: string) {
+    const staged = await execFile('git', ['diff', '--cached', '--quiet'], { cwd }).then(
+      () => false,
+      (e: any) => {
+        // exit 1 = 스테이징된 변경 있음, 그 외는 git 자체 오류
+        if (e?.code === 1) return true;
+        throw e;
+      },
+    );
+    if (!staged) return;
+    await execFile('git', ['commit', '-m', message], { cwd });
+  }
+
   @measure()
   async initWorkspace() {
     log.debug('[debug] CommandHandlersWorkspace initWorkspace: start');
-    if (!this.context) return log.error('[error] internal: no extension context');
+    if (!this.context) throw new CommandFailedError('internal: no extension context');
     try {
       const info = await resolveWorkspaceInfo(this.context);
       const cfgDir = vscode.Uri.joinPath(info.wsDirUri, '.config');
@@ -310,6 +412,7 @@ export class CommandHandlersWorkspace {
     } catch (e: any) {
       log.error('initWorkspace failed', e);
       vscode.window.showErrorMessage('Workspace 초기화 실패: ' + (e?.message ?? String(e)));
+      throw new CommandFailedError(`initWorkspace: ${e?.message ?? String(e)}`, e, true);
     } finally {
       log.debug('[debug] CommandHandlersWorkspace initWorkspace: end');
     }
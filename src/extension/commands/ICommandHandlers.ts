@@ -2,4 +2,5 @@
 export interface ICommandHandlers {
   route(raw: string): Promise<void>;
   help(): Promise<void>;
+  cancelRunning(): number;
 }
@@ -1,13 +1,24 @@
 // === src/extension/commands/commandHandlers.ts ===
 import { exec as execCb } from 'child_process';
+import * as fs from 'fs';
 import * as path from 'path';
 import { promisify } from 'util';
 import * as vscode from 'vscode';
 
 // 사용자 구성 저장소
 import { resolveWorkspaceInfo } from '../../core/config/userdata.js';
+import { connectionManager } from '../../core/connection/ConnectionManager.js';
+import {
+  formatPreflightReport,
+  requiredToolsFor,
+  runToolPreflight,
+} from '../../core/connection/toolPreflight.js';
+import { appendAuditLog, isAuditLogEnabled } from '../../core/logging/audit-log.js';
 import { getLogger } from '../../core/logging/extension-logger.js';
 import { measure } from '../../core/logging/perf.js';
+import { runQuiet, stripQuietFlag } from '../../core/logging/quiet-mode.js';
+import { AUDIT_LOG_REL } from '../../shared/const.js';
+import { CommandFailedError } from '../../shared/errors.js';
 import type { EdgePanelProvider } from '../panels/extensionPanel.js';
 import { CommandHandlersConnect } from './CommandHandlersConnect.js';
 import { CommandHandlersGit } from './CommandHandlersGit.js';
@@ -21,6 +32,20 @@ import { CommandHandlersWorkspace } from './CommandHandlersWorkspace.js';
 const log = getLogger('cmd');
 const exec = promisify(execCb);
 
+/** 명령 정의(이름/별칭/설명/실행기) */
+export type CommandDef = {
+  name: string;
+  aliases?: string[];
+  help: string;
+  /** help 목록에서 숨김 */
+  hidden?: boolean;
+  /**
+   * args: 명령 이름 뒤 나머지 문자열(없으면 '')
+   * signal: 명령 취소(Ctrl+C / cancelRunning) — 장시간 작업은 원격 명령·워크플로·전송까지 넘긴다
+   */
+  run: (args: string, signal: AbortSignal) => Promise<unknown> | unknown;
+};
+
 class CommandHandlers {
   // 분리된 핸들러들
   private workspaceHandler: CommandHandlersWorkspace;
@@ -31,6 +56,8 @@ class CommandHandlers {
   private gitHandler: CommandHandlersGit;
   private connectHandler: CommandHandlersConnect;
   private parserHandler: CommandHandlersParser;
+  /** 실행 중인 명령별 취소기 */
+  private running = new Set<AbortController>();
 
   constructor(
     private context?: vscode.ExtensionContext,
@@ -41,75 +68,462 @@ class CommandHandlers {
     this.workspaceHandler = new CommandHandlersWorkspace(this.context);
     this.updateHandler = new CommandHandlersUpdate(this.extensionUri);
     this.homeyHandler = new CommandHandlersHomey();
-    this.loggingHandler = new CommandHandlersLogging(this.provider);
+    this.loggingHandler = new CommandHandlersLogging(this.provider, this.context);
     this.hostHandler = new CommandHandlersHost();
     this.gitHandler = new CommandHandlersGit(this.context);
     this.connectHandler = new CommandHandlersConnect(this.context);
     this.parserHandler = new CommandHandlersParser(this.context);
   }
 
+  /**
+   * 명령 레지스트리 — 라우팅과 help가 모두 이 테이블을 순회한다.
+   * 새 명령은 항목 하나만 추가하면 된다.
+   */
+  private readonly registry: CommandDef[] = [
+    { name: 'help', aliases: ['h'], help: '명령 목록', hidden: true, run: () => this.help() },
+    {
+      name: 'doctor',
+      help: '로컬 도구(adb/ssh/scp/git) 설치 여부 점검 + 설치 안내',
+      run: () => this.doctor(),
+    },
+    {
+      name: 'runScript',
+      aliases: ['script'],
+      help: '명령 파일 실행(한 줄에 명령 하나, # 주석) <파일> [--continue-on-error]',
+      run: (args, signal) => this.runScript(args, signal),
+    },
+
+    // === 버튼 → handler 진입점들 ===
+    {
+      name: 'homeyLoggingLive',
+      help: '실시간 로그 보기 [--device [<serial>]](옆 뷰어) [--append <파일> …] [--since/--until <시각>] [필터]',
+      run: (args) => this.loggingHandler.startRealtime(args),
+    },
+    {
+      name: 'homeyLoggingFile',
+      aliases: ['logging'],
+      help: '로그 파일 병합 보기 [--dir <경로>|--file <경로>(단일 파일)|--tail <원격 경로>(실시간)]',
+      run: (args) => this.loggingHandler.startFileMerge(args),
+    },
+    {
+      name: 'homeyLogsSave',
+      help: '기기 로그 파일 로컬 보관(+병합 보기)',
+      run: (_args, signal) => this.loggingHandler.saveDeviceLogs(signal),
+    },
+    {
+      name: 'homeyRestart',
+      help: 'Homey 재시작',
+      run: (_args, signal) => this.homeyHandler.homeyRestart(signal),
+    },
+    {
+      name: 'homeyServiceRedetect',
+      help: 'Homey 서비스 유닛 다시 탐지(여러 개면 선택) [--service <유닛명>(직접 지정)]',
+      run: (args) => this.homeyHandler.homeyServiceRedetect(args),
+    },
+    {
+      name: 'homeyVolumeToggle',
+      help: 'Homey 볼륨 마운트 토글',
+      run: (_args, signal) => this.homeyHandler.homeyVolumeToggle(signal),
+    },
+    {
+      name: 'homeyMountStatus',
+      aliases: ['mountStatus'],
+      help: '서비스 파일 마운트/토글 편집 상태 보기',
+      run: () => this.homeyHandler.homeyMountStatus(),
+    },
+    {
+      name: 'homeyAppLogToggle',
+      help: 'App Log 토글',
+      run: (_args, signal) => this.homeyHandler.homeyAppLogToggle(signal),
+    },
+    {
+      name: 'homeyDevTokenToggle',
+      help: 'DevToken 토글',
+      run: (_args, signal) => this.homeyHandler.homeyDevTokenToggle(signal),
+    },
+    {
+      name: 'homeyEnv',
+      aliases: ['homey-env'],
+      help: '서비스 파일 환경변수(--env=) 보기/설정 [list | set <KEY> <VALUE> | unset <KEY>]',
+      run: (args, signal) => this.homeyHandler.homeyEnv(args, signal),
+    },
+    {
+      name: 'openHostShell',
+      aliases: ['shell'],
+      help: '호스트 셸 열기(ADB/SSH 공통 대화형 터미널)',
+      run: () => this.hostHandler.openHostShell(),
+    },
+    {
+      name: 'exec',
+      aliases: ['host'],
+      help: '현재 연결(ADB/SSH)에서 명령 실행 후 출력 표시 [--sudo <명령>]',
+      run: (args, signal) => this.hostHandler.hostCommand(args, signal),
+    },
+    {
+      name: 'hostPull',
+      help: '기기 디렉터리를 로컬로 재귀 다운로드(.git/node_modules 등 건너뜀) <기기 경로> <로컬 경로>',
+      run: (args, signal) => this.hostHandler.hostPull(args, signal),
+    },
+    {
+      name: 'hostPush',
+      help: '로컬 디렉터리를 기기로 재귀 업로드(.git/node_modules 등 건너뜀) <로컬 경로> <기기 경로>',
+      run: (args, signal) => this.hostHandler.hostPush(args, signal),
+    },
+    {
+      name: 'hostSudo',
+      help: '활성 SSH 연결의 원격 명령을 sudo -n으로 실행 [on|off]',
+      run: (args) => this.connectHandler.setSshSudo(args),
+    },
+
+    {
+      name: 'changeWorkspaceQuick',
+      help: '워크스페이스 변경',
+      run: () => this.workspaceHandler.changeWorkspaceQuick(),
+    },
+    {
+      name: 'openWorkspace',
+      help: '워크스페이스 열기',
+      run: () => this.workspaceHandler.openWorkspace(),
+    },
+    {
+      name: 'openWorkspaceShell',
+      help: '워크스페이스 셸 열기',
+      run: () => this.workspaceHandler.openWorkspaceShell(),
+    },
+    {
+      name: 'togglePerformanceMonitoring',
+      help: '성능 모니터 토글',
+      run: () => this.workspaceHandler.togglePerformanceMonitoring(this.extensionUri),
+    },
+    {
+      name: 'gitFlow',
+      help:
+        'Git pull / push [--deadline <초|Nm>(전체 마감, 기본 EDGE_TOOL_DEADLINE)] ' +
+        '[--dry-run(push 계획만 출력)] ' +
+        '[--include/--exclude <glob>(pull 대상 선택)]',
+      run: (args, signal) => this.gitHandler.gitFlow(args, signal),
+    },
+    {
+      name: 'browse',
+      help: '기기 디렉터리 탐색 후 고른 파일/디렉터리를 host_sync로 Pull [경로(기본 /)]',
+      run: (args, signal) => this.gitHandler.browse(args, signal),
+    },
+    {
+      name: 'git',
+      help: '워크스페이스에서 git <args> 실행 (history [N]: 커밋 이력, diff-device <카테고리>: 기기와 비교)',
+      run: (args, signal) => this.gitHandler.gitCommand(args, signal),
+    },
+    {
+      name: 'updateNow',
+      help: '확장 업데이트 [--deadline <초|Nm>(다운로드 전체 마감)]',
+      run: (args, signal) => this.updateHandler.updateNow(args, signal),
+    },
+    { name: 'openHelp', help: '도움말 열기', run: () => this.updateHandler.openHelp() },
+
+    {
+      name: 'workspaceClean',
+      aliases: ['workspace-clean'],
+      help: '동기화 폴더·git 정리 [--dirs|--git] [--wipe-history] [--force]',
+      run: (args) => this.workspaceHandler.workspaceClean(args),
+    },
+    {
+      name: 'initWorkspace',
+      help: '워크스페이스 초기화',
+      run: () => this.workspaceHandler.initWorkspace(),
+    },
+    // === 웹뷰 버튼 진입점
+    { name: 'connectDevice', help: '기기 연결', run: () => this.connectHandler.connectDevice() },
+    {
+      name: 'connect',
+      help: '저장된 연결을 alias/ID로 바로 활성화 <alias|id>',
+      run: (args) => this.connectHandler.connectByAlias(args),
+    },
+    {
+      name: 'connectDefault',
+      help: '시작 시 자동 연결 대상 지정/해제 [<alias|id>|--clear]',
+      run: (args) => this.connectHandler.setDefaultConnection(args),
+    },
+    {
+      name: 'connectInfo',
+      aliases: ['connect-info'],
+      help: '현재 연결 정보 [--all: 저장된 연결 전체(유형/대상/별칭/최근 사용)]',
+      run: (args) => this.connectHandler.connectInfo(args),
+    },
+    {
+      name: 'connectEdit',
+      aliases: ['connect-edit'],
+      help: '저장된 연결 편집 [<alias|id>] (별칭/대상/비밀번호/sudo/ssh config 별칭 추가·삭제)',
+      run: (args) => this.connectHandler.editConnection(args),
+    },
+    {
+      name: 'connectConfig',
+      aliases: ['config'],
+      help: '연결 설정 파일 점검 [show(비밀번호 가림)|validate(중복 id/최근·기본 대상/로깅 소스)]',
+      run: (args) => this.connectHandler.connectConfig(args),
+    },
+  ];
+
+  /** 이름/별칭 → 정의 조회 */
+  private findCommand(name: string): CommandDef | undefined {
+    return this.registry.find((d) => d.name === name || d.aliases?.includes(name));
+  }
+
+  /** 등록된 명령 정의(읽기 전용) — 도움말/완성 등 외부 소비용 */
+  listCommands(): readonly CommandDef[] {
+    return this.registry;
+  }
+
+  /**
+   * 명령 줄 실행. 실패는 CommandFailedError로 받아 `--json`이면 ok:false 봉투로,
+   * 아니면 (핸들러가 알리지 않은 경우만) 오류로 알리고 프롬프트로 돌아간다.
+   * opts.rethrow: 알린 실패도 호출부로 던진다(runScript가 중단 여부를 정함)
+   */
   @measure()
-  async route(raw: string) {
-    const cmd = String(raw || '').trim();
-
-    switch (cmd) {
-      // === 기존 단축 명령(유지하되 최소 노출) ===
-      case 'help':
-      case 'h':
-        return this.help();
-
-      // === 버튼 → handler 진입점들 ===
-      case 'homeyLoggingLive':
-        return this.loggingHandler.startRealtime();
-      case 'homeyLoggingFile':
-        return this.loggingHandler.startFileMerge();
-
-      case 'homeyRestart':
-        return this.homeyHandler.homeyRestart();
-      case 'homeyVolumeToggle':
-        return this.homeyHandler.homeyVolumeToggle();
-      case 'homeyAppLogToggle':
-        return this.homeyHandler.homeyAppLogToggle();
-      case 'homeyDevTokenToggle':
-        return this.homeyHandler.homeyDevTokenToggle();
-      case 'openHostShell':
-        return this.hostHandler.openHostShell();
-
-      case 'changeWorkspaceQuick':
-        return this.workspaceHandler.changeWorkspaceQuick();
-      case 'openWorkspace':
-        return this.workspaceHandler.openWorkspace();
-      case 'openWorkspaceShell':
-        return this.workspaceHandler.openWorkspaceShell();
-      case 'togglePerformanceMonitoring':
-        return this.workspaceHandler.togglePerformanceMonitoring(this.extensionUri);
-      case 'gitFlow':
-        return this.gitHandler.gitFlow();
-      case 'updateNow':
-        return this.updateHandler.updateNow();
-      case 'openHelp':
-        return this.updateHandler.openHelp();
-
-      case 'initWorkspace':
-        return this.workspaceHandler.initWorkspace();
-      // === 새로 추가: 웹뷰 버튼 진입점
-      case 'connectDevice':
-        return this.connectHandler.connectDevice();
-
-      default:
-        log.info(`[info] unknown command: ${raw}`);
+  async route(raw: string, opts: { rethrow?: boolean } = {}) {
+    const text = String(raw || '').trim();
+    const sp = text.search(/\s/);
+    const cmd = sp < 0 ? text : text.slice(0, sp);
+    // 명령 이름 바로 뒤의 전역 플래그(순서 무관)
+    // `--quiet` → 이 명령 동안만 진행/상태 안내 생략
+    // `--json` → 안내 없이 결과 봉투(JSON 한 줄)만 출력
+    let args = sp < 0 ? '' : text.slice(sp + 1).trim();
+    let quiet = false;
+    let json = false;
+    for (let more = true; more; ) {
+      const q = stripQuietFlag(args);
+      const j = stripJsonFlag(q.args);
+      more = q.quiet || j.json;
+      quiet ||= q.quiet;
+      json ||= j.json;
+      args = j.args;
+    }
+    const def = this.findCommand(cmd);
+    if (!def) {
+      if (json) return emitResult({ command: cmd, ok: false, error: 'unknown command' });
+      if (opts.rethrow) throw new CommandFailedError(`unknown command: ${cmd}`);
+      log.info(`[info] unknown command: ${raw}`);
+      return;
+    }
+    const startedAt = Date.now();
+    const ac = new AbortController();
+    this.running.add(ac);
+    try {
+      const run = async () => def.run(args, ac.signal);
+      const out = quiet || json ? await runQuiet(run) : await run();
+      void this.audit(text, startedAt);
+      const durationMs = Date.now() - startedAt;
+      if (json) emitResult({ command: def.name, ok: true, durationMs, result: out });
+      return out;
+    } catch (e) {
+      void this.audit(text, startedAt, e);
+      if (json) {
+        const error = ac.signal.aborted ? 'cancelled' : e instanceof Error ? e.message : String(e);
+        emitResult({ command: def.name, ok: false, durationMs: Date.now() - startedAt, error });
+        if (opts.rethrow && !ac.signal.aborted) throw new CommandFailedError(error, e, true);
+        return;
+      }
+      // 취소된 명령은 오류로 올리지 않고 프롬프트로 돌아간다
+      if (ac.signal.aborted) return log.info(`[info] ${cmd}: cancelled`);
+      if (!(e instanceof CommandFailedError)) throw e;
+      if (!e.reported) log.error(`[error] ${e.message}`);
+      e.reported = true;
+      if (opts.rethrow) throw e;
+    } finally {
+      this.running.delete(ac);
+    }
+  }
+
+  /** 실행 중인 명령을 모두 취소(연결/설정은 유지). 취소한 개수 반환 */
+  cancelRunning(): number {
+    const n = this.running.size;
+    for (const ac of this.running) ac.abort();
+    if (n) log.info(`[info] cancel requested (${n} running)`);
+    return n;
+  }
+
+  /** 감사 로그(opt-in) 기록 — 실패해도 명령 결과에는 영향 없음 */
+  private async audit(command: string, startedAt: number, error?: unknown) {
+    if (!isAuditLogEnabled() || !this.context) return;
+    try {
+      const ws = await resolveWorkspaceInfo(this.context);
+      const file = path.join(ws.wsDirFsPath, ...AUDIT_LOG_REL.split('/'));
+      const failed = error !== undefined;
+      const ok = await appendAuditLog(file, {
+        at: new Date(startedAt),
+        connectionId: connectionManager.getSnapshot()?.active?.id,
+        command,
+        ok: !failed,
+        durationMs: Date.now() - startedAt,
+        error: failed ? (error instanceof Error ? error.message : String(error)) : undefined,
+      });
+      if (!ok) log.warn(`[warn] audit log write failed: ${file}`);
+    } catch (e) {
+      log.warn(`[warn] audit log skipped: ${e instanceof Error ? e.message : String(e)}`);
     }
   }
 
   @measure()
   async help() {
-    log.info(`Commands:
-  homeyRestart
-  homeyVolumeToggle | homeyAppLogToggle | homeyDevTokenToggle
-  changeWorkspaceQuick | openWorkspace
-  gitFlow (pull / push)
-  updateNow | openHelp`);
+    const lines = this.registry
+      .filter((d) => !d.hidden)
+      .map((d) => {
+        const alias = d.aliases?.length ? ` (${d.aliases.join(', ')})` : '';
+        return `  ${d.name}${alias} — ${d.help}`;
+      });
+    const flags = '전역 옵션(명령 바로 뒤): --quiet(안내 생략) --json(결과 JSON 한 줄)';
+    log.result(`Commands:\n${lines.join('\n')}\n${flags}`);
+  }
+
+  /**
+   * 명령 파일을 한 줄씩 route()로 실행(타이핑한 명령과 같은 경로)
+   * - 상대 경로는 워크스페이스 기준
+   * - 첫 실패에서 멈춤(--continue-on-error면 끝까지 실행 후 실패 수만 보고)
+   * - 알 수 없는 명령/중첩 runScript는 실행 전에 거부
+   */
+  @measure()
+  async runScript(args: string, signal: AbortSignal) {
+    const m = /(?:^|\s)--continue-on-error(?=\s|$)/.exec(args);
+    const file = (m ? args.replace(m[0], ' ') : args).trim().replace(/^(['"])(.*)\1$/, '$2');
+    if (!file) throw new CommandFailedError('runScript <파일> [--continue-on-error]');
+    const ws = this.context ? (await resolveWorkspaceInfo(this.context)).wsDirFsPath : undefined;
+    const abs = path.isAbsolute(file) || !ws ? path.resolve(file) : path.join(ws, file);
+    let text: string;
+    try {
+      text = await fs.promises.readFile(abs, 'utf8');
+    } catch (e) {
+      throw new CommandFailedError(`script not readable: ${abs}`, e);
+    }
+
+    const lines = parseScriptLines(text);
+    for (const { lineNo, line } of lines) {
+      const def = this.findCommand(line.split(/\s/, 1)[0]);
+      if (!def) throw new CommandFailedError(`${abs}:${lineNo}: unknown command`);
+      if (def.name === 'runScript') {
+        throw new CommandFailedError(`${abs}:${lineNo}: nested runScript`);
+      }
+    }
+
+    const failed: number[] = [];
+    for (const { lineNo, line } of lines) {
+      if (signal.aborted) break;
+      log.info(`[script] ${lineNo}: ${line}`);
+      try {
+        await this.route(line, { rethrow: true });
+      } catch (e) {
+        // 실패한 명령은 route(또는 핸들러)가 이미 알렸다 — 그 외 예외만 여기서 알린다
+        failed.push(lineNo);
+        const msg = `${lineNo}: ${e instanceof Error ? e.message : String(e)}`;
+        if (!(e instanceof CommandFailedError)) log.error(`[script] ${msg}`);
+        if (!m) throw new CommandFailedError(`runScript stopped at line ${msg}`, e);
+      }
+    }
+    if (signal.aborted) return log.info(`[info] runScript: cancelled (${path.basename(abs)})`);
+    const summary = { file: abs, commands: lines.length, failed };
+    log.result(`[script] ${lines.length} commands, ${failed.length} failed (${abs})`);
+    return summary;
+  }
+
+  /** 로컬 외부 도구 점검(시작 시 preflight와 같은 검사를 다시 실행) */
+  @measure()
+  async doctor() {
+    const ws = this.context ? (await resolveWorkspaceInfo(this.context)).wsDirFsPath : undefined;
+    const active = connectionManager.getSnapshot()?.active?.type;
+    const results = await runToolPreflight({ require: await requiredToolsFor(ws, active) });
+    const report = formatPreflightReport(results);
+    const missing = results.filter((r) => !r.present && r.required);
+    if (!missing.length) {
+      log.result(report);
+      return results;
+    }
+    log.warn(report);
+    const names = missing.map((r) => r.name).join(', ');
+    throw new CommandFailedError(`doctor: required tool(s) missing: ${names}`, results, true);
+  }
+}
+
+/** `--json` 결과 봉투 — 자동화 스크립트가 사람용 로그 대신 파싱한다 */
+export type CommandResult = {
+  command: string;
+  ok: boolean;
+  durationMs?: number;
+  /** 핸들러가 돌려준 값(없으면 생략) */
+  result?: unknown;
+  error?: string;
+};
+
+function emitResult(r: CommandResult) {
+  log.result(JSON.stringify(r));
+}
+
+/** 인자 맨 앞의 `--json` 토큰을 떼어낸다(맨 앞만 — 원격 명령 인자는 건드리지 않음) */
+export function stripJsonFlag(args: string): { args: string; json: boolean } {
+  const m = /^--json(?:\s+|$)/.exec(args);
+  return m ? { args: args.slice(m[0].length), json: true } : { args, json: false };
+}
+
+/** 명령 파일 → 실행할 줄(빈 줄·`#` 주석 제외, 줄 번호는 1부터) */
+export function parseScriptLines(text: string): { lineNo: number; line: string }[] {
+  return text
+    .split(/\r?\n/)
+    .map((raw, i) => ({ lineNo: i + 1, line: raw.trim() }))
+    .filter(({ line }) => line && !line.startsWith('#'));
+}
+
+/** 이름 + 인자 목록 → route()가 받는 명령 줄(공백/따옴표가 있는 인자는 한 덩어리로 인용) */
+export function toCommandLine(name: string, args: readonly string[] = []): string {
+  const quote = (a: string) =>
+    a && !/[\s"']/.test(a) ? a : a.includes('"') ? `'${a}'` : `"${a}"`;
+  return [name.trim(), ...args.map(quote)].join(' ');
+}
+
+/** 명령 줄 완성 후보 — line은 후보를 적용한 전체 명령 줄 */
+export type CommandCompletion = { line: string; help?: string };
+
+/**
+ * 입력 중인 명령 줄 → 완성 후보(레지스트리의 이름/별칭/help 문구에서 뽑으므로 라우팅과 항상 일치)
+ * - 첫 단어: 숨김이 아닌 명령(이름/별칭 접두어)
+ * - 첫 인자: help의 `[a|b]` 하위 명령
+ * - `-`로 시작하거나 빈 토큰: help의 `--옵션` + 전역 `--quiet`/`--json`(이미 쓴 것 제외)
+ */
+export function completeCommandLine(
+  defs: readonly CommandDef[],
+  line: string,
+): CommandCompletion[] {
+  const text = line.trimStart();
+  const sp = text.search(/\s/);
+  if (sp < 0) {
+    return defs
+      .filter((d) => !d.hidden && [d.name, ...(d.aliases ?? [])].some((n) => n.startsWith(text)))
+      .map((d) => ({ line: `${d.name} `, help: d.help }));
+  }
+  const name = text.slice(0, sp);
+  const def = defs.find((d) => d.name === name || d.aliases?.includes(name));
+  if (!def) return [];
+
+  const word = /\S*$/.exec(text)![0];
+  const head = text.slice(0, text.length - word.length);
+  const typed = head.slice(sp).trim().split(/\s+/).filter(Boolean);
+  const { subs, opts } = helpWords(def.help);
+  let cands: string[];
+  if (word.startsWith('-')) cands = opts;
+  else if (!typed.length) cands = word ? subs : [...subs, ...opts];
+  else cands = word ? [] : opts;
+  return cands
+    .filter((c) => c.startsWith(word) && c !== word && !typed.includes(c))
+    .map((c) => ({ line: `${head}${c} ` }));
+}
+
+/** help 문구 → 하위 명령(`[list | set …]`의 첫 단어)과 옵션(`--x`, 전역 플래그 포함) */
+function helpWords(help: string): { subs: string[]; opts: string[] } {
+  const subs = new Set<string>();
+  for (const [, group] of help.matchAll(/\[([^\]]*)\]/g)) {
+    for (const alt of group.split('|')) {
+      const m = /^([a-z][\w-]*)(?=$|[\s(])/.exec(alt.trim());
+      if (m) subs.add(m[1]);
+    }
   }
+  const opts = new Set([...(help.match(/--[\w-]+(?![\w=-])/g) ?? []), '--quiet', '--json']);
+  return { subs: [...subs], opts: [...opts] };
 }
 
 export function createCommandHandlers(
@@ -227,6 +227,12 @@ export function getSections(): SectionDef[] {
           desc: UI_DESC.RELOAD,
           op: { kind: 'vscode', command: 'workbench.action.reloadWindow' },
         },
+        {
+          id: 'cmd.runCommand',
+          label: UI_STR.BTN_RUN_COMMAND,
+          desc: UI_DESC.RUN_COMMAND,
+          op: { kind: 'vscode', command: 'homey.runCommand' },
+        },
         { id: 'cmd.help', label: UI_STR.BTN_HELP, op: { kind: 'handler', name: 'openHelp' } },
       ],
     },
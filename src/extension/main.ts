@@ -3,8 +3,16 @@ import * as vscode from 'vscode';
 
 // 사용자 저장 구성 요소
 import { resolveWorkspaceInfo } from '../core/config/userdata.js';
-import { getLogger, patchConsole, setLogLevel } from '../core/logging/extension-logger.js';
+import { connectionManager } from '../core/connection/ConnectionManager.js';
+import { requiredToolsFor, runToolPreflight } from '../core/connection/toolPreflight.js';
+import {
+  getLogger,
+  patchConsole,
+  resolveLogLevel,
+  setLogLevel,
+} from '../core/logging/extension-logger.js';
 import { globalProfiler } from '../core/logging/perf.js';
+import { watchConnectionForUnitCache } from '../core/service/serviceDiscovery.js';
 import { LOG_LEVEL_DEFAULT, RAW_DIR_NAME } from '../shared/const.js';
 import { PerfMonitorPanel } from './editors/PerfMonitorPanel.js';
 import { EdgePanelProvider, registerEdgePanelCommands } from './panels/extensionPanel.js';
@@ -14,7 +22,7 @@ import { checkLatestVersion } from './update/updater.js';
 
 export async function activate(context: vscode.ExtensionContext) {
   return globalProfiler.measureFunction('activate', async () => {
-    setLogLevel(LOG_LEVEL_DEFAULT);
+    setLogLevel(resolveLogLevel(LOG_LEVEL_DEFAULT));
     patchConsole();
 
     const log = getLogger('main');
@@ -116,6 +124,23 @@ export async function activate(context: vscode.ExtensionContext) {
       // ✅ homey-logging을 외부 커맨드로 노출
       registerEdgePanelCommands(context, provider);
 
+      // ✅ 연결 전환 시 연결 단위 캐시(Homey 서비스 유닛 등) 무효화 + 유닛 선택 저장 위치 등록
+      context.subscriptions.push(watchConnectionForUnitCache(context));
+
+      // ✅ 로컬 도구(adb/git 등) preflight — 시작을 막지 않도록 백그라운드로
+      void requiredToolsFor(info.wsDirFsPath, connectionManager.getSnapshot()?.active?.type)
+        .then((require) => runToolPreflight({ require }))
+        .then(async (results) => {
+          const missing = results.filter((r) => !r.present && r.required).map((r) => r.name);
+          if (!missing.length) return;
+          const act = await vscode.window.showWarningMessage(
+            `로컬 도구를 찾을 수 없습니다: ${missing.join(', ')} — 설치 안내를 확인하세요.`,
+            '도구 점검(doctor)',
+          );
+          if (act) await vscode.commands.executeCommand('homey.runCommand', 'doctor');
+        })
+        .catch((e) => log.warn('tool preflight failed', e as any));
+
       log.info(
         `registerWebviewViewProvider OK, viewType=${EdgePanelProvider.viewType}, version=${version}`,
       );
@@ -1,11 +1,17 @@
 // === src/extension/messaging/hostWebviewBridge.ts ===
-import type { H2W, LogFilter, W2H } from '@ipc/messages';
+import type { H2W, LogEntry, LogFilter, LogStats, SearchHit, W2H } from '@ipc/messages';
 import * as vscode from 'vscode';
 
 import { getLogger } from '../../core/logging/extension-logger.js';
 import { globalProfiler, measure, measureBlock, perfNow } from '../../core/logging/perf.js';
-import { paginationService } from '../../core/logs/PaginationService.js';
-import { LOG_WINDOW_SIZE, MERGE_PROGRESS_THROTTLE_MS } from '../../shared/const.js';
+import { HybridLogBuffer } from '../../core/logs/HybridLogBuffer.js';
+import { type PaginationService, paginationService } from '../../core/logs/PaginationService.js';
+import {
+  LOG_WINDOW_SIZE,
+  MERGE_PROGRESS_THROTTLE_MS,
+  REALTIME_BUFFER_MAX,
+  SEARCH_PAGE_SIZE_DEFAULT,
+} from '../../shared/const.js';
 
 type Handler = (msg: W2H, api: BridgeAPI) => Promise<void> | void;
 
@@ -32,6 +38,8 @@ export type BridgeOptions = {
   readUserPrefs?: () => Promise<any>;
   /** 사용자 환경설정 저장 (필요 시 주입) */
   writeUserPrefs?: (patch: any) => Promise<void>;
+  /** 페이지/필터/검색을 답할 페이지 서비스(패널마다 하나, 기본은 공용 싱글톤) */
+  pages?: PaginationService;
 };
 
 export class HostWebviewBridge {
@@ -41,8 +49,19 @@ export class HostWebviewBridge {
   private pendings = new Map<string, AbortController>(); // abortKey -> controller
   private seq = 0;
   private kickedOnce = false; // 초기 리프레시 신호를 중복 발사하지 않도록 가드
+  // postMessage 전달 실패(웹뷰 숨김/해제 등) 타입별 누적 — 로그 배치 유실 시 resync 예약
+  private dropped = new Map<string, number>();
+  private resyncPending = false;
+  // 웹뷰가 테일을 따라가는 중인지(logs.follow.set) — 해제 중엔 실시간 배치를 밀지 않는다
+  private follow = true;
+  // viewer.ready 전(웹뷰 스크립트 로드 중)에 온 실시간 라인 — 버리지 않고 최근 N줄만 링버퍼로 보관
+  private viewerReady = false;
+  private early = new HybridLogBuffer(REALTIME_BUFFER_MAX);
+  private earlyCount = 0;
+  private earlyLastIdx = 0;
+  private visibilitySub?: vscode.Disposable;
   // ── Search buffer (host-held) ────────────────────────────────────────
-  private searchHits: { idx: number; text: string }[] = [];
+  private searchHits: SearchHit[] = [];
   // ── 로그 스로틀(반복 노이즈 억제) ─────────────────────────────────────
   private lastLogTs = new Map<string, number>();
   private lastPayload = new Map<string, string>();
@@ -87,8 +106,21 @@ export class HostWebviewBridge {
     private readonly options: BridgeOptions = {},
   ) {}
 
+  private get pages(): PaginationService {
+    return this.options.pages ?? paginationService;
+  }
+
   @measure()
   start() {
+    // 웹뷰가 다시 보이면, 그 사이 유실된 배치를 페이지 재요청으로 메운다.
+    const onVisible = () => {
+      if (this.host.visible && this.resyncPending) void this.resync();
+    };
+    this.visibilitySub =
+      'onDidChangeViewState' in this.host
+        ? this.host.onDidChangeViewState(onVisible)
+        : this.host.onDidChangeVisibility(onVisible);
+
     this.host.webview.onDidReceiveMessage(async (raw: any) => {
       await measureBlock('host.bridge.onMessage', async () => {
         const msg = this.validateIncoming(raw);
@@ -96,7 +128,12 @@ export class HostWebviewBridge {
 
         // ── 웹뷰가 준비 신호를 보낼 수 있는 경우(선행 핸드셰이크) ──
         if (msg.type === 'viewer.ready') {
+          this.viewerReady = true;
+          // 다시 열린 뷰어는 테일을 따라가는 상태로 시작한다
+          this.follow = true;
+          this.flushEarly();
           this.kickIfReady('viewer.ready');
+          if (this.resyncPending) void this.resync();
           return;
         }
 
@@ -138,12 +175,12 @@ export class HostWebviewBridge {
             const e = Number(endIdx) || s;
             if (this.shouldLog('page.req', 300, `${s}-${e}`)) {
               this.log.debug?.(
-                `bridge: logs.page.request ${s}-${e} filterActive=${paginationService.isFilterActive()}`,
+                `bridge: logs.page.request ${s}-${e} filterActive=${this.pages.isFilterActive()}`,
               );
             }
-            const logs = await paginationService.readRangeByIdx(s, e); // 내부에서 필터 적용 분기
+            const logs = await this.pages.readRangeByIdx(s, e); // 내부에서 필터 적용 분기
             // 현재 pagination 버전을 함께 내려, 웹뷰가 세션 불일치를 걸러낼 수 있게 한다.
-            const version = paginationService.getVersion();
+            const version = this.pages.getVersion();
             this.send({
               v: 1,
               type: 'logs.page.response',
@@ -174,46 +211,10 @@ export class HostWebviewBridge {
         // ── 서버측 필터 설정(단일 API: null=해제) ──────────────────────────
         if (msg.type === 'logs.filter.set') {
           try {
-            const warm = paginationService.isWarmupActive();
             const filter = (msg.payload?.filter ?? null) as LogFilter | null;
             this.log.info(`bridge: logs.filter.set ${JSON.stringify(filter)}`);
-            paginationService.setFilter(filter);
-            // ⬇️ 중요: 필터 적용 후의 총계(필터 미적용이면 전체 총계)를 기준으로 total/윈도우 계산
-            const total = (await paginationService.getFilteredTotal()) ?? 0;
-            const startIdx = Math.max(1, total - LOG_WINDOW_SIZE + 1);
-            const endIdx = Math.max(1, total);
-            const head = total > 0 ? await paginationService.readRangeByIdx(startIdx, endIdx) : [];
-            this.send({
-              v: 1,
-              type: 'logs.batch',
-              payload: {
-                logs: head,
-                total,
-                seq: ++this.seq,
-                version: paginationService.getVersion(),
-              },
-            } as any);
-            // 상태도 함께 브로드캐스트
-            this.send({
-              v: 1,
-              type: 'logs.state',
-              payload: {
-                total,
-                version: paginationService.getVersion(),
-                warm,
-                manifestDir: paginationService.getManifestDir(),
-              },
-            } as any);
-            this.send({
-              v: 1,
-              type: 'logs.refresh',
-              payload: {
-                reason: 'filter-changed',
-                total,
-                version: paginationService.getVersion(),
-                warm,
-              },
-            } as any);
+            this.pages.setFilter(filter);
+            await this.sendFilteredHead();
           } catch (err: any) {
             const message = err?.message || String(err);
             this.log.error(`bridge: FILTER_SET_ERROR ${message}`);
@@ -225,8 +226,39 @@ export class HostWebviewBridge {
           }
           return;
         }
+        // ── 버퍼 통계(상태 배지) ─────────────────────────────────────────
+        if (msg.type === 'logs.stats.request') {
+          await this.postStats();
+          return;
+        }
+
+        // ── 심각도 하한(빠른 토글): 텍스트 필터와 AND 결합 ─────────────────
+        if (msg.type === 'logs.severity.set') {
+          try {
+            const floor = msg.payload?.floor ?? null;
+            this.log.info(`bridge: logs.severity.set floor=${floor ?? '-'}`);
+            this.pages.setSeverityFloor(floor);
+            await this.sendFilteredHead();
+          } catch (err: any) {
+            const message = err?.message || String(err);
+            this.log.error(`bridge: SEVERITY_SET_ERROR ${message}`);
+            this.send({
+              v: 1,
+              type: 'error',
+              payload: { code: 'SEVERITY_SET_ERROR', message, detail: err, inReplyTo: msg.id },
+            });
+          }
+          return;
+        }
         // ──────────────────────────────────────────────
 
+        // ── 팔로우 토글: 위로 스크롤해 읽는 동안 새 라인이 뷰를 끌어내리지 않게 ──
+        if (msg.type === 'logs.follow.set') {
+          this.follow = msg.payload?.follow !== false;
+          this.log.info(`bridge: logs.follow.set follow=${this.follow}`);
+          return;
+        }
+
         // ── 전체 검색(노트패드++ 스타일): Enter 시 실행 ───────────────
         if (msg.type === 'search.query') {
           try {
@@ -234,18 +266,37 @@ export class HostWebviewBridge {
             const regex = !!msg?.payload?.regex;
             const range = msg?.payload?.range as [number, number] | undefined;
             const top = typeof msg?.payload?.top === 'number' ? msg.payload.top : undefined;
+            const cursor = msg?.payload?.cursor;
+            const options = {
+              caseSensitive: !!msg?.payload?.caseSensitive,
+              wholeWord: !!msg?.payload?.wholeWord,
+            };
+            // top(구버전 상한)이 있으면 그 값을 페이지 크기로 사용
+            const pageSize = msg?.payload?.pageSize ?? top ?? SEARCH_PAGE_SIZE_DEFAULT;
 
             this.log.info(
-              `bridge: search.query q="${q}" regex=${regex} range=${range ?? '-'} top=${top ?? '-'}`,
+              `bridge: search.query q="${q}" regex=${regex} range=${range ?? '-'} ` +
+                `case=${options.caseSensitive} word=${options.wholeWord} ` +
+                `page=${pageSize} cursor=${cursor ? cursor.next : '-'}`,
             );
-            // 단일 패스 검색으로 변경(필터 공간 기준)
-            this.searchHits = await paginationService.searchAll(q, { regex, range, top });
+            // 단일 패스 검색(필터 공간 기준) — 페이지 단위로 이어서 스캔
+            const { hits, nextCursor } = await this.pages.searchPage(q, {
+              regex,
+              range,
+              cursor,
+              pageSize,
+              ...options,
+            });
+            this.searchHits = cursor ? [...this.searchHits, ...hits] : hits;
 
-            this.log.info(`bridge: search.results hits=${this.searchHits.length}`);
+            this.log.info(
+              `bridge: search.results hits=${hits.length} (acc=${this.searchHits.length})` +
+                `${nextCursor ? ' more' : ''}`,
+            );
             this.send({
               v: 1,
               type: 'search.results',
-              payload: { hits: this.searchHits, q },
+              payload: { hits, q, append: !!cursor, nextCursor, options },
             } as any);
           } catch (err: any) {
             const message = err?.message || String(err);
@@ -318,11 +369,11 @@ export class HostWebviewBridge {
 
             // 검색 결과 수집 (간단 구현)
             const hits: { idx: number; text: string }[] = [];
-            const total = (await paginationService.getFilteredTotal()) || 0;
+            const total = (await this.pages.getFilteredTotal()) || 0;
 
             for (let idx = 1; idx <= total && !controller.signal.aborted; idx++) {
               try {
-                const page = await paginationService.readRangeByIdx(idx, idx);
+                const page = await this.pages.readRangeByIdx(idx, idx);
                 if (page.length > 0) {
                   const log = page[0];
                   const rawLevel = (log as any)?.level;
@@ -424,7 +475,7 @@ export class HostWebviewBridge {
               throw new Error('Invalid jump request: idx required');
             }
 
-            const total = (await paginationService.getFilteredTotal()) || 0;
+            const total = (await this.pages.getFilteredTotal()) || 0;
             if (idx < 1 || idx > total) {
               throw new Error(`Index out of range: ${idx}, total: ${total}`);
             }
@@ -453,8 +504,8 @@ export class HostWebviewBridge {
               }
             }
 
-            const page = await paginationService.readRangeByIdx(startIdx, endIdx);
-            const version = paginationService.getVersion();
+            const page = await this.pages.readRangeByIdx(startIdx, endIdx);
+            const version = this.pages.getVersion();
             this.send({
               v: 1,
               type: 'logs.page.response',
@@ -515,17 +566,144 @@ export class HostWebviewBridge {
   private send(m: H2W) {
     // 내부 전송 시작/끝 로그는 노이즈가 많아 제거
     if (!globalProfiler.isOn()) {
-      this.host.webview.postMessage(m);
+      this.trackDelivery(m, this.host.webview.postMessage(m));
       return;
     }
     const t0 = perfNow();
     try {
-      this.host.webview.postMessage(m);
+      this.trackDelivery(m, this.host.webview.postMessage(m));
     } finally {
       globalProfiler.recordFunctionCall('bridge.send', t0, perfNow() - t0);
     }
   }
 
+  /** postMessage 결과(false/reject)를 유실로 집계. 로그 배치가 유실되면 resync 예약 */
+  private trackDelivery(m: H2W, delivered: Thenable<boolean>) {
+    const onDrop = () => {
+      const type = String((m as any)?.type ?? 'unknown');
+      this.dropped.set(type, (this.dropped.get(type) ?? 0) + 1);
+      if (type === 'logs.batch' || type === 'logs.page.response') this.resyncPending = true;
+      if (this.shouldLog(`drop:${type}`, 2000)) {
+        const n = this.dropped.get(type);
+        this.log.warn(`bridge: message not delivered type=${type} (dropped=${n})`);
+      }
+    };
+    Promise.resolve(delivered).then((ok) => {
+      if (!ok) onDrop();
+    }, onDrop);
+  }
+
+  isFollowing() {
+    return this.follow;
+  }
+
+  /**
+   * 웹뷰가 아직 viewer.ready를 보내지 않았으면 실시간 배치를 보관하고 true.
+   * 보관은 REALTIME_BUFFER_MAX줄까지(넘치면 오래된 것부터), 준비되면 한 번에 보낸다.
+   */
+  deferUntilReady(logs: LogEntry[]): boolean {
+    if (this.viewerReady) return false;
+    // 실시간 배치는 최신 윈도우 교체 푸시라 서로 겹친다 → 이미 보관한 idx는 건너뛴다
+    const fresh = logs.filter((e) => typeof e.idx !== 'number' || e.idx > this.earlyLastIdx);
+    for (const e of fresh) {
+      if (typeof e.idx === 'number') this.earlyLastIdx = Math.max(this.earlyLastIdx, e.idx);
+    }
+    this.early.addBatch(fresh);
+    this.earlyCount += fresh.length;
+    return true;
+  }
+
+  private flushEarly() {
+    const logs = this.early.snapshot(REALTIME_BUFFER_MAX);
+    if (!logs.length) return;
+    const trimmed = this.earlyCount - logs.length;
+    this.early.clear();
+    this.earlyCount = 0;
+    this.earlyLastIdx = 0;
+    const note = trimmed ? ` (trimmed ${trimmed})` : '';
+    this.log.info(`bridge: sending ${logs.length} early realtime line(s)${note}`);
+    this.send({ v: 1, type: 'logs.batch', payload: { logs } } as H2W);
+  }
+
+  /** 버퍼 통계 + 전달 유실 합계를 웹뷰로 보낸다 */
+  async postStats() {
+    try {
+      const base = await this.pages.getStats();
+      let dropped = 0;
+      for (const n of this.dropped.values()) dropped += n;
+      const stats: LogStats = { ...base, dropped };
+      this.send({ v: 1, type: 'logs.stats', payload: stats });
+    } catch (err: any) {
+      this.log.warn(`bridge: stats failed ${err?.message || String(err)}`);
+    }
+  }
+
+  /** 필터/심각도 변경 후: 필터 공간 기준 최신 윈도우와 상태/리프레시를 보낸다 */
+  private async sendFilteredHead() {
+    const warm = this.pages.isWarmupActive();
+    // ⬇️ 중요: 필터 적용 후의 총계(필터 미적용이면 전체 총계)를 기준으로 total/윈도우 계산
+    const total = (await this.pages.getFilteredTotal()) ?? 0;
+    const startIdx = Math.max(1, total - LOG_WINDOW_SIZE + 1);
+    const endIdx = Math.max(1, total);
+    const head = total > 0 ? await this.pages.readRangeByIdx(startIdx, endIdx) : [];
+    this.send({
+      v: 1,
+      type: 'logs.batch',
+      payload: {
+        logs: head,
+        total,
+        seq: ++this.seq,
+        version: this.pages.getVersion(),
+      },
+    } as any);
+    // 상태도 함께 브로드캐스트
+    this.send({
+      v: 1,
+      type: 'logs.state',
+      payload: {
+        total,
+        version: this.pages.getVersion(),
+        warm,
+        manifestDir: this.pages.getManifestDir(),
+      },
+    } as any);
+    this.send({
+      v: 1,
+      type: 'logs.refresh',
+      payload: {
+        reason: 'filter-changed',
+        total,
+        version: this.pages.getVersion(),
+        warm,
+      },
+    } as any);
+  }
+
+  /** 유실 후 백필: 최신 총량/버전으로 refresh를 보내 웹뷰가 현재 뷰포트를 다시 요청하게 한다. */
+  private async resync() {
+    this.resyncPending = false;
+    try {
+      const warm = this.pages.isWarmupActive();
+      const filteredTotal = await this.pages.getFilteredTotal();
+      const total =
+        typeof filteredTotal === 'number'
+          ? filteredTotal
+          : warm
+            ? this.pages.getWarmTotal()
+            : this.pages.getFileTotal();
+      const version = this.pages.getVersion();
+      this.log.info(`bridge: resync after dropped batches total=${total ?? 'unknown'}`);
+      this.send({
+        v: 1,
+        type: 'logs.refresh',
+        payload: { reason: 'resync', total, version, warm },
+      });
+    } catch (e: any) {
+      this.resyncPending = true;
+      this.log.warn(`bridge: resync failed: ${e?.message || e}`);
+    }
+  }
+
   /** 외부(패널 매니저 등)에서 단방향 알림을 보낼 때 사용하는 공개 API.
    *  내부 계측/스로틀은 private send를 그대로 사용해 일관성을 유지한다. */
   public notify<T extends H2W>(msg: T): void {
@@ -621,17 +799,17 @@ export class HostWebviewBridge {
   @measure()
   private async kickIfReady(origin: 'bridge.start' | 'viewer.ready') {
     try {
-      const warm = paginationService.isWarmupActive();
+      const warm = this.pages.isWarmupActive();
       // 필터 활성 시에는 필터 총계를, 아니면 전체 총계를 보낸다.
-      const filteredTotal = await paginationService.getFilteredTotal();
+      const filteredTotal = await this.pages.getFilteredTotal();
       const total =
         typeof filteredTotal === 'number'
           ? filteredTotal
           : warm
-            ? paginationService.getWarmTotal()
-            : paginationService.getFileTotal();
-      const version = paginationService.getVersion();
-      const manifestDir = paginationService.getManifestDir();
+            ? this.pages.getWarmTotal()
+            : this.pages.getFileTotal();
+      const version = this.pages.getVersion();
+      const manifestDir = this.pages.getManifestDir();
 
       // 상태는 매번 보내도 무방(웹뷰가 최신값으로 덮어씀)
       this.log.info(
@@ -697,6 +875,11 @@ export class HostWebviewBridge {
       this.pendings.clear();
       this.handlers.clear();
       this.kickedOnce = false;
+      this.visibilitySub?.dispose();
+      this.visibilitySub = undefined;
+      this.dropped.clear();
+      this.resyncPending = false;
+      this.follow = true;
       // ⬇️ 진행률 타이머 정리 (누수 방지)
       if (this.progressTimer) {
         clearInterval(this.progressTimer);
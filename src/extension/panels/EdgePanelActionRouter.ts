@@ -6,7 +6,7 @@ import { getLogger } from '../../core/logging/extension-logger.js';
 import { measure } from '../../core/logging/perf.js';
 import { getEnvToggleEnabled, getMountState } from '../../core/state/DeviceState.js';
 import { UI_STR } from '../../shared/const.js';
-import { createCommandHandlers } from '../commands/commandHandlers.js';
+import { type CommandDef, createCommandHandlers } from '../commands/commandHandlers.js';
 import {
   buildButtonContext,
   BUSY_LOCK_BUTTON_IDS,
@@ -24,6 +24,9 @@ const log = getLogger('EdgePanelActionRouter');
 export interface IEdgePanelActionRouter {
   sendButtonSections(): void;
   dispatchButton(id: string): Promise<void>;
+  runCommandLine(text: string): Promise<void>;
+  listCommands(): readonly CommandDef[];
+  cancelRunning(): number;
   dispose(): void;
 }
 
@@ -120,33 +123,60 @@ export class EdgePanelActionRouter implements IEdgePanelActionRouter {
       log.warn(`busy: drop click ${id}`);
       return;
     }
-    if (!this._handlers) {
-      this._handlers = createCommandHandlers(this._context, this._extensionUri, this._provider);
-    }
 
     const invoke = async () => {
       const op = btn.op;
       if (op.kind === 'handler') {
-        await this._handlers!.route(op.name); // ← 반드시 await
+        await this._getHandlers().route(op.name); // ← 반드시 await
       } else if (op.kind === 'vscode') {
         await vscode.commands.executeCommand(op.command, ...(op.args ?? []));
       } else if (op.kind === 'post') {
         this._view.webview.postMessage({ type: op.event, payload: op.payload ?? null });
       }
     };
+    await this._run(id, isLockTarget, invoke);
+  }
 
+  /** 입력한 명령 줄(`<명령> [인자…]`)을 버튼과 같은 레지스트리로 실행 */
+  @measure()
+  async runCommandLine(text: string) {
+    const line = String(text ?? '').trim();
+    if (!line) return;
+    await this._run(line, false, async () => {
+      await this._getHandlers().route(line);
+    });
+  }
+
+  /** 명령 줄 입력창의 완성 후보용 레지스트리 */
+  listCommands(): readonly CommandDef[] {
+    return this._getHandlers().listCommands();
+  }
+
+  private _getHandlers() {
+    if (!this._handlers) {
+      this._handlers = createCommandHandlers(this._context, this._extensionUri, this._provider);
+    }
+    return this._handlers;
+  }
+
+  private async _run(label: string, lock: boolean, invoke: () => Promise<void>) {
     try {
-      if (isLockTarget) this._setBusyLock(true);
+      if (lock) this._setBusyLock(true);
       await invoke();
     } catch (e) {
-      log.error(`dispatch failed for ${id}: ${e instanceof Error ? e.message : String(e)}`);
+      log.error(`dispatch failed for ${label}: ${e instanceof Error ? e.message : String(e)}`);
     } finally {
-      if (isLockTarget) this._setBusyLock(false);
+      if (lock) this._setBusyLock(false);
       // ✅ 명령 수행 후 버튼 상태 재계산(연결 상태 변화 반영)
       this.sendButtonSections().catch(() => {});
     }
   }
 
+  /** 실행 중인 명령 취소(Ctrl+C) — 잠금 해제/버튼 갱신은 dispatchButton의 finally가 처리 */
+  cancelRunning(): number {
+    return this._handlers?.cancelRunning() ?? 0;
+  }
+
   @measure()
   dispose() {
     log.debug('[debug] EdgePanelActionRouter dispose: start');
@@ -12,11 +12,14 @@ import { readParserWhitelistGlobs } from '../../core/config/userdata.js';
 import { readParserConfigJson } from '../../core/config/userdata.js';
 import { getLogger } from '../../core/logging/extension-logger.js';
 import { globalProfiler, measure, perfNow } from '../../core/logging/perf.js';
-import { paginationService } from '../../core/logs/PaginationService.js';
-import { LogSessionManager } from '../../core/sessions/LogSessionManager.js';
-import { MERGED_DIR_NAME, RAW_DIR_NAME } from '../../shared/const.js';
+import type { LogCaptureOptions } from '../../core/logs/LogCaptureWriter.js';
+import { type PaginationService, paginationService } from '../../core/logs/PaginationService.js';
+import type { JournalWindow } from '../../core/logs/RealtimeSource.js';
+import { LogSessionManager, realtimePreserveKey } from '../../core/sessions/LogSessionManager.js';
+import { MERGED_DIR_NAME, RAW_DIR_NAME, REALTIME_DIR_NAME } from '../../shared/const.js';
 import type { MergeSavedInfo } from '../../shared/ipc/messages.js';
 import { HostWebviewBridge } from '../messaging/hostWebviewBridge.js';
+import { loadWebviewHtml } from './webviewHtml.js';
 
 export class LogViewerPanelManager {
   private log = getLogger('LogViewerPanelManager');
@@ -43,12 +46,23 @@ export class LogViewerPanelManager {
   private lastBatchLogMs = 0;
   private lastPageLogMs = 0;
   private readonly SEND_LOG_INTERVAL_MS = 800;
+  // 마지막 실시간 배치의 최대 idx — 팔로우 해제 중 새로 쌓인 줄 수(skipped) 계산용
+  private lastBatchIdx = 0;
 
+  /**
+   * opts.pages: 이 패널 전용 페이지 서비스 — 나란히 띄우는 두 번째 뷰어(--device)는 새 인스턴스를 쓴다
+   * opts.title: 패널 제목(기본 'Homey Log Viewer')
+   */
   constructor(
     private context: vscode.ExtensionContext,
     private extensionUri: vscode.Uri,
+    private opts: { pages?: PaginationService; title?: string } = {},
   ) {}
 
+  private get pages(): PaginationService {
+    return this.opts.pages ?? paginationService;
+  }
+
   dispose() {
     // quiet
     try {
@@ -74,6 +88,8 @@ export class LogViewerPanelManager {
         arrayBuffersMB,
         ts: Date.now(),
       });
+      // 버퍼 통계도 같은 주기로 갱신
+      void this.bridge?.postStats();
     } catch {
       // ignore
     }
@@ -97,7 +113,7 @@ export class LogViewerPanelManager {
     if (!this.panel) {
       this.panel = vscode.window.createWebviewPanel(
         'homey-log-viewer',
-        'Homey Log Viewer',
+        this.opts.title ?? 'Homey Log Viewer',
         { viewColumn: vscode.ViewColumn.Beside, preserveFocus: true },
         {
           enableScripts: true,
@@ -129,6 +145,7 @@ export class LogViewerPanelManager {
 
       // 메시지 라우팅을 bridge로 일원화
       this.bridge = new HostWebviewBridge(this.panel, {
+        pages: this.pages,
         onUiLog: ({ level, text, source, line }) => {},
         readUserPrefs: async () => {
           // quiet
@@ -153,30 +170,118 @@ export class LogViewerPanelManager {
     // quiet
   }
 
-  /** 실시간 세션 시작: 라인 들어오는 대로 즉시 UI 전송 */
+  /**
+   * 실시간 세션 시작: 라인 들어오는 대로 즉시 UI 전송
+   * (tailFile: 설정 소스 대신 원격 파일 하나, window: journalctl 과거 구간 조회)
+   */
   @measure()
-  async startRealtime(filter?: string) {
+  async startRealtime(
+    filter?: string,
+    tailFile?: string,
+    device?: string,
+    capture?: LogCaptureOptions,
+    window?: JournalWindow,
+  ) {
     // quiet
     if (!this.panel) await this.handleHomeyLoggingCommand();
     this.mode = 'realtime';
     this.initialSent = true; // 실시간은 제한 없음
+    this.lastBatchIdx = 0;
     // quiet
 
     this.session?.dispose();
-    this.session = new LogSessionManager();
+    this.session = new LogSessionManager(this.pages);
     // 실시간 모드는 병합이 없으므로 느리게
     this._setMemPeriod(this.MEM_SLOW_MS);
 
-    await this.session.startRealtimeSession({
-      filter,
-      onBatch: (logs) => {
-        // quiet
-        this._send('logs.batch', { logs });
-      },
-      onMetrics: (m) => {
-        this._send('metrics.update', m);
-      },
-    });
+    // 초기 backlog(tail N): 사용자 설정 > 환경변수 > 기본값(0)
+    const prefs = await readLogViewerPrefs(this.context).catch(() => undefined);
+    const pick = (pref: unknown, env?: string) => {
+      if (typeof pref === 'number') return pref;
+      const n = Number(env);
+      return env && Number.isFinite(n) ? n : undefined;
+    };
+    const tailLines = pick(prefs?.realtimeTail, process.env.EDGE_TOOL_REALTIME_TAIL);
+    // 청크 회전 정책: 사용자 설정 > 환경변수 > 기본값
+    const maxChunks = pick(prefs?.realtimeMaxChunks, process.env.EDGE_TOOL_REALTIME_MAX_CHUNKS);
+    const maxBytes = pick(prefs?.realtimeMaxBytes, process.env.EDGE_TOOL_REALTIME_MAX_BYTES);
+    // 배치 전송 간격/크기: 사용자 설정 > 환경변수 > 기본값(REALTIME_FLUSH_*)
+    const flushIntervalMs = pick(
+      prefs?.realtimeFlushIntervalMs,
+      process.env.EDGE_TOOL_REALTIME_FLUSH_MS,
+    );
+    const flushMaxLines = pick(
+      prefs?.realtimeFlushMaxLines,
+      process.env.EDGE_TOOL_REALTIME_FLUSH_LINES,
+    );
+    // 세션 간 보존: 사용자 설정 > 환경변수(EDGE_TOOL_REALTIME_PRESERVE=1) — workspace/raw/realtime-<연결> 사용
+    // (원격 파일 tail / 다른 기기(--device) / 과거 구간(--since)은 내용이 달라 보존 디렉터리에 섞지 않음)
+    const preserve =
+      !tailFile &&
+      !device &&
+      !window &&
+      (typeof prefs?.realtimePreserve === 'boolean'
+        ? prefs.realtimePreserve
+        : /^(1|true|yes|on)$/i.test(process.env.EDGE_TOOL_REALTIME_PRESERVE ?? ''));
+    // 연속 중복 라인 합치기: 사용자 설정 > 환경변수(EDGE_TOOL_REALTIME_COALESCE=1)
+    const coalesce =
+      typeof prefs?.realtimeCoalesce === 'boolean'
+        ? prefs.realtimeCoalesce
+        : /^(1|true|yes|on)$/i.test(process.env.EDGE_TOOL_REALTIME_COALESCE ?? '');
+    const wsRoot = preserve ? await this._resolveWorkspaceRoot() : undefined;
+    // 연결마다 따로 보존(같은 워크스페이스에서 다른 기기 세션이 서로 덮어쓰지 않게)
+    const indexOutDir = wsRoot
+      ? path.join(wsRoot, RAW_DIR_NAME, `${REALTIME_DIR_NAME}-${realtimePreserveKey(device)}`)
+      : undefined;
+
+    try {
+      await this.session.startRealtimeSession({
+        filter,
+        tailLines,
+        maxChunks,
+        maxBytes,
+        flushIntervalMs,
+        flushMaxLines,
+        preserve,
+        indexOutDir,
+        tailFile,
+        coalesce,
+        device,
+        capture,
+        window,
+        onBatch: (logs, total) => {
+          // 배치는 최신 윈도우 전체일 수 있으므로 새 줄 수는 직전 배치와의 idx 차이로 센다
+          const maxIdx = logs.reduce((m, e) => Math.max(m, e.idx ?? 0), 0);
+          const fresh = maxIdx > 0 ? Math.max(0, maxIdx - this.lastBatchIdx) : logs.length;
+          if (maxIdx > 0) this.lastBatchIdx = maxIdx;
+          // 심각도 하한이 켜져 있으면 실시간 라인도 호스트에서 걸러서 보낸다
+          const kept = logs.filter((e) => this.pages.meetsSeverityFloor(e));
+          if (!kept.length) return;
+          // 뷰어가 아직 준비 전이면 브리지가 최근 라인을 모아 두었다가 준비되면 보낸다
+          if (this.bridge?.deferUntilReady(kept)) return;
+          if (this.bridge && !this.bridge.isFollowing()) {
+            // 팔로우 해제 중: 라인은 페이지 요청으로만 주고 총량/배지용 개수만 알린다
+            // (필터 공간의 총량은 다르므로 필터가 켜져 있으면 총량은 생략)
+            const t = this.pages.isFilterActive() ? undefined : total;
+            this._send('logs.batch', { logs: [], total: t, skipped: fresh });
+            return;
+          }
+          this._send('logs.batch', { logs: kept });
+        },
+        onMetrics: (m) => {
+          this._send('metrics.update', m);
+        },
+        onStage: (text) => {
+          vscode.window.showWarningMessage(`실시간 로그: ${text}`);
+        },
+      });
+    } catch (e: any) {
+      // 로그 소스 검증 실패 등: 빈 뷰어만 남기지 않도록 원인을 바로 알림
+      const msg = e?.message ?? String(e);
+      this.log.error(`startRealtime failed: ${msg}`);
+      vscode.window.showErrorMessage(`실시간 로그를 시작할 수 없습니다: ${msg}`);
+      throw e;
+    }
     // quiet
   }
 
@@ -207,7 +312,7 @@ export class LogViewerPanelManager {
     }
 
     this.session?.dispose();
-    this.session = new LogSessionManager();
+    this.session = new LogSessionManager(this.pages);
     // 병합 시작: 빠르게 전환
     this._setMemPeriod(this.MEM_FAST_MS);
 
@@ -229,94 +334,160 @@ export class LogViewerPanelManager {
       this.log.warn(`merge: failed to read parser config (${e?.message ?? e})`);
     }
 
-    await this.session.startFileMergeSession({
-      dir,
-      indexOutDir,
-      whitelistGlobs,
-      parserConfig,
-      onBatch: (logs, total, seq) => {
-        if (this.initialSent) return;
-        // quiet
-        // 초기 배치에도 현재 pagination 버전을 함께 전달(웹뷰 버전 동기화)
-        const ver = paginationService.getVersion();
-        this._send('logs.batch', { logs, total, seq, version: ver });
-        this.initialSent = true;
-      },
-      onSaved: (info: MergeSavedInfo) => {
-        // quiet
-        this._send('logmerge.saved', info);
-      },
-      onMetrics: (m) => this._send('metrics.update', m),
-
-      // 정식 병합(T1) 완료 → UI 하드리프레시
-      onRefresh: ({
-        total,
-        version,
-        warm,
-      }: {
-        total?: number;
-        version?: number;
-        warm?: boolean;
-      }) => {
-        // warm=true면 “정식 병합 스킵(메모리 모드 완료)”을 명시로 알림
-        this._send('logs.refresh', {
-          reason: 'full-reindex',
-          total,
-          version,
-          warm: !!warm,
+    // 알림 영역 진행률(%) + 취소 버튼 → 세션 중단(부분 결과는 세션에서 폐기)
+    const session = this.session;
+    await vscode.window.withProgress(
+      { location: vscode.ProgressLocation.Notification, title: '로그 병합', cancellable: true },
+      async (progress, token) => {
+        token.onCancellationRequested(() => {
+          this.log.info('merge: cancel requested');
+          session.stopAll();
         });
-        // 정식 병합 완료(스킵 포함): 느리게 전환
-        this._setMemPeriod(this.MEM_SLOW_MS);
-      },
-
-      // ── 진행률: 로그는 샘플링해서 출력, 메시지 전달은 매번 유지 ─────────
-      onProgress: (p) => {
-        const { inc, total, done, active, reset } = p ?? {};
-        // 중앙 스로틀(브리지)로 전달
-        reporter?.onProgress?.({ inc, total, done, active, reset });
-
-        // ─ 로그 노이즈 억제 ─
-        const now = Date.now();
-        if (active) {
-          // 병합 중: 빠르게
-          this._setMemPeriod(this.MEM_FAST_MS);
-          if (typeof total === 'number') this.progTotal = total;
-          const add = typeof inc === 'number' ? inc : 0;
-          this.progAcc += add;
-          this.progDoneAcc += add;
-
-          // 조건: 누적 라인 임계 + 최소 간격 충족 시에만 1줄 로그
-          if (
-            this.progAcc >= this.PROG_LINES_THRESHOLD &&
-            now - this.progLastLogMs >= this.PROG_LOG_INTERVAL_MS
-          ) {
+        let lastPct = 0;
+        await session.startFileMergeSession({
+          dir,
+          indexOutDir,
+          whitelistGlobs,
+          parserConfig,
+          onPercent: (pct) => {
+            if (pct <= lastPct) return;
+            progress.report({ increment: pct - lastPct, message: `${pct}%` });
+            lastPct = pct;
+          },
+          onBatch: (logs, total, seq) => {
+            if (this.initialSent) return;
             // quiet
-            this.progAcc = 0;
-            this.progLastLogMs = now;
-          }
-        } else {
-          // 완료 시에는 정확 수치 1회만 출력
-          // 병합 종료: 느리게
-          this._setMemPeriod(this.MEM_SLOW_MS);
-          if (typeof total === 'number') this.progTotal = total;
-          if (typeof done === 'number') this.progDoneAcc = done;
-          // quiet
-          // 상태 초기화
-          this.progAcc = 0;
-          this.progDoneAcc = 0;
-          this.progTotal = undefined;
-          this.progLastLogMs = 0;
-        }
-      },
-      // stage 신호 중 "정식 병합 스킵: ..." 완료를 감지하면 warm refresh를 보강 전송
-      onStage: (text, kind) => {
-        reporter?.onStage?.(text, kind);
-        this._handleStageAndMaybeWarmRefresh(text, kind);
+            // 초기 배치에도 현재 pagination 버전을 함께 전달(웹뷰 버전 동기화)
+            const ver = this.pages.getVersion();
+            this._send('logs.batch', { logs, total, seq, version: ver });
+            this.initialSent = true;
+          },
+          onSaved: (info: MergeSavedInfo) => {
+            // quiet
+            this._send('logmerge.saved', info);
+          },
+          onMetrics: (m) => this._send('metrics.update', m),
+
+          // 정식 병합(T1) 완료 → UI 하드리프레시
+          onRefresh: ({
+            total,
+            version,
+            warm,
+          }: {
+            total?: number;
+            version?: number;
+            warm?: boolean;
+          }) => {
+            // warm=true면 “정식 병합 스킵(메모리 모드 완료)”을 명시로 알림
+            this._send('logs.refresh', {
+              reason: 'full-reindex',
+              total,
+              version,
+              warm: !!warm,
+            });
+            // 정식 병합 완료(스킵 포함): 느리게 전환
+            this._setMemPeriod(this.MEM_SLOW_MS);
+          },
+
+          // ── 진행률: 로그는 샘플링해서 출력, 메시지 전달은 매번 유지 ─────────
+          onProgress: (p) => {
+            const { inc, total, done, active, reset } = p ?? {};
+            // 중앙 스로틀(브리지)로 전달
+            reporter?.onProgress?.({ inc, total, done, active, reset });
+
+            // ─ 로그 노이즈 억제 ─
+            const now = Date.now();
+            if (active) {
+              // 병합 중: 빠르게
+              this._setMemPeriod(this.MEM_FAST_MS);
+              if (typeof total === 'number') this.progTotal = total;
+              const add = typeof inc === 'number' ? inc : 0;
+              this.progAcc += add;
+              this.progDoneAcc += add;
+
+              // 조건: 누적 라인 임계 + 최소 간격 충족 시에만 1줄 로그
+              if (
+                this.progAcc >= this.PROG_LINES_THRESHOLD &&
+                now - this.progLastLogMs >= this.PROG_LOG_INTERVAL_MS
+              ) {
+                // quiet
+                this.progAcc = 0;
+                this.progLastLogMs = now;
+              }
+            } else {
+              // 완료 시에는 정확 수치 1회만 출력
+              // 병합 종료: 느리게
+              this._setMemPeriod(this.MEM_SLOW_MS);
+              if (typeof total === 'number') this.progTotal = total;
+              if (typeof done === 'number') this.progDoneAcc = done;
+              // quiet
+              // 상태 초기화
+              this.progAcc = 0;
+              this.progDoneAcc = 0;
+              this.progTotal = undefined;
+              this.progLastLogMs = 0;
+            }
+          },
+          // stage 신호 중 "정식 병합 스킵: ..." 완료를 감지하면 warm refresh를 보강 전송
+          onStage: (text, kind) => {
+            reporter?.onStage?.(text, kind);
+            this._handleStageAndMaybeWarmRefresh(text, kind);
+          },
+        });
       },
-    });
+    );
     this.log.debug('[debug] LogViewerPanelManager startFileMerge: end');
   }
 
+  /** 단일 로그 파일 보기: 병합 없이 한 파일만 메모리 버퍼로 띄우고 파싱/건너뜀 수를 알린다 */
+  @measure()
+  async startSingleFile(filePath: string) {
+    if (!this.panel) await this.handleHomeyLoggingCommand();
+    const reporter = this.bridge?.createMergeReporter();
+    this.mode = 'filemerge';
+    this.initialSent = false;
+
+    this.session?.dispose();
+    this.session = new LogSessionManager(this.pages);
+
+    let parserConfig: any;
+    try {
+      parserConfig = await readParserConfigJson(this.context);
+    } catch (e: any) {
+      this.log.warn(`single-file: failed to read parser config (${e?.message ?? e})`);
+    }
+
+    // 대용량 파일은 병합과 같은 페이징 경로로 열리므로 인덱스 위치도 병합과 같게
+    const wsRoot = await this._resolveWorkspaceRoot();
+    const indexOutDir = wsRoot ? path.join(wsRoot, RAW_DIR_NAME, MERGED_DIR_NAME) : undefined;
+
+    try {
+      const r = await this.session.startSingleFileSession({
+        filePath,
+        parserConfig,
+        indexOutDir,
+        onBatch: (logs, total, seq) => {
+          const ver = this.pages.getVersion();
+          this._send('logs.batch', { logs, total, seq, version: ver });
+          this.initialSent = true;
+        },
+        onStage: (text, kind) => reporter?.onStage?.(text, kind),
+        onRefresh: ({ total, version, warm }) => {
+          this._send('logs.refresh', { reason: 'single-file', total, version, warm: !!warm });
+        },
+      });
+      vscode.window.showInformationMessage(
+        r.paged
+          ? `${path.basename(filePath)}: ${r.parsed}줄 — 큰 파일이라 페이징 모드로 열었습니다`
+          : `${path.basename(filePath)}: ${r.parsed}줄 읽음, 인식하지 못한 ${r.skipped}줄 건너뜀`,
+      );
+    } catch (e: any) {
+      const msg = e?.message ?? String(e);
+      this.log.error(`startSingleFile failed: ${msg}`);
+      vscode.window.showErrorMessage(`로그 파일을 열 수 없습니다: ${msg}`);
+    }
+  }
+
   /**
    * 병합 단계(stage) 신호를 UI로 중계하면서, "스킵 완료"를 감지하면
    * logs.refresh(warm=true)도 함께 보내 사후 처리를 통일한다.
@@ -328,8 +499,8 @@ export class LogViewerPanelManager {
     const t = String(text || '');
     // 완료 시그널이면서 "정식 병합 스킵" 텍스트를 포함하면 warm 리프레시를 보낸다.
     if (kind === 'done' && /정식\s*병합\s*스킵/.test(t)) {
-      const total = paginationService.getWarmTotal();
-      const version = paginationService.getVersion();
+      const total = this.pages.getWarmTotal();
+      const version = this.pages.getVersion();
       this.log.info(
         `viewer: warm-skip detected → sending logs.refresh(warm=true) total=${total} v=${version}`,
       );
@@ -426,61 +597,12 @@ export class LogViewerPanelManager {
   }
 
   // ─────────────────────────────────────────────────────────
-  // 정식 UI HTML 로드 (CSP/nonce 및 리소스 경로 재작성)
+  // 정식 UI HTML 로드 (CSP/nonce·리소스 경로 재작성은 webviewHtml 공용 로더)
   // ─────────────────────────────────────────────────────────
-  @measure()
-  private _randomNonce(len = 32) {
-    const chars = 'ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789';
-    let out = '';
-    for (let i = 0; i < len; i++) out += chars.charAt(Math.floor(Math.random() * chars.length));
-    return out;
-  }
-
   @measure()
   private async _getHtmlFromFiles(webview: vscode.Webview, root: vscode.Uri) {
     try {
-      const indexHtml = vscode.Uri.joinPath(root, 'index.html');
-      const htmlRaw = await vscode.workspace.fs.readFile(indexHtml);
-      let html = new TextDecoder('utf-8').decode(htmlRaw);
-
-      const nonce = this._randomNonce();
-
-      // 1) placeholder 치환(있으면)
-      html = html.replace(/%CSP_SOURCE%/g, webview.cspSource);
-      html = html.replace(/%NONCE%/g, nonce);
-
-      // 2) 리소스 경로 재작성 (script/link/img - src/href)
-      const ATTR_RE = /(<(script|link|img)\b[^>]*?\s(?:src|href)=)(['"])([^'"]+)\3/gi;
-      html = html.replace(ATTR_RE, (_m, p1, _tag, q, url) => {
-        const abs =
-          /^(?:https?:|data:|blob:|vscode-)/i.test(url) ||
-          url.startsWith('#') ||
-          url.startsWith('//');
-        if (abs) return `${p1}${q}${url}${q}`;
-        const rewritten = webview.asWebviewUri(vscode.Uri.joinPath(root, url)).toString();
-        return `${p1}${q}${rewritten}${q}`;
-      });
-
-      // 3) nonce가 없는 script 태그에 nonce 부여
-      html = html.replace(/<script\b(?![^>]*\bnonce=)/gi, `<script nonce="${nonce}"`);
-
-      // 4) meta CSP가 없다면 최소 CSP 주입
-      if (!/Content-Security-Policy/i.test(html)) {
-        const cspMeta = `
-          <meta http-equiv="Content-Security-Policy"
-            content="
-              default-src 'none';
-              img-src ${webview.cspSource} blob: data:;
-              style-src ${webview.cspSource} 'unsafe-inline';
-              font-src ${webview.cspSource};
-              script-src 'nonce-${nonce}';
-              connect-src ${webview.cspSource} https:;
-            ">
-        `;
-        html = html.replace(/<head[^>]*>/i, (m) => `${m}\n${cspMeta}`);
-      }
-
-      return html;
+      return await loadWebviewHtml(webview, root);
     } catch (e) {
       this.log.error('[LogViewerPanelManager] UI load failed:', e);
       this.log.error('viewer: UI load failed');
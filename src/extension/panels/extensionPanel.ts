@@ -1,25 +1,45 @@
 // === src/extension/panels/extensionPanel.ts ===
+import * as path from 'path';
 import * as vscode from 'vscode';
 
+import { appendCommandHistory, readCommandHistory } from '../../core/config/commandHistory.js';
 import {
   readEdgePanelState,
   resolveWorkspaceInfo,
   writeEdgePanelState,
 } from '../../core/config/userdata.js';
 import { getStatusLiteFromDir } from '../../core/controller/GitController.js';
+import { isAuditLogEnabled, setAuditLogEnabled } from '../../core/logging/audit-log.js';
+import {
+  isCommandTraceEnabled,
+  setCommandTraceEnabled,
+} from '../../core/logging/command-trace.js';
 import {
   addLogSink,
   getLogger,
+  getLogLevel,
+  type LogLevel,
   removeLogSink,
+  setLogLevel,
   setWebviewReady,
 } from '../../core/logging/extension-logger.js';
 import { measure } from '../../core/logging/perf.js';
-import { DEBUG_LOG_MEMORY_MAX, PANEL_VIEW_TYPE, RANDOM_STRING_LENGTH } from '../../shared/const.js';
-import { readFileAsText } from '../../shared/utils.js';
+import { isQuietMode, setQuietMode } from '../../core/logging/quiet-mode.js';
+import type { LogCaptureOptions } from '../../core/logs/LogCaptureWriter.js';
+import { PaginationService } from '../../core/logs/PaginationService.js';
+import type { JournalWindow } from '../../core/logs/RealtimeSource.js';
+import { COMMAND_HISTORY_REL, DEBUG_LOG_MEMORY_MAX, PANEL_VIEW_TYPE } from '../../shared/const.js';
+import {
+  type CommandDef,
+  completeCommandLine,
+  createCommandHandlers,
+  toCommandLine,
+} from '../commands/commandHandlers.js';
 import type { PerfMonitor } from '../editors/PerfMonitorEditorProvider.js';
 import { EdgePanelActionRouter, type IEdgePanelActionRouter } from './EdgePanelActionRouter.js';
 import { createExplorerBridge, type ExplorerBridge } from './explorerBridge.js';
 import { LogViewerPanelManager } from './LogViewerPanelManager.js';
+import { loadWebviewHtml } from './webviewHtml.js';
 
 interface EdgePanelState {
   version: string;
@@ -42,6 +62,8 @@ export class EdgePanelProvider implements vscode.WebviewViewProvider {
   private _explorer?: ExplorerBridge;
   private _actionRouter?: IEdgePanelActionRouter;
   private _logViewer?: LogViewerPanelManager;
+  /** --device 실시간 뷰어: 기기 serial별로 하나씩, 기본 뷰어 옆에 나란히 띄운다 */
+  private _deviceViewers = new Map<string, LogViewerPanelManager>();
 
   private _disposables = new Set<() => void>();
 
@@ -237,6 +259,9 @@ export class EdgePanelProvider implements vscode.WebviewViewProvider {
         } else if (msg?.command === 'reloadWindow') {
           await vscode.commands.executeCommand('workbench.action.reloadWindow');
           return;
+        } else if (msg?.type === 'command.cancel' && msg?.v === 1) {
+          this.cancelRunningCommands();
+          return;
         } else if (
           msg?.type === 'button.click' &&
           msg?.v === 1 &&
@@ -244,6 +269,14 @@ export class EdgePanelProvider implements vscode.WebviewViewProvider {
         ) {
           await this._actionRouter?.dispatchButton(msg.payload.id);
           return;
+        } else if (
+          msg?.type === 'homey.command.run' &&
+          msg?.v === 1 &&
+          typeof msg.payload?.name === 'string'
+        ) {
+          const args = Array.isArray(msg.payload.args) ? msg.payload.args.map(String) : [];
+          await this._actionRouter?.runCommandLine(toCommandLine(msg.payload.name, args));
+          return;
         }
 
         // ====== 로그 뷰어 위임 ======
@@ -344,6 +377,8 @@ export class EdgePanelProvider implements vscode.WebviewViewProvider {
 
       this._logViewer?.dispose();
       this._logViewer = undefined;
+      for (const v of this._deviceViewers.values()) v.dispose();
+      this._deviceViewers.clear();
 
       this._view = undefined;
     });
@@ -357,69 +392,73 @@ export class EdgePanelProvider implements vscode.WebviewViewProvider {
   }
 
   @measure()
-  public async startRealtime(filter?: string) {
+  public async startRealtime(
+    filter?: string,
+    tailFile?: string,
+    device?: string,
+    capture?: LogCaptureOptions,
+    window?: JournalWindow,
+  ) {
     this.log.debug('[debug] EdgePanelProvider startRealtime: start');
-    await this._logViewer?.startRealtime(filter);
+    // 다른 기기는 별도 패널(페이지 서비스도 별도)로 열어 현재 연결의 뷰어와 나란히 비교한다
+    const viewer = device ? this._deviceViewer(device) : this._logViewer;
+    await viewer?.startRealtime(filter, tailFile, device, capture, window);
     this.log.debug('[debug] EdgePanelProvider startRealtime: end');
   }
+
+  private _deviceViewer(serial: string): LogViewerPanelManager | undefined {
+    if (!this._logViewer) return undefined;
+    let v = this._deviceViewers.get(serial);
+    if (!v) {
+      v = new LogViewerPanelManager(this._context, this._extensionUri, {
+        pages: new PaginationService(),
+        title: `Homey Log Viewer — ${serial}`,
+      });
+      this._deviceViewers.set(serial, v);
+    }
+    return v;
+  }
+
   @measure()
   public async startFileMerge(dir: string) {
     await this._logViewer?.startFileMerge(dir);
   }
-  public stopLogging() {
-    this._logViewer?.stop();
+  @measure()
+  public async startSingleFile(filePath: string) {
+    await this._logViewer?.startSingleFile(filePath);
+  }
+  /** 명령 줄 실행(Command Palette/웹뷰 입력) — 패널이 아직 열리지 않았으면 먼저 연다 */
+  public async runCommandLine(text: string) {
+    if (!this._actionRouter) {
+      await vscode.commands.executeCommand(`${EdgePanelProvider.viewType}.focus`);
+    }
+    if (!this._actionRouter) {
+      vscode.window.showWarningMessage('Edge Console 패널이 준비된 뒤 다시 실행하세요.');
+      return;
+    }
+    await this._actionRouter.runCommandLine(text);
   }
 
-  @measure()
-  private _randomNonce(len = RANDOM_STRING_LENGTH || 32) {
-    const chars = 'ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789';
-    let out = '';
-    for (let i = 0; i < len; i++) out += chars.charAt(Math.floor(Math.random() * chars.length));
-    return out;
+  /** 등록된 명령(입력창 완성용) — 패널이 아직 없으면 이름/help만 쓰는 임시 레지스트리 */
+  public listCommands(): readonly CommandDef[] {
+    return this._actionRouter?.listCommands() ?? createCommandHandlers().listCommands();
+  }
+
+  /** 실행 중인 명령 취소 — 없으면 안내만 */
+  public cancelRunningCommands() {
+    const n = this._actionRouter?.cancelRunning() ?? 0;
+    if (!n) vscode.window.setStatusBarMessage('취소할 실행 중 작업이 없습니다.', 2000);
+  }
+
+  public stopLogging() {
+    this._logViewer?.stop();
   }
 
-  /**
-   * index.html을 읽어:
-   *  - %CSP_SOURCE% → webview.cspSource
-   *  - %NONCE%      → 생성된 nonce
-   *  - src/href의 로컬 상대경로 → webview.asWebviewUri(...)로 재작성
-   *  - 모든 <script> 태그에 nonce 속성 자동 주입(기존에 없을 때만)
-   */
+  /** Edge Panel index.html → 웹뷰 HTML (읽기 실패 시 안내 문구) */
   @measure()
   private async _getHtmlFromFiles(webview: vscode.Webview, root: vscode.Uri) {
     try {
-      const indexHtml = vscode.Uri.joinPath(root, 'index.html');
-      let html = await readFileAsText(indexHtml);
-
-      // 1) CSP 치환
-      const nonce = this._randomNonce();
-      html = html.replace(/%CSP_SOURCE%/g, webview.cspSource);
-      html = html.replace(/%NONCE%/g, nonce);
-
-      // 2) 리소스 경로 재작성(src/href)
-      const ATTR_RE = /(<(script|link|img)\b[^>]*?\s(?:src|href)=)(['"])([^'"]+)\3/gi;
-      html = html.replace(ATTR_RE, (_m, p1: string, _tag: string, q: string, url: string) => {
-        const lower = url.toLowerCase();
-        const isAbs =
-          lower.startsWith('http:') ||
-          lower.startsWith('https:') ||
-          lower.startsWith('data:') ||
-          lower.startsWith('blob:') ||
-          lower.startsWith('vscode-webview:') ||
-          lower.startsWith('vscode-resource:') ||
-          lower.startsWith('chrome:') ||
-          lower.startsWith('about:') ||
-          lower.startsWith('#') ||
-          lower.startsWith('//');
-        if (isAbs) return `${p1}${q}${url}${q}`;
-        const rewritten = webview.asWebviewUri(vscode.Uri.joinPath(root, url)).toString();
-        return `${p1}${q}${rewritten}${q}`;
-      });
-
-      // 3) <script> nonce 자동 주입 (이미 nonce가 있으면 유지)
-      html = html.replace(/<script\b(?![^>]*\bnonce=)/gi, `<script nonce="${nonce}"`);
-
-      return html;
+      return await loadWebviewHtml(webview, root);
     } catch (e) {
       return `<html><body><pre>Edge Panel UI missing</pre></body></html>`;
     }
@@ -438,7 +477,7 @@ export function registerEdgePanelCommands(
         [
           {
             label: '$(debug-start) 실시간 로그 보기',
-            description: 'ADB / journalctl 스트리밍',
+            description: 'ADB logcat(threadtime) / journalctl 스트리밍',
             id: 'realtime',
           },
           {
@@ -498,7 +537,128 @@ export function registerEdgePanelCommands(
       }
     }),
 
+    vscode.commands.registerCommand('homey.logging.openFile', async (file?: string) => {
+      if (file && typeof file === 'string') {
+        await provider.startSingleFile(file);
+        return;
+      }
+      const picked = await vscode.window.showOpenDialog({
+        canSelectFiles: true,
+        canSelectFolders: false,
+        canSelectMany: false,
+        title: '열 로그 파일을 선택하세요',
+      });
+      if (picked && picked[0]) {
+        await provider.startSingleFile(picked[0].fsPath);
+      }
+    }),
+
     vscode.commands.registerCommand('homey.logging.stop', () => provider.stopLogging()),
+
+    // 명령 줄 실행 — 인자(문자열)가 없으면 입력창으로 받는다. 예) `git log -n 5`, `browse /data`
+    // 입력창에서 실행한 명령은 워크스페이스 .config 이력에 남아 다음 입력창에서 다시 고를 수 있다
+    vscode.commands.registerCommand('homey.runCommand', async (line?: string) => {
+      if (typeof line === 'string') {
+        if (line.trim()) await provider.runCommandLine(line);
+        return;
+      }
+      const ws = await resolveWorkspaceInfo(context);
+      const file = path.join(ws.wsDirFsPath, ...COMMAND_HISTORY_REL.split('/'));
+      const history = await readCommandHistory(file);
+      const text = await promptCommandLine(provider.listCommands(), history);
+      if (!text) return;
+      await appendCommandHistory(file, text);
+      await provider.runCommandLine(text);
+    }),
+
+    // 실행 중인 명령 취소(웹뷰 Ctrl+C와 동일)
+    vscode.commands.registerCommand('homey.cancelOperation', () =>
+      provider.cancelRunningCommands(),
+    ),
+
+    // 원격/로컬 명령 추적 토글(재빌드 없이 실제 실행 명령 확인)
+    vscode.commands.registerCommand('homey.debug.toggleCommandTrace', () => {
+      const next = !isCommandTraceEnabled();
+      setCommandTraceEnabled(next);
+      vscode.window.showInformationMessage(`명령 추적: ${next ? 'ON' : 'OFF'}`);
+    }),
+
+    // 작업 감사 로그(.config/edgetool-audit.log) 토글
+    vscode.commands.registerCommand('homey.debug.toggleAuditLog', () => {
+      const next = !isAuditLogEnabled();
+      setAuditLogEnabled(next);
+      vscode.window.showInformationMessage(`감사 로그: ${next ? 'ON' : 'OFF'}`);
+    }),
+
+    // 조용한 모드(진행/상태 안내 생략, 결과·경고·오류만) 토글
+    vscode.commands.registerCommand('homey.debug.toggleQuiet', () => {
+      const next = !isQuietMode();
+      setQuietMode(next);
+      vscode.window.showInformationMessage(`조용한 모드: ${next ? 'ON' : 'OFF'}`);
+    }),
+
+    // 로그 최소 레벨 변경(debug/info/warn/error)
+    vscode.commands.registerCommand('homey.debug.setLogLevel', async () => {
+      const levels: LogLevel[] = ['debug', 'info', 'warn', 'error'];
+      const picked = await vscode.window.showQuickPick(levels, {
+        placeHolder: `현재 로그 레벨: ${getLogLevel()}`,
+      });
+      if (!picked) return;
+      setLogLevel(picked as LogLevel);
+      vscode.window.showInformationMessage(`로그 레벨: ${picked}`);
+    }),
   ];
   regs.forEach((d) => context.subscriptions.push(d));
 }
+
+type CommandLineItem = vscode.QuickPickItem & { line: string; run?: boolean };
+
+/**
+ * 명령 줄 입력(QuickPick) — 입력값 그대로 실행하는 항목이 맨 위, 그 아래 이력(최신순)과 완성 후보.
+ * 후보/이력을 고르면 입력값만 바꾸고 창은 유지한다(Tab 완성처럼 이어서 고쳐 입력).
+ */
+function promptCommandLine(
+  defs: readonly CommandDef[],
+  history: readonly string[] = [],
+): Promise<string | undefined> {
+  return new Promise((resolve) => {
+    const qp = vscode.window.createQuickPick<CommandLineItem>();
+    qp.title = 'Homey Edge: 명령 실행';
+    qp.placeholder = "명령과 인자 — 'help'로 명령 목록 확인 (예: git history 20, browse /etc)";
+    qp.ignoreFocusOut = true;
+
+    const refresh = () => {
+      const text = qp.value.trim();
+      const run: CommandLineItem[] = text
+        ? [{ label: `$(play) ${text}`, description: '실행', line: text, run: true }]
+        : [];
+      const recent = [...history]
+        .reverse()
+        .filter((h) => h !== text && h.toLowerCase().startsWith(text.toLowerCase()))
+        .map((h): CommandLineItem => ({ label: `$(history) ${h}`, description: '이력', line: h }));
+      const completions = completeCommandLine(defs, qp.value).map(
+        (c): CommandLineItem => ({ label: c.line.trim(), description: c.help, line: c.line }),
+      );
+      // VS Code 기본 필터 대신 이력/completeCommandLine 결과를 그대로 보여준다
+      qp.items = [...run, ...recent, ...completions].map((i) => ({ ...i, alwaysShow: true }));
+    };
+
+    qp.onDidChangeValue(refresh);
+    qp.onDidAccept(() => {
+      const item = qp.selectedItems[0] ?? qp.activeItems[0];
+      if (item && !item.run) {
+        qp.value = item.line;
+        refresh();
+        return;
+      }
+      resolve(qp.value.trim() || undefined);
+      qp.hide();
+    });
+    qp.onDidHide(() => {
+      resolve(undefined); // 실행 항목으로 이미 resolve된 경우 무시됨
+      qp.dispose();
+    });
+    refresh();
+    qp.show();
+  });
+}
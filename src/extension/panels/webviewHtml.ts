@@ -0,0 +1,88 @@
+// === src/extension/panels/webviewHtml.ts ===
+/**
+ * 웹뷰 index.html 로더(Edge Panel / Log Viewer 공용)
+ * - %CSP_SOURCE% / %NONCE% 치환 → 로컬 src/href를 asWebviewUri로 재작성 → <script> nonce 주입
+ * - 자산(JS/CSS/이미지)은 index.html 태그만 보고 한 곳에서 처리하므로 새 모듈은 태그 한 줄이면 된다.
+ * - dist에 없는 로컬 자산은 웹뷰에서 조용히 404가 되므로 로드 시점에 경고로 남긴다.
+ */
+import * as vscode from 'vscode';
+
+import { getLogger } from '../../core/logging/extension-logger.js';
+import { RANDOM_STRING_LENGTH } from '../../shared/const.js';
+import { readFileAsText } from '../../shared/utils.js';
+
+const log = getLogger('webviewHtml');
+
+/** 재작성하지 않는 URL(원격/인라인/웹뷰 스킴/앵커/프로토콜 상대) */
+const EXTERNAL_URL_RE =
+  /^(?:https?:|data:|blob:|vscode-webview:|vscode-resource:|vscode-file:|chrome:|about:|#|\/\/)/i;
+const ASSET_ATTR_RE = /(<(?:script|link|img)\b[^>]*?\s(?:src|href)=)(['"])([^'"]+)\2/gi;
+
+export function randomNonce(len = RANDOM_STRING_LENGTH) {
+  const chars = 'ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789';
+  let out = '';
+  for (let i = 0; i < len; i++) out += chars.charAt(Math.floor(Math.random() * chars.length));
+  return out;
+}
+
+/**
+ * script/link/img의 로컬 src/href를 toUri로 바꾼다.
+ * @returns 재작성된 html과 참조된 로컬 자산 경로(중복 제거, 등장 순)
+ */
+export function rewriteAssetUrls(
+  html: string,
+  toUri: (rel: string) => string,
+): { html: string; assets: string[] } {
+  const assets = new Set<string>();
+  const out = html.replace(ASSET_ATTR_RE, (m, prefix: string, q: string, url: string) => {
+    if (EXTERNAL_URL_RE.test(url)) return m;
+    assets.add(url);
+    return `${prefix}${q}${toUri(url)}${q}`;
+  });
+  return { html: out, assets: [...assets] };
+}
+
+/** `<root>/index.html`을 웹뷰용으로 변환. CSP meta가 없으면 최소 CSP를 넣는다 */
+export async function loadWebviewHtml(webview: vscode.Webview, root: vscode.Uri): Promise<string> {
+  const nonce = randomNonce();
+  const raw = (await readFileAsText(vscode.Uri.joinPath(root, 'index.html')))
+    .replace(/%CSP_SOURCE%/g, webview.cspSource)
+    .replace(/%NONCE%/g, nonce);
+
+  const { html: rewritten, assets } = rewriteAssetUrls(raw, (rel) =>
+    webview.asWebviewUri(vscode.Uri.joinPath(root, rel)).toString(),
+  );
+  const missing = await missingAssets(root, assets);
+  if (missing.length) log.warn(`webview: missing assets in ${root.fsPath}: ${missing.join(', ')}`);
+
+  let html = rewritten.replace(/<script\b(?![^>]*\bnonce=)/gi, `<script nonce="${nonce}"`);
+  if (!/Content-Security-Policy/i.test(html)) {
+    const cspMeta = `
+      <meta http-equiv="Content-Security-Policy"
+        content="
+          default-src 'none';
+          img-src ${webview.cspSource} blob: data:;
+          style-src ${webview.cspSource} 'unsafe-inline';
+          font-src ${webview.cspSource};
+          script-src 'nonce-${nonce}';
+          connect-src ${webview.cspSource} https:;
+        ">
+    `;
+    html = html.replace(/<head[^>]*>/i, (m) => `${m}\n${cspMeta}`);
+  }
+  return html;
+}
+
+async function missingAssets(root: vscode.Uri, assets: string[]): Promise<string[]> {
+  const checks = await Promise.all(
+    assets.map(async (rel) => {
+      // 쿼리/해시(`app.css?v=1`)는 파일 경로가 아니므로 떼고 확인
+      const file = rel.replace(/[?#].*$/, '');
+      return vscode.workspace.fs.stat(vscode.Uri.joinPath(root, file)).then(
+        () => undefined,
+        () => rel,
+      );
+    }),
+  );
+  return checks.filter((x): x is string => !!x);
+}
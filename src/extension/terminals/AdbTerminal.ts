@@ -2,6 +2,7 @@
 import * as child_process from 'child_process';
 import * as vscode from 'vscode';
 
+import { isToolMissingError, toolInstallHint } from '../../core/connection/toolPreflight.js';
 import { getLogger } from '../../core/logging/extension-logger.js';
 
 const log = getLogger('terminal.adb');
@@ -47,6 +48,11 @@ export class AdbPtyTerminal implements vscode.Pseudoterminal {
       this.close();
     });
     this.proc.on('error', (e) => {
+      if (isToolMissingError(e)) {
+        this.writeEmitter.fire(`\r\n[ADB] adb를 찾을 수 없습니다. ${toolInstallHint('adb')}\r\n`);
+        this.close();
+        return;
+      }
       this.writeEmitter.fire(`\r\n[ADB] error: ${e instanceof Error ? e.message : String(e)}\r\n`);
       this.close();
     });
@@ -2,25 +2,28 @@
 import { Client } from 'ssh2';
 import * as vscode from 'vscode';
 
+import type { SshDetails } from '../../core/config/connection-config.js';
+import { resolveSshTarget, type SshTarget } from '../../core/config/ssh-config.js';
 import { connectionManager } from '../../core/connection/ConnectionManager.js';
+import { sshAuthOptions, sshKeepaliveOptions } from '../../core/connection/sshClient.js';
 import { getLogger } from '../../core/logging/extension-logger.js';
 
 const log = getLogger('terminal.ssh');
 
-type ActiveSshDetails = {
-  host: string;
-  user: string;
-  port?: number;
-  password?: string;
-};
+type ActiveSshDetails = SshTarget;
 
 function getActiveSsh(): ActiveSshDetails | undefined {
   const snap = connectionManager.getSnapshot?.();
   const active = snap?.active;
   if (!active || active.type !== 'SSH') return undefined;
-  const d = active.details as any;
-  if (!d?.host || !d?.user) return undefined;
-  return { host: d.host, user: d.user, port: d.port, password: d.password };
+  const d = active.details as SshDetails;
+  if (!d?.configAlias && (!d?.host || !d?.user)) return undefined;
+  try {
+    return resolveSshTarget(d);
+  } catch (e) {
+    log.warn(`[warn] ssh terminal: ${e instanceof Error ? e.message : String(e)}`);
+    return undefined;
+  }
 }
 
 export class SshPtyTerminal implements vscode.Pseudoterminal {
@@ -102,9 +105,9 @@ export class SshPtyTerminal implements vscode.Pseudoterminal {
         host: details.host,
         port: details.port ?? 22,
         username: details.user,
-        password: details.password,
+        ...sshAuthOptions(details),
         readyTimeout: 15000,
-        keepaliveInterval: 10000,
+        ...sshKeepaliveOptions(details),
         tryKeyboard: false,
       });
   }
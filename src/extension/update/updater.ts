@@ -5,6 +5,7 @@ import * as os from 'os';
 import * as path from 'path';
 import * as vscode from 'vscode';
 
+import { throwIfAborted } from '../../core/connection/deadline.js';
 import { getLogger } from '../../core/logging/extension-logger.js';
 import { globalProfiler } from '../../core/logging/perf.js';
 import {
@@ -49,16 +50,28 @@ async function fetchJson<T>(url: string, timeoutMs = FETCH_JSON_TIMEOUT_MS): Pro
   }
 }
 
-async function fetchBuffer(url: string, timeoutMs = FETCH_BUFFER_TIMEOUT_MS): Promise<Buffer> {
+async function fetchBuffer(
+  url: string,
+  timeoutMs = FETCH_BUFFER_TIMEOUT_MS,
+  signal?: AbortSignal,
+): Promise<Buffer> {
   const controller = new AbortController();
   const t = setTimeout(() => controller.abort(), timeoutMs);
+  // 전체 마감/취소(signal)도 진행 중 다운로드를 끊는다
+  const onAbort = () => controller.abort();
+  if (signal?.aborted) onAbort();
+  else signal?.addEventListener('abort', onAbort, { once: true });
   try {
     const res = await fetch(url, { signal: controller.signal });
     if (!res.ok) throw new Error(`HTTP ${res.status} ${res.statusText}`);
     const arr = await res.arrayBuffer();
     return Buffer.from(arr);
+  } catch (e) {
+    throwIfAborted(signal);
+    throw e;
   } finally {
     clearTimeout(t);
+    signal?.removeEventListener('abort', onAbort);
   }
 }
 
@@ -70,8 +83,9 @@ async function downloadFile(
   url: string,
   destPath: string,
   progress?: (downloaded: number, total: number) => void,
+  signal?: AbortSignal,
 ): Promise<void> {
-  const buffer = await fetchBuffer(url);
+  const buffer = await fetchBuffer(url, FETCH_BUFFER_TIMEOUT_MS, signal);
   await fs.promises.writeFile(destPath, buffer);
   if (progress) {
     progress(buffer.length, buffer.length);
@@ -127,6 +141,7 @@ export async function downloadAndInstall(
   url: string,
   sha256: string,
   onProgress?: (progress: number) => void,
+  signal?: AbortSignal,
 ): Promise<void> {
   log.debug('[debug] downloadAndInstall: start');
   return globalProfiler.measureFunction('downloadAndInstall', async () => {
@@ -135,7 +150,8 @@ export async function downloadAndInstall(
       const tempFile = path.join(tempDir, 'update.vsix');
 
       log.info(`downloadAndInstall: downloading ${url} to ${tempFile}`);
-      await downloadFile(url, tempFile, onProgress);
+      await downloadFile(url, tempFile, onProgress, signal);
+      throwIfAborted(signal);
 
       log.info(`downloadAndInstall: verifying sha256 ${sha256}`);
       const actualSha256 = await computeSha256(tempFile);
@@ -45,7 +45,33 @@ export const FETCH_BUFFER_TIMEOUT_MS = 60_000;
 // Webview/Panel
 export const DEFAULT_SSH_PORT = 22;
 export const DEFAULT_TRANSFER_TIMEOUT_MS = 60_000;
+/** 디렉터리 전송 시 건너뛸 이름(경로 세그먼트 단위 일치) */
+export const TRANSFER_SKIP_NAMES = ['.git', 'node_modules', '.DS_Store', 'Thumbs.db'] as const;
+/** Windows 파일명 금지 문자(제어문자 포함) / 예약 이름 */
+export const WINDOWS_ILLEGAL_NAME_CHARS = /[<>:"|?*\x00-\x1f]/g;
+export const WINDOWS_RESERVED_NAMES = /^(con|prn|aux|nul|com[1-9]|lpt[1-9])(\..*)?$/i;
+/** ADB 디렉터리 pull 동시 실행 수 기본값(기기 부하를 고려해 보수적으로) — EDGE_TOOL_ADB_PULL_CONCURRENCY로 조정 */
+export const DEFAULT_ADB_PULL_CONCURRENCY = 3;
+export const MAX_ADB_PULL_CONCURRENCY = 8;
+/** 받을 수 없는 가상 파일시스템(커널/장치 노드) — browse는 이 아래 경로를 거부하고 `/` pull에서 제외 */
+export const REMOTE_PSEUDO_FS_DIRS = ['/proc', '/sys', '/dev'] as const;
+/** browse로 이 크기(du) 이상 디렉터리를 받기 전에는 확인을 받는다 */
+export const BROWSE_CONFIRM_BYTES = 100 * 1024 * 1024;
+/** 이 크기 이상 push 전에는 원격 여유 공간(df)을 먼저 확인 */
+export const PUSH_SPACE_CHECK_MIN_BYTES = 8 * 1024 * 1024;
 export const DEFAULT_COMMAND_TIMEOUT_MS = 30_000;
+/** 서비스 재시작 후 연결(ADB 기기/SSH 세션)이 돌아오기를 기다리는 한도 */
+export const RELINK_TIMEOUT_MS = 60_000;
+/** SSH 재접속 backoff(시작 → 2배씩 → 상한) */
+export const RELINK_BACKOFF_MIN_MS = 500;
+export const RELINK_BACKOFF_MAX_MS = 5_000;
+/**
+ * SSH keepalive(OpenSSH ServerAliveInterval/ServerAliveCountMax 대응): 간격(초) × 횟수 동안 응답이 없으면
+ * 끊긴 것으로 보고 세션을 닫는다 — 실제 단절을 가리지 않도록 1분 안팎으로 보수적으로 잡는다.
+ * EDGE_TOOL_SSH_ALIVE_INTERVAL / EDGE_TOOL_SSH_ALIVE_COUNT_MAX 또는 연결별 설정으로 조정(간격 0 = 끔)
+ */
+export const SSH_ALIVE_INTERVAL_S_DEFAULT = 15;
+export const SSH_ALIVE_COUNT_MAX_DEFAULT = 4;
 export const MAX_SSH_PORT = 65535;
 export const MIN_SSH_PORT = 1;
 
@@ -61,17 +87,37 @@ export const FIRST_BATCH_SIZE = DEFAULT_BATCH_SIZE;
 /** 웜업 목표치 및 메모리 모드 임계값의 기본값 */
 export const DEFAULT_MEMORY_MODE_THRESHOLD = 10000;
 export const REALTIME_BUFFER_MAX = 1000;
+/** 실시간 배치 flush 최소 간격(ms) — 버스트는 이 간격으로 묶고, 한산할 땐 즉시 전송 */
+export const REALTIME_FLUSH_INTERVAL_MS = 250;
+/** 실시간 배치 최대 라인 수 — 넘으면 간격을 기다리지 않고 즉시 flush */
+export const REALTIME_FLUSH_MAX_LINES = 2000;
+/** 실시간 시작 시 함께 보여줄 직전 로그 라인 수(0 = 연결 시점부터) */
+export const REALTIME_TAIL_LINES_DEFAULT = 0;
+/** 실시간 초기 backlog 상한(과도한 덤프 방지) */
+export const REALTIME_TAIL_LINES_MAX = 5000;
+/** 실시간 세션 청크 보관 개수 상한 — 넘으면 가장 오래된 part부터 삭제(0 = 무제한) */
+export const REALTIME_MAX_CHUNKS_DEFAULT = 200;
+/** 실시간 세션 청크 총 용량 상한(bytes, 0 = 무제한) */
+export const REALTIME_MAX_BYTES_DEFAULT = 0;
+/** 실시간 로컬 캡처(--append) 회전 시 남기는 이전 파일 수 */
+export const LOG_CAPTURE_KEEP_DEFAULT = 3;
 export const PERF_DATA_MAX = 1000;
 export const LOG_TOTAL_CALLS_THRESHOLD = 1000;
 
 /** workspace 하위의 raw 디렉터리명 */
 export const RAW_DIR_NAME = 'raw';
+/** 기기 로그 보관(homeyLogsSave) 폴더명 — workspace/<이름>/<연결ID>_<시각> */
+export const DEVICE_LOGS_DIR_NAME = 'device_logs';
 /** 병합 결과 저장 디렉터리명 (raw 하위에 생성) */
 export const MERGED_DIR_NAME = 'merged';
+/** 실시간 로그 보존 디렉터리명 (raw 하위, realtimePreserve 설정 시 세션 간 유지) */
+export const REALTIME_DIR_NAME = 'realtime';
 /** 병합 manifest 파일명 */
 export const MERGED_MANIFEST_FILENAME = 'manifest.json';
 /** 병합 결과 한 청크의 최대 라인 수 */
 export const MERGED_CHUNK_MAX_LINES = 5000;
+/** 전체 검색 결과 한 페이지 크기(search.query에 pageSize가 없을 때) */
+export const SEARCH_PAGE_SIZE_DEFAULT = 100;
 /** PagedReader 기본 페이지 크기(웹뷰가 별도 지정하지 않으면) */
 export const PAGED_READER_DEFAULT_PAGE_SIZE = 500;
 
@@ -106,6 +152,42 @@ export const GITIGNORE_TEMPLATE_REL = 'media/resources/.gitignore.template';
 export const USERCFG_REL = '.config/custom_user_config.json';
 export const USERCFG_TEMPLATE_REL = 'media/resources/custom_user_config.template.json';
 
+/** 작업 감사 로그(opt-in) — 워크스페이스 기준 경로와 회전 정책 */
+export const AUDIT_LOG_REL = '.config/edgetool-audit.log';
+export const AUDIT_LOG_MAX_BYTES = 1024 * 1024;
+export const AUDIT_LOG_KEEP = 3;
+
+/** 명령 줄 실행 이력 — 워크스페이스 기준 경로와 보관 개수 */
+export const COMMAND_HISTORY_REL = '.config/edgetool-history';
+export const COMMAND_HISTORY_MAX = 200;
+
+/** Homey 카테고리(컨테이너 볼륨 기반 pull/push 대상) */
+export type HomeyKind = 'pro' | 'core' | 'sdk' | 'bridge';
+
+/**
+ * 기기 디렉터리 레이아웃 기본값 — custom_user_config.json의 homey_layout(+연결별)으로 덮어쓸 수 있음
+ * - volumes: docker 볼륨 이름 + 볼륨 내부 하위 경로
+ * - local_dirs: 워크스페이스 내 로컬 디렉터리 이름
+ */
+export const DEFAULT_HOMEY_LAYOUT = {
+  docker_root_fallback: '/lg_rw/var/lib/docker',
+  volumes: {
+    pro: { volume: 'homey-app', subpath: '' },
+    core: { volume: 'homey-node', subpath: '@athombv/homey-core/dist' },
+    sdk: { volume: 'homey-node', subpath: '@athombv/homey-apps-sdk-v3' },
+    bridge: { volume: 'homey-node', subpath: '@athombv/homey-bridge' },
+  } as Record<HomeyKind, { volume: string; subpath: string }>,
+  /** 카테고리별 원격 절대 경로 강제(설정 시 탐색 생략) */
+  paths: {} as Partial<Record<HomeyKind, string>>,
+  local_dirs: {
+    pro: 'homey_pro',
+    core: 'homey_core',
+    sdk: 'homey_sdk',
+    bridge: 'homey_bridge',
+  } as Record<HomeyKind, string>,
+};
+export type HomeyLayout = typeof DEFAULT_HOMEY_LAYOUT;
+
 // ─────────────────────────────────────────────────────────────
 // UI 문자열(라벨/설명/섹션 타이틀) — SSOT
 // ─────────────────────────────────────────────────────────────
@@ -145,6 +227,7 @@ export const UI_STR = {
   // Help
   BTN_PERF_MONITOR: '성능 측정',
   BTN_RELOAD_VSCODE: 'VS Code 재시작',
+  BTN_RUN_COMMAND: '명령 실행',
   BTN_HELP: '도움말',
 } as const;
 
@@ -152,7 +235,7 @@ export const UI_DESC = {
   UPDATE_NOW: '확장 업데이트 확인/설치',
   OPEN_HOST_SHELL: '현재 활성 연결(ADB/SSH)로 셸을 실행',
   GIT_FLOW: 'Host/Homey pull 또는 push',
-  LOGGING_LIVE: 'ADB / journalctl 스트리밍',
+  LOGGING_LIVE: 'ADB logcat(threadtime) / journalctl 스트리밍',
   LOGGING_FILE: '폴더 선택 → 병합 뷰',
   APPLOG: 'HOMEY_APP_LOG=1 On/Off',
   DEVTOKEN: 'HOMEY_DEV_TOKEN=1 On/Off',
@@ -161,4 +244,5 @@ export const UI_DESC = {
   OPEN_WORKSPACE_SHELL: '작업폴더 경로에서 로컬 터미널 열기',
   INIT_WORKSPACE: '.config/custom_log_parser.json + README 재생성',
   RELOAD: 'VSCode 창 새로고침',
+  RUN_COMMAND: '명령 이름 + 인자 입력(예: git history 20, browse /etc)',
 } as const;
@@ -1,6 +1,14 @@
 // === src/shared/errors.ts ===
 export enum ErrorCategory {
   Connection = 'CONNECTION',
+  /** 활성 연결 없음(연결 전 명령 시도) */
+  NotConnected = 'NOT_CONNECTED',
+  /** 기기에 Homey 서비스/유닛이 없음 */
+  HomeyNotInstalled = 'HOMEY_NOT_INSTALLED',
+  /** 원격 명령이 실행됐지만 실패(종료코드 ≠ 0) */
+  RemoteCommand = 'REMOTE_COMMAND',
+  /** 원격 여유 공간 부족(대용량 전송 전 확인) */
+  InsufficientSpace = 'INSUFFICIENT_SPACE',
   Permission = 'PERMISSION',
   ToolMissing = 'TOOL_MISSING',
   Path = 'PATH',
@@ -19,3 +27,67 @@ export class XError extends Error {
     this.name = `XError/${category}`;
   }
 }
+
+/** 원격 명령 실패 — 명령/종료코드/출력을 함께 보관 */
+export class RemoteCommandError extends XError {
+  constructor(
+    public cmd: string,
+    public code: number | null,
+    public output: string,
+    message = `원격 명령 실패(code=${code}): ${cmd}`,
+  ) {
+    super(ErrorCategory.RemoteCommand, message, { cmd, code, output });
+  }
+}
+
+/**
+ * 명령 실패(사용법 오류 포함) — 명령 라우터가 `--json` 결과(ok:false)와 runScript 중단에 쓴다.
+ * reported: 핸들러가 이미 로그/알림으로 알렸으면 true(라우터는 다시 알리지 않음)
+ */
+export class CommandFailedError extends XError {
+  constructor(
+    message: string,
+    detail?: unknown,
+    public reported = false,
+  ) {
+    super(findXError(detail)?.category ?? ErrorCategory.Unknown, message, detail);
+  }
+}
+
+export const NOT_CONNECTED_MESSAGE = '활성 연결이 없습니다. 먼저 "기기 연결"을 수행하세요.';
+
+export function notConnectedError(): XError {
+  return new XError(ErrorCategory.NotConnected, NOT_CONNECTED_MESSAGE);
+}
+
+/** XError 여부(+카테고리 일치) 판별 — 경계(명령 핸들러)에서 분기용 */
+export function isXError(e: unknown, category?: ErrorCategory): e is XError {
+  return e instanceof XError && (category === undefined || e.category === category);
+}
+
+/** 래핑된 원인(detail)까지 따라가며 첫 XError를 찾는다 */
+export function findXError(e: unknown): XError | undefined {
+  for (let cur: any = e, i = 0; cur && i < 8; cur = cur.detail, i++) {
+    if (cur instanceof XError) return cur;
+  }
+  return undefined;
+}
+
+/** 사용자 표시용 메시지: 카테고리별 안내 + 원본 메시지 */
+export function describeError(e: unknown): string {
+  const x = findXError(e);
+  const msg = e instanceof Error ? e.message : String(e);
+  switch (x?.category) {
+    case ErrorCategory.NotConnected:
+      return NOT_CONNECTED_MESSAGE;
+    case ErrorCategory.HomeyNotInstalled:
+      return `기기에서 Homey 서비스를 찾을 수 없습니다. Homey 설치 상태를 확인하세요. (${x.message})`;
+    case ErrorCategory.RemoteCommand: {
+      const r = x as RemoteCommandError;
+      const out = String(r.output ?? '').trim();
+      return `${x.message}${out ? `\n${out.slice(0, 500)}` : ''}`;
+    }
+    default:
+      return msg;
+  }
+}
@@ -8,6 +8,16 @@ export type ParsedPayload = {
   message?: string | null;
 };
 
+/** 검색 페이지 이어보기 위치 — Webview는 불투명 값으로 그대로 되돌려 보낸다 */
+export type SearchCursor = { next: number; v: number };
+/** 검색 매칭 옵션(기본: 대소문자 무시, 부분 문자열) */
+export type SearchOptions = { caseSensitive?: boolean; wholeWord?: boolean };
+/** 검색 결과 1건 — ranges는 text 안의 매칭 구간 [start, end) 목록 */
+export type SearchHit = { idx: number; text: string; ranges?: [number, number][] };
+
+/** 정규화된 로그 레벨 — 심각도 순서 D < I < W < E */
+export type LogLevel = 'D' | 'I' | 'W' | 'E';
+
 export type LogEntry = {
   id: number;
   /**
@@ -17,7 +27,7 @@ export type LogEntry = {
    */
   idx?: number;
   ts: number; // epoch ms
-  level?: 'D' | 'I' | 'W' | 'E';
+  level?: LogLevel;
   type?: 'system' | 'homey' | 'application' | 'other';
   /**
    * 표시/검색용 소스 정보(과거 호환):
@@ -35,11 +45,28 @@ export type LogEntry = {
   text: string;
   /** 파싱 결과 원문 필드(테스트/필터/검색용) */
   parsed?: ParsedPayload;
+  /** 중복 합치기: 이 엔트리 뒤로 같은 메시지가 추가로 반복된 횟수(실시간 전용) */
+  repeat?: number;
   /** 병합 타이브레이커 메타(내부용) */
   _fRank?: number;
   _rev?: number;
 };
 
+/** 로그 버퍼 통계 — 모든 값은 호스트에서 정수로 정규화되어 내려온다 */
+export type LogStats = {
+  /** 현재 보이는(필터 적용) 총 라인 수 */
+  total: number;
+  /** 워밍업 메모리 버퍼에 든 라인 수 */
+  memory: number;
+  /** 파일(manifest) 기반 총 라인 수 */
+  file: number;
+  /** 보관 정책으로 회전되어 사라진 앞쪽 라인 수 */
+  rotated: number;
+  /** 웹뷰로 전달되지 못한 메시지 수(타입 합계) */
+  dropped: number;
+  warm: boolean;
+};
+
 export type EdgePanelState = {
   version: string;
   updateAvailable: boolean;
@@ -87,11 +114,22 @@ export type LogFilter = {
   src?: string; // 파일/소스
   proc?: string; // 프로세스명
   msg?: string; // 메시지
+  exclude?: string; // 제외어(라인 전체 대상, 매칭 시 숨김)
 };
 
 // Host → Webview
 export type H2W =
-  | Envelope<'logs.batch', { logs: LogEntry[]; total?: number; seq?: number; version?: number }>
+  | Envelope<
+      'logs.batch',
+      {
+        logs: LogEntry[];
+        total?: number;
+        seq?: number;
+        version?: number;
+        /** 팔로우 해제 중 보내지 않은 새 라인 수(logs는 비어 있음, total만 갱신) */
+        skipped?: number;
+      }
+    >
   | Envelope<
       'logs.page.response',
       {
@@ -114,6 +152,7 @@ export type H2W =
         mem: { rss: number; heapUsed: number };
       }
     >
+  | Envelope<'logs.stats', LogStats>
   | Envelope<'connection.status', { state: 'connected' | 'disconnected'; host: string }>
   | Envelope<'update.available', { version: string }>
   | Envelope<
@@ -181,13 +220,27 @@ export type H2W =
           | 'manifest-updated'
           | 'filter-changed'
           | 'bridge.start'
-          | 'viewer.ready';
+          | 'viewer.ready'
+          | 'resync'
+          | 'single-file';
         total?: number;
         version?: number;
         warm?: boolean;
       }
     >
-  | Envelope<'search.results', { hits: { idx: number; text: string }[]; q: string }>;
+  | Envelope<
+      'search.results',
+      {
+        hits: SearchHit[];
+        q: string;
+        /** 이어받기 결과(기존 목록 뒤에 붙임) */
+        append?: boolean;
+        /** 다음 페이지 요청 시 그대로 돌려줄 값(없으면 마지막 페이지) */
+        nextCursor?: SearchCursor;
+        /** 이번 검색에 적용된 매칭 옵션(UI 표시용) */
+        options?: SearchOptions;
+      }
+    >;
 
 // Webview → Host
 export type W2H =
@@ -205,10 +258,29 @@ export type W2H =
   | Envelope<'logs.page.request', { startIdx: number; endIdx: number }>
   /** 서버측 필터 적용/해제(단일 API, null=해제) */
   | Envelope<'logs.filter.set', { filter: LogFilter | null }>
-  | Envelope<'search.query', { q: string; regex?: boolean; range?: [number, number]; top?: number }>
+  /** 심각도 하한(floor 이상만 전달) — 텍스트 필터와 AND로 결합, null=해제 */
+  | Envelope<'logs.severity.set', { floor: LogLevel | null }>
+  /** 테일 따라가기 상태 — false면 호스트는 실시간 새 라인을 밀어주지 않고 총량만 알린다 */
+  | Envelope<'logs.follow.set', { follow: boolean }>
+  | Envelope<'logs.stats.request', Empty>
+  | Envelope<
+      'search.query',
+      {
+        q: string;
+        regex?: boolean;
+        range?: [number, number];
+        top?: number;
+        /** 이전 search.results의 nextCursor(없으면 처음부터) */
+        cursor?: SearchCursor;
+        /** 페이지 크기(기본 SEARCH_PAGE_SIZE_DEFAULT) */
+        pageSize?: number;
+      } & SearchOptions
+    >
   | Envelope<'search.clear', Empty>
   | Envelope<'homey.command.run', { name: string; args?: string[] }>
   | Envelope<'button.click', { id: string }>
+  /** 실행 중인 명령 취소(Ctrl+C) */
+  | Envelope<'command.cancel', Empty>
   | Envelope<'perfMeasure', { name: string; duration: number }>
   | Envelope<'perf.startCapture', Empty>
   | Envelope<'prefs.load', Empty>
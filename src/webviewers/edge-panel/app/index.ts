@@ -229,6 +229,15 @@ import { createStore } from './store.js';
     });
   });
   document.addEventListener('keydown', (e) => {
+    // Ctrl+C(선택 영역 없음) → 실행 중인 명령 취소. 선택이 있으면 일반 복사
+    const k = e as KeyboardEvent;
+    if (k.ctrlKey && !k.shiftKey && !k.altKey && k.key.toLowerCase() === 'c') {
+      if (!String(window.getSelection() ?? '')) {
+        e.preventDefault();
+        m('UI.command.cancel', () => hostProxy.post({ v: 1, type: 'command.cancel', payload: {} }));
+      }
+      return;
+    }
     if ((e as KeyboardEvent).key === 'Escape') {
       m('Explorer.clearSelection(Escape)', () => {
         appView.clearExplorerSelection();
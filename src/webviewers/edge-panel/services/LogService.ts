@@ -93,6 +93,16 @@ export class LogService {
     return this.seg;
   }
 
+  /** 라인 내용 기반 강조 (세그먼트 토글과 무관) */
+  private decorate(div: HTMLElement, line: string) {
+    // 에러 힌트
+    if (/\[E\]/.test(line)) div.style.color = '#ff6b6b';
+    // git history 커밋 분류 태그
+    const m = line.match(/\[(dl|skip|user)\] [0-9a-f]{4,}/);
+    if (m) div.classList.add(`git-${m[1]}`);
+    div.textContent = line;
+  }
+
   reset(lines?: string[]) {
     this.m('LogService.reset', () => {
       this.ensureContainer();
@@ -116,13 +126,7 @@ export class LogService {
 
       const div = document.createElement('div');
       div.className = `log-line seg${seg}`;
-
-      // 에러 힌트는 기존처럼 표시(토글과 무관)
-      if (/\[E\]/.test(line)) {
-        div.style.color = '#ff6b6b';
-      }
-
-      div.textContent = line;
+      this.decorate(div, line);
       (this.body as HTMLElement).appendChild(div);
 
       // 스크롤 맨 아래로
@@ -147,8 +151,7 @@ export class LogService {
         const seg = this.pickSegment(line);
         const div = document.createElement('div');
         div.className = `log-line seg${seg}`;
-        if (/\[E\]/.test(line)) div.style.color = '#ff6b6b';
-        div.textContent = line;
+        this.decorate(div, line);
         frag.appendChild(div);
       }
       (this.body as HTMLElement).insertBefore(frag, this.body.firstChild);
@@ -4,6 +4,7 @@ import { createPortal } from 'react-dom';
 import { createUiLog } from '../../../shared/utils';
 import { createUiMeasure } from '../../../shared/utils';
 import { vscode } from '../ipc';
+import { sourceColor, sourceKey } from '../sourceColor';
 import { useLogStore } from '../store';
 import type { Filter } from '../types';
 
@@ -11,9 +12,32 @@ export function FilterDialog({ open, onClose }: { open: boolean; onClose: () =>
   const storeFilter = useLogStore((s) => s.filter);
   const applyFilter = useLogStore((s) => s.applyFilter);
   const resetFilters = useLogStore((s) => s.resetFilters);
+  const filterPresets = useLogStore((s) => s.filterPresets);
+  const saveFilterPreset = useLogStore((s) => s.saveFilterPreset);
+  const deleteFilterPreset = useLogStore((s) => s.deleteFilterPreset);
+  const [presetName, setPresetName] = useState('');
   const measureUi = useLogStore((s) => s.measureUi);
+  const rows = useLogStore((s) => s.rows);
   const ui = useMemo(() => createUiLog(vscode, 'log-viewer.filter'), []);
 
+  // 현재 로드된 행에서 프로세스(태그)/소스(파일) 후보 추출 — 다중 선택용
+  const MAX_PROC_OPTIONS = 40;
+  const collectOptions = (pick: (r: (typeof rows)[number]) => string | undefined) => {
+    const uniq = new Set<string>();
+    for (const r of rows) {
+      const v = String(pick(r) ?? '').trim();
+      if (v) uniq.add(v);
+    }
+    return Array.from(uniq)
+      .sort((a, b) => a.localeCompare(b))
+      .slice(0, MAX_PROC_OPTIONS);
+  };
+  const procOptions = useMemo(() => (open ? collectOptions((r) => r.proc) : []), [open, rows]);
+  const srcOptions = useMemo(
+    () => (open ? collectOptions((r) => (r.src ? sourceKey(r.src) : undefined)) : []),
+    [open, rows],
+  );
+
   const [local, setLocal] = useState(storeFilter);
   useEffect(() => {
     console.debug?.('[debug] FilterDialog: useEffect open/filter change');
@@ -23,7 +47,14 @@ export function FilterDialog({ open, onClose }: { open: boolean; onClose: () =>
     } else {
       measureUi('FilterDialog.close', () => ui.info('filterDialog.close'));
     }
-  }, [open, storeFilter.pid, storeFilter.src, storeFilter.proc, storeFilter.msg]);
+  }, [
+    open,
+    storeFilter.pid,
+    storeFilter.src,
+    storeFilter.proc,
+    storeFilter.msg,
+    storeFilter.exclude,
+  ]);
 
   // 오픈 시 실제 DOM의 z-index/크기를 로그로 확인
   useEffect(() => {
@@ -126,6 +157,7 @@ export function FilterDialog({ open, onClose }: { open: boolean; onClose: () =>
     src: serializeGroups(parseGroups(f?.src)),
     proc: serializeGroups(parseGroups(f?.proc)),
     msg: serializeGroups(parseGroups(f?.msg)),
+    exclude: serializeGroups(parseGroups(f?.exclude)),
   });
 
   const setGroupsFor = (k: keyof Filter, groups: string[][]) => {
@@ -144,6 +176,40 @@ export function FilterDialog({ open, onClose }: { open: boolean; onClose: () =>
     setGroupsFor(k, groups);
   };
   const clearField = (k: keyof Filter) => setGroupsFor(k, []);
+  // ── 프리셋: 선택 시 로컬 폼에만 로드(적용은 '적용' 버튼) ─────────────────
+  const presetNames = Object.keys(filterPresets).sort((a, b) => a.localeCompare(b));
+  const onPickPreset = (name: string) => {
+    setPresetName(name);
+    const p = filterPresets[name];
+    if (!p) return;
+    measureUi('FilterDialog.preset.load', () => ui.info(`filterDialog.preset.load "${name}"`));
+    setLocal(normalizeAll(p));
+  };
+  const onSavePreset = () => {
+    const name = presetName.trim();
+    if (!name) return;
+    measureUi('FilterDialog.preset.save', () => ui.info(`filterDialog.preset.save "${name}"`));
+    saveFilterPreset(name, normalizeAll(local));
+  };
+  const onDeletePreset = () => {
+    const name = presetName.trim();
+    if (!name || !filterPresets[name]) return;
+    measureUi('FilterDialog.preset.delete', () => ui.info(`filterDialog.preset.delete "${name}"`));
+    deleteFilterPreset(name);
+    setPresetName('');
+  };
+
+  // 프로세스/소스 다중 선택: 각 값을 전체 일치 토큰(`=이름`) OR 그룹으로 토글(빈 값 = ALL)
+  // (부분 포함으로 매칭하면 `net`을 골라도 `netd`/`ethernet`이 같이 걸린다)
+  const isOptSelected = (k: 'proc' | 'src', name: string) =>
+    parseGroups(local[k]).some((g) => g.length === 1 && g[0] === `=${name}`);
+  const toggleOpt = (k: 'proc' | 'src', name: string) => {
+    const groups = parseGroups(local[k]);
+    const i = groups.findIndex((g) => g.length === 1 && g[0] === `=${name}`);
+    if (i >= 0) groups.splice(i, 1);
+    else groups.push([`=${name}`]);
+    setGroupsFor(k, groups);
+  };
   const addOrGroup = (k: keyof Filter) => {
     // 단순히 ", "을 추가(빈 그룹은 적용 시 정리)
     const cur = String((local as any)[k] ?? '');
@@ -151,6 +217,43 @@ export function FilterDialog({ open, onClose }: { open: boolean; onClose: () =>
     setLocal({ ...local, [k]: next ? next + ', ' : ', ' });
   };
 
+  // 후보 칩 행(ALL + 값별 토글). 소스는 그리드와 같은 고정 색으로 테두리 표시
+  const OptionChips = (k: 'proc' | 'src', options: string[], allTitle: string) => {
+    if (!options.length) return null;
+    const chip = (on: boolean, accent?: string): React.CSSProperties => ({
+      fontSize: 11,
+      padding: '2px 8px',
+      borderRadius: 999,
+      cursor: 'pointer',
+      border: `1px solid ${accent ?? 'var(--border, rgba(255,255,255,0.15))'}`,
+      background: on ? 'var(--accent, #2e7dd7)' : 'transparent',
+      color: on ? 'var(--accent-fg, #ffffff)' : 'var(--fg, #e6e6e6)',
+    });
+    return (
+      <div
+        data-testid={`filter-${k}-options`}
+        style={{ display: 'flex', gap: 6, flexWrap: 'wrap', paddingLeft: 82 }}
+      >
+        <button title={allTitle} onClick={() => clearField(k)} style={chip(!local[k].trim())}>
+          ALL
+        </button>
+        {options.map((p) => {
+          const on = isOptSelected(k, p);
+          return (
+            <button
+              key={`${k}-opt-${p}`}
+              title={on ? `선택 해제: ${p}` : `선택: ${p}`}
+              onClick={() => toggleOpt(k, p)}
+              style={chip(on, k === 'src' ? sourceColor(p) : undefined)}
+            >
+              {p}
+            </button>
+          );
+        })}
+      </div>
+    );
+  };
+
   // 단일 필드 행(라벨 / 입력 / 칩 / 필드 초기화)
   const FieldRow = (k: keyof Filter, label: string, ph?: string) => {
     const value = (local[k] ?? '') as string;
@@ -384,12 +487,86 @@ export function FilterDialog({ open, onClose }: { open: boolean; onClose: () =>
           >
             필터 설정
           </div>
+          {/* 프리셋 저장/불러오기 */}
+          <div
+            data-testid="filter-presets"
+            style={{ display: 'flex', gap: 8, alignItems: 'center', marginBottom: 12 }}
+          >
+            <div style={{ fontSize: 12, width: 72, color: 'var(--fg, #e6e6e6)' }}>프리셋</div>
+            <select
+              value={presetNames.includes(presetName) ? presetName : ''}
+              onChange={(e) => onPickPreset(e.currentTarget.value)}
+              style={{
+                padding: '5px 8px',
+                border: '1px solid var(--border, rgba(255,255,255,0.15))',
+                borderRadius: 8,
+                background: 'var(--bg, #121212)',
+                color: 'var(--fg, #e6e6e6)',
+                fontSize: 12,
+              }}
+            >
+              <option value="">{presetNames.length ? '선택…' : '저장된 프리셋 없음'}</option>
+              {presetNames.map((n) => (
+                <option key={`preset-${n}`} value={n}>
+                  {n}
+                </option>
+              ))}
+            </select>
+            <input
+              placeholder="프리셋 이름"
+              value={presetName}
+              onChange={(e) => setPresetName(e.currentTarget.value)}
+              style={{
+                flex: 1,
+                minWidth: 0,
+                padding: '5px 8px',
+                border: '1px solid var(--border, rgba(255,255,255,0.15))',
+                borderRadius: 8,
+                background: 'var(--bg, #121212)',
+                color: 'var(--fg, #e6e6e6)',
+                fontSize: 12,
+              }}
+            />
+            <button
+              onClick={onSavePreset}
+              disabled={!presetName.trim()}
+              style={{
+                fontSize: 12,
+                padding: '5px 10px',
+                border: '1px solid var(--border, rgba(255,255,255,0.15))',
+                borderRadius: 8,
+                background: 'transparent',
+                color: 'var(--fg, #e6e6e6)',
+                cursor: 'pointer',
+              }}
+            >
+              저장
+            </button>
+            <button
+              onClick={onDeletePreset}
+              disabled={!filterPresets[presetName.trim()]}
+              style={{
+                fontSize: 12,
+                padding: '5px 10px',
+                border: '1px solid var(--border, rgba(255,255,255,0.15))',
+                borderRadius: 8,
+                background: 'transparent',
+                color: 'var(--fg, #e6e6e6)',
+                cursor: 'pointer',
+              }}
+            >
+              삭제
+            </button>
+          </div>
           {/* 세로(Vertical) 필드 영역 */}
           <div style={{ display: 'grid', gap: 12 }}>
             {FieldRow('pid', 'PID', '예: 1234 5678, 9012')}
             {FieldRow('src', '파일', '예: kernel.log, matter')}
-            {FieldRow('proc', '프로세스', '예: wlan0 hostapd, cpcd')}
+            {OptionChips('src', srcOptions, '파일 제한 없음')}
+            {FieldRow('proc', '프로세스', '예: wlan0 hostapd, cpcd, =netd(전체 일치)')}
+            {OptionChips('proc', procOptions, '프로세스 제한 없음')}
             {FieldRow('msg', '메시지', '예: wlan host, deauth')}
+            {FieldRow('exclude', '제외', '예: heartbeat, ping pong')}
           </div>
 
           <div
@@ -5,6 +5,7 @@ import { createUiMeasure } from '../../../shared/utils';
 import { createUiLog } from '../../../shared/utils';
 import { useLogStore } from '../../react/store';
 import { vscode } from '../ipc';
+import { sourceColor } from '../sourceColor';
 import type { HighlightRule, LogRow } from '../types';
 import { BookmarkSquare } from './BookmarkSquare';
 import { GridHeader } from './GridHeader';
@@ -733,10 +734,21 @@ export function Grid() {
                   {hi(r.pid, m.highlights)}
                 </Cell>
                 <Cell kind="src" hidden={!m.showCols.src} mono last={lastVisibleCol === 'src'}>
-                  {hi(r.src ?? '', m.highlights)}
+                  <span style={{ color: sourceColor(r.src ?? '') }}>
+                    {hi(r.src ?? '', m.highlights)}
+                  </span>
                 </Cell>
                 <Cell kind="msg" hidden={!m.showCols.msg} last={lastVisibleCol === 'msg'}>
                   {hi(r.msg, m.highlights)}
+                  {r.repeat ? (
+                    <span
+                      className="tw-ml-2 tw-text-[11px] tw-opacity-80 tw-px-1.5 tw-rounded-full tw-border tw-border-[var(--border)]"
+                      title={`같은 메시지가 ${r.repeat}번 더 반복됨`}
+                      data-testid="badge-repeat"
+                    >
+                      {`×${r.repeat + 1}`}
+                    </span>
+                  ) : null}
                 </Cell>
               </div>
             );
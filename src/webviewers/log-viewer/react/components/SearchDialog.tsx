@@ -1,32 +1,40 @@
+import type { SearchOptions } from '@ipc/messages';
 import { useEffect, useRef, useState } from 'react';
 import { createPortal } from 'react-dom';
 
 export function SearchDialog({
   open,
   initialQuery,
+  initialOptions,
   onSearch,
   onCancel,
 }: {
   open: boolean;
   initialQuery?: string;
-  onSearch: (q: string) => void;
+  initialOptions?: SearchOptions;
+  onSearch: (q: string, opts: SearchOptions) => void;
   onCancel: () => void;
 }) {
   // search dialog 전용 ui logger
   const [q, setQ] = useState(initialQuery ?? '');
+  const [caseSensitive, setCaseSensitive] = useState(!!initialOptions?.caseSensitive);
+  const [wholeWord, setWholeWord] = useState(!!initialOptions?.wholeWord);
   const inputRef = useRef<HTMLInputElement | null>(null);
   useEffect(() => {
-    if (open) setQ(initialQuery ?? '');
-  }, [open, initialQuery]);
+    if (!open) return;
+    setQ(initialQuery ?? '');
+    setCaseSensitive(!!initialOptions?.caseSensitive);
+    setWholeWord(!!initialOptions?.wholeWord);
+  }, [open, initialQuery, initialOptions]);
   useEffect(() => {
     if (!open) return;
     const onKey = (e: KeyboardEvent) => {
       if (e.key === 'Escape') onCancel();
-      if (e.key === 'Enter') onSearch(q);
+      if (e.key === 'Enter') onSearch(q, { caseSensitive, wholeWord });
     };
     window.addEventListener('keydown', onKey, true);
     return () => window.removeEventListener('keydown', onKey, true);
-  }, [open, q, onSearch, onCancel]);
+  }, [open, q, caseSensitive, wholeWord, onSearch, onCancel]);
   useEffect(() => {
     if (open) setTimeout(() => inputRef.current?.focus(), 0);
   }, [open]);
@@ -82,6 +90,24 @@ export function SearchDialog({
               outline: 'none',
             }}
           />
+          <div style={{ display: 'flex', gap: 16, marginTop: 10, fontSize: 12 }}>
+            <label style={{ display: 'flex', alignItems: 'center', gap: 4, cursor: 'pointer' }}>
+              <input
+                type="checkbox"
+                checked={caseSensitive}
+                onChange={(e) => setCaseSensitive(e.currentTarget.checked)}
+              />
+              대소문자 구분
+            </label>
+            <label style={{ display: 'flex', alignItems: 'center', gap: 4, cursor: 'pointer' }}>
+              <input
+                type="checkbox"
+                checked={wholeWord}
+                onChange={(e) => setWholeWord(e.currentTarget.checked)}
+              />
+              단어 단위
+            </label>
+          </div>
           <div style={{ display: 'flex', justifyContent: 'flex-end', gap: 8, marginTop: 12 }}>
             <button
               onClick={() => onCancel()}
@@ -97,7 +123,7 @@ export function SearchDialog({
               취소
             </button>
             <button
-              onClick={() => onSearch(q)}
+              onClick={() => onSearch(q, { caseSensitive, wholeWord })}
               style={{
                 padding: '6px 12px',
                 border: 'none',
@@ -8,6 +8,8 @@ export function SearchPanel() {
   const open = useLogStore((s) => s.searchOpen);
   const q = useLogStore((s) => s.searchQuery);
   const hits = useLogStore((s) => s.searchHits);
+  const hasMore = useLogStore((s) => !!s.searchNextCursor);
+  const opts = useLogStore((s) => s.searchOptions);
   const totalRows = useLogStore((s) => s.totalRows);
   // 인덱스 열 너비(총행수 자릿수 기반): 최소 48px, 최대 120px
   const idxWidthPx = useMemo(() => {
@@ -27,8 +29,10 @@ export function SearchPanel() {
         style={{ ['--col-idx-w' as any]: `${idxWidthPx}px` }}
       >
         <div className="tw-text-xs tw-opacity-80">
-          {`찾은 결과 ${hits.length}개`}
+          {`찾은 결과 ${hits.length}개${hasMore ? '+' : ''}`}
           {q ? ` — "${q}"` : ''}
+          {opts.caseSensitive ? ' [Aa]' : ''}
+          {opts.wholeWord ? ' [단어]' : ''}
         </div>
         <button
           title="검색 결과 닫기"
@@ -47,14 +51,8 @@ export function SearchPanel() {
       >
         {!hits.length && <div className="tw-px-3 tw-py-2">검색 결과 없음</div>}
         {hits.map((h, i) => {
-          // 서버에서 내려온 스니펫(text)을 하이라이트
-          const snippet = String(h?.text ?? '');
-          const html = q
-            ? escapeHtml(snippet).replace(
-                new RegExp(escapeRegExp(q), 'ig'),
-                (m) => `<mark>${m}</mark>`,
-              )
-            : escapeHtml(snippet);
+          // 서버가 계산한 매칭 구간(ranges)으로 하이라이트 — 클라이언트 재스캔 없음
+          const html = markRanges(String(h?.text ?? ''), h?.ranges);
           const idx = Number((h as any)?.idx ?? 0);
           return (
             <div
@@ -75,6 +73,15 @@ export function SearchPanel() {
             </div>
           );
         })}
+        {hasMore && (
+          <button
+            title="다음 검색 결과 불러오기"
+            className="tw-w-full tw-text-xs tw-px-3 tw-py-1.5 tw-opacity-80 hover:tw-bg-[var(--row-hover)]"
+            onClick={() => useLogStore.getState().loadMoreSearch()}
+          >
+            더 보기
+          </button>
+        )}
       </section>
     </>
   );
@@ -82,6 +89,14 @@ export function SearchPanel() {
 function escapeHtml(s: string) {
   return s.replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;');
 }
-function escapeRegExp(s: string) {
-  return s.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+function markRanges(text: string, ranges?: [number, number][]) {
+  if (!ranges?.length) return escapeHtml(text);
+  let out = '';
+  let pos = 0;
+  for (const [s, e] of [...ranges].sort((a, b) => a[0] - b[0])) {
+    if (s < pos) continue; // 겹치는 구간은 건너뜀
+    out += escapeHtml(text.slice(pos, s)) + `<mark>${escapeHtml(text.slice(s, e))}</mark>`;
+    pos = e;
+  }
+  return out + escapeHtml(text.slice(pos));
 }
@@ -1,4 +1,5 @@
 import { Popover, Transition } from '@headlessui/react';
+import type { LogStats } from '@ipc/messages';
 import { useMemo, useState } from 'react';
 
 import { createUiLog } from '../../../shared/utils';
@@ -17,6 +18,7 @@ export function Toolbar() {
   const hostMB = useLogStore((s: any) => (s as any).hostMemMB as number | undefined);
   const webMB = useLogStore((s: any) => (s as any).webMemMB as number | undefined);
   const hasAnyMem = typeof hostMB === 'number' || typeof webMB === 'number';
+  const stats = useLogStore((s: any) => (s as any).logStats as LogStats | undefined);
   const totalMB =
     (typeof hostMB === 'number' ? hostMB : 0) + (typeof webMB === 'number' ? webMB : 0);
   const setCol = useLogStore((s) => s.toggleColumn);
@@ -29,6 +31,8 @@ export function Toolbar() {
     total: s.mergeTotal,
   }));
   const filter = useLogStore((s) => s.filter);
+  const severityFloor = useLogStore((s) => s.severityFloor);
+  const setSeverityFloor = useLogStore((s) => s.setSeverityFloor);
   const follow = useLogStore((s) => s.follow);
   const newSincePause = useLogStore((s) => s.newSincePause);
   const setFollow = useLogStore((s) => s.setFollow);
@@ -46,7 +50,10 @@ export function Toolbar() {
   const activeCount = (() => {
     ui.debug?.('[debug] Toolbar: activeCount');
     const t = (v?: string) => String(v ?? '').trim();
-    return ['pid', 'src', 'proc', 'msg'].reduce((n, k) => n + (t((filter as any)[k]) ? 1 : 0), 0);
+    return ['pid', 'src', 'proc', 'msg', 'exclude'].reduce(
+      (n, k) => n + (t((filter as any)[k]) ? 1 : 0),
+      0,
+    );
   })();
 
   const labelOf = (id: 'time' | 'proc' | 'pid' | 'src' | 'msg') => {
@@ -116,6 +123,17 @@ export function Toolbar() {
                   {`[${totalMB}MB]`}
                 </span>
               )}
+              {/* ── 버퍼 통계: 총계 · 메모리/파일 · 유실(있을 때만) ── */}
+              {stats && (
+                <span
+                  className="tw-text-[11px] tw-opacity-70 tw-px-1"
+                  title={`메모리 ${stats.memory} / 파일 ${stats.file} · 회전 ${stats.rotated} · 유실 메시지 ${stats.dropped}`}
+                  data-testid="text-buffer-stats"
+                >
+                  {`${stats.total.toLocaleString()}줄`}
+                  {stats.dropped > 0 ? ` · 유실 ${stats.dropped}` : ''}
+                </span>
+              )}
             </span>
           )}
           {mergeStage ? (
@@ -159,13 +177,17 @@ export function Toolbar() {
       <SearchDialog
         open={searchDlgOpen}
         initialQuery={useLogStore((s) => s.searchQuery)}
+        initialOptions={useLogStore((s) => s.searchOptions)}
         onCancel={() => setSearchDlgOpen(false)}
-        onSearch={(q) => {
+        onSearch={(q, opts) => {
           const query = (q ?? '').trim();
-          ui.info(`search.button q="${query}"`);
-          setSearch(query); // 쿼리만 저장
+          ui.info(
+            `search.button q="${query}" case=${!!opts.caseSensitive} word=${!!opts.wholeWord}`,
+          );
+          setSearch(query, opts); // 쿼리/옵션만 저장
           openSearchPanel(); // 패널 오픈을 명시적으로
-          if (query) vscode?.postMessage({ v: 1, type: 'search.query', payload: { q: query } });
+          if (query)
+            vscode?.postMessage({ v: 1, type: 'search.query', payload: { q: query, ...opts } });
           setSearchDlgOpen(false);
         }}
       />
@@ -188,6 +210,25 @@ export function Toolbar() {
         {`필터${activeCount > 0 ? `(${activeCount})` : ''}`}
       </button>
 
+      {/* 경고/에러만(심각도 하한 W) 빠른 토글 */}
+      <button
+        className={[
+          'tw-text-sm tw-px-2 tw-py-1 tw-rounded tw-border tw-border-[var(--border)]',
+          severityFloor
+            ? 'tw-bg-[var(--accent)] tw-text-[var(--accent-fg)] hover:tw-bg-[var(--accent-hover)]'
+            : '',
+        ].join(' ')}
+        onClick={() => {
+          const next = severityFloor ? undefined : 'W';
+          ui.info(`toolbar.severity.click floor=${next ?? '-'}`);
+          setSeverityFloor(next);
+        }}
+        title="경고(W)·에러(E)만 표시"
+        data-testid="btn-severity-floor"
+      >
+        W/E
+      </button>
+
       {/* 북마크 */}
       <button
         className="tw-text-sm tw-px-2 tw-py-1 tw-rounded tw-border tw-border-[var(--border)]"
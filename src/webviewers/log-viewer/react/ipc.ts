@@ -1,8 +1,10 @@
+import type { LogStats } from '@ipc/messages';
 import { z } from 'zod';
 
 // ⛔️ host utils가 아니라 webview 전용 utils를 사용해야 함
 import { createUiMeasure } from '../../shared/utils';
 import { useLogStore } from './store';
+import type { Filter } from './types';
 
 declare const acquireVsCodeApi: () => {
   postMessage: (m: any) => void;
@@ -31,6 +33,8 @@ const ZLogEntry = z.object({
   file: z.string().optional(),
   path: z.string().optional(),
   text: z.string(),
+  /** 중복 합치기 반복 횟수(실시간) */
+  repeat: z.number().optional(),
 });
 
 // 현재 세션(version) 추적
@@ -67,7 +71,13 @@ let MERGE_ACTIVE = false;
 
 // 필터 전송 gate: warmup/초기 배치 수신 전에는 필터 변경을 보류
 let READY_FOR_FILTER = false;
-let PENDING_FILTER: { pid: string; src: string; proc: string; msg: string } | null = null;
+let PENDING_FILTER: {
+  pid: string;
+  src: string;
+  proc: string;
+  msg: string;
+  exclude: string;
+} | null = null;
 function setReadyForFilter() {
   // quiet
   if (!READY_FOR_FILTER) {
@@ -138,6 +148,11 @@ export function setupIpc() {
           useLogStore.getState().setHostMemMB(hostMB);
           return;
         }
+        case 'logs.stats': {
+          // 호스트가 정수로 정규화해서 보낸다(추가 캐스팅 불필요)
+          useLogStore.getState().setLogStats(payload as LogStats);
+          return;
+        }
         case 'logs.state': {
           // host 쪽 pagination 상태 스냅샷(디버깅/초기 배너/프로그레스 용)
           const total = typeof payload?.total === 'number' ? payload.total : undefined;
@@ -170,6 +185,13 @@ export function setupIpc() {
             useLogStore.getState().toggleColumn('src', !!p.showSrc);
           if (typeof p.showMsg === 'boolean')
             useLogStore.getState().toggleColumn('msg', !!p.showMsg);
+          if (p.filterPresets && typeof p.filterPresets === 'object') {
+            const presets: Record<string, Filter> = {};
+            for (const [name, f] of Object.entries(p.filterPresets as Record<string, any>)) {
+              if (f && typeof f === 'object') presets[name] = normalizeFilter(f);
+            }
+            useLogStore.getState().setFilterPresets(presets);
+          }
           // 북마크 패널은 시작 시 기본 닫힘.
           // prefs 가 true 라도, 현재 세션에 실제 북마크가 있을 때만 열도록 제한.
           if (typeof p.bookmarksOpen === 'boolean') {
@@ -183,6 +205,8 @@ export function setupIpc() {
           const logs = z.array(ZLogEntry).parse(payload?.logs ?? []);
           const total = typeof payload?.total === 'number' ? payload.total : undefined;
           const v = typeof payload?.version === 'number' ? payload.version : undefined;
+          // 팔로우 해제 중 호스트가 라인 없이 보낸 알림(총량/개수만)
+          const skipped = Number(payload?.skipped ?? 0) || 0;
           if (typeof total === 'number') useLogStore.getState().setTotalRows(total);
           const baseId = useLogStore.getState().nextId;
           const mapped = measureUi('ipc.logs.batch.map', () => {
@@ -190,7 +214,7 @@ export function setupIpc() {
               const raw = String(e.text ?? '');
               const p = parseLine(raw);
               const src = pickSrcName(e);
-              return { idx: e.idx, ...p, src, raw };
+              return { idx: e.idx, ...p, src, raw, repeat: e.repeat };
             });
           });
           // ✅ idx 오름차순 정렬 후 id를 정렬 순서대로 부여
@@ -206,9 +230,10 @@ export function setupIpc() {
           // quiet
           // 🚩 정렬 이후 첫 원소의 idx를 startIdx로 사용
           const startIdx = rows.length && typeof rows[0].idx === 'number' ? rows[0].idx! : 1;
-          useLogStore.getState().receiveRows(startIdx, rows);
+          // 라인 없는 알림은 창 위치를 건드리지 않는다(읽던 위치 유지)
+          if (rows.length || !skipped) useLogStore.getState().receiveRows(startIdx, rows);
           // FOLLOW 모드가 아닐 때는 새 로그 도착을 알림
-          if (!useLogStore.getState().follow && rows.length > 0) {
+          if (!useLogStore.getState().follow && (rows.length > 0 || skipped > 0)) {
             useLogStore.getState().incNewSincePause();
           }
           setReadyForFilter(); // 최초 배치 수신 시 필터 전송 허용
@@ -232,6 +257,7 @@ export function setupIpc() {
           setReadyForFilter(); // 풀 리인덱스 이후에도 허용
           // 정식 병합 완료 → 느리게
           setWebMemPeriod(MEM_SLOW_MS);
+          vscode?.postMessage({ v: 1, type: 'logs.stats.request', payload: {} });
 
           // ── 뷰포트 유지용 앵커 계산 ─────────────────────────────────
           // 1) Jump 요청이 대기중이면 그 인덱스
@@ -317,7 +343,7 @@ export function setupIpc() {
               const raw = String(e.text ?? '');
               const p = parseLine(raw);
               const src = pickSrcName(e);
-              return { idx: e.idx, ...p, src, raw };
+              return { idx: e.idx, ...p, src, raw, repeat: e.repeat };
             });
           });
           const sorted = mapped.slice().sort((a, b) => (a.idx ?? 0) - (b.idx ?? 0));
@@ -426,11 +452,17 @@ export function setupIpc() {
           const hits = (payload?.hits ?? []).map((h: any) => ({
             idx: Number(h?.idx) || 0,
             text: String(h?.text || ''),
+            ranges: Array.isArray(h?.ranges) ? h.ranges : undefined,
           }));
           // quiet
           // q 동기화(+ 닫힘 상태 레이스 방지 로직은 store 쪽에 존재)
           const q = typeof payload?.q === 'string' ? String(payload.q) : undefined;
-          useLogStore.getState().setSearchResults(hits, { q });
+          useLogStore.getState().setSearchResults(hits, {
+            q,
+            append: !!payload?.append,
+            nextCursor: payload?.nextCursor,
+            options: payload?.options,
+          });
           return;
         }
         case 'error': {
@@ -486,6 +518,7 @@ export function postFilterUpdate(filter: {
   src?: string;
   proc?: string;
   msg?: string;
+  exclude?: string;
 }) {
   // quiet
   const next = measureUi('ipc.normalizeFilter', () => normalizeFilter(filter));
@@ -506,16 +539,29 @@ function normalizeFilter(f: any) {
   const src = s(f?.src);
   const proc = s(f?.proc);
   const msg = s(f?.msg);
+  const exclude = s(f?.exclude);
   // quiet
-  return { pid, src, proc, msg };
+  return { pid, src, proc, msg, exclude };
 }
 
-function isEmptyFilter(f: { pid?: string; src?: string; proc?: string; msg?: string }) {
+function isEmptyFilter(f: {
+  pid?: string;
+  src?: string;
+  proc?: string;
+  msg?: string;
+  exclude?: string;
+}) {
   const s = (v: any) => String(v ?? '').trim();
-  return !s(f.pid) && !s(f.src) && !s(f.proc) && !s(f.msg);
+  return !s(f.pid) && !s(f.src) && !s(f.proc) && !s(f.msg) && !s(f.exclude);
 }
 
-function flushFilter(next: { pid: string; src: string; proc: string; msg: string }) {
+function flushFilter(next: {
+  pid: string;
+  src: string;
+  proc: string;
+  msg: string;
+  exclude: string;
+}) {
   // quiet
   // 모든 필드가 빈 문자열이면 '해제'로 간주하여 null 전송
   const payload = isEmptyFilter(next) ? { filter: null } : { filter: next };
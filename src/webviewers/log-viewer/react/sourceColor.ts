@@ -0,0 +1,29 @@
+// === src/webviewers/log-viewer/react/sourceColor.ts ===
+// 소스(파일/로그 타입)별 고정 색상: 같은 이름이면 세션/재시작과 무관하게 항상 같은 색
+// - 로테이션 접미사(.1, .2, .gz)는 무시 → system.log 와 system.log.1 은 같은 색
+
+const cache = new Map<string, string>();
+
+/** 로테이션 접미사를 뗀 소스 키 */
+export function sourceKey(src: string): string {
+  return String(src ?? '')
+    .trim()
+    .replace(/\.gz$/i, '')
+    .replace(/\.\d+$/, '');
+}
+
+export function sourceColor(src: string): string | undefined {
+  const key = sourceKey(src);
+  if (!key) return undefined;
+  const hit = cache.get(key);
+  if (hit) return hit;
+  // FNV-1a 32bit → 색상(hue)
+  let h = 0x811c9dc5;
+  for (let i = 0; i < key.length; i++) {
+    h ^= key.charCodeAt(i);
+    h = Math.imul(h, 0x01000193) >>> 0;
+  }
+  const color = `hsl(${h % 360}, 60%, 65%)`;
+  cache.set(key, color);
+  return color;
+}
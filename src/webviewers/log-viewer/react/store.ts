@@ -1,3 +1,10 @@
+import type {
+  LogLevel,
+  LogStats,
+  SearchCursor,
+  SearchHit,
+  SearchOptions,
+} from '@ipc/messages';
 import { create } from 'zustand';
 
 import { LOG_OVERSCAN, LOG_ROW_HEIGHT, LOG_WINDOW_SIZE } from '../../../shared/const';
@@ -26,6 +33,8 @@ const initial: Model & { mergeMode?: 'memory' | 'hybrid'; mergeStage?: string }
   searchQuery: '',
   searchOpen: false,
   searchHits: [],
+  searchNextCursor: undefined,
+  searchOptions: {},
   showBookmarks: false,
   selectedRowId: undefined,
   pendingJumpIdx: undefined,
@@ -33,7 +42,9 @@ const initial: Model & { mergeMode?: 'memory' | 'hybrid'; mergeStage?: string }
   mergeDone: 0,
   mergeTotal: 0,
   // NOTE: 타입 상 Model에 없을 수 있어 런타임 전용으로 취급(액션으로만 갱신)
-  filter: { pid: '', src: '', proc: '', msg: '' },
+  filter: { pid: '', src: '', proc: '', msg: '', exclude: '' },
+  filterPresets: {},
+  severityFloor: undefined,
   follow: true,
   newSincePause: 0,
   bookmarks: {},
@@ -60,10 +71,15 @@ type Actions = {
   receiveRows(startIdx: number, rows: LogRow[]): void;
   toggleColumn(col: ColumnId, on: boolean): void;
   setHighlights(rules: HighlightRule[]): void;
-  setSearch(q: string): void;
+  setSearch(q: string, opts?: SearchOptions): void;
   closeSearch(): void;
   openSearchPanel(): void;
-  setSearchResults(hits: { idx: number; text: string }[], opts?: { q?: string }): void;
+  setSearchResults(
+    hits: SearchHit[],
+    opts?: { q?: string; append?: boolean; nextCursor?: SearchCursor; options?: SearchOptions },
+  ): void;
+  /** 다음 검색 페이지 요청(nextCursor가 있을 때만) */
+  loadMoreSearch(): void;
   toggleBookmark: (rowId: number) => void;
   toggleBookmarkByIdx: (globalIdx: number) => void;
   toggleBookmarksPane(): void;
@@ -84,15 +100,31 @@ type Actions = {
   setFilterField(f: keyof Filter, v: string): void;
   applyFilter(next: Filter): void; // ← 디바운스 후 한 번만 전송
   resetFilters(): void;
+  /** 심각도 하한 설정(undefined=해제) — 호스트로 logs.severity.set 전송 */
+  setSeverityFloor(floor?: LogLevel): void;
+  setFilterPresets(presets: Record<string, Filter>): void;
+  saveFilterPreset(name: string, filter: Filter): void;
+  deleteFilterPreset(name: string): void;
   setFollow(follow: boolean): void;
   incNewSincePause(): void;
   clearNewSincePause(): void;
   // ── 메모리 표시용 액션 ────────────────────────────────────────────────
   setHostMemMB(mb?: number): void;
   setWebMemMB(mb?: number): void;
+  setLogStats(stats?: LogStats): void;
 };
 
-type ExtraState = { hostMemMB?: number; webMemMB?: number };
+type ExtraState = { hostMemMB?: number; webMemMB?: number; logStats?: LogStats };
+
+/** 필터 프리셋 전체 맵을 사용자 prefs 로 저장 */
+function persistFilterPresets(presets: Record<string, Filter>) {
+  vscode?.postMessage({ v: 1, type: 'prefs.save', payload: { prefs: { filterPresets: presets } } });
+}
+
+/** 팔로우 상태를 호스트에 알림 — 해제 중엔 실시간 새 라인 대신 총량만 온다 */
+function postFollow(follow: boolean) {
+  vscode?.postMessage({ v: 1, type: 'logs.follow.set', payload: { follow } });
+}
 
 export const useLogStore = create<Model & ExtraState & Actions>()((set, get) => ({
   ...initial,
@@ -170,18 +202,25 @@ export const useLogStore = create<Model & ExtraState & Actions>()((set, get) =>
     });
   },
 
-  setSearch(q) {
+  setSearch(q, opts) {
     get().measureUi('store.setSearch', () => {
       // 쿼리만 저장. 패널 오픈은 명시적으로 openSearchPanel에서 처리.
       const t = q.trim();
-      if (!t) return set({ searchQuery: '', searchHits: [], searchOpen: false });
-      set({ searchQuery: t });
+      if (!t) {
+        return set({
+          searchQuery: '',
+          searchHits: [],
+          searchNextCursor: undefined,
+          searchOpen: false,
+        });
+      }
+      set(opts ? { searchQuery: t, searchOptions: opts } : { searchQuery: t });
       (get() as any).__ui?.debug?.('store.setSearch');
     });
   },
   closeSearch() {
     get().measureUi('store.closeSearch', () => {
-      set({ searchOpen: false, searchQuery: '', searchHits: [] });
+      set({ searchOpen: false, searchQuery: '', searchHits: [], searchNextCursor: undefined });
       (get() as any).__ui?.debug?.('store.closeSearch');
     });
   },
@@ -197,8 +236,28 @@ export const useLogStore = create<Model & ExtraState & Actions>()((set, get) =>
       // 사용자가 닫은 뒤(쿼리도 비움) 늦게 도착한 결과는 무시하여 재오픈 방지
       if (!st.searchOpen && !st.searchQuery.trim()) return;
       const nextQ = (opts?.q ?? st.searchQuery) || '';
-      set({ searchOpen: true, searchHits: hits, searchQuery: nextQ });
-      (get() as any).__ui?.info?.(`search.results hits=${hits.length}`);
+      const all = opts?.append ? [...st.searchHits, ...hits] : hits;
+      set({
+        searchOpen: true,
+        searchHits: all,
+        searchQuery: nextQ,
+        searchNextCursor: opts?.nextCursor,
+        ...(opts?.options ? { searchOptions: opts.options } : {}),
+      });
+      (get() as any).__ui?.info?.(`search.results hits=${all.length}`);
+    });
+  },
+  loadMoreSearch() {
+    get().measureUi('store.loadMoreSearch', () => {
+      const { searchQuery, searchNextCursor, searchOptions } = get();
+      if (!searchQuery || !searchNextCursor) return;
+      // 중복 요청 방지: 응답이 올 때까지 커서를 비워 둔다
+      set({ searchNextCursor: undefined });
+      vscode?.postMessage({
+        v: 1,
+        type: 'search.query',
+        payload: { q: searchQuery, cursor: searchNextCursor, ...searchOptions },
+      });
     });
   },
 
@@ -271,7 +330,9 @@ export const useLogStore = create<Model & ExtraState & Actions>()((set, get) =>
     get().measureUi('store.jumpToIdx', () => {
       // 검색/북마크 등 "명시적 점프" 시에는 tail 팔로우를 자동 해제한다.
       // (follow=true 상태에서 점프 직후 다시 tail로 되돌아가는 현상 방지)
-      set({ pendingJumpIdx: Math.max(1, idx | 0), follow: false });
+      // setFollow을 거쳐야 호스트에도 팔로우 해제가 전달된다
+      get().setFollow(false);
+      set({ pendingJumpIdx: Math.max(1, idx | 0) });
       (get() as any).__ui?.info?.(`store.jumpToIdx idx=${idx} (auto-pause follow)`);
     });
   },
@@ -359,15 +420,48 @@ export const useLogStore = create<Model & ExtraState & Actions>()((set, get) =>
   resetFilters() {
     get().measureUi('store.resetFilters', () => {
       (get() as any).__ui?.debug?.('[debug] resetFilters: start');
-      const empty = { pid: '', src: '', proc: '', msg: '' };
+      const empty = { pid: '', src: '', proc: '', msg: '', exclude: '' };
       set({ filter: empty });
       postFilterUpdate(empty); // 초기화는 즉시 반영
       (get() as any).__ui?.info?.('store.resetFilters');
       (get() as any).__ui?.debug?.('[debug] resetFilters: end');
     });
   },
+  setSeverityFloor(floor) {
+    get().measureUi('store.setSeverityFloor', () => {
+      set({ severityFloor: floor });
+      vscode?.postMessage({ v: 1, type: 'logs.severity.set', payload: { floor: floor ?? null } });
+      (get() as any).__ui?.info?.(`store.setSeverityFloor ${floor ?? '-'}`);
+    });
+  },
+  setFilterPresets(presets) {
+    get().measureUi('store.setFilterPresets', () => {
+      set({ filterPresets: { ...presets } });
+    });
+  },
+  saveFilterPreset(name, filter) {
+    get().measureUi('store.saveFilterPreset', () => {
+      const key = String(name ?? '').trim();
+      if (!key) return;
+      const next = { ...get().filterPresets, [key]: { ...filter } };
+      set({ filterPresets: next });
+      persistFilterPresets(next);
+      (get() as any).__ui?.info?.(`store.saveFilterPreset "${key}"`);
+    });
+  },
+  deleteFilterPreset(name) {
+    get().measureUi('store.deleteFilterPreset', () => {
+      const next = { ...get().filterPresets };
+      if (!(name in next)) return;
+      delete next[name];
+      set({ filterPresets: next });
+      persistFilterPresets(next);
+      (get() as any).__ui?.info?.(`store.deleteFilterPreset "${name}"`);
+    });
+  },
   setFollow(follow) {
     get().measureUi('store.setFollow', () => {
+      if (get().follow !== follow) postFollow(follow);
       set({ follow });
       (get() as any).__ui?.debug?.(`store.setFollow ${follow}`);
     });
@@ -398,6 +492,9 @@ export const useLogStore = create<Model & ExtraState & Actions>()((set, get) =>
       webMemMB: typeof mb === 'number' ? Math.max(0, mb | 0) : undefined,
     } as any);
   },
+  setLogStats(stats) {
+    set({ ...(get() as any), logStats: stats } as any);
+  },
 }));
 
 function escapeRegExp(s: string) {
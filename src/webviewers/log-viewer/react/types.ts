@@ -1,3 +1,5 @@
+import type { LogLevel, SearchCursor, SearchHit, SearchOptions } from '@ipc/messages';
+
 export type ColumnId = 'time' | 'proc' | 'pid' | 'src' | 'msg';
 export type HighlightColor =
   | 'c1'
@@ -29,6 +31,8 @@ export interface LogRow {
   src?: string;
   /** 원본 한 줄 전체 문자열(팝업, 복사용) */
   raw: string;
+  /** 중복 합치기: 이 행 뒤로 같은 메시지가 추가로 반복된 횟수 */
+  repeat?: number;
   bookmarked?: boolean;
 }
 
@@ -39,7 +43,7 @@ export interface BookmarkItem {
   src?: string;
 }
 
-export type Filter = { pid: string; src: string; proc: string; msg: string };
+export type Filter = { pid: string; src: string; proc: string; msg: string; exclude: string };
 
 export interface Model {
   rows: LogRow[];
@@ -58,7 +62,11 @@ export interface Model {
   highlights: HighlightRule[];
   searchQuery: string;
   searchOpen: boolean;
-  searchHits: { idx: number; text: string }[];
+  searchHits: SearchHit[];
+  /** 다음 검색 페이지 위치(없으면 마지막 페이지) */
+  searchNextCursor?: SearchCursor;
+  /** 검색 매칭 옵션(대소문자 구분/단어 단위) */
+  searchOptions: SearchOptions;
   showBookmarks: boolean;
   selectedRowId?: number;
 
@@ -67,6 +75,10 @@ export interface Model {
   mergeTotal: number;
 
   filter: Filter;
+  /** 심각도 하한(호스트 적용, 텍스트 필터와 AND) — undefined=해제 */
+  severityFloor?: LogLevel;
+  /** 이름 → 필터 프리셋(prefs.filterPresets 로 영속) */
+  filterPresets: Record<string, Filter>;
   pendingJumpIdx?: number;
   follow: boolean;
   newSincePause: number;